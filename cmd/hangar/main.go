@@ -24,6 +24,8 @@ import (
 
 	"github.com/sjoeboo/hangar/internal/git"
 	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/notify"
+	"github.com/sjoeboo/hangar/internal/scheduler"
 	"github.com/sjoeboo/hangar/internal/session"
 	"github.com/sjoeboo/hangar/internal/statedb"
 	"github.com/sjoeboo/hangar/internal/ui"
@@ -232,6 +234,9 @@ func main() {
 		case "launch":
 			handleLaunch(profile, args[1:])
 			return
+		case "run":
+			handleRun(profile, args[1:])
+			return
 		case "worktree", "wt":
 			handleWorktree(profile, args[1:])
 			return
@@ -256,6 +261,15 @@ func main() {
 		case "tower":
 			handleTower(profile, args[1:])
 			return
+		case "usage":
+			handleUsage(profile, args[1:])
+			return
+		case "search":
+			handleSearch(profile, args[1:])
+			return
+		case "schedule":
+			handleSchedule(profile, args[1:])
+			return
 		}
 	}
 
@@ -435,6 +449,26 @@ func main() {
 		p.Send(ui.MaintenanceCompleteMsg{Result: result})
 	})
 
+	// Start auto-start todos when the todos they depend on are done.
+	session.EnableTodoChaining(profile)
+
+	// Push todo completions to notification sinks; status transitions come
+	// from the notify daemon, which also routes them to Tower when there is
+	// one.
+	sinks := notify.FromConfig()
+	if sinks.HasSinks() {
+		sinks.Enable()
+	}
+	towerNotify := session.GetTowerSettings().Notifications
+	if sinks.HasSinks() || (towerNotify.GetEnabled() && profileHasTower(profile)) {
+		startNotifyDaemonInBackground()
+	}
+
+	// Run scheduled jobs; only the primary instance executes them.
+	if db := statedb.GetGlobal(); db != nil {
+		go scheduler.New(profile, db).Run(maintenanceCtx)
+	}
+
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -710,6 +744,8 @@ func handleAdd(profile string, args []string) {
 	// Resume session flag
 	resumeSession := fs.String("resume-session", "", "Claude session ID to resume (skips new session creation)")
 
+	templateName := fs.String("template", "", "Session template from config.toml (explicit flags override it)")
+
 	fs.Usage = func() {
 		fmt.Println("Usage: hangar add [path] [options]")
 		fmt.Println()
@@ -733,6 +769,7 @@ func handleAdd(profile string, args []string) {
 		fmt.Println("  hangar add -c \"codex --dangerously-bypass-approvals-and-sandbox\" .")
 		fmt.Println("  hangar add -g ard --no-parent -c claude .")
 		fmt.Println("  hangar add --quick -c claude .   # Auto-generated name")
+		fmt.Println("  hangar add --template bugfix -w fix/login .  # Use [templates.bugfix]")
 		fmt.Println()
 		fmt.Println("Worktree Examples:")
 		fmt.Println("  hangar add -w feature/login .    # Create worktree for existing branch")
@@ -762,12 +799,35 @@ func handleAdd(profile string, args []string) {
 	}
 	createNewBranch := *newBranch || *newBranchLong
 
+	// Load the template; its settings fill in whatever flags were not given
+	var tmpl *session.SessionTemplate
+	if *templateName != "" {
+		var err error
+		if tmpl, err = session.GetSessionTemplate(*templateName); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if tmpl.UsesWorktree() && wtBranch == "" {
+			fmt.Printf("Error: template '%s' creates a worktree; pass a branch with -w\n", *templateName)
+			os.Exit(1)
+		}
+	}
+
 	// Merge short and long flags
 	sessionTitle := mergeFlags(*title, *titleShort)
 	sessionGroup := mergeFlags(*group, *groupShort)
-	explicitGroupProvided := strings.TrimSpace(sessionGroup) != ""
 	sessionCommandInput := mergeFlags(*command, *commandShort)
-	sessionCommandTool, sessionCommandResolved, sessionWrapperResolved, sessionCommandNote := resolveSessionCommand(sessionCommandInput, *wrapper)
+	wrapperInput := *wrapper
+	if tmpl != nil {
+		sessionGroup = firstNonEmpty(sessionGroup, tmpl.Group)
+		sessionCommandInput = firstNonEmpty(sessionCommandInput, tmpl.Tool)
+		wrapperInput = firstNonEmpty(wrapperInput, tmpl.Wrapper)
+		if len(mcpFlags) == 0 {
+			mcpFlags = tmpl.MCPs
+		}
+	}
+	explicitGroupProvided := strings.TrimSpace(sessionGroup) != ""
+	sessionCommandTool, sessionCommandResolved, sessionWrapperResolved, sessionCommandNote := resolveSessionCommand(sessionCommandInput, wrapperInput)
 	sessionParent := mergeFlags(*parent, *parentShort)
 	if sessionParent != "" && *noParent {
 		fmt.Println("Error: --parent and --no-parent cannot be used together")
@@ -999,6 +1059,16 @@ func handleAdd(profile string, args []string) {
 		newInstance.Wrapper = sessionWrapperResolved
 	}
 
+	// Apply the template's Claude options
+	if tmpl != nil && newInstance.Tool == "claude" {
+		userConfig, _ := session.LoadUserConfig()
+		opts := session.NewClaudeOptions(userConfig)
+		tmpl.ApplyClaudeOptions(opts)
+		if err := newInstance.SetClaudeOptions(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to set template options: %v\n", err)
+		}
+	}
+
 	// Set worktree fields if created
 	if worktreePath != "" {
 		newInstance.WorktreePath = worktreePath
@@ -1060,6 +1130,29 @@ func handleAdd(profile string, args []string) {
 		}
 	}
 
+	// A template prompt is only useful once the session runs, so start it
+	// and deliver the prompt when the agent is ready.
+	var templatePrompt string
+	if tmpl != nil {
+		templatePrompt = tmpl.RenderPrompt(session.TemplateVars{
+			Title:  sessionTitle,
+			Branch: wtBranch,
+			Path:   path,
+			Group:  newInstance.GroupPath,
+		})
+	}
+	if templatePrompt != "" {
+		if err := newInstance.StartWithMessage(templatePrompt); err != nil {
+			fmt.Printf("Error: failed to start session: %v\n", err)
+			os.Exit(1)
+		}
+		newInstance.PostStartSync(3 * time.Second)
+		if err := saveSessionData(storage, instances); err != nil {
+			fmt.Printf("Error: failed to save session state: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	quietMode := *quiet || *quietShort
 	out := NewCLIOutput(*jsonOutput, quietMode)
 
@@ -1092,9 +1185,16 @@ func handleAdd(profile string, args []string) {
 	if *resumeSession != "" {
 		humanLines = append(humanLines, fmt.Sprintf("  Resume:  %s", *resumeSession))
 	}
+	if tmpl != nil {
+		humanLines = append(humanLines, fmt.Sprintf("  Template: %s", *templateName))
+	}
 	humanLines = append(humanLines, "")
 	humanLines = append(humanLines, "Next steps:")
-	humanLines = append(humanLines, fmt.Sprintf("  hangar session start %s   # Start the session", sessionTitle))
+	if templatePrompt != "" {
+		humanLines = append(humanLines, fmt.Sprintf("  hangar session attach %s   # Session started with the template prompt", sessionTitle))
+	} else {
+		humanLines = append(humanLines, fmt.Sprintf("  hangar session start %s   # Start the session", sessionTitle))
+	}
 	humanLines = append(humanLines, "  hangar                         # Open TUI and press Enter to attach")
 
 	// Build JSON data
@@ -1132,6 +1232,10 @@ func handleAdd(profile string, args []string) {
 	if *resumeSession != "" {
 		jsonData["resume_session"] = *resumeSession
 	}
+	if tmpl != nil {
+		jsonData["template"] = *templateName
+		jsonData["started"] = templatePrompt != ""
+	}
 
 	out.Success(humanLines[0], jsonData)
 	if !*jsonOutput && !quietMode {
@@ -2023,14 +2127,18 @@ func printHelp() {
 	fmt.Println("  (none)           Start the TUI")
 	fmt.Println("  add <path>       Add a new session")
 	fmt.Println("  launch [path]    Add, start, and optionally send a message in one step")
+	fmt.Println("  run              Run a prompt headless, wait, and print the response")
 	fmt.Println("  try <name>       Quick experiment (create/find dated folder + session)")
 	fmt.Println("  list, ls         List all sessions")
 	fmt.Println("  remove, rm       Remove a session")
 	fmt.Println("  rename, mv       Rename a session")
 	fmt.Println("  status           Show session status summary")
+	fmt.Println("  usage            Show token usage and estimated cost by project, branch, day, model")
+	fmt.Println("  search <query>   Search agent conversations across all projects")
 	fmt.Println("  session          Manage session lifecycle")
 	fmt.Println("  project          Manage projects (git repo pointers)")
 	fmt.Println("  worktree, wt     Manage git worktrees")
+	fmt.Println("  schedule         Schedule recurring or one-shot prompts")
 	fmt.Println("  hooks            Manage Claude Code lifecycle hooks")
 	fmt.Println("  web              Manage the embedded web UI server")
 	fmt.Println("  profile          Manage profiles")
@@ -2050,6 +2158,7 @@ func printHelp() {
 	fmt.Println("  session set <id> <field> <value>      Update a session property")
 	fmt.Println("  session send <id> <message>           Send a message to a running session")
 	fmt.Println("  session output <id>                   Get the last response from a session")
+	fmt.Println("  session history <id>                  Show status transitions and time per status")
 	fmt.Println("  session set-parent <id> <parent>      Link session as sub-session of parent")
 	fmt.Println("  session unset-parent <id>             Remove sub-session link")
 	fmt.Println()
@@ -2059,6 +2168,11 @@ func printHelp() {
 	fmt.Println("  worktree finish <session>   Merge branch, remove worktree, delete session")
 	fmt.Println("  worktree cleanup            Find and remove orphaned worktrees/sessions")
 	fmt.Println()
+	fmt.Println("Schedule Commands:")
+	fmt.Println("  schedule add               Add a cron (--cron) or one-shot (--at) job")
+	fmt.Println("  schedule list              List jobs with next and last run")
+	fmt.Println("  schedule rm <id>           Remove a job")
+	fmt.Println()
 	fmt.Println("Hook Commands:")
 	fmt.Println("  hooks install     Install Claude Code lifecycle hooks")
 	fmt.Println("  hooks uninstall   Remove Claude Code lifecycle hooks")
@@ -2069,6 +2183,7 @@ func printHelp() {
 	fmt.Println("  web start         Start standalone web server (auto-started by TUI if not running)")
 	fmt.Println("  web stop          Stop standalone web server")
 	fmt.Println("  web status        Show web server status and URL")
+	fmt.Println("  web token         Manage API keys (create, list, revoke)")
 	fmt.Println()
 	fmt.Println("Profile Commands:")
 	fmt.Println("  profile list              List all profiles")
@@ -2087,6 +2202,7 @@ func printHelp() {
 	fmt.Println("  hangar worktree finish my-branch  # Merge and clean up a worktree")
 	fmt.Println("  hangar hooks install              # Set up Claude Code hooks")
 	fmt.Println("  hangar project list/add/remove    # Manage projects")
+	fmt.Println("  hangar usage --since 7d --group-by project")
 	fmt.Println()
 	fmt.Println("Environment Variables:")
 	fmt.Println("  HANGAR_PROFILE    Default profile to use")
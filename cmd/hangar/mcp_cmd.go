@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/sjoeboo/hangar/internal/apiserver"
 	"github.com/sjoeboo/hangar/internal/mcpserver"
 	"github.com/sjoeboo/hangar/internal/session"
 )
@@ -17,7 +18,16 @@ func handleMCPServer(args []string) {
 
 	_, _ = session.GetHangarDir() // ensure config dir initialized
 
-	srv := mcpserver.New(baseURL, Version)
+	// HANGAR_API_TOKEN lets remote or sandboxed agents use a scoped key;
+	// otherwise fall back to the local token published by the running server.
+	token := func() string {
+		if t := os.Getenv("HANGAR_API_TOKEN"); t != "" {
+			return t
+		}
+		return apiserver.LoadLocalToken()
+	}
+
+	srv := mcpserver.New(baseURL, token, Version)
 	if err := srv.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "mcp-server error: %v\n", err)
 		os.Exit(1)
@@ -5,9 +5,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"syscall"
+	"time"
 
+	"github.com/sjoeboo/hangar/internal/notify"
 	"github.com/sjoeboo/hangar/internal/session"
 )
 
@@ -15,22 +21,55 @@ import (
 func handleNotifyDaemon(args []string) {
 	fs := flag.NewFlagSet("notify-daemon", flag.ExitOnError)
 	once := fs.Bool("once", false, "Run one sync pass and exit")
+	test := fs.Bool("test", false, "Send a test event to every [[notifications.sinks]] entry and exit")
+	exitWhenIdle := fs.Bool("exit-when-idle", false, "Exit once no TUI or web server has been running for two minutes")
 
 	fs.Usage = func() {
-		fmt.Println("Usage: hangar notify-daemon [--once]")
+		fmt.Println("Usage: hangar notify-daemon [--once] [--test] [--exit-when-idle]")
 		fmt.Println()
 		fmt.Println("Run status-driven transition notification daemon.")
+		fmt.Println("Events go to the session's parent, or to Tower per [tower.notifications],")
+		fmt.Println("and to the sinks configured under [[notifications.sinks]].")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
 		os.Exit(1)
 	}
 
+	sinks := notify.FromConfig()
+	if *test {
+		testNotificationSinks(sinks)
+		return
+	}
+	sinks.Enable()
+	defer sinks.Wait()
+
 	daemon := session.NewTransitionDaemon()
+	if *exitWhenIdle {
+		daemon.Idle = func() bool {
+			if daemon.TUIAlive() {
+				return false
+			}
+			pid := readWebPID(webPIDFile())
+			return pid <= 0 || !webProcessRunning(pid)
+		}
+	}
 	if *once {
 		daemon.SyncOnce(context.Background())
+		daemon.Flush()
+		return
+	}
+
+	// Only one daemon may run; a second would deliver every event twice.
+	pidFile := notifyDaemonPIDFile()
+	if pid := readWebPID(pidFile); pid > 0 && pid != os.Getpid() && webProcessRunning(pid) {
+		fmt.Printf("notify-daemon already running (PID %d).\n", pid)
 		return
 	}
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write PID file: %v\n", err)
+	}
+	defer os.Remove(pidFile)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -40,3 +79,95 @@ func handleNotifyDaemon(args []string) {
 		os.Exit(1)
 	}
 }
+
+// testNotificationSinks sends a sample event to every configured sink and
+// prints the result for each.
+func testNotificationSinks(sinks *notify.Dispatcher) {
+	if !sinks.HasSinks() {
+		fmt.Println("No [[notifications.sinks]] configured in config.toml.")
+		return
+	}
+	results := sinks.Test(notify.Event{
+		Type:    notify.EventStatus,
+		Time:    time.Now(),
+		Title:   "Hangar test notification",
+		Message: "If you can read this, the sink works.",
+		Status:  string(session.StatusWaiting),
+	})
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failed := false
+	for _, name := range names {
+		if err := results[name]; err != nil {
+			failed = true
+			fmt.Printf("%s %s: %v\n", errorSymbol, name, err)
+		} else {
+			fmt.Printf("%s %s\n", successSymbol, name)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// notifyDaemonPIDFile returns the path to the notify-daemon PID file.
+func notifyDaemonPIDFile() string {
+	dir, err := session.GetHangarDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "hangar-notify-daemon.pid")
+	}
+	return filepath.Join(dir, "notify-daemon.pid")
+}
+
+// profileHasTower reports whether the profile has a Tower session.
+func profileHasTower(profile string) bool {
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		return false
+	}
+	defer storage.Close()
+	for _, inst := range instances {
+		if inst.SessionType == "tower" {
+			return true
+		}
+	}
+	return false
+}
+
+// startNotifyDaemonInBackground launches this binary's notify-daemon in its
+// own session, logging to ~/.hangar/logs/notify-daemon.log, unless one is
+// already running. The daemon exits once no TUI or web server is left; the
+// child is reaped if it exits while we are still up.
+func startNotifyDaemonInBackground() {
+	if pid := readWebPID(notifyDaemonPIDFile()); pid > 0 && webProcessRunning(pid) {
+		return
+	}
+	hangarDir, err := session.GetHangarDir()
+	if err != nil {
+		return
+	}
+	logsDir := filepath.Join(hangarDir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return
+	}
+	logFile, err := os.OpenFile(filepath.Join(logsDir, "notify-daemon.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer logFile.Close()
+	self, err := os.Executable()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(self, "notify-daemon", "--exit-when-idle")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	go func() { _ = cmd.Wait() }()
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/git"
+	"github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// handleRun runs one agent task without the TUI: it creates a session the
+// same way POST /api/v1/sessions does, sends the prompt, optionally waits
+// for the agent to stop and prints its last response, then leaves, kills or
+// finishes the session. Meant for Makefiles, CI and cron.
+func handleRun(profile string, args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	project := fs.String("project", "", "Run in this project's repository (from projects.toml)")
+	path := fs.String("path", "", "Run in this directory (default: current directory)")
+	title := fs.String("title", "", "Session title (default: run-<timestamp>)")
+	titleShort := fs.String("t", "", "Session title (short)")
+	group := fs.String("group", "", "Group path (default: the project's group)")
+	tool := fs.String("tool", "", "Agent tool (default: claude)")
+	template := fs.String("template", "", "Session template from config.toml")
+	todoID := fs.String("todo", "", "Todo ID to work on: fills the template's {todo.*} variables, is the default prompt, and is linked to the session")
+	worktree := fs.Bool("worktree", false, "Run in a new git worktree")
+	branch := fs.String("branch", "", "Worktree branch (default: derived from the title)")
+	skipPermissions := fs.Bool("skip-permissions", false, "Start Claude with --dangerously-skip-permissions")
+	prompt := fs.String("prompt", "", "Prompt to send")
+	promptFile := fs.String("prompt-file", "", "Read the prompt from a file (- for stdin)")
+	wait := fs.Bool("wait", false, "Wait for the agent to stop and print its last response")
+	timeout := fs.Duration("timeout", 30*time.Minute, "With --wait, give up after this long")
+	finish := fs.String("finish", "leave", "After --wait: leave the session, kill it, or open a PR (pr, needs --worktree)")
+	into := fs.String("into", "", "With --finish=pr, the PR's base branch (default: the repository's default branch)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Print only the agent's last response")
+	quietShort := fs.Bool("q", false, "Print only the agent's last response (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar run [options]")
+		fmt.Println()
+		fmt.Println("Start an agent session with a prompt, headless. With --wait, block until the")
+		fmt.Println("agent stops, print its last response, and exit 1 if it ended in error or timed out.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  hangar run --project api --worktree --prompt-file task.md --wait --finish=pr")
+		fmt.Println("  hangar run --prompt \"Update the changelog\" --wait -q > summary.txt")
+		fmt.Println("  git diff | hangar run --prompt-file - --wait --finish=kill --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	switch *finish {
+	case "leave", "kill":
+	case "pr":
+		if !*worktree {
+			out.Error("--finish=pr requires --worktree", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	default:
+		out.Error(fmt.Sprintf("invalid --finish %q (want leave, kill or pr)", *finish), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *finish != "leave" && !*wait {
+		out.Error("--finish requires --wait", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	message, err := readRunPrompt(*prompt, *promptFile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if message == "" && *template == "" && *todoID == "" {
+		out.Error("a prompt is required (--prompt, --prompt-file, --template or --todo)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	req := apiserver.CreateSessionRequest{
+		Title:           mergeFlags(*title, *titleShort),
+		Path:            *path,
+		Tool:            *tool,
+		Group:           *group,
+		Message:         message,
+		Worktree:        *worktree,
+		Branch:          *branch,
+		SkipPermissions: *skipPermissions,
+		Template:        *template,
+		TodoID:          *todoID,
+	}
+	if *project != "" {
+		if req.Path != "" {
+			out.Error("--project and --path cannot be used together", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		p, err := session.GetProject(*project)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		req.Path = session.ExpandPath(p.BaseDir)
+		if req.Group == "" {
+			req.Group = p.GroupPath()
+		}
+	}
+	if req.Path == "" {
+		if req.Path, err = os.Getwd(); err != nil {
+			out.Error(fmt.Sprintf("failed to get current directory: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	} else if req.Path, err = filepath.Abs(req.Path); err != nil {
+		out.Error(fmt.Sprintf("failed to resolve path: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if req.Worktree && !git.IsGitRepo(req.Path) {
+		out.Error(fmt.Sprintf("%s is not a git repository", req.Path), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if req.Title == "" {
+		req.Title = "run-" + time.Now().Format("20060102-150405")
+	}
+
+	inst, message, err := apiserver.PrepareSession(profile, req)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := inst.StartWithMessage(message); err != nil {
+		out.Error(fmt.Sprintf("failed to start session: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	result := map[string]interface{}{
+		"session_id": inst.ID,
+		"title":      inst.Title,
+		"path":       inst.ProjectPath,
+	}
+	if inst.IsWorktree() {
+		result["branch"] = inst.WorktreeBranch
+	}
+	if !*wait {
+		if quietMode {
+			fmt.Println(inst.ID)
+			return
+		}
+		out.Print(fmt.Sprintf("%s Started %s (%s) in %s\n", successSymbol, inst.Title, TruncateID(inst.ID), FormatPath(inst.ProjectPath)), result)
+		return
+	}
+
+	waited := waitForRun(inst, *timeout)
+	for k, v := range waited {
+		result[k] = v
+	}
+	status, _ := waited["status"].(string)
+	timedOut, _ := waited["timed_out"].(bool)
+	failed := timedOut || status == string(session.StatusError)
+
+	// A timed-out or failed session is left running for inspection unless
+	// the caller asked for it to be killed; it never gets a PR.
+	var prLine string
+	switch {
+	case *finish == "kill":
+		if err := inst.Kill(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to kill session: %v\n", err)
+		}
+		result["killed"] = true
+	case *finish == "pr" && !failed:
+		p, err := finishRunViaPR(profile, inst, *into)
+		if p == nil {
+			out.Error(fmt.Sprintf("failed to open PR: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		result["pr_number"] = p.Number
+		result["pr_url"] = p.URL
+		prLine = fmt.Sprintf("%s Opened PR #%d: %s\n", successSymbol, p.Number, p.URL)
+	}
+
+	lastResponse, _ := waited["last_response"].(string)
+	switch {
+	case timedOut:
+		if *jsonOutput {
+			out.Print("", result)
+		} else {
+			out.Error(fmt.Sprintf("timed out after %s: %s is %s", *timeout, inst.Title, status), ErrCodeInvalidOperation)
+		}
+		os.Exit(1)
+	case quietMode:
+		fmt.Println(lastResponse)
+	default:
+		symbol := successSymbol
+		if failed {
+			symbol = errorSymbol
+		}
+		human := fmt.Sprintf("%s %s is %s\n", symbol, inst.Title, status)
+		if lastResponse != "" {
+			human += "\n" + lastResponse + "\n"
+		}
+		if prLine != "" {
+			human += "\n" + prLine
+		}
+		out.Print(human, result)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// waitForRun waits for the agent to finish the run's prompt. The SessionStart
+// hook reports "waiting" before the agent has picked the prompt up, so the
+// session must leave the finished statuses once before the wait can end.
+func waitForRun(inst *session.Instance, timeout time.Duration) map[string]interface{} {
+	return waitForSession(inst, "", timeout, true)
+}
+
+// readRunPrompt returns the prompt from --prompt or --prompt-file ("-" reads
+// stdin); giving both is an error.
+func readRunPrompt(prompt, promptFile string) (string, error) {
+	if promptFile == "" {
+		return strings.TrimSpace(prompt), nil
+	}
+	if prompt != "" {
+		return "", fmt.Errorf("--prompt and --prompt-file cannot be used together")
+	}
+	var data []byte
+	var err error
+	if promptFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(promptFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// finishRunViaPR pushes the run's worktree branch and opens a PR; the
+// worktree and session are removed once it merges (see pr.FinishViaPR).
+func finishRunViaPR(profile string, inst *session.Instance, into string) (*pr.PR, error) {
+	if dirty, err := git.HasUncommittedChanges(inst.WorktreePath); err == nil && dirty {
+		return nil, fmt.Errorf("worktree has uncommitted changes; the agent did not commit its work")
+	}
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return nil, fmt.Errorf("gh CLI not found")
+	}
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+	return pr.FinishViaPR(ghPath, storage, inst, pr.FinishOptions{Base: into})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestReadRunPrompt(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "task.md")
+	if err := os.WriteFile(file, []byte("\nFix the flaky test\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := readRunPrompt("  hello ", ""); err != nil || got != "hello" {
+		t.Errorf("--prompt = %q, %v", got, err)
+	}
+	if got, err := readRunPrompt("", file); err != nil || got != "Fix the flaky test" {
+		t.Errorf("--prompt-file = %q, %v", got, err)
+	}
+	if _, err := readRunPrompt("hello", file); err == nil {
+		t.Error("expected error when both --prompt and --prompt-file are set")
+	}
+	if _, err := readRunPrompt("", filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("expected error for a missing prompt file")
+	}
+}
+
+func TestWaitForRunIgnoresInitialWaiting(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	orig := currentSessionStatus
+	defer func() { currentSessionStatus = orig }()
+	// The SessionStart hook has reported "waiting" but the agent has not
+	// picked up the prompt yet.
+	currentSessionStatus = func(*session.Instance, *session.StatusFileWatcher) session.Status {
+		return session.StatusWaiting
+	}
+
+	inst := session.NewInstance("run-wait-test", t.TempDir())
+	result := waitForRun(inst, 200*time.Millisecond)
+	if timedOut, _ := result["timed_out"].(bool); !timedOut {
+		t.Errorf("result = %v, want the wait to block until timeout", result)
+	}
+}
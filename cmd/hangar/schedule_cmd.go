@@ -0,0 +1,272 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/scheduler"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// handleSchedule dispatches "hangar schedule" subcommands.
+func handleSchedule(profile string, args []string) {
+	if len(args) == 0 {
+		printScheduleHelp()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "add", "new":
+		handleScheduleAdd(profile, args[1:])
+	case "list", "ls":
+		handleScheduleList(profile, args[1:])
+	case "rm", "remove", "delete":
+		handleScheduleRemove(profile, args[1:])
+	case "help", "--help", "-h":
+		printScheduleHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown schedule command: %s\n", args[0])
+		printScheduleHelp()
+		os.Exit(1)
+	}
+}
+
+// printScheduleHelp prints usage for schedule commands.
+func printScheduleHelp() {
+	fmt.Println("Usage: hangar schedule <command> [options]")
+	fmt.Println()
+	fmt.Println("Schedule prompts for later. Jobs run inside the primary hangar TUI,")
+	fmt.Println("so at least one TUI must be open when a job is due.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  add     Add a recurring (--cron) or one-shot (--at) job")
+	fmt.Println("  list    List jobs with their next and last run")
+	fmt.Println("  rm <id> Remove a job")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  hangar schedule add --session my-app --cron \"0 9 * * 1-5\" \"/pr-review\"")
+	fmt.Println("  hangar schedule add --session my-app --at 30m \"check CI and fix failures\"")
+	fmt.Println("  hangar schedule add --todo todo-ab12 --at 02:00")
+	fmt.Println("  hangar schedule rm sched-1a2b3c4d")
+}
+
+// handleScheduleAdd creates a job.
+func handleScheduleAdd(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule add", flag.ExitOnError)
+	sessionRef := fs.String("session", "", "Session to send the message to (title or ID)")
+	todoRef := fs.String("todo", "", "Todo to start a fresh worktree session from (ID or title)")
+	cronExpr := fs.String("cron", "", "Recurring schedule: 5-field cron expression or @hourly/@daily/@weekly/@weekdays")
+	at := fs.String("at", "", "One-shot time: HH:MM, a duration (2h), \"YYYY-MM-DD HH:MM\" or RFC 3339")
+	message := fs.String("message", "", "Message to send (or override the todo prompt); may also be given as arguments")
+	name := fs.String("name", "", "Label for the job")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar schedule add (--session <id> | --todo <id>) (--cron <expr> | --at <time>) [message]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	msg := *message
+	if msg == "" {
+		msg = strings.Join(fs.Args(), " ")
+	}
+	if (*sessionRef == "") == (*todoRef == "") {
+		out.Error("specify exactly one of --session or --todo", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+
+	spec := scheduler.Spec{Name: *name, Cron: *cronExpr, At: *at, Message: msg}
+	target := ""
+	if *sessionRef != "" {
+		inst, errMsg, errCode := ResolveSession(*sessionRef, instances)
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(2)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		spec.Action = scheduler.ActionSend
+		spec.SessionID = inst.ID
+		target = inst.Title
+	} else {
+		todo, errMsg := resolveTodo(storage, *todoRef)
+		if todo == nil {
+			out.Error(errMsg, ErrCodeNotFound)
+			os.Exit(2)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		spec.Action = scheduler.ActionTodo
+		spec.TodoID = todo.ID
+		target = "todo " + todo.Title
+	}
+
+	row, err := scheduler.NewJob(spec, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := storage.GetDB().SaveSchedule(row); err != nil {
+		out.Error(fmt.Sprintf("failed to save schedule: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	out.Success(fmt.Sprintf("Scheduled %s for %s (%s, next run %s)",
+		row.ID, target, scheduler.Describe(row), row.NextRun.Format("2006-01-02 15:04")),
+		scheduleJSON(row))
+}
+
+// handleScheduleList prints all jobs.
+func handleScheduleList(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(normalizeArgs(fs, args))
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	rows, err := storage.GetDB().LoadSchedules()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load schedules: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	titles := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		titles[inst.ID] = inst.Title
+	}
+
+	jsonRows := make([]map[string]interface{}, 0, len(rows))
+	var sb strings.Builder
+	if len(rows) == 0 {
+		sb.WriteString("No scheduled jobs. Add one with: hangar schedule add --help\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%-26s %-26s %-24s %-17s %s\n", "ID", "TARGET", "WHEN", "NEXT RUN", "LAST RUN"))
+		sb.WriteString(strings.Repeat("-", 110) + "\n")
+	}
+	for _, r := range rows {
+		target := r.SessionID
+		if r.Action == scheduler.ActionTodo {
+			target = "todo " + r.TodoID
+		} else if t, ok := titles[r.SessionID]; ok {
+			target = t
+		}
+		if r.Name != "" {
+			target = r.Name + ": " + target
+		}
+		next := "-"
+		if r.Enabled && !r.NextRun.IsZero() {
+			next = r.NextRun.Format("2006-01-02 15:04")
+		}
+		last := "never"
+		if !r.LastRun.IsZero() {
+			last = r.LastRun.Format("2006-01-02 15:04")
+			if r.LastError != "" {
+				last += " (" + errorSymbol + " " + r.LastError + ")"
+			} else {
+				last += " " + successSymbol
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%-26s %-26s %-24s %-17s %s\n",
+			r.ID, truncate(target, 26), truncate(scheduler.Describe(r), 24), next, last))
+		jsonRows = append(jsonRows, scheduleJSON(r))
+	}
+	out.Print(sb.String(), jsonRows)
+}
+
+// handleScheduleRemove deletes a job by ID.
+func handleScheduleRemove(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule rm", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(normalizeArgs(fs, args))
+
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() < 1 {
+		out.Error("schedule id is required (see: hangar schedule list)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	id := fs.Arg(0)
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to open storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	if err := storage.GetDB().DeleteSchedule(id); err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	out.Success(fmt.Sprintf("Removed schedule %s", id), map[string]interface{}{
+		"success": true,
+		"id":      id,
+	})
+}
+
+// resolveTodo finds a todo by exact ID, ID prefix or case-insensitive title.
+func resolveTodo(storage *session.Storage, ref string) (*session.Todo, string) {
+	todos, err := storage.LoadAllTodos()
+	if err != nil {
+		return nil, fmt.Sprintf("failed to load todos: %v", err)
+	}
+	var matches []*session.Todo
+	for _, t := range todos {
+		if t.ID == ref {
+			return t, ""
+		}
+		if strings.HasPrefix(t.ID, ref) || strings.EqualFold(t.Title, ref) {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], ""
+	case 0:
+		return nil, fmt.Sprintf("todo '%s' not found", ref)
+	}
+	return nil, fmt.Sprintf("'%s' matches %d todos; use the full ID", ref, len(matches))
+}
+
+func scheduleJSON(r *statedb.ScheduleRow) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":         r.ID,
+		"name":       r.Name,
+		"action":     r.Action,
+		"session_id": r.SessionID,
+		"todo_id":    r.TodoID,
+		"message":    r.Message,
+		"cron":       r.Cron,
+		"enabled":    r.Enabled,
+		"created_at": r.CreatedAt,
+	}
+	if !r.RunAt.IsZero() {
+		m["run_at"] = r.RunAt
+	}
+	if !r.NextRun.IsZero() {
+		m["next_run"] = r.NextRun
+	}
+	if !r.LastRun.IsZero() {
+		m["last_run"] = r.LastRun
+		m["last_error"] = r.LastError
+	}
+	return m
+}
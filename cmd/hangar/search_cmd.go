@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/mcpserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// handleSearch searches agent conversation transcripts. It asks the running
+// daemon, whose index is already warm, and otherwise builds a one-off index.
+func handleSearch(profile string, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	project := fs.String("project", "", "Only conversations in this project (name or directory)")
+	since := fs.String("since", "", "Only conversations active since: duration (24h, 7d), \"today\", YYYY-MM-DD or RFC 3339")
+	fuzzy := fs.Bool("fuzzy", false, "Typo-tolerant matching instead of exact substring")
+	limit := fs.Int("limit", 20, "Maximum results")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Print only transcript paths")
+	quietShort := fs.Bool("q", false, "Print only transcript paths (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar search <query> [options]")
+		fmt.Println()
+		fmt.Println("Search agent conversations across all projects. Needs [global_search]")
+		fmt.Println("enabled = true in config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  hangar search \"auth bug\"")
+		fmt.Println("  hangar search --project api --since 14d migration")
+		fmt.Println("  hangar search --fuzzy --json authentcation")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	req := apiserver.SearchRequest{
+		Query:   strings.Join(fs.Args(), " "),
+		Project: *project,
+		Since:   *since,
+		Fuzzy:   *fuzzy,
+		Limit:   *limit,
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	resp, err := searchViaDaemon(req)
+	if resp == nil && err == nil {
+		resp, err = searchLocally(profile, req)
+	}
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if quietMode {
+		for _, r := range resp.Results {
+			fmt.Println(r.FilePath)
+		}
+		return
+	}
+	out.Print(formatSearchResults(resp), resp)
+}
+
+// searchViaDaemon runs the search on the running daemon. It returns nil and
+// no error when no daemon is reachable.
+func searchViaDaemon(req apiserver.SearchRequest) (*apiserver.SearchResponse, error) {
+	port := resolvePort()
+	if !probeDaemon(port) {
+		return nil, nil
+	}
+	token := func() string {
+		if t := os.Getenv("HANGAR_API_TOKEN"); t != "" {
+			return t
+		}
+		return apiserver.LoadLocalToken()
+	}
+	client := mcpserver.NewClient(fmt.Sprintf("http://localhost:%d", port), token)
+	result, err := client.SearchConversations(req.Query, req.Project, req.Since, req.Fuzzy, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var resp apiserver.SearchResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// searchLocally builds a search index, waits for it to load, and runs req.
+func searchLocally(profile string, req apiserver.SearchRequest) (*apiserver.SearchResponse, error) {
+	cfg, err := session.LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := session.NewGlobalSearchIndex(session.GetClaudeConfigDir(), cfg.GlobalSearch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search index: %w", err)
+	}
+	if idx == nil {
+		return nil, fmt.Errorf("conversation search is disabled; set enabled = true under [global_search] in config.toml")
+	}
+	defer idx.Close()
+	idx.WaitLoaded(context.Background())
+
+	var instances []*session.Instance
+	if storage, insts, _, err := loadSessionData(profile); err == nil {
+		instances = insts
+		storage.Close()
+	}
+	return apiserver.SearchConversations(idx, instances, req)
+}
+
+// formatSearchResults renders search results for the terminal.
+func formatSearchResults(resp *apiserver.SearchResponse) string {
+	if len(resp.Results) == 0 {
+		return fmt.Sprintf("No conversations matching %q\n", resp.Query)
+	}
+	var sb strings.Builder
+	noun := "conversations"
+	if len(resp.Results) == 1 {
+		noun = "conversation"
+	}
+	fmt.Fprintf(&sb, "%d %s matching %q:\n", len(resp.Results), noun, resp.Query)
+	for _, r := range resp.Results {
+		title := r.Summary
+		if r.SessionTitle != "" {
+			title = fmt.Sprintf("%s [%s]", r.SessionTitle, TruncateID(r.SessionID))
+		}
+		if title == "" {
+			title = r.ConversationID
+		}
+		fmt.Fprintf(&sb, "\n%s %s\n", bulletSymbol, truncateSearchText(title, 80))
+		meta := []string{r.ModifiedAt.Local().Format("2006-01-02 15:04")}
+		if r.Tool != "" {
+			meta = append([]string{r.Tool}, meta...)
+		}
+		if r.CWD != "" {
+			meta = append([]string{FormatPath(r.CWD)}, meta...)
+		}
+		fmt.Fprintf(&sb, "  %s\n", strings.Join(meta, " · "))
+		if r.Snippet != "" {
+			fmt.Fprintf(&sb, "  %s\n", truncateSearchText(r.Snippet, 160))
+		}
+		fmt.Fprintf(&sb, "  %s\n", r.FilePath)
+	}
+	if resp.Indexing {
+		sb.WriteString("\nIndexing is still running; results may be incomplete.\n")
+	}
+	return sb.String()
+}
+
+// truncateSearchText flattens s onto one line and cuts it to max runes.
+func truncateSearchText(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-3]) + "..."
+	}
+	return s
+}
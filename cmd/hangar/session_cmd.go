@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -10,8 +11,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sjoeboo/hangar/internal/apiserver"
 	"github.com/sjoeboo/hangar/internal/clipboard"
 	"github.com/sjoeboo/hangar/internal/git"
+	"github.com/sjoeboo/hangar/internal/mcpserver"
 	"github.com/sjoeboo/hangar/internal/profile"
 	"github.com/sjoeboo/hangar/internal/session"
 	"github.com/sjoeboo/hangar/internal/tmux"
@@ -49,6 +52,12 @@ func handleSession(profile string, args []string) {
 		handleSessionSend(profile, args[1:])
 	case "output":
 		handleSessionOutput(profile, args[1:])
+	case "history":
+		handleSessionHistory(profile, args[1:])
+	case "wait":
+		handleSessionWait(profile, args[1:])
+	case "export":
+		handleSessionExport(profile, args[1:])
 	case "help", "--help", "-h":
 		printSessionHelp()
 	default:
@@ -74,7 +83,11 @@ func printSessionHelp() {
 	fmt.Println("  current                 Show current session and profile (auto-detect)")
 	fmt.Println("  set <id> <field> <value>  Update session property")
 	fmt.Println("  send <id> <message>     Send a message to a running session")
+	fmt.Println("  send --group/--status/--project <message>  Broadcast to matching sessions")
 	fmt.Println("  output <id>             Get the last response from a session")
+	fmt.Println("  history <id>            Show status transitions and time spent per status")
+	fmt.Println("  wait <id>               Block until the agent is waiting, idle or errored")
+	fmt.Println("  export <id>             Export the conversation as Markdown, HTML or JSON")
 	fmt.Println("  set-parent <id> <parent>  Link session as sub-session of parent")
 	fmt.Println("  unset-parent <id>       Remove sub-session link")
 	fmt.Println()
@@ -95,6 +108,9 @@ func printSessionHelp() {
 	fmt.Println("  hangar session unset-parent sub-task             # Remove sub-session link")
 	fmt.Println("  hangar session output my-project                 # Get last response from session")
 	fmt.Println("  hangar session output my-project --json          # Get response as JSON")
+	fmt.Println("  hangar session history my-project --since today  # Time spent waiting today")
+	fmt.Println("  hangar session wait my-project --for waiting --timeout 30m")
+	fmt.Println("  hangar session export my-project --format html -o transcript.html")
 	fmt.Println()
 	fmt.Println("Set command fields:")
 	fmt.Println("  title              Session title")
@@ -1291,6 +1307,66 @@ func handleSessionUnsetParent(profile string, args []string) {
 	)
 }
 
+// broadcastMessage sends message to every session matching sel and prints
+// the per-session results. Exits 1 if any delivery failed.
+func broadcastMessage(profile string, out *CLIOutput, sel session.BroadcastSelector, message string) {
+	if message == "" {
+		out.Error("message is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	switch sel.Status {
+	case "", session.StatusRunning, session.StatusWaiting, session.StatusIdle, session.StatusError:
+	default:
+		out.Error(fmt.Sprintf("invalid status '%s' (want running, waiting, idle or error)", sel.Status), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	var projects []*session.Project
+	if sel.Project != "" {
+		if projects, err = session.LoadProjects(); err != nil {
+			out.Error(fmt.Sprintf("failed to load projects: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+	targets, err := session.SelectSessions(instances, sel, projects)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	if len(targets) == 0 {
+		out.Error(fmt.Sprintf("no sessions match %s", sel), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	results := session.Broadcast(targets, message)
+	failed := 0
+	var human strings.Builder
+	for _, r := range results {
+		if r.Sent {
+			fmt.Fprintf(&human, "%s %s\n", successSymbol, r.Title)
+		} else {
+			failed++
+			fmt.Fprintf(&human, "%s %s: %s\n", errorSymbol, r.Title, r.Error)
+		}
+	}
+	fmt.Fprintf(&human, "Sent to %d/%d sessions\n", len(results)-failed, len(results))
+	out.Print(human.String(), map[string]interface{}{
+		"success": failed == 0,
+		"message": message,
+		"sent":    len(results) - failed,
+		"failed":  failed,
+		"results": results,
+	})
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
 // handleSessionSend sends a message to a running session
 // Waits for the agent to be ready before sending (Claude, Gemini, etc.)
 func handleSessionSend(profile string, args []string) {
@@ -1301,11 +1377,16 @@ func handleSessionSend(profile string, args []string) {
 	noWait := fs.Bool("no-wait", false, "Don't wait for agent to be ready (send immediately)")
 	wait := fs.Bool("wait", false, "Block until agent finishes processing, then print output")
 	timeout := fs.Duration("timeout", 10*time.Minute, "Max time to wait for completion (used with --wait)")
+	group := fs.String("group", "", "Broadcast to every session in this group (and its subgroups)")
+	status := fs.String("status", "", "Broadcast to every session with this status (running, waiting, idle, error)")
+	project := fs.String("project", "", "Broadcast to every session of this project")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: hangar session send <id|title> <message> [options]")
+		fmt.Println("       hangar session send [--group <path>] [--status <status>] [--project <name>] <message>")
 		fmt.Println()
-		fmt.Println("Send a message to a running session.")
+		fmt.Println("Send a message to a running session, or broadcast it to every session")
+		fmt.Println("matching --group, --status and --project (combined with AND).")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -1314,6 +1395,8 @@ func handleSessionSend(profile string, args []string) {
 		fmt.Println("  hangar session send my-project \"Summarize recent changes\"")
 		fmt.Println("  hangar session send my-project \"run tests\" --wait")
 		fmt.Println("  hangar session send my-project \"quick ping\" --no-wait")
+		fmt.Println("  hangar session send --group projects/foo --status waiting \"git pull --rebase\"")
+		fmt.Println("  hangar session send --project myrepo \"rebase on main and fix conflicts\"")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -1323,6 +1406,16 @@ func handleSessionSend(profile string, args []string) {
 
 	out := NewCLIOutput(*jsonOutput, *quiet)
 
+	sel := session.BroadcastSelector{Group: *group, Status: session.Status(*status), Project: *project}
+	if !sel.IsEmpty() {
+		if *wait {
+			out.Error("--wait cannot be used when broadcasting", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		broadcastMessage(profile, out, sel, strings.Join(remaining, " "))
+		return
+	}
+
 	if len(remaining) < 2 {
 		fs.Usage()
 		out.Error("session and message are required", ErrCodeInvalidOperation)
@@ -1915,6 +2008,361 @@ func handleSessionOutput(profile string, args []string) {
 	out.Print(sb.String(), jsonData)
 }
 
+// handleSessionHistory prints a session's status transitions and the time it
+// spent in each status over a window.
+func handleSessionHistory(profile string, args []string) {
+	fs := flag.NewFlagSet("session history", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	since := fs.String("since", "24h", "Window start: duration (24h), \"today\", YYYY-MM-DD or RFC 3339")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar session history [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Show status transitions (with hook/poller source) and time spent per status.")
+		fmt.Println("If no ID is provided, auto-detects current session.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	sinceTime, err := session.ParseSince(*since, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	tl, err := session.LoadStatusTimeline(storage.GetDB(), inst.ID, sinceTime)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load history: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	transitions := make([]map[string]interface{}, 0, len(tl.Transitions))
+	totals := make(map[string]float64, len(tl.Totals))
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Session: %s (%s)\n", inst.Title, TruncateID(inst.ID)))
+	sb.WriteString(fmt.Sprintf("Window:  %s → now\n\n", tl.Since.Format("2006-01-02 15:04")))
+	if len(tl.Transitions) == 0 {
+		sb.WriteString("No status transitions in this window.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%-17s %-20s %-7s %s\n", "TIME", "TRANSITION", "SOURCE", "DURATION"))
+	}
+	for _, t := range tl.Transitions {
+		from := t.FromStatus
+		if from == "" {
+			from = "?"
+		}
+		sb.WriteString(fmt.Sprintf("%-17s %-20s %-7s %s\n",
+			t.At.Format("2006-01-02 15:04"), from+" → "+t.ToStatus, t.Source, formatHistoryDuration(t.Duration)))
+		transitions = append(transitions, map[string]interface{}{
+			"from":             t.FromStatus,
+			"to":               t.ToStatus,
+			"source":           t.Source,
+			"at":               t.At,
+			"duration_seconds": t.Duration.Seconds(),
+		})
+	}
+	if len(tl.Totals) > 0 {
+		sb.WriteString("\nTime per status:\n")
+		for _, status := range []string{"running", "waiting", "idle", "error"} {
+			if d, ok := tl.Totals[status]; ok {
+				sb.WriteString(fmt.Sprintf("  %s %-8s %s\n", bulletSymbol, status, formatHistoryDuration(d)))
+			}
+		}
+	}
+	for status, d := range tl.Totals {
+		totals[status] = d.Seconds()
+	}
+
+	out.Print(sb.String(), map[string]interface{}{
+		"session_id":     inst.ID,
+		"session_title":  inst.Title,
+		"since":          tl.Since,
+		"until":          tl.Until,
+		"transitions":    transitions,
+		"totals_seconds": totals,
+	})
+}
+
+// handleSessionExport writes a session's conversation (turns, tool calls,
+// subagents and token usage) as Markdown, HTML or JSON.
+func handleSessionExport(profile string, args []string) {
+	fs := flag.NewFlagSet("session export", flag.ExitOnError)
+	format := fs.String("format", "markdown", "Output format: markdown, html or json")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	outputShort := fs.String("o", "", "Write to this file (short)")
+	since := fs.String("since", "", "Only turns since: duration (2h), \"today\", YYYY-MM-DD or RFC 3339")
+	maxResult := fs.Int("max-result-bytes", 0, "Truncate each tool result to this many bytes (0 = full)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar session export [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Export a Claude, Gemini or Codex session's conversation, including tool calls,")
+		fmt.Println("subagents and token usage. If no ID is provided, auto-detects current session.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(false, false)
+
+	switch *format {
+	case "markdown", "md", "html", "json":
+	default:
+		out.Error(fmt.Sprintf("invalid --format %q (want markdown, html or json)", *format), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	opts := session.ConversationOptions{MaxResultBytes: *maxResult}
+	if *since != "" {
+		t, err := session.ParseSince(*since, time.Now())
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		opts.Since = t
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	conv, err := inst.GetConversation(opts)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read conversation: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	w := os.Stdout
+	if path := mergeFlags(*output, *outputShort); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to create output file: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	switch *format {
+	case "html":
+		err = conv.WriteHTML(w, inst.Title)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(map[string]interface{}{
+			"session_id":    inst.ID,
+			"session_title": inst.Title,
+			"conversation":  conv,
+		})
+	default:
+		err = conv.WriteMarkdown(w, inst.Title)
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to write export: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+}
+
+// formatHistoryDuration renders a duration as e.g. "1h05m", "4m12s" or "8s".
+func formatHistoryDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+// handleSessionWait blocks until a session reaches one of the requested
+// statuses, then prints the final status and the agent's last response.
+// With a running daemon it long-polls the API (woken by hook events);
+// otherwise it polls tmux directly. Exits 1 on timeout.
+func handleSessionWait(profile string, args []string) {
+	fs := flag.NewFlagSet("session wait", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	forStatus := fs.String("for", "", "Comma-separated statuses to wait for (default: waiting,idle,error)")
+	timeout := fs.Duration("timeout", 10*time.Minute, "Give up after this long")
+	changed := fs.Bool("changed", false, "Ignore a matching status until the session has left it (use right after send)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar session wait [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Block until a session reaches a status, then print it and the agent's last response.")
+		fmt.Println("If no ID is provided, auto-detects current session. Exits 1 on timeout.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  hangar session send my-project \"Run the tests\" && hangar session wait my-project --changed")
+		fmt.Println("  hangar session wait my-project --for waiting --timeout 30m --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if _, err := session.ParseWaitStatuses(*forStatus); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	start := time.Now()
+	result := waitForSession(inst, *forStatus, *timeout, *changed)
+
+	status, _ := result["status"].(string)
+	lastResponse, _ := result["last_response"].(string)
+	waited := time.Since(start).Round(time.Second)
+	if timedOut, _ := result["timed_out"].(bool); timedOut {
+		if *jsonOutput {
+			out.Print("", result)
+		} else {
+			out.Error(fmt.Sprintf("timed out after %s: %s is %s", waited, inst.Title, status), ErrCodeInvalidOperation)
+		}
+		os.Exit(1)
+	}
+
+	if quietMode {
+		fmt.Println(lastResponse)
+		return
+	}
+	human := fmt.Sprintf("%s %s is %s (waited %s)\n", successSymbol, inst.Title, status, waited)
+	if lastResponse != "" {
+		human += "\n" + lastResponse + "\n"
+	}
+	out.Print(human, result)
+}
+
+// waitForSession waits for inst to reach one of statuses (a comma-separated
+// list, already validated) and returns the result in the shape of the API's
+// WaitResponse. It long-polls the daemon when one is running and otherwise
+// watches hook status files and tmux itself.
+func waitForSession(inst *session.Instance, statuses string, timeout time.Duration, changed bool) map[string]interface{} {
+	if result, ok := waitViaDaemon(inst.ID, statuses, timeout, changed); ok {
+		return result
+	}
+
+	opts := session.WaitOptions{Changed: changed, Poll: 2 * time.Second}
+	opts.Statuses, _ = session.ParseWaitStatuses(statuses)
+	var watcher *session.StatusFileWatcher
+	if w, err := session.NewStatusFileWatcher(); err == nil {
+		watcher = w
+		go watcher.Start()
+		defer watcher.Stop()
+		opts.Wake = watcher.NotifyChannel
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	status, matched := session.WaitForStatus(ctx, func() session.Status {
+		return currentSessionStatus(inst, watcher)
+	}, opts)
+
+	result := map[string]interface{}{
+		"session_id":     inst.ID,
+		"title":          inst.Title,
+		"status":         string(status),
+		"timed_out":      !matched,
+		"waited_seconds": time.Since(start).Round(time.Millisecond).Seconds(),
+	}
+	if matched {
+		if resp, err := inst.GetLastResponseBestEffort(); err == nil && resp.Content != "" {
+			result["last_response"] = resp.Content
+		}
+	}
+	return result
+}
+
+// currentSessionStatus refreshes inst's status from its hook status file (if
+// watcher is non-nil) and tmux, and returns it. A variable so tests can fake
+// session status.
+var currentSessionStatus = func(inst *session.Instance, watcher *session.StatusFileWatcher) session.Status {
+	if watcher != nil {
+		inst.UpdateHookStatus(watcher.GetHookStatus(inst.ID))
+	}
+	_ = inst.UpdateStatus()
+	return inst.GetStatusThreadSafe()
+}
+
+// waitViaDaemon long-polls the running daemon's wait endpoint. It returns
+// false when no daemon is reachable or it cannot answer for this session
+// (e.g. it serves another profile), so the caller can poll locally instead.
+func waitViaDaemon(id, statuses string, timeout time.Duration, changed bool) (map[string]interface{}, bool) {
+	port := resolvePort()
+	if !probeDaemon(port) {
+		return nil, false
+	}
+	token := func() string {
+		if t := os.Getenv("HANGAR_API_TOKEN"); t != "" {
+			return t
+		}
+		return apiserver.LoadLocalToken()
+	}
+	client := mcpserver.NewClient(fmt.Sprintf("http://localhost:%d", port), token)
+	result, err := client.WaitForSession(id, statuses, timeout, changed)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
 // handleSessionCurrent shows current session and profile (auto-detected)
 // Uses a fast path that reads session data without tmux initialization (LoadLite).
 func handleSessionCurrent(profileArg string, args []string) {
@@ -119,6 +119,12 @@ func handleTower(profile string, args []string) {
 		}
 	}
 
+	// Route child session transitions to Tower.
+	towerNotify := session.GetTowerSettings().Notifications
+	if towerNotify.GetEnabled() {
+		startNotifyDaemonInBackground()
+	}
+
 	// Step 5: Attach (only when --attach / -a is passed).
 	if !attach {
 		fmt.Printf("Tower is running (ID: %s). Use 'hangar tower --attach' or the TUI to connect.\n", towerInst.ID[:8])
@@ -202,10 +208,12 @@ all other sessions.
 
 You have access to Hangar tools for:
 - Listing and inspecting all sessions (status, output, PR info)
-- Sending messages/prompts to any running session
+- Sending messages/prompts to any running session, or broadcasting to many at once
 - Starting, stopping, and restarting sessions
-- Creating new sessions
+- Creating and forking sessions
 - Managing todos across projects
+- Pull requests: listing them with check status, reading detail, reviewing and commenting
+- Worktrees: showing what a session changed and finishing it (PR, merge or cleanup)
 
 ## How to Respond
 
@@ -213,10 +221,25 @@ You have access to Hangar tools for:
   Title | Status | Tool | Project/Branch
 - **"What is X working on?"** → call ` + "`hangar_get_session`" + ` + ` + "`hangar_get_output`" + `
 - **"Send X a message"** → call ` + "`hangar_send_message`" + `; confirm first if ambiguous
+- **"Ask X to do Y and tell me what it says"** → ` + "`hangar_send_message`" + `, then ` + "`hangar_wait_for_session`" + ` with ` + "`changed`" + ` set
+- **"Tell all waiting sessions in group G to ..."** → call ` + "`hangar_broadcast`" + ` with a group/status/project selector and report which sessions failed
 - **"Create a session for Y"** → ask for path if not provided, then ` + "`hangar_create_session`" + `
+- **"Which of my PRs have failing checks?"** → call ` + "`hangar_list_prs`" + ` with ` + "`failing_checks`" + `, then ` + "`hangar_pr_detail`" + ` for specifics
+- **"Get X to fix the review comments / CI on PR N"** → call ` + "`hangar_pr_address_feedback`" + `; it finds the PR's session or starts one on its branch
+- **"Merge PR N"** → check it with ` + "`hangar_pr_detail`" + `, confirm first, then ` + "`hangar_pr_merge`" + `; never set ` + "`force`" + ` unless the user explicitly accepts failing checks
+- **"Find the conversation where we ..."** → call ` + "`hangar_search_conversations`" + `, then ` + "`hangar_get_session`" + ` for any owning session
+- **"Show me what X changed"** → call ` + "`hangar_get_diff`" + ` (start with ` + "`summary_only`" + ` for large changes)
+- **"Finish X" / "Open a PR for X"** → call ` + "`hangar_finish_worktree`" + `; confirm first, since it removes the worktree and session
 - Keep responses concise; use tables for session lists
 - Flag any sessions in "error" status prominently
 
+## Events
+
+Messages starting with ` + "`[EVENT]`" + ` are sent by Hangar when sessions without a parent
+stop running (waiting, error or idle). Several may arrive batched in one message.
+Check the session's output, then briefly tell the user which agents need attention
+and why. Do not reply to the sessions unless the user asks you to.
+
 ## Self-Awareness
 
 Your own session ID is available as $HANGAR_INSTANCE_ID. You are session_type="tower".
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// handleUsage implements `hangar usage`: token usage and estimated cost
+// across all sessions, grouped by project, branch, day and model.
+func handleUsage(profile string, args []string) {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	since := fs.String("since", "30d", "Window start: duration (24h, 7d), \"today\", YYYY-MM-DD or RFC 3339")
+	project := fs.String("project", "", "Only include sessions in this project")
+	groupBy := fs.String("group-by", "", "Comma-separated dimensions: project,branch,day,model (default: all)")
+	format := fs.String("format", "table", "Output format: table, csv or json")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (same as --format json)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: hangar usage [options]")
+		fmt.Println()
+		fmt.Println("Aggregate Claude token usage and estimated cost across sessions.")
+		fmt.Println("Rows are sorted by estimated cost, highest first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  hangar usage                          # Last 30 days, all dimensions")
+		fmt.Println("  hangar usage --since 7d --group-by project")
+		fmt.Println("  hangar usage --project my-app --group-by branch,model")
+		fmt.Println("  hangar usage --since 2026-03-01 --format csv > usage.csv")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if *jsonOutput {
+		*format = "json"
+	}
+	out := NewCLIOutput(*format == "json", false)
+
+	switch *format {
+	case "table", "csv", "json":
+	default:
+		out.Error(fmt.Sprintf("invalid format %q (use table, csv or json)", *format), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	sinceTime, err := session.ParseSince(*since, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	dims, err := session.ParseUsageGroupBy(*groupBy)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+
+	projects, _ := session.LoadProjects()
+	report := session.AggregateUsage(instances, session.UsageOptions{
+		Since:    sinceTime,
+		Project:  *project,
+		GroupBy:  dims,
+		Projects: projects,
+	})
+
+	switch *format {
+	case "csv":
+		if err := report.WriteCSV(os.Stdout); err != nil {
+			out.Error(fmt.Sprintf("failed to write CSV: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		return
+	case "json":
+		rows := make([]map[string]interface{}, 0, len(report.Records))
+		for i := range report.Records {
+			rows = append(rows, usageRecordJSON(&report.Records[i], report.GroupBy))
+		}
+		out.Print("", map[string]interface{}{
+			"since":    report.Since,
+			"until":    report.Until,
+			"group_by": report.GroupBy,
+			"rows":     rows,
+			"total":    usageRecordJSON(&report.Total, nil),
+		})
+		return
+	}
+
+	fmt.Print(formatUsageTable(report))
+}
+
+// usageRecordJSON renders a record with the same keys as GET /api/v1/usage.
+func usageRecordJSON(rec *session.UsageRecord, dims []string) map[string]interface{} {
+	m := map[string]interface{}{
+		"sessions":           rec.Sessions,
+		"turns":              rec.Turns,
+		"input_tokens":       rec.InputTokens,
+		"output_tokens":      rec.OutputTokens,
+		"cache_read_tokens":  rec.CacheReadTokens,
+		"cache_write_tokens": rec.CacheWriteTokens,
+		"total_tokens":       rec.TotalTokens(),
+		"estimated_cost_usd": rec.EstimatedCost,
+	}
+	for _, d := range dims {
+		m[d] = rec.Dimension(d)
+	}
+	return m
+}
+
+// formatUsageTable renders the report as an aligned text table.
+func formatUsageTable(report *session.UsageReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Usage since %s\n\n", report.Since.Format("2006-01-02 15:04")))
+	if len(report.Records) == 0 {
+		sb.WriteString("No Claude usage recorded in this window.\n")
+		return sb.String()
+	}
+
+	header := make([]string, 0, len(report.GroupBy)+5)
+	for _, d := range report.GroupBy {
+		header = append(header, strings.ToUpper(d))
+	}
+	header = append(header, "SESSIONS", "INPUT", "OUTPUT", "CACHE", "COST")
+	firstNumeric := len(report.GroupBy)
+
+	rows := [][]string{header}
+	for i := range report.Records {
+		rows = append(rows, usageTableRow(&report.Records[i], report.GroupBy))
+	}
+	total := usageTableRow(&report.Total, report.GroupBy)
+	for i := range report.GroupBy {
+		total[i] = ""
+	}
+	if firstNumeric > 0 {
+		total[0] = "TOTAL"
+	}
+	rows = append(rows, total)
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for r, row := range rows {
+		if r == len(rows)-1 {
+			sb.WriteString("\n")
+		}
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if i >= firstNumeric {
+				sb.WriteString(pad + cell)
+			} else {
+				sb.WriteString(cell + pad)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func usageTableRow(rec *session.UsageRecord, dims []string) []string {
+	row := make([]string, 0, len(dims)+5)
+	for _, d := range dims {
+		v := rec.Dimension(d)
+		if v == "" {
+			v = "-"
+		}
+		row = append(row, v)
+	}
+	return append(row,
+		fmt.Sprintf("%d", rec.Sessions),
+		formatTokenCount(rec.InputTokens),
+		formatTokenCount(rec.OutputTokens),
+		formatTokenCount(rec.CacheReadTokens+rec.CacheWriteTokens),
+		fmt.Sprintf("$%.2f", rec.EstimatedCost),
+	)
+}
+
+// formatTokenCount renders a token count as e.g. "950", "12.3K" or "4.1M".
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
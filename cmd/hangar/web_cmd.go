@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/notify"
 	"github.com/sjoeboo/hangar/internal/pr"
 	"github.com/sjoeboo/hangar/internal/session"
 )
@@ -42,9 +43,11 @@ func handleWeb(profile string, args []string) {
 		handleWebStop()
 	case "status":
 		handleWebStatus()
+	case "token":
+		handleWebToken(profile, rest)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown web subcommand: %s\n", sub)
-		fmt.Fprintln(os.Stderr, "Usage: hangar web [start|stop|status]")
+		fmt.Fprintln(os.Stderr, "Usage: hangar web [start|stop|status|token]")
 		os.Exit(1)
 	}
 }
@@ -152,7 +155,26 @@ func handleWebStart(profile string, noOpen bool, detach bool) {
 	// Create a PR manager for standalone mode. It self-initialises gh detection
 	// and background-fetches Mine/ReviewRequested lists via Start().
 	prManager := pr.New()
+	prStorage, err := session.NewStorageWithProfile(profile)
+	if err == nil {
+		defer prStorage.Close()
+		prManager.LoadSessionPRCache(prStorage)
+	}
 	prManager.Start()
+	// A running TUI moves linked todos and sends the session PR
+	// notifications; do it here only while none is alive.
+	noTUI := func() bool {
+		if prStorage == nil || prStorage.GetDB() == nil {
+			return true
+		}
+		alive, err := prStorage.GetDB().AliveInstanceCount()
+		return err != nil || alive == 0
+	}
+	pr.NewTodoReconciler(prManager, profile).Start(ctx, noTUI)
+	pr.NewFinishWatcher(prManager, profile).Start(ctx)
+	sinks := notify.FromConfig()
+	sinks.Enable()
+	sinks.WatchPRs(ctx, prManager, noTUI)
 
 	// Poll sessions from SQLite and call UpdateSessionPR for each worktree
 	// session so the PR dashboard is populated without the TUI running.
@@ -195,7 +217,9 @@ func handleWebStart(profile string, noOpen bool, detach bool) {
 		}
 	}
 
-	cfg := apiserver.APIConfig{Port: port, BindAddress: bindAddr}
+	session.EnableTodoChaining(profile)
+
+	cfg := webLoadAPIConfig()
 	srv := apiserver.New(cfg, watcher, getInstances, getPRInfo, nil, prManager, profile, Version)
 
 	uiURL := fmt.Sprintf("http://%s:%d/ui/", webDisplayAddr(bindAddr), port)
@@ -349,6 +373,19 @@ func webLoadConfig() (port int, bindAddr string) {
 	return
 }
 
+// webLoadAPIConfig resolves the full API server configuration, including
+// the [api] require_auth and allowed_origins settings.
+func webLoadAPIConfig() apiserver.APIConfig {
+	port, bindAddr := webLoadConfig()
+	cfg := apiserver.APIConfig{Port: port, BindAddress: bindAddr}
+	if userConfig, err := session.LoadUserConfig(); err == nil && userConfig != nil {
+		cfg.RequireAuth = userConfig.API.GetRequireAuth()
+		cfg.AllowedOrigins = userConfig.API.AllowedOrigins
+		cfg.GitHubWebhookSecret = userConfig.API.GitHubWebhookSecret
+	}
+	return cfg
+}
+
 // webDisplayAddr converts 0.0.0.0 to localhost for display in URLs.
 func webDisplayAddr(bind string) string {
 	if bind == "0.0.0.0" || bind == "" {
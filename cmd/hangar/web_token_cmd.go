@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// handleWebToken dispatches "hangar web token" subcommands.
+func handleWebToken(profile string, args []string) {
+	if len(args) == 0 {
+		printWebTokenHelp()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "create", "new":
+		handleWebTokenCreate(profile, args[1:])
+	case "list", "ls":
+		handleWebTokenList(profile, args[1:])
+	case "revoke", "rm", "delete":
+		handleWebTokenRevoke(profile, args[1:])
+	case "help", "--help", "-h":
+		printWebTokenHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown web token command: %s\n", args[0])
+		printWebTokenHelp()
+		os.Exit(1)
+	}
+}
+
+// printWebTokenHelp prints usage for web token commands.
+func printWebTokenHelp() {
+	fmt.Println("Usage: hangar web token <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage API keys for the embedded API server.")
+	fmt.Println("Keys are enforced when [api] require_auth = true in ~/.hangar/config.toml.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  create --scope <scope> [--name <name>]  Create a key (token is shown once)")
+	fmt.Println("  list                                    List keys")
+	fmt.Println("  revoke <id>                             Revoke a key")
+	fmt.Println()
+	fmt.Println("Scopes:")
+	fmt.Println("  read     View sessions, output, todos, projects and PRs")
+	fmt.Println("  control  read + send input, start/stop/restart sessions, edit todos and PRs")
+	fmt.Println("  admin    control + create/delete sessions, manage projects and schedules")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  hangar web token create --scope read --name dashboard")
+	fmt.Println("  hangar web token create --scope control --name phone")
+	fmt.Println("  hangar web token revoke key-1a2b3c4d")
+}
+
+// handleWebTokenCreate creates a new API key and prints its token once.
+func handleWebTokenCreate(profile string, args []string) {
+	fs := flag.NewFlagSet("web token create", flag.ExitOnError)
+	scopeFlag := fs.String("scope", "", "Key scope: read, control or admin (required)")
+	name := fs.String("name", "", "Human-readable label for the key")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(normalizeArgs(fs, args))
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	scope, err := apiserver.ParseScope(*scopeFlag)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	token, row, err := apiserver.NewAPIKey(strings.TrimSpace(*name), scope)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to open storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	if err := storage.GetDB().SaveAPIKey(row); err != nil {
+		out.Error(fmt.Sprintf("failed to save key: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s Created %s key %s", successSymbol, scope, row.ID))
+	if row.Name != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", row.Name))
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(fmt.Sprintf("  %s\n\n", token))
+	sb.WriteString("Store this token now; it cannot be shown again.\n")
+	sb.WriteString("Use it as: Authorization: Bearer <token>\n")
+	if cfg, err := session.LoadUserConfig(); err == nil && cfg != nil && !cfg.API.GetRequireAuth() {
+		sb.WriteString("\nNote: keys are not enforced until you set [api] require_auth = true in ~/.hangar/config.toml\n")
+	}
+	out.Print(sb.String(), map[string]interface{}{
+		"success": true,
+		"id":      row.ID,
+		"name":    row.Name,
+		"scope":   row.Scope,
+		"token":   token,
+	})
+}
+
+// handleWebTokenList prints all API keys (never the tokens themselves).
+func handleWebTokenList(profile string, args []string) {
+	fs := flag.NewFlagSet("web token list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(normalizeArgs(fs, args))
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to open storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	keys, err := storage.GetDB().LoadAPIKeys()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load keys: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	jsonKeys := make([]map[string]interface{}, 0, len(keys))
+	var sb strings.Builder
+	if len(keys) == 0 {
+		sb.WriteString("No API keys. Create one with: hangar web token create --scope read\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%-14s %-8s %-20s %-17s %s\n", "ID", "SCOPE", "NAME", "CREATED", "LAST USED"))
+		sb.WriteString(strings.Repeat("-", 76) + "\n")
+	}
+	for _, k := range keys {
+		lastUsed := "never"
+		if !k.LastUsedAt.IsZero() {
+			lastUsed = k.LastUsedAt.Format("2006-01-02 15:04")
+		}
+		sb.WriteString(fmt.Sprintf("%-14s %-8s %-20s %-17s %s\n",
+			k.ID, k.Scope, truncate(k.Name, 20), k.CreatedAt.Format("2006-01-02 15:04"), lastUsed))
+		entry := map[string]interface{}{
+			"id":         k.ID,
+			"name":       k.Name,
+			"scope":      k.Scope,
+			"created_at": k.CreatedAt,
+		}
+		if !k.LastUsedAt.IsZero() {
+			entry["last_used_at"] = k.LastUsedAt
+		}
+		jsonKeys = append(jsonKeys, entry)
+	}
+	out.Print(sb.String(), jsonKeys)
+}
+
+// handleWebTokenRevoke deletes an API key by ID.
+func handleWebTokenRevoke(profile string, args []string) {
+	fs := flag.NewFlagSet("web token revoke", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(normalizeArgs(fs, args))
+
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() < 1 {
+		out.Error("key id is required (see: hangar web token list)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	id := fs.Arg(0)
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to open storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	if err := storage.GetDB().DeleteAPIKey(id); err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	out.Success(fmt.Sprintf("Revoked key %s", id), map[string]interface{}{
+		"success": true,
+		"id":      id,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/sjoeboo/hangar/internal/git"
+	"github.com/sjoeboo/hangar/internal/pr"
 	"github.com/sjoeboo/hangar/internal/session"
 )
 
@@ -60,6 +61,7 @@ func printWorktreeUsage() {
 	fmt.Println("  hangar worktree finish \"My Session\"")
 	fmt.Println("  hangar worktree finish \"My Session\" --no-merge")
 	fmt.Println("  hangar worktree finish \"My Session\" --into develop")
+	fmt.Println("  hangar worktree finish \"My Session\" --pr --auto-merge")
 	fmt.Println("  hangar worktree cleanup")
 	fmt.Println("  hangar worktree cleanup --force")
 }
@@ -486,6 +488,9 @@ func handleWorktreeFinish(profile string, args []string) {
 	fs := flag.NewFlagSet("worktree finish", flag.ExitOnError)
 	into := fs.String("into", "", "Target branch to merge into (default: auto-detect)")
 	noMerge := fs.Bool("no-merge", false, "Skip merge (e.g. for PR workflows)")
+	viaPR := fs.Bool("pr", false, "Push the branch and open a PR instead of merging locally; clean up once it is merged")
+	autoMerge := fs.Bool("auto-merge", false, "With --pr, enable auto-merge on the PR")
+	mergeMethod := fs.String("merge-method", "squash", "With --auto-merge: merge, squash, or rebase")
 	keepBranch := fs.Bool("keep-branch", false, "Don't delete local branch after finish")
 	force := fs.Bool("force", false, "Skip safety checks and force branch deletion")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
@@ -494,6 +499,8 @@ func handleWorktreeFinish(profile string, args []string) {
 		fmt.Println("Usage: hangar worktree finish <session> [options]")
 		fmt.Println()
 		fmt.Println("Merge a worktree branch, remove the worktree, and delete the session.")
+		fmt.Println("With --pr, push the branch and open a pull request instead; the worktree")
+		fmt.Println("and session are removed once the PR is merged.")
 		fmt.Println()
 		fmt.Println("Arguments:")
 		fmt.Println("  session    Session title, ID prefix, or path")
@@ -506,6 +513,7 @@ func handleWorktreeFinish(profile string, args []string) {
 		fmt.Println("  hangar worktree finish \"My Feature\" --into develop")
 		fmt.Println("  hangar worktree finish \"My Feature\" --no-merge")
 		fmt.Println("  hangar worktree finish \"My Feature\" --no-merge --force")
+		fmt.Println("  hangar worktree finish \"My Feature\" --pr --auto-merge")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -566,6 +574,19 @@ func handleWorktreeFinish(profile string, args []string) {
 		}
 	}
 
+	if *autoMerge && !*viaPR {
+		out.Error("--auto-merge requires --pr", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *viaPR && *noMerge {
+		out.Error("--pr and --no-merge cannot be combined", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *viaPR {
+		finishWorktreeViaPR(out, storage, inst, *into, *autoMerge, *mergeMethod, *keepBranch, *force || *jsonOutput)
+		return
+	}
+
 	// Determine target branch
 	targetBranch := *into
 	if targetBranch == "" && !*noMerge {
@@ -614,20 +635,9 @@ func handleWorktreeFinish(profile string, args []string) {
 	if !*noMerge {
 		fmt.Printf("Merging %s into %s...\n", worktreeBranch, targetBranch)
 
-		// Checkout target branch in main repo
-		cmd := exec.Command("git", "-C", repoRoot, "checkout", targetBranch)
-		checkoutOutput, err := cmd.CombinedOutput()
-		if err != nil {
-			out.Error(fmt.Sprintf("failed to checkout %s: %s", targetBranch, strings.TrimSpace(string(checkoutOutput))), ErrCodeInvalidOperation)
-			os.Exit(1)
-		}
-
-		// Merge the worktree branch
-		if err := git.MergeBranch(repoRoot, worktreeBranch); err != nil {
-			// Abort the merge to leave things clean
-			abortCmd := exec.Command("git", "-C", repoRoot, "merge", "--abort")
-			_ = abortCmd.Run()
-			out.Error(fmt.Sprintf("merge failed (aborted): %v", err), ErrCodeInvalidOperation)
+		// Checkout the target branch in the main repo and merge the worktree branch
+		if err := git.MergeInto(repoRoot, targetBranch, worktreeBranch); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
 		fmt.Printf("  %s Merged successfully\n", successSymbol)
@@ -692,6 +702,85 @@ func handleWorktreeFinish(profile string, args []string) {
 	}
 }
 
+// finishWorktreeViaPR pushes the session's branch and opens a PR for it. The
+// worktree and session are torn down by a running hangar TUI or hangar web
+// once the PR is merged.
+func finishWorktreeViaPR(out *CLIOutput, storage *session.Storage, inst *session.Instance, into string, autoMerge bool, mergeMethod string, keepBranch, skipConfirm bool) {
+	switch mergeMethod {
+	case "merge", "squash", "rebase":
+	default:
+		out.Error(fmt.Sprintf("invalid --merge-method %q (want merge, squash, or rebase)", mergeMethod), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		out.Error("gh CLI not found; it is required for --pr", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	targetBranch := into
+	if targetBranch == "" {
+		targetBranch, err = git.GetDefaultBranch(inst.WorktreeRepoRoot)
+		if err != nil {
+			out.Error(fmt.Sprintf("could not determine target branch: %v\nUse --into <branch> to specify", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	if !skipConfirm {
+		fmt.Printf("Session:   %s\n", inst.Title)
+		fmt.Printf("Branch:    %s\n", inst.WorktreeBranch)
+		fmt.Printf("PR:        %s → %s", inst.WorktreeBranch, targetBranch)
+		if autoMerge {
+			fmt.Printf(" (auto-merge, %s)", mergeMethod)
+		}
+		fmt.Println()
+		fmt.Println("Cleanup:   worktree and session are removed once the PR is merged")
+		fmt.Println()
+		fmt.Print("Proceed? [y/N]: ")
+
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted.")
+			return
+		}
+		fmt.Println()
+	}
+
+	p, err := pr.FinishViaPR(ghPath, storage, inst, pr.FinishOptions{
+		Base:        targetBranch,
+		AutoMerge:   autoMerge,
+		MergeMethod: mergeMethod,
+		KeepBranch:  keepBranch,
+	})
+	if p == nil {
+		out.Error(fmt.Sprintf("failed to open PR: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if out.jsonMode {
+		out.Print("", map[string]interface{}{
+			"success":        true,
+			"session":        inst.Title,
+			"session_id":     inst.ID,
+			"branch":         inst.WorktreeBranch,
+			"pr_number":      p.Number,
+			"pr_url":         p.URL,
+			"base":           targetBranch,
+			"auto_merge":     autoMerge && err == nil,
+			"pending_finish": true,
+		})
+		return
+	}
+	fmt.Printf("%s Opened PR #%d: %s\n", successSymbol, p.Number, p.URL)
+	fmt.Println("The worktree and session will be removed once it is merged (while hangar or hangar web is running).")
+}
+
 // truncateString truncates a string to maxLen, adding "..." if truncated
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -0,0 +1,423 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// Scope is the permission level granted to an API key.
+// Scopes are ordered: admin implies control, control implies read.
+type Scope string
+
+const (
+	// ScopeRead allows listing sessions, todos, projects and PRs, reading
+	// output and subscribing to the event WebSocket.
+	ScopeRead Scope = "read"
+	// ScopeControl additionally allows typing into sessions (send, /stream),
+	// starting/stopping/restarting them and editing todos and PRs.
+	ScopeControl Scope = "control"
+	// ScopeAdmin additionally allows creating and deleting sessions (including
+	// skip_permissions) and managing projects and schedules.
+	ScopeAdmin Scope = "admin"
+)
+
+// tokenPrefix makes Hangar API keys easy to recognise in configs and secret scanners.
+const tokenPrefix = "hgr_"
+
+// keyCacheTTL bounds how long a revoked key can keep working after
+// "hangar web token revoke" (the server reloads keys at least this often).
+const keyCacheTTL = 10 * time.Second
+
+// keyTouchInterval rate-limits last_used_at writes per key.
+const keyTouchInterval = time.Minute
+
+// ParseScope validates a scope string.
+func ParseScope(s string) (Scope, error) {
+	switch Scope(strings.ToLower(strings.TrimSpace(s))) {
+	case ScopeRead:
+		return ScopeRead, nil
+	case ScopeControl:
+		return ScopeControl, nil
+	case ScopeAdmin:
+		return ScopeAdmin, nil
+	}
+	return "", fmt.Errorf("invalid scope %q (want read, control or admin)", s)
+}
+
+func (s Scope) rank() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeControl:
+		return 2
+	case ScopeAdmin:
+		return 3
+	}
+	return 0
+}
+
+// Allows reports whether a key with scope s may perform an action requiring required.
+func (s Scope) Allows(required Scope) bool {
+	return s.rank() > 0 && s.rank() >= required.rank()
+}
+
+// HashToken returns the hex-encoded SHA-256 hash under which a token is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken returns a new random bearer token.
+func generateToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// NewAPIKey creates a new API key with the given name and scope.
+// The plaintext token is returned once and never persisted; callers save the row.
+func NewAPIKey(name string, scope Scope) (string, *statedb.APIKeyRow, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("generate token: %w", err)
+	}
+	idBytes := make([]byte, 4)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", nil, fmt.Errorf("generate key id: %w", err)
+	}
+	row := &statedb.APIKeyRow{
+		ID:        "key-" + hex.EncodeToString(idBytes),
+		Name:      name,
+		Scope:     string(scope),
+		TokenHash: HashToken(token),
+		CreatedAt: time.Now(),
+	}
+	return token, row, nil
+}
+
+// LocalTokenPath returns the path of the per-server local token file.
+// The running server writes an admin token there (mode 0600) when auth is
+// required, so same-user processes (TUI, mcp-server) keep working without a
+// manually created key.
+func LocalTokenPath() string {
+	dir, err := session.GetHangarDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "hangar-api-token")
+	}
+	return filepath.Join(dir, "api-token")
+}
+
+// LoadLocalToken returns the local admin token written by a running server,
+// or "" if auth is disabled or no server is running.
+func LoadLocalToken() string {
+	data, err := os.ReadFile(LocalTokenPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+type scopeCtxKey struct{}
+
+// scopeFromContext returns the scope granted to the current request.
+func scopeFromContext(ctx context.Context) Scope {
+	if s, ok := ctx.Value(scopeCtxKey{}).(Scope); ok {
+		return s
+	}
+	return ""
+}
+
+// authenticator validates bearer tokens against the api_keys table.
+// Keys are cached in memory and reloaded at most every keyCacheTTL.
+type authenticator struct {
+	required       bool
+	allowedOrigins []string
+	loadKeys       func() ([]*statedb.APIKeyRow, error)
+	touchKey       func(id string)
+
+	mu         sync.Mutex
+	localToken string
+	keys       map[string]*statedb.APIKeyRow // keyed by token hash
+	loadedAt   time.Time
+	touchedAt  map[string]time.Time
+}
+
+func newAuthenticator(cfg APIConfig, profile string) *authenticator {
+	return &authenticator{
+		required:       cfg.RequireAuth,
+		allowedOrigins: cfg.AllowedOrigins,
+		touchedAt:      make(map[string]time.Time),
+		loadKeys: func() ([]*statedb.APIKeyRow, error) {
+			storage, err := session.NewStorageWithProfile(profile)
+			if err != nil {
+				return nil, err
+			}
+			defer storage.Close()
+			return storage.GetDB().LoadAPIKeys()
+		},
+		touchKey: func(id string) {
+			storage, err := session.NewStorageWithProfile(profile)
+			if err != nil {
+				return
+			}
+			defer storage.Close()
+			_ = storage.GetDB().TouchAPIKey(id)
+		},
+	}
+}
+
+// lookup returns the scope for token, or "" if the token is unknown.
+func (a *authenticator) lookup(token string) Scope {
+	if token == "" {
+		return ""
+	}
+	a.mu.Lock()
+	local := a.localToken
+	a.mu.Unlock()
+	if local != "" && subtle.ConstantTimeCompare([]byte(token), []byte(local)) == 1 {
+		return ScopeAdmin
+	}
+
+	hash := HashToken(token)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	row, ok := a.keys[hash]
+	// Reload on expiry, and on a miss at most once per second so a freshly
+	// created key works immediately without letting bad tokens hammer SQLite.
+	if time.Since(a.loadedAt) > keyCacheTTL || (!ok && time.Since(a.loadedAt) > time.Second) {
+		if rows, err := a.loadKeys(); err != nil {
+			slog.Warn("apiserver_load_keys_failed", slog.String("error", err.Error()))
+		} else {
+			a.keys = make(map[string]*statedb.APIKeyRow, len(rows))
+			for _, r := range rows {
+				a.keys[r.TokenHash] = r
+			}
+			a.loadedAt = time.Now()
+		}
+		row, ok = a.keys[hash]
+	}
+	if !ok {
+		return ""
+	}
+	if time.Since(a.touchedAt[row.ID]) > keyTouchInterval {
+		a.touchedAt[row.ID] = time.Now()
+		go a.touchKey(row.ID)
+	}
+	scope, err := ParseScope(row.Scope)
+	if err != nil {
+		return ""
+	}
+	return scope
+}
+
+// setLocalToken installs (or clears) the local admin token.
+func (a *authenticator) setLocalToken(token string) {
+	a.mu.Lock()
+	a.localToken = token
+	a.mu.Unlock()
+}
+
+// originAllowed reports whether a browser request may talk to the API.
+// Requests without an Origin header (curl, CLI, MCP) are always allowed, and
+// same-origin or allowed_origins pages may do anything their token allows.
+// Without auth, other pages may still read (the Tailscale trust model) but
+// not send input, change state or open WebSockets, so a malicious site
+// cannot drive sessions through the user's browser (CSRF).
+func (a *authenticator) originAllowed(r *http.Request) bool {
+	if r.Header.Get("Origin") == "" || a.originTrusted(r) {
+		return true
+	}
+	return !a.required && isReadMethod(r.Method) && !websocket.IsWebSocketUpgrade(r)
+}
+
+// isReadMethod reports whether method cannot change server state.
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// originTrusted reports whether the request's Origin is the API's own host
+// or listed in allowed_origins.
+func (a *authenticator) originTrusted(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	u, err := url.Parse(origin)
+	if err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range a.allowedOrigins {
+		if strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestToken extracts the bearer token from the Authorization header.
+// WebSocket upgrades may pass it as ?access_token= because browsers cannot
+// set headers on WebSocket connections.
+func requestToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	if websocket.IsWebSocketUpgrade(r) {
+		return r.URL.Query().Get("access_token")
+	}
+	return ""
+}
+
+// requiredScope returns the scope needed for a request, or "" for public endpoints.
+func requiredScope(r *http.Request) Scope {
+	p := r.URL.Path
+	switch {
+	case p == "/api/v1/status", p == "/api/v1/auth", p == "/hooks", p == "/ui", strings.HasPrefix(p, "/ui/"):
+		return ""
+	case p == "/api/v1/webhooks/github":
+		// GitHub cannot send a bearer token; the handler checks the
+		// delivery's HMAC signature instead.
+		return ""
+	case p == "/mcp":
+		// Any key may connect; each tool call is checked against the scope
+		// its REST endpoint requires.
+		return ScopeRead
+	case strings.HasPrefix(p, "/api/v1/sessions/") && strings.HasSuffix(p, "/stream"):
+		// The PTY stream accepts keyboard input, so reading it requires control.
+		return ScopeControl
+	case p == "/api/v1/sessions" && r.Method == http.MethodPost:
+		// Session creation can enable skip_permissions.
+		return ScopeAdmin
+	case strings.HasPrefix(p, "/api/v1/sessions/") && r.Method == http.MethodDelete:
+		return ScopeAdmin
+	case strings.HasPrefix(p, "/api/v1/sessions/") && (strings.HasSuffix(p, "/fork") || strings.HasSuffix(p, "/finish")):
+		// Forking creates a session; finishing deletes one along with its branch.
+		return ScopeAdmin
+	case p == "/api/v1/prs/merge":
+		// Merging can delete the head branch.
+		return ScopeAdmin
+	case p == "/api/v1/prs/feedback":
+		// Starts a worktree session when the PR has none.
+		return ScopeAdmin
+	case strings.HasPrefix(p, "/api/v1/projects") && r.Method != http.MethodGet:
+		return ScopeAdmin
+	case strings.HasPrefix(p, "/api/v1/schedules") && r.Method != http.MethodGet:
+		// Todo jobs create sessions, like POST /sessions. Todo writes that
+		// set up auto-start are checked by the todo handlers.
+		return ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return ScopeRead
+	}
+	return ScopeControl
+}
+
+// isLoopback reports whether the request originated from the local machine.
+func isLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// middleware enforces origin checks and API key scopes, then records the
+// granted scope on the request context for downstream handlers (WS commands).
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.originAllowed(r) {
+			writeError(w, http.StatusForbidden, "origin not allowed")
+			return
+		}
+		if !a.required {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopeCtxKey{}, ScopeAdmin)))
+			return
+		}
+		// Claude hook events are posted by local processes only.
+		if r.URL.Path == "/hooks" && !isLoopback(r) {
+			writeError(w, http.StatusForbidden, "hooks are accepted from localhost only")
+			return
+		}
+
+		scope := a.lookup(requestToken(r))
+		required := requiredScope(r)
+		if required != "" {
+			if scope == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="hangar"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+				return
+			}
+			if !scope.Allows(required) {
+				writeError(w, http.StatusForbidden, fmt.Sprintf("token scope %q cannot perform this action (requires %q)", scope, required))
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopeCtxKey{}, scope)))
+	})
+}
+
+// handleAuth serves GET /api/v1/auth. It is public so the web UI can decide
+// whether to show the login screen and validate a pasted token.
+func (s *APIServer) handleAuth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	resp := AuthResponse{AuthRequired: s.auth.required}
+	if !s.auth.required {
+		resp.Authenticated = true
+		resp.Scope = string(ScopeAdmin)
+	} else if scope := s.auth.lookup(requestToken(r)); scope != "" {
+		resp.Authenticated = true
+		resp.Scope = string(scope)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeLocalToken generates a local admin token and writes it to LocalTokenPath.
+// Returns a cleanup func that removes the file if it still holds our token.
+func (s *APIServer) writeLocalToken() func() {
+	token, err := generateToken()
+	if err != nil {
+		slog.Warn("apiserver_local_token_failed", slog.String("error", err.Error()))
+		return func() {}
+	}
+	path := LocalTokenPath()
+	if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+		slog.Warn("apiserver_local_token_failed", slog.String("error", err.Error()))
+		return func() {}
+	}
+	s.auth.setLocalToken(token)
+	return func() {
+		if LoadLocalToken() == token {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// newUpgrader returns a WebSocket upgrader that applies the same origin policy
+// as REST requests.
+func (a *authenticator) newUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin:     a.originAllowed,
+	}
+}
@@ -0,0 +1,262 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// newAuthTestServer returns a server with require_auth enabled and one key per
+// scope. touchKey is stubbed so no background DB writes outlive the test.
+func newAuthTestServer(t *testing.T) (*APIServer, map[Scope]string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(tmpDir, "hooks"), 0755)
+	t.Setenv("HOME", tmpDir)
+	watcher, err := session.NewStatusFileWatcher()
+	if err != nil {
+		t.Fatalf("NewStatusFileWatcher: %v", err)
+	}
+
+	storage, err := session.NewStorageWithProfile("")
+	if err != nil {
+		t.Fatalf("NewStorageWithProfile: %v", err)
+	}
+	tokens := make(map[Scope]string)
+	for _, scope := range []Scope{ScopeRead, ScopeControl, ScopeAdmin} {
+		token, row, err := NewAPIKey("test-"+string(scope), scope)
+		if err != nil {
+			t.Fatalf("NewAPIKey: %v", err)
+		}
+		if err := storage.GetDB().SaveAPIKey(row); err != nil {
+			t.Fatalf("SaveAPIKey: %v", err)
+		}
+		tokens[scope] = token
+	}
+	storage.Close()
+
+	srv := New(APIConfig{Port: 0, RequireAuth: true, AllowedOrigins: []string{"https://hangar.example.com"}},
+		watcher, nil, nil, nil, nil, "", "test")
+	srv.auth.touchKey = func(string) {}
+	return srv, tokens
+}
+
+func doAuthRequest(srv *APIServer, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+	req.RemoteAddr = "192.0.2.10:4321"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestParseScope(t *testing.T) {
+	for _, s := range []string{"read", "control", "admin", " Admin "} {
+		if _, err := ParseScope(s); err != nil {
+			t.Errorf("ParseScope(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseScope("root"); err == nil {
+		t.Error("ParseScope(root) should fail")
+	}
+	if !ScopeAdmin.Allows(ScopeControl) || !ScopeControl.Allows(ScopeRead) {
+		t.Error("higher scopes should allow lower ones")
+	}
+	if ScopeRead.Allows(ScopeControl) {
+		t.Error("read should not allow control")
+	}
+}
+
+func TestAuth_Scopes(t *testing.T) {
+	srv, tokens := newAuthTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"status is public", http.MethodGet, "/api/v1/status", "", http.StatusOK},
+		{"missing token", http.MethodGet, "/api/v1/sessions", "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "/api/v1/sessions", "hgr_nope", http.StatusUnauthorized},
+		{"read can list", http.MethodGet, "/api/v1/sessions", tokens[ScopeRead], http.StatusOK},
+		{"read cannot send", http.MethodPost, "/api/v1/sessions/abc/send", tokens[ScopeRead], http.StatusForbidden},
+		{"control can send", http.MethodPost, "/api/v1/sessions/abc/send", tokens[ScopeControl], http.StatusNotFound},
+		{"control cannot create", http.MethodPost, "/api/v1/sessions", tokens[ScopeControl], http.StatusForbidden},
+		{"control cannot delete", http.MethodDelete, "/api/v1/sessions/abc", tokens[ScopeControl], http.StatusForbidden},
+		{"control cannot finish", http.MethodPost, "/api/v1/sessions/abc/finish", tokens[ScopeControl], http.StatusForbidden},
+		{"control cannot merge PRs", http.MethodPost, "/api/v1/prs/merge?repo=o/r&number=1", tokens[ScopeControl], http.StatusForbidden},
+		{"control cannot address PR feedback", http.MethodPost, "/api/v1/prs/feedback?repo=o/r&number=1", tokens[ScopeControl], http.StatusForbidden},
+		{"admin can address PR feedback", http.MethodPost, "/api/v1/prs/feedback?repo=o/r&number=1", tokens[ScopeAdmin], http.StatusServiceUnavailable},
+		{"read can diff", http.MethodGet, "/api/v1/sessions/abc/diff", tokens[ScopeRead], http.StatusNotFound},
+		{"hooks are loopback only", http.MethodPost, "/hooks", "", http.StatusForbidden},
+		{"webhooks need no token", http.MethodPost, "/api/v1/webhooks/github", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doAuthRequest(srv, tt.method, tt.path, tt.token)
+			if rr.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d (%s)", tt.method, tt.path, rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuth_AutoStartTodos(t *testing.T) {
+	srv, tokens := newAuthTestServer(t)
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.RemoteAddr = "192.0.2.10:4321"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
+	}
+
+	create := `{"title":"next","project_path":"/src/api","prompt":"go","auto_start":true}`
+	if rr := do(http.MethodPost, "/api/v1/todos", tokens[ScopeControl], create); rr.Code != http.StatusForbidden {
+		t.Errorf("control creating an auto-start todo = %d, want 403 (%s)", rr.Code, rr.Body.String())
+	}
+	if rr := do(http.MethodPost, "/api/v1/todos", tokens[ScopeControl], `{"title":"plain","project_path":"/src/api"}`); rr.Code != http.StatusCreated {
+		t.Errorf("control creating a plain todo = %d, want 201 (%s)", rr.Code, rr.Body.String())
+	}
+	rr := do(http.MethodPost, "/api/v1/todos", tokens[ScopeAdmin], create)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("admin creating an auto-start todo = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var todo TodoResponse
+	if err := json.NewDecoder(rr.Body).Decode(&todo); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// In order: turning auto-start off last makes the todo plain again.
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"prompt":"rm -rf"}`, http.StatusForbidden},
+		{`{"blocked_by":[]}`, http.StatusForbidden},
+		{`{"status":"done"}`, http.StatusOK},
+		{`{"status":"todo"}`, http.StatusForbidden},
+		{`{"auto_start":false}`, http.StatusOK},
+		{`{"status":"todo"}`, http.StatusOK},
+	} {
+		if rr := do(http.MethodPatch, "/api/v1/todos/"+todo.ID, tokens[ScopeControl], tt.body); rr.Code != tt.want {
+			t.Errorf("control PATCH %s = %d, want %d (%s)", tt.body, rr.Code, tt.want, rr.Body.String())
+		}
+	}
+}
+
+func TestAuth_Endpoint(t *testing.T) {
+	srv, tokens := newAuthTestServer(t)
+
+	rr := doAuthRequest(srv, http.MethodGet, "/api/v1/auth", "")
+	var resp AuthResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.AuthRequired || resp.Authenticated {
+		t.Errorf("anonymous = %+v, want required and unauthenticated", resp)
+	}
+
+	rr = doAuthRequest(srv, http.MethodGet, "/api/v1/auth", tokens[ScopeControl])
+	resp = AuthResponse{}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Authenticated || resp.Scope != "control" {
+		t.Errorf("control token = %+v, want authenticated control", resp)
+	}
+}
+
+func TestAuth_Origin(t *testing.T) {
+	srv, tokens := newAuthTestServer(t)
+
+	for origin, want := range map[string]int{
+		"https://hangar.example.com": http.StatusOK,
+		"https://evil.example.com":   http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/sessions", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Authorization", "Bearer "+tokens[ScopeRead])
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		if rr.Code != want {
+			t.Errorf("origin %s = %d, want %d", origin, rr.Code, want)
+		}
+	}
+}
+
+func TestAuth_OriginWithoutAuth(t *testing.T) {
+	tmpDir := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(tmpDir, "hooks"), 0755)
+	t.Setenv("HOME", tmpDir)
+	watcher, err := session.NewStatusFileWatcher()
+	if err != nil {
+		t.Fatalf("NewStatusFileWatcher: %v", err)
+	}
+	srv := New(APIConfig{Port: 0, AllowedOrigins: []string{"https://hangar.example.com"}},
+		watcher, nil, nil, nil, nil, "", "test")
+
+	tests := []struct {
+		name, method, path, origin string
+		want                       int
+	}{
+		{"other origin can read", http.MethodGet, "/api/v1/status", "https://evil.example.com", http.StatusOK},
+		{"other origin cannot send", http.MethodPost, "/api/v1/sessions/abc/send", "https://evil.example.com", http.StatusForbidden},
+		{"other origin cannot create", http.MethodPost, "/api/v1/sessions", "https://evil.example.com", http.StatusForbidden},
+		{"same origin can send", http.MethodPost, "/api/v1/sessions/abc/send", "http://example.com", http.StatusNotFound},
+		{"allowed origin can send", http.MethodPost, "/api/v1/sessions/abc/send", "https://hangar.example.com", http.StatusNotFound},
+		{"no origin can send", http.MethodPost, "/api/v1/sessions/abc/send", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Errorf("%s %s from %q = %d, want %d", tt.method, tt.path, tt.origin, rr.Code, tt.want)
+			}
+		})
+	}
+
+	// WebSocket upgrades from other origins are refused too.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	if srv.auth.originAllowed(req) {
+		t.Error("cross-origin WebSocket upgrade allowed")
+	}
+}
+
+func TestAuth_LocalToken(t *testing.T) {
+	srv, _ := newAuthTestServer(t)
+	cleanup := srv.writeLocalToken()
+
+	token := LoadLocalToken()
+	if token == "" {
+		t.Fatal("local token not written")
+	}
+	rr := doAuthRequest(srv, http.MethodGet, "/api/v1/sessions", token)
+	if rr.Code != http.StatusOK {
+		t.Errorf("local token = %d, want 200", rr.Code)
+	}
+
+	cleanup()
+	if LoadLocalToken() != "" {
+		t.Error("local token file should be removed on cleanup")
+	}
+}
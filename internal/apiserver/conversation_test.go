@@ -0,0 +1,66 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_SessionConversation(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	projectPath := t.TempDir()
+	inst := session.NewInstanceWithTool("agent", projectPath, "claude")
+	inst.ClaudeSessionID = "conv-1"
+	shell := session.NewInstance("shell", t.TempDir())
+
+	resolved, _ := filepath.EvalSymlinks(projectPath)
+	dir := filepath.Join(configDir, "projects", session.ConvertToClaudeDirName(resolved))
+	_ = os.MkdirAll(dir, 0755)
+	transcript := `{"type":"user","sessionId":"conv-1","timestamp":"2026-03-01T09:00:00Z","message":{"role":"user","content":"hi"}}
+{"type":"assistant","sessionId":"conv-1","timestamp":"2026-03-01T09:00:01Z","message":{"id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"ls"}}],"usage":{"input_tokens":3,"output_tokens":2}}}
+{"type":"user","sessionId":"conv-1","timestamp":"2026-03-01T09:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"main.go go.mod"}]}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "conv-1.jsonl"), []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	getInstances := func() []*session.Instance { return []*session.Instance{inst, shell} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := get("/api/v1/sessions/" + inst.ID + "/conversation?max_result_bytes=4")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("conversation = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.ConversationResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.SessionID != inst.ID || resp.ConversationID != "conv-1" || len(resp.Turns) != 2 || resp.Usage.OutputTokens != 2 {
+		t.Fatalf("conversation = %+v", resp.Conversation)
+	}
+	if got := resp.Turns[1].ToolCalls[0].Result; got != "main\n[truncated: 4 of 14 bytes shown]" {
+		t.Errorf("truncated result = %q", got)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/sessions/" + inst.ID + "/conversation?since=yesterday-ish": http.StatusBadRequest,
+		"/api/v1/sessions/" + shell.ID + "/conversation":                    http.StatusNotFound,
+		"/api/v1/sessions/missing/conversation":                             http.StatusNotFound,
+	} {
+		if rr := get(path); rr.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rr.Code, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,18 +16,82 @@ const (
 	wsMaxMsgSize = 65536
 )
 
-var wsUpgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 4096,
-	// Permissive origin check — Tailscale trust model.
-	CheckOrigin: func(r *http.Request) bool { return true },
-}
-
 // Client represents a single WebSocket connection.
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan WsMessage
+	hub   *Hub
+	conn  *websocket.Conn
+	send  chan WsMessage
+	scope Scope // scope granted at upgrade; gates send_message/stop_session
+
+	subMu      sync.Mutex
+	outputSubs map[string]bool // session IDs receiving session_output
+	statusSubs map[string]bool // session IDs receiving session_status; nil = all
+}
+
+// wants reports whether msg should be delivered to this client. Per-session
+// events are filtered by the client's subscriptions; everything else is sent.
+func (c *Client) wants(msg WsMessage) bool {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	switch d := msg.Data.(type) {
+	case SessionOutputData:
+		return c.outputSubs[d.SessionID]
+	case WsSessionStatusData:
+		return c.statusSubs == nil || c.statusSubs[d.SessionID]
+	}
+	return true
+}
+
+// subscribe applies a subscribe/unsubscribe command and returns the session
+// IDs whose output subscription was added or removed.
+func (c *Client) subscribe(data WsSubscribeData, add bool) (changed []string) {
+	output, status := len(data.Events) == 0, len(data.Events) == 0
+	for _, e := range data.Events {
+		switch e {
+		case "session_output":
+			output = true
+		case "session_status":
+			status = true
+		}
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if status && c.statusSubs == nil {
+		c.statusSubs = make(map[string]bool)
+	}
+	for _, id := range data.SessionIDs {
+		if id == "" {
+			continue
+		}
+		if status {
+			if add {
+				c.statusSubs[id] = true
+			} else {
+				delete(c.statusSubs, id)
+			}
+		}
+		if output && c.outputSubs[id] != add {
+			if add {
+				c.outputSubs[id] = true
+			} else {
+				delete(c.outputSubs, id)
+			}
+			changed = append(changed, id)
+		}
+	}
+	return changed
+}
+
+// outputSessions returns the session IDs this client receives output for.
+func (c *Client) outputSessions() []string {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	ids := make([]string, 0, len(c.outputSubs))
+	for id := range c.outputSubs {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 // Hub manages all connected WebSocket clients.
@@ -60,6 +125,9 @@ func (h *Hub) run() {
 			}
 		case msg := <-h.broadcast:
 			for c := range h.clients {
+				if !c.wants(msg) {
+					continue
+				}
 				select {
 				case c.send <- msg:
 				default:
@@ -72,7 +140,7 @@ func (h *Hub) run() {
 	}
 }
 
-// BroadcastOutput sends a session_output event to all connected WS clients.
+// BroadcastOutput sends a session_output event to clients subscribed to sessionID.
 func (h *Hub) BroadcastOutput(sessionID, content string) {
 	h.broadcast <- WsMessage{
 		Type: "session_output",
@@ -82,16 +150,18 @@ func (h *Hub) BroadcastOutput(sessionID, content string) {
 
 // handleWS upgrades an HTTP connection to WebSocket and registers a new client.
 func (s *APIServer) handleWS(w http.ResponseWriter, r *http.Request) {
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Debug("ws_upgrade_failed", slog.String("error", err.Error()))
 		return
 	}
 
 	c := &Client{
-		hub:  s.hub,
-		conn: conn,
-		send: make(chan WsMessage, 32),
+		hub:        s.hub,
+		conn:       conn,
+		send:       make(chan WsMessage, 32),
+		scope:      scopeFromContext(r.Context()),
+		outputSubs: make(map[string]bool),
 	}
 	s.hub.register <- c
 
@@ -146,6 +216,9 @@ func (c *Client) writePump() {
 // readPump reads commands from the client and dispatches them.
 func (c *Client) readPump(s *APIServer) {
 	defer func() {
+		for _, id := range c.outputSessions() {
+			s.streamer.unwatch(id)
+		}
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
@@ -174,10 +247,43 @@ func (c *Client) readPump(s *APIServer) {
 		switch msg.Type {
 		case "ping":
 			c.send <- WsMessage{Type: "pong"}
-		case "send_message":
-			s.wsHandleSendMessage(c, msg)
-		case "stop_session":
-			s.wsHandleStopSession(c, msg)
+		case "subscribe", "unsubscribe":
+			s.wsHandleSubscribe(c, msg)
+		case "send_message", "stop_session":
+			if !c.scope.Allows(ScopeControl) {
+				c.send <- WsMessage{Type: "error", Data: map[string]string{"error": "token scope does not allow " + msg.Type}}
+				continue
+			}
+			if msg.Type == "send_message" {
+				s.wsHandleSendMessage(c, msg)
+			} else {
+				s.wsHandleStopSession(c, msg)
+			}
+		}
+	}
+}
+
+// wsHandleSubscribe handles subscribe/unsubscribe commands. New output
+// subscribers get the current pane immediately so they need not poll
+// GET /sessions/{id}/output for the initial snapshot.
+func (s *APIServer) wsHandleSubscribe(c *Client, msg WsMessage) {
+	raw, err := json.Marshal(msg.Data)
+	if err != nil {
+		return
+	}
+	var data WsSubscribeData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return
+	}
+	add := msg.Type == "subscribe"
+	for _, id := range c.subscribe(data, add) {
+		if !add {
+			s.streamer.unwatch(id)
+			continue
+		}
+		s.streamer.watch(id)
+		if content := s.streamer.capture(id); content != "" {
+			c.send <- WsMessage{Type: "session_output", Data: SessionOutputData{SessionID: id, Output: content}}
 		}
 	}
 }
@@ -206,16 +206,24 @@ func TestAPIServer_UIServesAssets(t *testing.T) {
 func TestAPIServer_CORS(t *testing.T) {
 	srv := newTestServer(t)
 
-	req := httptest.NewRequest(http.MethodOptions, "/api/v1/sessions", nil)
-	req.Header.Set("Origin", "http://example.com")
-	rr := httptest.NewRecorder()
-
-	srv.ServeHTTP(rr, req)
+	preflight := func(method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/sessions", nil)
+		req.Header.Set("Origin", "http://other.example.com")
+		req.Header.Set("Access-Control-Request-Method", method)
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
+	}
 
+	rr := preflight(http.MethodGet)
 	if rr.Code != http.StatusNoContent {
 		t.Errorf("OPTIONS status = %d, want 204", rr.Code)
 	}
 	if rr.Header().Get("Access-Control-Allow-Origin") != "*" {
 		t.Error("CORS header missing")
 	}
+	// Other sites may read without auth, but not write.
+	if got := preflight(http.MethodPost).Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("POST preflight from another origin allowed %q", got)
+	}
 }
@@ -0,0 +1,87 @@
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// mcpCall posts one JSON-RPC message to /mcp and returns the response.
+// sessionID is the Mcp-Session-Id returned by initialize, if any.
+func mcpCall(srv *APIServer, token, sessionID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.RemoteAddr = "192.0.2.10:4321"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	return rr
+}
+
+const (
+	mcpInitialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	mcpListTools  = `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+)
+
+func mcpToolCall(name, args string) string {
+	return `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"` + name + `","arguments":` + args + `}}`
+}
+
+func TestMCP_HTTP(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	watcher, err := session.NewStatusFileWatcher()
+	if err != nil {
+		t.Fatalf("NewStatusFileWatcher: %v", err)
+	}
+	inst := session.NewInstance("mcp-http-session", t.TempDir())
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := New(APIConfig{Port: 0}, watcher, getInstances, nil, nil, nil, "", "test")
+
+	rr := mcpCall(srv, "", "", mcpInitialize)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"hangar"`) {
+		t.Fatalf("initialize = %d (%s)", rr.Code, rr.Body.String())
+	}
+	sid := rr.Header().Get("Mcp-Session-Id")
+	rr = mcpCall(srv, "", sid, mcpListTools)
+	if !strings.Contains(rr.Body.String(), "hangar_list_sessions") || !strings.Contains(rr.Body.String(), "hangar_create_todo") ||
+		!strings.Contains(rr.Body.String(), "hangar_list_prs") || !strings.Contains(rr.Body.String(), "hangar_finish_worktree") {
+		t.Errorf("tools/list missing tools: %s", rr.Body.String())
+	}
+	rr = mcpCall(srv, "", sid, mcpToolCall("hangar_list_sessions", `{}`))
+	if !strings.Contains(rr.Body.String(), "mcp-http-session") {
+		t.Errorf("hangar_list_sessions = %s", rr.Body.String())
+	}
+}
+
+func TestMCP_HTTPScopes(t *testing.T) {
+	srv, tokens := newAuthTestServer(t)
+
+	if rr := mcpCall(srv, "", "", mcpInitialize); rr.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous initialize = %d, want 401", rr.Code)
+	}
+	rr := mcpCall(srv, tokens[ScopeRead], "", mcpInitialize)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("read initialize = %d (%s)", rr.Code, rr.Body.String())
+	}
+	sid := rr.Header().Get("Mcp-Session-Id")
+
+	// A read key may connect but not use tools whose endpoint needs control.
+	stop := mcpToolCall("hangar_stop_session", `{"id":"abc"}`)
+	rr = mcpCall(srv, tokens[ScopeRead], sid, stop)
+	if !strings.Contains(rr.Body.String(), "403") {
+		t.Errorf("read stop = %s, want a 403 tool error", rr.Body.String())
+	}
+	rr = mcpCall(srv, tokens[ScopeControl], sid, stop)
+	if !strings.Contains(rr.Body.String(), "404") {
+		t.Errorf("control stop = %s, want a 404 tool error", rr.Body.String())
+	}
+}
@@ -0,0 +1,252 @@
+package apiserver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/tmux"
+)
+
+const (
+	// outputDebounce coalesces bursts of %output events into one capture per session.
+	outputDebounce = 150 * time.Millisecond
+	// statusPollInterval is how often session statuses are diffed for
+	// session_status events. Hook notifications trigger an immediate diff.
+	statusPollInterval = 2 * time.Second
+)
+
+// outputStreamer turns tmux %output events into per-session session_output
+// WS messages for subscribed clients, and diffs session statuses into
+// session_status messages.
+//
+// It reuses the TUI's PipeManager when running inside the TUI process, and
+// otherwise (standalone "hangar web start") owns a private one that only
+// connects pipes for sessions that have at least one output subscriber.
+type outputStreamer struct {
+	s      *APIServer
+	pm     *tmux.PipeManager
+	ownsPM bool
+
+	mu       sync.Mutex
+	watched  map[string]int    // session ID -> output subscriber count
+	names    map[string]string // tmux session name -> session ID (watched only)
+	pending  map[string]bool   // tmux session names with a flush scheduled
+	lastOut  map[string]string // session ID -> last broadcast output
+	statuses map[string]string // session ID -> last known status
+	seeded   bool
+}
+
+func newOutputStreamer(s *APIServer) *outputStreamer {
+	return &outputStreamer{
+		s:        s,
+		watched:  make(map[string]int),
+		names:    make(map[string]string),
+		pending:  make(map[string]bool),
+		lastOut:  make(map[string]string),
+		statuses: make(map[string]string),
+	}
+}
+
+// run attaches to a PipeManager and polls statuses until ctx is cancelled.
+func (st *outputStreamer) run(ctx context.Context) {
+	pm := tmux.GetPipeManager()
+	owns := pm == nil
+	if owns {
+		pm = tmux.NewPipeManager(ctx, nil)
+	}
+	st.mu.Lock()
+	st.pm = pm
+	st.ownsPM = owns
+	names := make([]string, 0, len(st.names))
+	for name := range st.names {
+		names = append(names, name)
+	}
+	st.mu.Unlock()
+
+	remove := pm.AddOutputListener(st.onOutput)
+	defer func() {
+		remove()
+		if owns {
+			pm.Close()
+		}
+	}()
+
+	// Connect pipes for subscriptions made before the streamer started.
+	for _, name := range names {
+		go func() { _ = pm.Connect(name) }()
+	}
+
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+	st.checkStatuses()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.checkStatuses()
+		}
+	}
+}
+
+// watch adds an output subscriber for a session and connects its pipe.
+func (st *outputStreamer) watch(id string) {
+	inst := st.s.findInstance(id)
+	if inst == nil || inst.GetTmuxSession() == nil {
+		return
+	}
+	name := inst.GetTmuxSession().Name
+
+	st.mu.Lock()
+	st.watched[id]++
+	first := st.watched[id] == 1
+	if first {
+		st.names[name] = id
+	}
+	pm := st.pm
+	st.mu.Unlock()
+
+	if first && pm != nil {
+		go func() { _ = pm.Connect(name) }()
+	}
+}
+
+// unwatch drops an output subscriber. When the last one leaves, the session's
+// pipe is closed if the streamer owns the PipeManager.
+func (st *outputStreamer) unwatch(id string) {
+	st.mu.Lock()
+	if st.watched[id] == 0 {
+		st.mu.Unlock()
+		return
+	}
+	st.watched[id]--
+	if st.watched[id] > 0 {
+		st.mu.Unlock()
+		return
+	}
+	delete(st.watched, id)
+	delete(st.lastOut, id)
+	var name string
+	for n, sid := range st.names {
+		if sid == id {
+			name = n
+			delete(st.names, n)
+			break
+		}
+	}
+	pm, owns := st.pm, st.ownsPM
+	st.mu.Unlock()
+
+	if owns && pm != nil && name != "" {
+		pm.Disconnect(name)
+	}
+}
+
+// onOutput is the PipeManager listener. It schedules one flush per debounce
+// window for watched sessions and ignores everything else.
+func (st *outputStreamer) onOutput(name string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	id, ok := st.names[name]
+	if !ok || st.pending[name] {
+		return
+	}
+	st.pending[name] = true
+	time.AfterFunc(outputDebounce, func() { st.flush(name, id) })
+}
+
+// flush captures the pane and broadcasts it if it changed since the last send.
+func (st *outputStreamer) flush(name, id string) {
+	st.mu.Lock()
+	delete(st.pending, name)
+	st.mu.Unlock()
+
+	content := st.capture(id)
+	if content == "" {
+		return
+	}
+
+	st.mu.Lock()
+	if _, watched := st.watched[id]; !watched || st.lastOut[id] == content {
+		st.mu.Unlock()
+		return
+	}
+	st.lastOut[id] = content
+	st.mu.Unlock()
+
+	st.s.hub.BroadcastOutput(id, content)
+}
+
+// capture returns the normalized pane content for a session, preferring the
+// control pipe and falling back to the session's own capture path.
+func (st *outputStreamer) capture(id string) string {
+	inst := st.s.findInstance(id)
+	if inst == nil {
+		return ""
+	}
+	ts := inst.GetTmuxSession()
+	if ts == nil {
+		return ""
+	}
+	st.mu.Lock()
+	pm := st.pm
+	st.mu.Unlock()
+
+	var content string
+	var err error
+	if pm != nil {
+		content, err = pm.CapturePane(ts.Name)
+	}
+	if pm == nil || err != nil {
+		if content, err = ts.CapturePane(); err != nil {
+			return ""
+		}
+	}
+	return normalizePaneOutput(content)
+}
+
+// checkStatuses diffs current session statuses against the last snapshot and
+// broadcasts a session_status message for each change. The first call only
+// seeds the snapshot.
+func (st *outputStreamer) checkStatuses() {
+	instances := st.s.instances()
+
+	var changes []WsSessionStatusData
+	st.mu.Lock()
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		status := string(inst.Status)
+		seen[inst.ID] = true
+		prev, ok := st.statuses[inst.ID]
+		st.statuses[inst.ID] = status
+		if st.seeded && (!ok || prev != status) {
+			changes = append(changes, WsSessionStatusData{SessionID: inst.ID, Status: status, PreviousStatus: prev})
+		}
+	}
+	for id := range st.statuses {
+		if !seen[id] {
+			delete(st.statuses, id)
+		}
+	}
+	st.seeded = true
+	st.mu.Unlock()
+
+	if len(changes) > 0 {
+		st.s.signalStatusChange()
+	}
+	for _, c := range changes {
+		st.s.hub.broadcast <- WsMessage{Type: "session_status", Data: c}
+	}
+}
+
+// normalizePaneOutput trims tmux's pane-width padding from each line and joins
+// with \r\n so xterm.js (convertEol:false) renders lines correctly.
+func normalizePaneOutput(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\r\n")
+}
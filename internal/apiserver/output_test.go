@@ -0,0 +1,96 @@
+package apiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestClient_Subscriptions(t *testing.T) {
+	c := &Client{outputSubs: make(map[string]bool)}
+	out := func(id string) WsMessage {
+		return WsMessage{Type: "session_output", Data: SessionOutputData{SessionID: id}}
+	}
+	status := func(id string) WsMessage {
+		return WsMessage{Type: "session_status", Data: WsSessionStatusData{SessionID: id}}
+	}
+
+	// Fresh clients get every status and general event, but no output.
+	if c.wants(out("a")) {
+		t.Error("unsubscribed client should not receive output")
+	}
+	if !c.wants(status("a")) || !c.wants(WsMessage{Type: "sessions_changed"}) {
+		t.Error("fresh client should receive statuses and general events")
+	}
+
+	// Output-only subscription leaves status delivery unfiltered.
+	changed := c.subscribe(WsSubscribeData{SessionIDs: []string{"a"}, Events: []string{"session_output"}}, true)
+	if len(changed) != 1 || changed[0] != "a" {
+		t.Errorf("changed = %v, want [a]", changed)
+	}
+	if !c.wants(out("a")) || c.wants(out("b")) {
+		t.Error("output should be delivered for a only")
+	}
+	if !c.wants(status("b")) {
+		t.Error("status filter should not apply after output-only subscribe")
+	}
+
+	// Subscribing to statuses narrows them to the listed sessions.
+	if changed := c.subscribe(WsSubscribeData{SessionIDs: []string{"a"}}, true); len(changed) != 0 {
+		t.Errorf("re-subscribing output should report no change, got %v", changed)
+	}
+	if !c.wants(status("a")) || c.wants(status("b")) {
+		t.Error("status should be delivered for a only")
+	}
+
+	changed = c.subscribe(WsSubscribeData{SessionIDs: []string{"a"}}, false)
+	if len(changed) != 1 || c.wants(out("a")) || c.wants(status("a")) {
+		t.Errorf("unsubscribe should remove a (changed=%v)", changed)
+	}
+}
+
+func TestOutputStreamer_CheckStatuses(t *testing.T) {
+	srv := newTestServerInternal(t)
+	inst := session.NewInstance("status-test", t.TempDir())
+	inst.Status = session.StatusIdle
+	srv.getInstances = func() []*session.Instance { return []*session.Instance{inst} }
+
+	go srv.hub.run()
+	c := &Client{hub: srv.hub, send: make(chan WsMessage, 4), outputSubs: make(map[string]bool)}
+	srv.hub.register <- c
+	time.Sleep(10 * time.Millisecond)
+
+	// First pass only seeds the snapshot.
+	srv.streamer.checkStatuses()
+	inst.Status = session.StatusRunning
+	srv.streamer.checkStatuses()
+
+	select {
+	case msg := <-c.send:
+		data, ok := msg.Data.(WsSessionStatusData)
+		if msg.Type != "session_status" || !ok {
+			t.Fatalf("got %q %T, want session_status", msg.Type, msg.Data)
+		}
+		if data.SessionID != inst.ID || data.Status != "running" || data.PreviousStatus != "idle" {
+			t.Errorf("unexpected status event: %+v", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for session_status")
+	}
+
+	// No change, no event.
+	srv.streamer.checkStatuses()
+	select {
+	case msg := <-c.send:
+		t.Errorf("unexpected message %q", msg.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNormalizePaneOutput(t *testing.T) {
+	got := normalizePaneOutput("hello   \nworld \n")
+	if want := "hello\r\nworld\r\n"; got != want {
+		t.Errorf("normalizePaneOutput = %q, want %q", got, want)
+	}
+}
@@ -2,10 +2,14 @@ package apiserver
 
 import (
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/sjoeboo/hangar/internal/git"
 	internalprs "github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
 )
 
 // prFullInfoFromPR converts a *pr.PR to a *PRFullInfo for the dashboard response.
@@ -162,20 +166,19 @@ func (s *APIServer) handlePRReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ghPath := s.prManager.GHPath()
-	if ghPath == "" {
-		writeError(w, http.StatusServiceUnavailable, "gh CLI not available")
+	provider, ok := s.prProvider(w, repo)
+	if !ok {
 		return
 	}
 
 	var err error
 	switch req.Action {
 	case "approve":
-		err = internalprs.Approve(ghPath, repo, number, req.Body)
+		err = provider.Approve(repo, number, req.Body)
 	case "request_changes":
-		err = internalprs.RequestChanges(ghPath, repo, number, req.Body)
+		err = provider.RequestChanges(repo, number, req.Body)
 	case "comment":
-		err = internalprs.AddComment(ghPath, repo, number, req.Body, "", 0)
+		err = provider.Comment(repo, number, req.Body, "", 0)
 	default:
 		writeError(w, http.StatusBadRequest, "invalid action: must be approve, request_changes, or comment")
 		return
@@ -220,13 +223,12 @@ func (s *APIServer) handlePRComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ghPath := s.prManager.GHPath()
-	if ghPath == "" {
-		writeError(w, http.StatusServiceUnavailable, "gh CLI not available")
+	provider, ok := s.prProvider(w, repo)
+	if !ok {
 		return
 	}
 
-	if err := internalprs.AddComment(ghPath, repo, number, req.Body, req.Path, req.Line); err != nil {
+	if err := provider.Comment(repo, number, req.Body, req.Path, req.Line); err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -261,22 +263,21 @@ func (s *APIServer) handlePRState(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ghPath := s.prManager.GHPath()
-	if ghPath == "" {
-		writeError(w, http.StatusServiceUnavailable, "gh CLI not available")
+	provider, ok := s.prProvider(w, repo)
+	if !ok {
 		return
 	}
 
 	var err error
 	switch req.Action {
 	case "close":
-		err = internalprs.Close(ghPath, repo, number)
+		err = provider.Close(repo, number)
 	case "reopen":
-		err = internalprs.Reopen(ghPath, repo, number)
+		err = provider.Reopen(repo, number)
 	case "draft":
-		err = internalprs.ConvertToDraft(ghPath, repo, number)
+		err = provider.Draft(repo, number)
 	case "ready":
-		err = internalprs.ConvertToReady(ghPath, repo, number)
+		err = provider.Ready(repo, number)
 	default:
 		writeError(w, http.StatusBadRequest, "invalid action: must be close, reopen, draft, or ready")
 		return
@@ -292,3 +293,229 @@ func (s *APIServer) handlePRState(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
+
+// handlePRMerge serves POST /api/v1/prs/merge?repo=owner%2Frepo&number=123.
+// Body: {"action": "merge"|"auto"|"disable_auto", "method": "squash"|"merge"|"rebase",
+// "delete_branch": bool, "force": bool}.
+// A PR that is not open, is a draft or has conflicts is refused with 409, as
+// are failing checks (and pending ones for an immediate merge) unless force
+// is set. Returns 503 if prManager is not configured.
+func (s *APIServer) handlePRMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.prManager == nil {
+		writeError(w, http.StatusServiceUnavailable, "PR manager not available")
+		return
+	}
+
+	repo, number, ok := parsePRQueryParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Action == "" {
+		req.Action = "merge"
+	}
+	if req.Method == "" {
+		req.Method = "squash"
+	}
+	if req.Action != "merge" && req.Action != "auto" && req.Action != "disable_auto" {
+		writeError(w, http.StatusBadRequest, "invalid action: must be merge, auto, or disable_auto")
+		return
+	}
+	if !internalprs.ValidMergeMethod(req.Method) {
+		writeError(w, http.StatusBadRequest, "invalid method: must be squash, merge, or rebase")
+		return
+	}
+
+	provider, ok := s.prProvider(w, repo)
+	if !ok {
+		return
+	}
+
+	var err error
+	if req.Action == "disable_auto" {
+		err = provider.DisableAutoMerge(repo, number)
+	} else {
+		// Judge mergeability and checks on fresh data, not the cached detail.
+		s.prManager.InvalidateDetail(repo, number)
+		detail, ferr := s.prManager.FetchDetail(repo, number)
+		if ferr != nil {
+			writeError(w, http.StatusInternalServerError, ferr.Error())
+			return
+		}
+		if detail == nil {
+			writeError(w, http.StatusNotFound, "PR not found")
+			return
+		}
+		auto := req.Action == "auto"
+		blocker, warning := internalprs.MergeCheck(detail, auto)
+		if blocker != "" {
+			writeError(w, http.StatusConflict, blocker)
+			return
+		}
+		if warning != "" && !req.Force {
+			writeError(w, http.StatusConflict, warning+" (set force to merge anyway)")
+			return
+		}
+		if auto {
+			err = provider.EnableAutoMerge(repo, number, req.Method, req.DeleteBranch)
+		} else {
+			err = provider.Merge(repo, number, req.Method, req.DeleteBranch)
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Invalidate cached detail so the next fetch is fresh, and refresh the
+	// dashboard lists so a merged PR moves out of the open ones.
+	s.prManager.InvalidateDetail(repo, number)
+	s.prManager.TriggerRefresh()
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handlePRFeedback serves POST /api/v1/prs/feedback?repo=owner%2Frepo&number=123.
+// It collects the PR's unresolved review comments and failed check logs into
+// a prompt and sends it to the session linked to the PR, restarting that
+// session if it has stopped. When no session is linked, it starts a worktree
+// session on the PR's branch in the project whose repo matches.
+func (s *APIServer) handlePRFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.prManager == nil {
+		writeError(w, http.StatusServiceUnavailable, "PR manager not available")
+		return
+	}
+
+	repo, number, ok := parsePRQueryParams(w, r)
+	if !ok {
+		return
+	}
+
+	if s.prManager.ProviderNameFor(repo) != internalprs.ProviderGitHub {
+		writeError(w, http.StatusConflict, "addressing feedback is only supported for GitHub PRs")
+		return
+	}
+	ghPath := s.prManager.GHPath()
+	if ghPath == "" {
+		writeError(w, http.StatusServiceUnavailable, "gh CLI not available")
+		return
+	}
+
+	detail, err := s.prManager.FetchDetail(repo, number)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if detail == nil {
+		writeError(w, http.StatusNotFound, "PR not found")
+		return
+	}
+	fb, err := internalprs.FetchFeedback(ghPath, repo, number)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if fb.Empty() {
+		writeError(w, http.StatusConflict, "PR has no unresolved review comments or failed checks")
+		return
+	}
+	prompt := internalprs.FeedbackPrompt(&detail.PR, fb)
+
+	if inst := s.findInstance(s.prManager.SessionForPR(repo, number)); inst != nil {
+		if !inst.Exists() {
+			if err := inst.Restart(); err != nil {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("restart failed: %v", err))
+				return
+			}
+			// The restarted agent takes a while to load; deliver the prompt
+			// once it is ready rather than typing it into its startup screen.
+			go sendWhenReady(inst, prompt)
+		} else if err := inst.SendText(prompt); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("send failed: %v", err))
+			return
+		}
+		s.hub.broadcast <- WsMessage{Type: "session_updated", Data: sessionToResponse(inst, s.getPRInfoFor)}
+		writeJSON(w, http.StatusOK, PRFeedbackResponse{SessionID: inst.ID, SessionTitle: inst.Title, Prompt: prompt})
+		return
+	}
+
+	proj := projectForRepo(repo)
+	if proj == nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf("no session is linked to this PR and no project matches %s", repo))
+		return
+	}
+	if err := git.FetchBranch(proj.BaseDir, detail.HeadBranch); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	inst, message, err := PrepareSession(s.profile, CreateSessionRequest{
+		Title:    "feedback/pr-" + strconv.Itoa(number),
+		Path:     proj.BaseDir,
+		Group:    proj.Name,
+		Worktree: true,
+		Branch:   detail.HeadBranch,
+		Message:  prompt,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := inst.Start(); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("start error: %v", err))
+		return
+	}
+	go sendWhenReady(inst, message)
+	s.sessionCreated(inst)
+	writeJSON(w, http.StatusCreated, PRFeedbackResponse{SessionID: inst.ID, SessionTitle: inst.Title, Created: true, Prompt: prompt})
+}
+
+// sendWhenReady sends message to a session that was just started once its
+// agent is ready, logging a failed delivery.
+func sendWhenReady(inst *session.Instance, message string) {
+	if err := inst.SendMessageWhenReady(message); err != nil {
+		slog.Warn("apiserver_send_when_ready_failed", slog.String("id", inst.ID), slog.String("error", err.Error()))
+	}
+}
+
+// projectForRepo returns the project whose base directory is a checkout of
+// repo ("owner/repo" or "host/owner/repo"), or nil.
+func projectForRepo(repo string) *session.Project {
+	projects, err := session.LoadProjects()
+	if err != nil {
+		return nil
+	}
+	for _, proj := range projects {
+		if proj.BaseDir != "" && internalprs.RepoFromDir(proj.BaseDir) == repo {
+			return proj
+		}
+	}
+	return nil
+}
+
+// prProvider returns the provider serving repo, writing a 503 when the CLI
+// it needs (gh or glab) is not installed.
+func (s *APIServer) prProvider(w http.ResponseWriter, repo string) (internalprs.Provider, bool) {
+	provider := s.prManager.ProviderFor(repo)
+	if provider == nil {
+		cli := "gh"
+		if s.prManager.ProviderNameFor(repo) == internalprs.ProviderGitLab {
+			cli = "glab"
+		}
+		writeError(w, http.StatusServiceUnavailable, cli+" CLI not available")
+		return nil, false
+	}
+	return provider, true
+}
@@ -0,0 +1,64 @@
+package apiserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/pr"
+)
+
+func TestAPIServer_PRActionsUseRepoProvider(t *testing.T) {
+	watcher := newTestWatcher(t)
+	gh := pr.NewFakeProvider(pr.ProviderGitHub)
+	gl := pr.NewFakeProvider(pr.ProviderGitLab)
+	gl.Details["gitlab.com/g/r#7"] = &pr.PRDetail{
+		PR:           pr.PR{Number: 7, State: "OPEN", Repo: "gitlab.com/g/r"},
+		Mergeability: "MERGEABLE",
+		MergeState:   "CLEAN",
+	}
+	mgr := pr.New()
+	defer mgr.Stop()
+	mgr.SetProvider(gh)
+	mgr.SetProvider(gl)
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, watcher, nil, nil, nil, mgr, "", "test")
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rr
+	}
+
+	if rr := post("/api/v1/prs/state?repo=o/r&number=4", `{"action":"close"}`); rr.Code != http.StatusOK {
+		t.Fatalf("state = %d (%s)", rr.Code, rr.Body.String())
+	}
+	if rr := post("/api/v1/prs/review?repo=gitlab.com/g/r&number=7", `{"action":"approve"}`); rr.Code != http.StatusOK {
+		t.Fatalf("review = %d (%s)", rr.Code, rr.Body.String())
+	}
+	if rr := post("/api/v1/prs/merge?repo=gitlab.com/g/r&number=7", `{"method":"merge"}`); rr.Code != http.StatusOK {
+		t.Fatalf("merge = %d (%s)", rr.Code, rr.Body.String())
+	}
+	if got := strings.Join(gh.Calls(), ","); got != "close o/r#4" {
+		t.Errorf("github calls = %s", got)
+	}
+	if got := strings.Join(gl.Calls(), ","); got != "approve gitlab.com/g/r#7,merge:merge gitlab.com/g/r#7" {
+		t.Errorf("gitlab calls = %s", got)
+	}
+
+	// Feedback collection is GitHub-only.
+	if rr := post("/api/v1/prs/feedback?repo=gitlab.com/g/r&number=7", ""); rr.Code != http.StatusConflict {
+		t.Errorf("gitlab feedback = %d, want 409", rr.Code)
+	}
+
+	// A repo whose provider CLI is missing gets a 503 naming it.
+	noGitLab := pr.New()
+	defer noGitLab.Stop()
+	noGitLab.SetProvider(gh)
+	srv = apiserver.New(apiserver.APIConfig{Port: 0}, watcher, nil, nil, nil, noGitLab, "", "test")
+	rr := post("/api/v1/prs/state?repo=gitlab.com/g/r&number=7", `{"action":"close"}`)
+	if rr.Code != http.StatusServiceUnavailable || !strings.Contains(rr.Body.String(), "glab") {
+		t.Errorf("missing glab = %d (%s)", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,164 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/scheduler"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+func scheduleToResponse(r *statedb.ScheduleRow) ScheduleResponse {
+	resp := ScheduleResponse{
+		ID:        r.ID,
+		Name:      r.Name,
+		Action:    r.Action,
+		SessionID: r.SessionID,
+		TodoID:    r.TodoID,
+		Message:   r.Message,
+		Cron:      r.Cron,
+		Enabled:   r.Enabled,
+		LastError: r.LastError,
+		CreatedAt: r.CreatedAt,
+	}
+	timePtr := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	resp.RunAt = timePtr(r.RunAt)
+	resp.NextRun = timePtr(r.NextRun)
+	resp.LastRun = timePtr(r.LastRun)
+	return resp
+}
+
+// handleSchedules routes GET /api/v1/schedules and POST /api/v1/schedules.
+func (s *APIServer) handleSchedules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listSchedules(w, r)
+	case http.MethodPost:
+		s.createSchedule(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSchedule routes GET/DELETE /api/v1/schedules/{id}.
+func (s *APIServer) handleSchedule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	switch r.Method {
+	case http.MethodGet:
+		s.getSchedule(w, r, id)
+	case http.MethodDelete:
+		s.deleteSchedule(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *APIServer) listSchedules(w http.ResponseWriter, r *http.Request) {
+	s.withTodoStorage(w, func(storage *session.Storage) {
+		rows, err := storage.GetDB().LoadSchedules()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+			return
+		}
+		resp := make([]ScheduleResponse, 0, len(rows))
+		for _, row := range rows {
+			resp = append(resp, scheduleToResponse(row))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+func (s *APIServer) getSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	s.withTodoStorage(w, func(storage *session.Storage) {
+		rows, err := storage.GetDB().LoadSchedules()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+			return
+		}
+		for _, row := range rows {
+			if row.ID == id {
+				writeJSON(w, http.StatusOK, scheduleToResponse(row))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "schedule not found")
+	})
+}
+
+func (s *APIServer) createSchedule(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	var req CreateScheduleRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if (req.SessionID == "") == (req.TodoID == "") {
+		writeError(w, http.StatusBadRequest, "exactly one of session_id or todo_id is required")
+		return
+	}
+
+	spec := scheduler.Spec{
+		Name:      req.Name,
+		Cron:      req.Cron,
+		At:        req.At,
+		SessionID: req.SessionID,
+		TodoID:    req.TodoID,
+		Message:   req.Message,
+	}
+	if req.SessionID != "" {
+		if s.findInstance(req.SessionID) == nil {
+			writeError(w, http.StatusNotFound, "session not found")
+			return
+		}
+		spec.Action = scheduler.ActionSend
+	} else {
+		spec.Action = scheduler.ActionTodo
+	}
+
+	s.withTodoStorage(w, func(storage *session.Storage) {
+		if spec.Action == scheduler.ActionTodo {
+			t, err := storage.LoadTodoByID(req.TodoID)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+				return
+			}
+			if t == nil {
+				writeError(w, http.StatusNotFound, "todo not found")
+				return
+			}
+		}
+		row, err := scheduler.NewJob(spec, time.Now())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := storage.GetDB().SaveSchedule(row); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("save error: %v", err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, scheduleToResponse(row))
+	})
+}
+
+func (s *APIServer) deleteSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	s.withTodoStorage(w, func(storage *session.Storage) {
+		if err := storage.GetDB().DeleteSchedule(id); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
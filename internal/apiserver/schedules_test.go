@@ -0,0 +1,62 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_Schedules(t *testing.T) {
+	inst := session.NewInstance("sched-target", t.TempDir())
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rr
+	}
+
+	rr := do(http.MethodPost, "/api/v1/schedules",
+		`{"session_id":"`+inst.ID+`","cron":"0 9 * * 1-5","message":"/pr-review"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var created apiserver.ScheduleResponse
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.Action != "send" || created.NextRun == nil || !created.Enabled {
+		t.Errorf("unexpected schedule: %+v", created)
+	}
+
+	rr = do(http.MethodGet, "/api/v1/schedules", "")
+	var list []apiserver.ScheduleResponse
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil || len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("list = %v (err %v)", list, err)
+	}
+
+	for body, want := range map[string]int{
+		`{"session_id":"` + inst.ID + `","cron":"bogus","message":"x"}`: http.StatusBadRequest,
+		`{"session_id":"` + inst.ID + `","at":"2h"}`:                    http.StatusBadRequest, // no message
+		`{"session_id":"nope","at":"2h","message":"x"}`:                 http.StatusNotFound,
+		`{"todo_id":"todo-missing","at":"2h"}`:                          http.StatusNotFound,
+		`{"at":"2h","message":"x"}`:                                     http.StatusBadRequest,
+	} {
+		if rr := do(http.MethodPost, "/api/v1/schedules", body); rr.Code != want {
+			t.Errorf("POST %s = %d, want %d (%s)", body, rr.Code, want, rr.Body.String())
+		}
+	}
+
+	if rr := do(http.MethodDelete, "/api/v1/schedules/"+created.ID, ""); rr.Code != http.StatusNoContent {
+		t.Errorf("delete = %d", rr.Code)
+	}
+	if rr := do(http.MethodGet, "/api/v1/schedules/"+created.ID, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("get after delete = %d, want 404", rr.Code)
+	}
+}
@@ -0,0 +1,205 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+const (
+	defaultSearchLimit = 20
+	// searchLoadWait bounds how long a search waits for a fresh index to
+	// finish its initial load before answering with partial results.
+	searchLoadWait = 10 * time.Second
+)
+
+// errSearchDisabled is returned when [global_search] is not enabled.
+var errSearchDisabled = errors.New("conversation search is disabled; set enabled = true under [global_search] in config.toml")
+
+// searchIndex returns the conversation search index, building it from
+// [global_search] in config.toml on first use.
+func (s *APIServer) searchIndex() (*session.GlobalSearchIndex, error) {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	if s.search != nil {
+		return s.search, nil
+	}
+	cfg, err := session.LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := session.NewGlobalSearchIndex(session.GetClaudeConfigDir(), cfg.GlobalSearch)
+	if err != nil {
+		return nil, err
+	}
+	if idx == nil {
+		return nil, errSearchDisabled
+	}
+	s.search = idx
+	return idx, nil
+}
+
+// closeSearchIndex stops the search index's watcher and frees its memory.
+func (s *APIServer) closeSearchIndex() {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	if s.search != nil {
+		s.search.Close()
+		s.search = nil
+	}
+}
+
+// handleSearch serves GET /api/v1/search?q=&project=&since=&fuzzy=true&limit=.
+// It searches agent conversation transcripts; project is a project name
+// from projects.toml or a directory, and since takes the timeline's forms.
+// Results owned by a Hangar session carry its ID and title.
+func (s *APIServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	req := SearchRequest{
+		Query:   q.Get("q"),
+		Project: q.Get("project"),
+		Since:   q.Get("since"),
+		Fuzzy:   q.Get("fuzzy") == "true",
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = n
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	idx, err := s.searchIndex()
+	if err != nil {
+		if errors.Is(err, errSearchDisabled) {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), searchLoadWait)
+	defer cancel()
+	idx.WaitLoaded(ctx)
+
+	resp, err := SearchConversations(idx, s.instances(), req)
+	if err != nil {
+		var invalid invalidRequestError
+		if errors.As(err, &invalid) {
+			writeError(w, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SearchConversations runs req against idx and links each result to the
+// Hangar session in instances that owns the conversation. It is shared by
+// GET /api/v1/search and "hangar search" without a running daemon.
+func SearchConversations(idx *session.GlobalSearchIndex, instances []*session.Instance, req SearchRequest) (*SearchResponse, error) {
+	query := session.SearchQuery{
+		Text:  strings.TrimSpace(req.Query),
+		Fuzzy: req.Fuzzy,
+		Limit: req.Limit,
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if req.Since != "" {
+		since, err := session.ParseSince(req.Since, time.Now())
+		if err != nil {
+			return nil, invalidRequestError(err.Error())
+		}
+		query.Since = since
+	}
+	if req.Project != "" {
+		dirs, err := searchDirs(req.Project, instances)
+		if err != nil {
+			return nil, err
+		}
+		query.Dirs = dirs
+	}
+
+	owners := make(map[string]*session.Instance)
+	for _, inst := range instances {
+		for _, id := range []string{inst.ClaudeSessionID, inst.GeminiSessionID, inst.CodexSessionID, inst.OpenCodeSessionID} {
+			if id != "" {
+				owners[id] = inst
+			}
+		}
+	}
+	resp := &SearchResponse{
+		Query:    query.Text,
+		Fuzzy:    query.Fuzzy,
+		Tier:     session.TierName(idx.GetTier()),
+		Indexing: idx.IsLoading(),
+		Results:  []SearchResultResponse{},
+	}
+	for _, res := range idx.Query(query) {
+		item := SearchResultResponse{
+			ConversationID: res.Entry.SessionID,
+			Tool:           res.Entry.Tool,
+			FilePath:       res.Entry.FilePath,
+			CWD:            res.Entry.CWD,
+			Summary:        res.Entry.Summary,
+			Snippet:        res.Snippet,
+			Score:          res.Score,
+			ModifiedAt:     res.Entry.ModTime,
+		}
+		if inst := owners[res.Entry.SessionID]; inst != nil {
+			item.SessionID = inst.ID
+			item.SessionTitle = inst.Title
+			if item.CWD == "" {
+				item.CWD = inst.ProjectPath
+			}
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	return resp, nil
+}
+
+// searchDirs resolves a project filter to the directories its conversations
+// run in: the project's repository plus the worktrees of sessions on it.
+// A project containing "/" or starting with "~" is taken as a directory.
+func searchDirs(project string, instances []*session.Instance) ([]string, error) {
+	var base string
+	if strings.Contains(project, "/") || strings.HasPrefix(project, "~") {
+		base = session.ExpandPath(project)
+	} else {
+		p, err := session.GetProject(project)
+		if err != nil {
+			return nil, invalidRequestError(err.Error())
+		}
+		base = session.ExpandPath(p.BaseDir)
+	}
+	base = filepath.Clean(base)
+	dirs := []string{base}
+	// Claude records the resolved working directory.
+	if resolved, err := filepath.EvalSymlinks(base); err == nil && resolved != base {
+		dirs = append(dirs, resolved)
+	}
+	for _, inst := range instances {
+		root := filepath.Clean(inst.WorktreeRepoRoot)
+		if inst.WorktreePath != "" && (root == base || strings.HasPrefix(root, base+string(filepath.Separator))) {
+			dirs = append(dirs, inst.WorktreePath)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,114 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_Search(t *testing.T) {
+	watcher := newTestWatcher(t) // sets HOME
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	session.ClearUserConfigCache()
+	t.Cleanup(session.ClearUserConfigCache)
+
+	dir := filepath.Join(configDir, "projects", "-src-api")
+	_ = os.MkdirAll(dir, 0755)
+	for id, line := range map[string]string{
+		"11111111-1111-1111-1111-111111111111": `{"sessionId":"11111111-1111-1111-1111-111111111111","type":"user","message":{"role":"user","content":"fix the auth bug in login"},"cwd":"/src/api"}`,
+		"22222222-2222-2222-2222-222222222222": `{"sessionId":"22222222-2222-2222-2222-222222222222","type":"user","message":{"role":"user","content":"auth bug in the web app"},"cwd":"/src/web"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, id+".jsonl"), []byte(line), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	codexDir := filepath.Join(home, ".codex", "sessions", "2026", "10", "16")
+	_ = os.MkdirAll(codexDir, 0755)
+	codexLine := `{"type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"rotate the deploy keys"}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(codexDir, "rollout-2026-10-16T09-00-00-33333333-3333-3333-3333-333333333333.jsonl"), []byte(codexLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inst := session.NewInstanceWithTool("auth-fix", "/src/api", "claude")
+	inst.ClaudeSessionID = "11111111-1111-1111-1111-111111111111"
+	codex := session.NewInstanceWithTool("keys", "/src/ops", "codex")
+	codex.CodexSessionID = "33333333-3333-3333-3333-333333333333"
+	getInstances := func() []*session.Instance { return []*session.Instance{inst, codex} }
+	get := func(srv *apiserver.APIServer, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	// Search is opt-in through [global_search].
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, watcher, getInstances, nil, nil, nil, "", "test")
+	if rr := get(srv, "/api/v1/search?q=auth"); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("disabled search = %d, want 503", rr.Code)
+	}
+
+	_ = os.MkdirAll(filepath.Join(home, ".hangar"), 0755)
+	config := "[global_search]\nenabled = true\ntier = \"instant\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".hangar", "config.toml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	session.ClearUserConfigCache()
+	srv = apiserver.New(apiserver.APIConfig{Port: 0}, watcher, getInstances, nil, nil, nil, "", "test")
+
+	rr := get(srv, "/api/v1/search?q=auth+bug")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("search = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.SearchResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Tier != "instant" || resp.Indexing || len(resp.Results) != 2 {
+		t.Fatalf("search = %+v", resp)
+	}
+
+	rr = get(srv, "/api/v1/search?q=auth&project=/src/api")
+	resp = apiserver.SearchResponse{}
+	_ = json.NewDecoder(rr.Body).Decode(&resp)
+	if len(resp.Results) != 1 {
+		t.Fatalf("project-filtered search = %+v", resp)
+	}
+	got := resp.Results[0]
+	if got.ConversationID != inst.ClaudeSessionID || got.Tool != "claude" || got.SessionID != inst.ID || got.SessionTitle != "auth-fix" || got.Snippet == "" || got.CWD != "/src/api" {
+		t.Errorf("result = %+v", got)
+	}
+
+	// Other agents' conversations link to their session by its own ID; the
+	// owner supplies the directory the transcript lacks.
+	rr = get(srv, "/api/v1/search?q=deploy+keys")
+	resp = apiserver.SearchResponse{}
+	_ = json.NewDecoder(rr.Body).Decode(&resp)
+	if len(resp.Results) != 1 {
+		t.Fatalf("codex search = %+v", resp)
+	}
+	if got := resp.Results[0]; got.Tool != "codex" || got.SessionID != codex.ID || got.CWD != "/src/ops" {
+		t.Errorf("codex result = %+v", got)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/search":                       http.StatusBadRequest,
+		"/api/v1/search?q=auth&since=whenever": http.StatusBadRequest,
+		"/api/v1/search?q=auth&limit=-1":       http.StatusBadRequest,
+		"/api/v1/search?q=auth&project=nope":   http.StatusBadRequest,
+	} {
+		if rr := get(srv, path); rr.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rr.Code, want)
+		}
+	}
+}
@@ -14,8 +14,11 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/sjoeboo/hangar/internal/mcpserver"
 	"github.com/sjoeboo/hangar/internal/pr"
 	"github.com/sjoeboo/hangar/internal/session"
 	"github.com/sjoeboo/hangar/internal/webui"
@@ -25,6 +28,17 @@ import (
 type APIConfig struct {
 	Port        int
 	BindAddress string
+	// RequireAuth enforces API keys (see "hangar web token") on every
+	// endpoint except /api/v1/status, /api/v1/auth, /ui/, localhost /hooks and
+	// the signed /api/v1/webhooks/github.
+	RequireAuth bool
+	// AllowedOrigins lists extra browser origins (e.g. "https://hangar.tailnet.ts.net")
+	// allowed to change state through the API. Same-origin is always allowed;
+	// without RequireAuth other origins may still read.
+	AllowedOrigins []string
+	// GitHubWebhookSecret enables POST /api/v1/webhooks/github. Deliveries
+	// are authenticated by their HMAC signature rather than an API key.
+	GitHubWebhookSecret string
 }
 
 // APIServer is the embedded HTTP/WebSocket server.
@@ -38,10 +52,19 @@ type APIServer struct {
 	prManager     *pr.Manager                   // unified PR data layer; may be nil in standalone mode
 	profile       string
 	hub           *Hub
+	streamer      *outputStreamer
+	auth          *authenticator
+	upgrader      *websocket.Upgrader
 	server        *http.Server
 	startedAt     time.Time
 	version       string
 	done          chan struct{}
+
+	statusMu sync.Mutex
+	statusCh chan struct{} // closed and replaced on every session status change
+
+	searchMu sync.Mutex
+	search   *session.GlobalSearchIndex // built on the first /search request
 }
 
 // New creates a new APIServer.
@@ -63,6 +86,7 @@ type APIServer struct {
 // is delegated to the manager (no internal PR refresh loop is started).
 func New(cfg APIConfig, watcher *session.StatusFileWatcher, getInstances func() []*session.Instance, getPRInfo func(string) *PRInfo, triggerReload func(), prManager *pr.Manager, profile string, version string) *APIServer {
 	hub := newHub()
+	auth := newAuthenticator(cfg, profile)
 
 	s := &APIServer{
 		cfg:           cfg,
@@ -73,10 +97,14 @@ func New(cfg APIConfig, watcher *session.StatusFileWatcher, getInstances func()
 		prManager:     prManager,
 		profile:       profile,
 		hub:           hub,
+		auth:          auth,
+		upgrader:      auth.newUpgrader(),
 		startedAt:     time.Now(),
 		version:       version,
 		done:          make(chan struct{}),
+		statusCh:      make(chan struct{}),
 	}
+	s.streamer = newOutputStreamer(s)
 
 	// Register onChange callback on prManager so web clients receive PR updates.
 	if prManager != nil {
@@ -92,18 +120,30 @@ func New(cfg APIConfig, watcher *session.StatusFileWatcher, getInstances func()
 
 	// REST API
 	mux.HandleFunc("/api/v1/status", s.handleStatus)
+	mux.HandleFunc("/api/v1/auth", s.handleAuth)
 	mux.HandleFunc("/api/v1/sessions", s.handleSessions)
+	mux.HandleFunc("/api/v1/sessions/broadcast", s.handleSessionBroadcast)
 	mux.HandleFunc("/api/v1/sessions/{id}", s.handleSession)
 	mux.HandleFunc("/api/v1/sessions/{id}/start", s.handleSessionStart)
 	mux.HandleFunc("/api/v1/sessions/{id}/stop", s.handleSessionStop)
 	mux.HandleFunc("/api/v1/sessions/{id}/restart", s.handleSessionRestart)
 	mux.HandleFunc("/api/v1/sessions/{id}/send", s.handleSessionSend)
 	mux.HandleFunc("/api/v1/sessions/{id}/output", s.handleSessionOutput)
+	mux.HandleFunc("/api/v1/sessions/{id}/timeline", s.handleSessionTimeline)
+	mux.HandleFunc("/api/v1/sessions/{id}/conversation", s.handleSessionConversation)
+	mux.HandleFunc("/api/v1/sessions/{id}/wait", s.handleSessionWait)
 	mux.HandleFunc("/api/v1/sessions/{id}/stream", s.handleSessionStream)
+	mux.HandleFunc("/api/v1/sessions/{id}/diff", s.handleSessionDiff)
+	mux.HandleFunc("/api/v1/sessions/{id}/fork", s.handleSessionFork)
+	mux.HandleFunc("/api/v1/sessions/{id}/finish", s.handleSessionFinish)
 	mux.HandleFunc("/api/v1/projects", s.handleProjects)
 	mux.HandleFunc("/api/v1/projects/{id}", s.handleProject)
 	mux.HandleFunc("/api/v1/todos", s.handleTodos)
 	mux.HandleFunc("/api/v1/todos/{id}", s.handleTodo)
+	mux.HandleFunc("/api/v1/usage", s.handleUsage)
+	mux.HandleFunc("/api/v1/search", s.handleSearch)
+	mux.HandleFunc("/api/v1/schedules", s.handleSchedules)
+	mux.HandleFunc("/api/v1/schedules/{id}", s.handleSchedule)
 
 	// PR dashboard endpoints (require prManager)
 	mux.HandleFunc("/api/v1/prs", s.handlePRDashboard)
@@ -111,10 +151,17 @@ func New(cfg APIConfig, watcher *session.StatusFileWatcher, getInstances func()
 	mux.HandleFunc("/api/v1/prs/review", s.handlePRReview)
 	mux.HandleFunc("/api/v1/prs/comment", s.handlePRComment)
 	mux.HandleFunc("/api/v1/prs/state", s.handlePRState)
+	mux.HandleFunc("/api/v1/prs/merge", s.handlePRMerge)
+	mux.HandleFunc("/api/v1/prs/feedback", s.handlePRFeedback)
+	mux.HandleFunc("/api/v1/webhooks/github", s.handleGitHubWebhook)
 
 	// WebSocket
 	mux.HandleFunc("/api/v1/ws", s.handleWS)
 
+	// MCP over streamable HTTP, for agents that cannot spawn "hangar mcp-server".
+	// Tool calls go back through s so each one is authorised like a REST call.
+	mux.Handle("/mcp", mcpserver.NewInProcess(s, version).HTTPHandler())
+
 	// Serve embedded web UI assets; fall back to index.html for SPA routing
 	uiFS := webui.Assets()
 	uiHandler := http.FileServer(uiFS)
@@ -138,7 +185,7 @@ func New(cfg APIConfig, watcher *session.StatusFileWatcher, getInstances func()
 	})
 
 	s.server = &http.Server{
-		Handler:      corsMiddleware(mux),
+		Handler:      s.corsMiddleware(auth.middleware(mux)),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 	}
@@ -161,11 +208,19 @@ func (s *APIServer) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("apiserver listen %s: %w", addr, err)
 	}
-	slog.Info("apiserver_started", slog.String("addr", addr))
+	slog.Info("apiserver_started", slog.String("addr", addr), slog.Bool("require_auth", s.cfg.RequireAuth))
+
+	// Publish a local admin token for same-user clients (TUI, mcp-server).
+	if s.cfg.RequireAuth {
+		defer s.writeLocalToken()()
+	}
 
 	// Run WebSocket hub
 	go s.hub.run()
 
+	// Stream per-session output and status changes to subscribed WS clients
+	go s.streamer.run(ctx)
+
 	// Bridge status watcher notifications → WS broadcasts
 	if s.watcher != nil {
 		go s.bridgeWatcherToHub(ctx)
@@ -184,9 +239,11 @@ func (s *APIServer) Start(ctx context.Context) error {
 		defer cancel()
 		_ = s.server.Shutdown(shutCtx)
 	case err := <-errCh:
+		s.closeSearchIndex()
 		close(s.done)
 		return err
 	}
+	s.closeSearchIndex()
 	close(s.done)
 	return nil
 }
@@ -196,7 +253,9 @@ func (s *APIServer) WaitDone() <-chan struct{} {
 	return s.done
 }
 
-// bridgeWatcherToHub listens for hook notifications and broadcasts session_updated events.
+// bridgeWatcherToHub listens for hook notifications, broadcasts a
+// sessions_changed ping (for list views), and diffs statuses immediately so
+// session_status events are not delayed until the next poll.
 func (s *APIServer) bridgeWatcherToHub(ctx context.Context) {
 	ch := s.watcher.NotifyChannel()
 	for {
@@ -204,22 +263,30 @@ func (s *APIServer) bridgeWatcherToHub(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ch:
-			// Broadcast the full session list refresh signal; clients can re-fetch
-			// or we can send individual session updates. For now send a lightweight ping.
 			s.hub.broadcast <- WsMessage{Type: "sessions_changed"}
-			// TODO: broadcast session_output events here when per-session change
-			// notifications are available. Clients use GET /api/v1/sessions/:id/output
-			// for the initial terminal snapshot.
+			s.streamer.checkStatuses()
 		}
 	}
 }
 
-// corsMiddleware adds permissive CORS headers — appropriate for Tailscale trust model.
-func corsMiddleware(next http.Handler) http.Handler {
+// corsMiddleware adds CORS headers. Same-origin and allowed origins are
+// echoed back. Without auth, any origin may also read (Tailscale trust
+// model), so GET responses and GET preflights answer "*"; writes from other
+// origins get no CORS grant and are rejected by the auth middleware.
+func (s *APIServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		method := r.Method
+		if method == http.MethodOptions {
+			method = r.Header.Get("Access-Control-Request-Method")
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && s.auth.originTrusted(r) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		} else if !s.auth.required && isReadMethod(method) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Hangar-Instance-Id")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Hangar-Instance-Id")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -2,6 +2,7 @@ package apiserver
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -184,6 +185,63 @@ func (s *APIServer) handleSessionSend(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
 }
 
+// handleSessionBroadcast sends one message to every session matching a
+// selector and reports delivery per session.
+func (s *APIServer) handleSessionBroadcast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	var req BroadcastRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Message == "" {
+		writeError(w, http.StatusBadRequest, "message field required")
+		return
+	}
+	sel := session.BroadcastSelector{
+		SessionIDs: req.SessionIDs,
+		Group:      req.Group,
+		Status:     session.Status(req.Status),
+		Project:    req.Project,
+	}
+	switch sel.Status {
+	case "", session.StatusRunning, session.StatusWaiting, session.StatusIdle, session.StatusError:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status %q", req.Status))
+		return
+	}
+	var projects []*session.Project
+	if req.Project != "" {
+		if projects, err = session.LoadProjects(); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("load projects: %v", err))
+			return
+		}
+	}
+	targets, err := session.SelectSessions(s.instances(), sel, projects)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := BroadcastResponse{Results: []BroadcastResult{}}
+	for _, res := range session.Broadcast(targets, req.Message) {
+		resp.Results = append(resp.Results, BroadcastResult(res))
+		if res.Sent {
+			resp.Sent++
+		} else {
+			resp.Failed++
+		}
+	}
+	if resp.Sent > 0 {
+		s.hub.broadcast <- WsMessage{Type: "sessions_changed"}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (s *APIServer) handleSessionOutput(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -224,14 +282,9 @@ func (s *APIServer) handleSessionOutput(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
-	// Post-process: trim trailing spaces per line (removes tmux's pane-width
-	// padding, e.g. 220-col padding on a wide server terminal), then join with
-	// \r\n so xterm.js (convertEol:false) renders lines correctly.
-	rawLines := strings.Split(content, "\n")
-	for i, line := range rawLines {
-		rawLines[i] = strings.TrimRight(line, " ")
-	}
-	content = strings.Join(rawLines, "\r\n")
+	// Post-process: trim per-line pane-width padding (e.g. 220-col padding on a
+	// wide server terminal) and join with \r\n for xterm.js.
+	content = normalizePaneOutput(content)
 
 	lines := strings.Count(content, "\r\n")
 	writeJSON(w, http.StatusOK, SessionOutputResponse{
@@ -241,6 +294,110 @@ func (s *APIServer) handleSessionOutput(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// handleSessionTimeline serves GET /api/v1/sessions/{id}/timeline?since=.
+// since accepts a duration ("24h"), "today", a date or RFC 3339; default 24h.
+func (s *APIServer) handleSessionTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.PathValue("id")
+	if s.findInstance(id) == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		sinceStr = "24h"
+	}
+	since, err := session.ParseSince(sinceStr, time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	storage, err := session.NewStorageWithProfile(s.profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage error: %v", err))
+		return
+	}
+	defer storage.Close()
+	tl, err := session.LoadStatusTimeline(storage.GetDB(), id, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("load history: %v", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, timelineToResponse(tl))
+}
+
+// defaultMaxResultBytes caps each tool result in a conversation response
+// unless the caller asks for more; file reads can be megabytes.
+const defaultMaxResultBytes = 16 * 1024
+
+// handleSessionConversation serves GET /api/v1/sessions/{id}/conversation?since=&max_result_bytes=.
+// since takes the same forms as the timeline's; empty returns the whole
+// transcript. max_result_bytes=0 returns tool results in full.
+func (s *APIServer) handleSessionConversation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	inst := s.findInstance(r.PathValue("id"))
+	if inst == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	q := r.URL.Query()
+	opts := session.ConversationOptions{MaxResultBytes: defaultMaxResultBytes}
+	if v := q.Get("since"); v != "" {
+		since, err := session.ParseSince(v, time.Now())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts.Since = since
+	}
+	if v := q.Get("max_result_bytes"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid max_result_bytes")
+			return
+		}
+		opts.MaxResultBytes = n
+	}
+
+	conv, err := inst.GetConversation(opts)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, ConversationResponse{SessionID: inst.ID, Title: inst.Title, Conversation: conv})
+}
+
+// timelineToResponse converts a StatusTimeline to its JSON DTO.
+func timelineToResponse(tl *session.StatusTimeline) TimelineResponse {
+	resp := TimelineResponse{
+		SessionID:   tl.SessionID,
+		Since:       tl.Since,
+		Until:       tl.Until,
+		Transitions: make([]TimelineTransition, 0, len(tl.Transitions)),
+		Totals:      make(map[string]float64, len(tl.Totals)),
+	}
+	for _, t := range tl.Transitions {
+		resp.Transitions = append(resp.Transitions, TimelineTransition{
+			From:            t.FromStatus,
+			To:              t.ToStatus,
+			Source:          t.Source,
+			At:              t.At,
+			DurationSeconds: t.Duration.Seconds(),
+		})
+	}
+	for status, d := range tl.Totals {
+		resp.Totals[status] = d.Seconds()
+	}
+	return resp
+}
+
 // listSessions handles GET /api/v1/sessions.
 func (s *APIServer) listSessions(w http.ResponseWriter, r *http.Request) {
 	instances := s.instances()
@@ -274,10 +431,83 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
-	if req.Title == "" || req.Path == "" {
-		writeError(w, http.StatusBadRequest, "title and path are required")
+	inst, message, err := PrepareSession(s.profile, req)
+	if err != nil {
+		var invalid invalidRequestError
+		if errors.As(err, &invalid) {
+			writeError(w, http.StatusBadRequest, err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
+
+	// Start the tmux session
+	if err := inst.Start(); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("start error: %v", err))
+		return
+	}
+
+	// Send initial message if provided
+	if message != "" {
+		if ts := inst.GetTmuxSession(); ts != nil {
+			_ = ts.SendKeysAndEnter(message)
+		}
+	}
+
+	s.sessionCreated(inst)
+	writeJSON(w, http.StatusCreated, sessionToResponse(inst, s.getPRInfoFor))
+}
+
+// invalidRequestError marks an error from an exported helper such as
+// PrepareSession that was caused by the request rather than by git or
+// storage; handlers answer it with 400.
+type invalidRequestError string
+
+func (e invalidRequestError) Error() string { return string(e) }
+
+// PrepareSession builds the session described by req, creating its git
+// worktree first when req.Worktree is set, and saves it to the profile's
+// storage without starting it. It returns the session and its initial
+// prompt: req.Message, the template's rendered prompt, or the prompt of the
+// todo named by req.TodoID, which is linked to the session and marked in
+// progress. POST /api/v1/sessions and "hangar run" both create sessions
+// through it.
+func PrepareSession(profile string, req CreateSessionRequest) (*session.Instance, string, error) {
+	if req.Title == "" || req.Path == "" {
+		return nil, "", invalidRequestError("title and path are required")
+	}
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, "", fmt.Errorf("storage error: %w", err)
+	}
+	defer storage.Close()
+
+	var todo *session.Todo
+	if req.TodoID != "" {
+		if todo, err = storage.LoadTodoByID(req.TodoID); err != nil {
+			return nil, "", fmt.Errorf("load todo: %w", err)
+		}
+		if todo == nil {
+			return nil, "", invalidRequestError(fmt.Sprintf("todo '%s' not found", req.TodoID))
+		}
+	}
+	var tmpl *session.SessionTemplate
+	if req.Template != "" {
+		if tmpl, err = session.GetSessionTemplate(req.Template); err != nil {
+			return nil, "", invalidRequestError(err.Error())
+		}
+		if err := tmpl.ValidateMCPs(); err != nil {
+			return nil, "", invalidRequestError(err.Error())
+		}
+		if req.Tool == "" {
+			req.Tool = tmpl.Tool
+		}
+		if req.Group == "" {
+			req.Group = tmpl.Group
+		}
+		req.Worktree = req.Worktree || tmpl.UsesWorktree()
+	}
 	tool := req.Tool
 	if tool == "" {
 		tool = "claude"
@@ -288,6 +518,7 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 	// session is created with the correct WorkDir from the start.
 	effectivePath := req.Path
 	var worktreePath, worktreeBranch string
+	saved := false
 	if req.Worktree {
 		worktreeBranch = req.Branch
 		if worktreeBranch == "" {
@@ -305,10 +536,23 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		newBranch := !git.BranchExists(req.Path, worktreeBranch)
 		if err := git.CreateWorktree(req.Path, worktreePath, worktreeBranch); err != nil {
-			writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create worktree: %v", err))
-			return
+			return nil, "", fmt.Errorf("failed to create worktree: %w", err)
 		}
+		// Remove the worktree again (and the branch, if it was created
+		// here) when the session is not saved.
+		defer func() {
+			if saved {
+				return
+			}
+			if err := git.RemoveWorktree(req.Path, worktreePath, true); err != nil {
+				slog.Warn("failed to remove worktree", "path", worktreePath, "err", err)
+			}
+			if newBranch {
+				_ = git.DeleteBranch(req.Path, worktreeBranch, true)
+			}
+		}()
 
 		effectivePath = worktreePath
 	}
@@ -331,6 +575,25 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 		inst.WorktreeRepoRoot = req.Path
 	}
 
+	message := req.Message
+	if tmpl != nil {
+		if err := tmpl.ApplyToInstance(inst); err != nil {
+			return nil, "", fmt.Errorf("template error: %w", err)
+		}
+		if message == "" {
+			message = tmpl.RenderPrompt(session.TemplateVars{
+				Title:  req.Title,
+				Branch: worktreeBranch,
+				Path:   effectivePath,
+				Group:  req.Group,
+				Todo:   todo,
+			})
+		}
+	}
+	if message == "" && todo != nil {
+		message = todo.Prompt
+	}
+
 	// Handle skip-permissions
 	if req.SkipPermissions {
 		opts := inst.GetClaudeOptions()
@@ -344,37 +607,26 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Persist to storage before starting so the TUI picks it up
-	storage, err := session.NewStorageWithProfile(s.profile)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage error: %v", err))
-		return
-	}
-	defer storage.Close()
-
 	existing, err := storage.Load()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
-		return
+		return nil, "", fmt.Errorf("load error: %w", err)
 	}
 	all := append(existing, inst)
 	if err := storage.Save(all); err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("save error: %v", err))
-		return
-	}
-
-	// Start the tmux session
-	if err := inst.Start(); err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("start error: %v", err))
-		return
+		return nil, "", fmt.Errorf("save error: %w", err)
 	}
-
-	// Send initial message if provided
-	if req.Message != "" {
-		if ts := inst.GetTmuxSession(); ts != nil {
-			_ = ts.SendKeysAndEnter(req.Message)
+	saved = true
+	if todo != nil {
+		if err := storage.UpdateTodoStatus(todo.ID, session.TodoStatusInProgress, inst.ID); err != nil {
+			slog.Warn("failed to link todo", "todo", todo.ID, "err", err)
 		}
 	}
+	return inst, message, nil
+}
 
+// sessionCreated tells WS clients and the TUI about a session that was just
+// saved to storage.
+func (s *APIServer) sessionCreated(inst *session.Instance) {
 	// Broadcast session_created event immediately so the frontend can show feedback.
 	s.hub.broadcast <- WsMessage{Type: "session_created", Data: sessionToResponse(inst, s.getPRInfoFor)}
 
@@ -387,8 +639,6 @@ func (s *APIServer) createSession(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(200 * time.Millisecond)
 		s.hub.broadcast <- WsMessage{Type: "sessions_changed"}
 	}()
-
-	writeJSON(w, http.StatusCreated, sessionToResponse(inst, s.getPRInfoFor))
 }
 
 // updateSession handles PATCH /api/v1/sessions/{id}.
@@ -472,6 +722,13 @@ func (s *APIServer) deleteSession(w http.ResponseWriter, r *http.Request, id str
 		return
 	}
 
+	s.sessionDeleted(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionDeleted tells WS clients and the TUI about a session that was just
+// removed from storage.
+func (s *APIServer) sessionDeleted(id string) {
 	s.hub.broadcast <- WsMessage{Type: "session_deleted", Data: WsSessionDeletedData{ID: id}}
 
 	// Trigger an immediate TUI reload so getInstances() reflects the deletion
@@ -483,7 +740,6 @@ func (s *APIServer) deleteSession(w http.ResponseWriter, r *http.Request, id str
 		time.Sleep(200 * time.Millisecond)
 		s.hub.broadcast <- WsMessage{Type: "sessions_changed"}
 	}()
-	w.WriteHeader(http.StatusNoContent)
 }
 
 // wsHandleSendMessage handles send_message commands from WS clients.
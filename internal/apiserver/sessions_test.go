@@ -0,0 +1,121 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_CreateSessionUnknownTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session.ClearUserConfigCache()
+	defer session.ClearUserConfigCache()
+
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), nil, nil, nil, nil, "", "test")
+	rr := httptest.NewRecorder()
+	body := `{"title":"t","path":"` + t.TempDir() + `","template":"missing"}`
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/sessions", strings.NewReader(body)))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "missing") {
+		t.Errorf("create with unknown template = %d (%s), want 400", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAPIServer_Broadcast(t *testing.T) {
+	a := session.NewInstanceWithGroup("a", t.TempDir(), "work")
+	b := session.NewInstanceWithGroup("b", t.TempDir(), "work/api")
+	c := session.NewInstanceWithGroup("c", t.TempDir(), "play")
+	getInstances := func() []*session.Instance { return []*session.Instance{a, b, c} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	do := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/broadcast", strings.NewReader(body)))
+		return rr
+	}
+
+	// None of the sessions are running, so every delivery fails but is reported.
+	rr := do(`{"message":"git pull --rebase","group":"work"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("broadcast = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.BroadcastResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Failed != 2 || resp.Results[0].SessionID != a.ID || resp.Results[1].Error == "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	for body, want := range map[string]int{
+		`{"group":"work"}`:                 http.StatusBadRequest, // no message
+		`{"message":"x"}`:                  http.StatusBadRequest, // no selector
+		`{"message":"x","status":"bogus"}`: http.StatusBadRequest,
+	} {
+		if rr := do(body); rr.Code != want {
+			t.Errorf("POST %s = %d, want %d (%s)", body, rr.Code, want, rr.Body.String())
+		}
+	}
+}
+
+func TestPrepareSessionWithTodo(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	session.ClearUserConfigCache()
+	defer session.ClearUserConfigCache()
+	if err := os.MkdirAll(filepath.Join(home, ".hangar"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[templates.bugfix]\nprompt = \"Fix: {todo.title}\\n\\n{todo.description}\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".hangar", "config.toml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage, err := session.NewStorageWithProfile("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo := session.NewTodo("Login 500", "Happens on empty passwords", "Fix the login 500", t.TempDir())
+	err = storage.SaveTodo(todo)
+	storage.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := apiserver.CreateSessionRequest{Title: "login", Path: t.TempDir(), Template: "bugfix", TodoID: todo.ID}
+	_, message, err := apiserver.PrepareSession("test", req)
+	if err != nil {
+		t.Fatalf("PrepareSession: %v", err)
+	}
+	if message != "Fix: Login 500\n\nHappens on empty passwords" {
+		t.Errorf("message = %q, want the template rendered with the todo", message)
+	}
+
+	// Without a template the todo's own prompt is sent.
+	req.Template, req.Title = "", "login-2"
+	inst, message, err := apiserver.PrepareSession("test", req)
+	if err != nil || message != "Fix the login 500" {
+		t.Fatalf("without template: message = %q, err = %v", message, err)
+	}
+
+	storage, err = session.NewStorageWithProfile("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	linked, err := storage.LoadTodoByID(todo.ID)
+	if err != nil || linked == nil || linked.Status != session.TodoStatusInProgress || linked.SessionID != inst.ID {
+		t.Errorf("todo = %+v, %v; want it in progress and linked to %s", linked, err, inst.ID)
+	}
+
+	req.TodoID = "missing"
+	if _, _, err := apiserver.PrepareSession("test", req); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown todo: err = %v", err)
+	}
+}
@@ -43,7 +43,7 @@ func (s *APIServer) handleSessionStream(w http.ResponseWriter, r *http.Request)
 	}
 	sessionName := ts.Name
 
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Debug("stream_ws_upgrade_failed", slog.String("error", err.Error()))
 		return
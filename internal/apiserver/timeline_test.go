@@ -0,0 +1,69 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+func TestAPIServer_SessionTimeline(t *testing.T) {
+	watcher := newTestWatcher(t)
+	inst := session.NewInstance("timeline", t.TempDir())
+
+	storage, err := session.NewStorageWithProfile("")
+	if err != nil {
+		t.Fatalf("NewStorageWithProfile: %v", err)
+	}
+	db := storage.GetDB()
+	if err := db.SaveInstance(&statedb.InstanceRow{
+		ID: inst.ID, Title: inst.Title, ProjectPath: inst.ProjectPath, GroupPath: "grp",
+		Tool: "claude", Status: "idle", CreatedAt: time.Now(), ToolData: json.RawMessage("{}"),
+	}); err != nil {
+		t.Fatalf("SaveInstance: %v", err)
+	}
+	_ = db.WriteStatusWithSource(inst.ID, "running", "claude", statedb.StatusSourceHook)
+	_ = db.WriteStatusWithSource(inst.ID, "waiting", "claude", statedb.StatusSourceHook)
+	storage.Close()
+
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, watcher, getInstances, nil, nil, nil, "", "test")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+inst.ID+"/timeline?since=1h", nil)
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", rr.Code, rr.Body.String())
+	}
+
+	var resp apiserver.TimelineResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Transitions) != 2 {
+		t.Fatalf("expected 2 transitions, got %d", len(resp.Transitions))
+	}
+	last := resp.Transitions[1]
+	if last.From != "running" || last.To != "waiting" || last.Source != "hook" {
+		t.Errorf("unexpected transition: %+v", last)
+	}
+	if _, ok := resp.Totals["waiting"]; !ok {
+		t.Error("totals missing waiting")
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/sessions/" + inst.ID + "/timeline?since=bogus": http.StatusBadRequest,
+		"/api/v1/sessions/nope/timeline":                        http.StatusNotFound,
+	} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rr.Code, want)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/sjoeboo/hangar/internal/session"
 )
 
-func todoToResponse(t *session.Todo) TodoResponse {
+// todoToResponse converts t to its API form. all holds the todos of t's
+// project and is used to work out whether t is blocked.
+func todoToResponse(t *session.Todo, all []*session.Todo) TodoResponse {
 	return TodoResponse{
 		ID:          t.ID,
 		ProjectPath: t.ProjectPath,
@@ -19,6 +21,9 @@ func todoToResponse(t *session.Todo) TodoResponse {
 		Status:      string(t.Status),
 		SessionID:   t.SessionID,
 		Order:       t.Order,
+		BlockedBy:   t.BlockedBy,
+		Blocked:     t.IsBlocked(all),
+		AutoStart:   t.AutoStart,
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
 	}
@@ -76,7 +81,7 @@ func (s *APIServer) listTodos(w http.ResponseWriter, r *http.Request) {
 		}
 		resp := make([]TodoResponse, 0, len(todos))
 		for _, t := range todos {
-			resp = append(resp, todoToResponse(t))
+			resp = append(resp, todoToResponse(t, todos))
 		}
 		writeJSON(w, http.StatusOK, resp)
 	})
@@ -93,7 +98,8 @@ func (s *APIServer) getTodo(w http.ResponseWriter, r *http.Request, id string) {
 			writeError(w, http.StatusNotFound, "todo not found")
 			return
 		}
-		writeJSON(w, http.StatusOK, todoToResponse(t))
+		all, _ := storage.LoadTodos(t.ProjectPath)
+		writeJSON(w, http.StatusOK, todoToResponse(t, all))
 	})
 }
 
@@ -112,19 +118,56 @@ func (s *APIServer) createTodo(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "title and project_path are required")
 		return
 	}
+	if req.AutoStart && !scopeFromContext(r.Context()).Allows(ScopeAdmin) {
+		writeError(w, http.StatusForbidden, errAutoStartScope)
+		return
+	}
 	todo := session.NewTodo(req.Title, req.Description, req.Prompt, req.ProjectPath)
+	todo.AutoStart = req.AutoStart
 	s.withTodoStorage(w, func(storage *session.Storage) {
+		all, err := storage.LoadTodos(todo.ProjectPath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+			return
+		}
+		if len(req.BlockedBy) > 0 {
+			if err := session.ValidateTodoBlockers(todo, req.BlockedBy, all); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			todo.BlockedBy = req.BlockedBy
+		}
 		if err := storage.SaveTodo(todo); err != nil {
 			writeError(w, http.StatusInternalServerError, fmt.Sprintf("save error: %v", err))
 			return
 		}
-		resp := todoToResponse(todo)
+		resp := todoToResponse(todo, all)
 		// Broadcast over WS
 		s.hub.broadcast <- WsMessage{Type: "todo_updated", Data: resp}
 		writeJSON(w, http.StatusCreated, resp)
 	})
 }
 
+// errAutoStartScope is returned when a non-admin key sets up a todo that
+// chaining would start a session for (see session.EnableTodoChaining);
+// creating sessions requires admin, like POST /sessions.
+const errAutoStartScope = "auto-start todos start sessions; this change requires an admin key"
+
+// changesAutoStart reports whether req makes t an auto-start todo or changes
+// what an auto-start todo would start with, or when. Progress updates, such
+// as moving it to done, are not.
+func (req UpdateTodoRequest) changesAutoStart(t *session.Todo) bool {
+	if req.AutoStart != nil {
+		return *req.AutoStart
+	}
+	if !t.AutoStart {
+		return false
+	}
+	return req.Title != nil || req.Description != nil || req.Prompt != nil ||
+		req.BlockedBy != nil || req.SessionID != nil ||
+		(req.Status != nil && session.TodoStatus(*req.Status) == session.TodoStatusTodo)
+}
+
 func (s *APIServer) updateTodo(w http.ResponseWriter, r *http.Request, id string) {
 	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
 	if err != nil {
@@ -146,6 +189,10 @@ func (s *APIServer) updateTodo(w http.ResponseWriter, r *http.Request, id string
 			writeError(w, http.StatusNotFound, "todo not found")
 			return
 		}
+		if req.changesAutoStart(t) && !scopeFromContext(r.Context()).Allows(ScopeAdmin) {
+			writeError(w, http.StatusForbidden, errAutoStartScope)
+			return
+		}
 		if req.Title != nil {
 			t.Title = *req.Title
 		}
@@ -161,11 +208,26 @@ func (s *APIServer) updateTodo(w http.ResponseWriter, r *http.Request, id string
 		if req.Prompt != nil {
 			t.Prompt = *req.Prompt
 		}
+		if req.AutoStart != nil {
+			t.AutoStart = *req.AutoStart
+		}
+		all, err := storage.LoadTodos(t.ProjectPath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+			return
+		}
+		if req.BlockedBy != nil {
+			if err := session.ValidateTodoBlockers(t, *req.BlockedBy, all); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			t.BlockedBy = *req.BlockedBy
+		}
 		if err := storage.SaveTodo(t); err != nil {
 			writeError(w, http.StatusInternalServerError, fmt.Sprintf("save error: %v", err))
 			return
 		}
-		resp := todoToResponse(t)
+		resp := todoToResponse(t, all)
 		s.hub.broadcast <- WsMessage{Type: "todo_updated", Data: resp}
 		writeJSON(w, http.StatusOK, resp)
 	})
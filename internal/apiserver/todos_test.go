@@ -0,0 +1,65 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+)
+
+func TestAPIServer_TodoDependencies(t *testing.T) {
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), nil, nil, nil, nil, "", "test")
+	project := t.TempDir()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rr
+	}
+	create := func(body string) apiserver.TodoResponse {
+		t.Helper()
+		rr := do(http.MethodPost, "/api/v1/todos", body)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("create = %d (%s)", rr.Code, rr.Body.String())
+		}
+		var resp apiserver.TodoResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	schema := create(`{"project_path":"` + project + `","title":"schema"}`)
+	api := create(`{"project_path":"` + project + `","title":"api","blocked_by":["` + schema.ID + `"],"auto_start":true}`)
+	if !api.Blocked || !api.AutoStart || len(api.BlockedBy) != 1 {
+		t.Errorf("api todo = %+v, want blocked auto-start", api)
+	}
+
+	// schema -> api -> schema would be a cycle.
+	rr := do(http.MethodPatch, "/api/v1/todos/"+schema.ID, `{"blocked_by":["`+api.ID+`"]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("cyclic PATCH = %d, want 400", rr.Code)
+	}
+	rr = do(http.MethodPost, "/api/v1/todos", `{"project_path":"`+project+`","title":"x","blocked_by":["todo-missing"]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown blocker = %d, want 400", rr.Code)
+	}
+
+	// Once the blocker is done, the list shows the dependent as unblocked.
+	if rr := do(http.MethodPatch, "/api/v1/todos/"+schema.ID, `{"status":"done"}`); rr.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %d (%s)", rr.Code, rr.Body.String())
+	}
+	rr = do(http.MethodGet, "/api/v1/todos?project="+project, "")
+	var list []apiserver.TodoResponse
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	for _, td := range list {
+		if td.ID == api.ID && td.Blocked {
+			t.Error("api todo should be unblocked once schema is done")
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package apiserver
 
-import "time"
+import (
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
 
 // PRDashboardResponse is returned by GET /api/v1/prs.
 type PRDashboardResponse struct {
@@ -51,6 +55,22 @@ type StateActionRequest struct {
 	Action string `json:"action"` // "close", "reopen", "draft", "ready"
 }
 
+// MergeRequest is the JSON body for POST /api/v1/prs/merge.
+type MergeRequest struct {
+	Action       string `json:"action,omitempty"` // "merge" (default), "auto" or "disable_auto"
+	Method       string `json:"method,omitempty"` // "squash" (default), "merge" or "rebase"
+	DeleteBranch bool   `json:"delete_branch,omitempty"`
+	Force        bool   `json:"force,omitempty"` // merge despite failing or pending checks
+}
+
+// PRFeedbackResponse is returned by POST /api/v1/prs/feedback.
+type PRFeedbackResponse struct {
+	SessionID    string `json:"session_id"`
+	SessionTitle string `json:"session_title"`
+	Created      bool   `json:"created"` // a new worktree session was started for the PR
+	Prompt       string `json:"prompt"`
+}
+
 // SessionResponse is the JSON representation of a session returned by the API.
 type SessionResponse struct {
 	ID             string    `json:"id"`
@@ -79,6 +99,8 @@ type CreateSessionRequest struct {
 	Worktree        bool   `json:"worktree,omitempty"`         // create git worktree for this session
 	Branch          string `json:"branch,omitempty"`           // worktree branch name (auto-gen from title if empty)
 	SkipPermissions bool   `json:"skip_permissions,omitempty"` // --dangerously-skip-permissions
+	Template        string `json:"template,omitempty"`         // [templates.<name>] from config.toml; explicit fields win
+	TodoID          string `json:"todo_id,omitempty"`          // todo to start: fills {todo.*} and links the todo to the session
 }
 
 // UpdateSessionRequest is the JSON body for PATCH /api/v1/sessions/{id}.
@@ -94,11 +116,88 @@ type SendMessageRequest struct {
 	Raw     bool   `json:"raw,omitempty"` // if true, send literal keys without appending Enter
 }
 
+// BroadcastRequest is the JSON body for POST /api/v1/sessions/broadcast.
+// Selector fields are combined with AND; at least one must be set.
+type BroadcastRequest struct {
+	Message    string   `json:"message"`
+	SessionIDs []string `json:"session_ids,omitempty"`
+	Group      string   `json:"group,omitempty"`   // group path, including subgroups
+	Status     string   `json:"status,omitempty"`  // running, waiting, idle or error
+	Project    string   `json:"project,omitempty"` // project name
+}
+
+// BroadcastResponse is returned by POST /api/v1/sessions/broadcast.
+type BroadcastResponse struct {
+	Sent    int               `json:"sent"`
+	Failed  int               `json:"failed"`
+	Results []BroadcastResult `json:"results"`
+}
+
+// BroadcastResult reports delivery of a broadcast to one session.
+type BroadcastResult struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	Sent      bool   `json:"sent"`
+	Error     string `json:"error,omitempty"`
+}
+
 // StartSessionRequest is the optional JSON body for POST /api/v1/sessions/{id}/start.
 type StartSessionRequest struct {
 	Message string `json:"message,omitempty"` // optional initial message
 }
 
+// SessionDiffResponse is returned by GET /api/v1/sessions/{id}/diff.
+type SessionDiffResponse struct {
+	SessionID string `json:"session_id"`
+	Path      string `json:"path"`
+	Summary   string `json:"summary"`        // e.g. "3 files, +40 -12"
+	Diff      string `json:"diff,omitempty"` // omitted with ?summary=true
+}
+
+// FinishWorktreeRequest is the JSON body for POST /api/v1/sessions/{id}/finish.
+// Like "hangar worktree finish", the branch is merged locally into Into by
+// default, then the worktree, branch and session are removed. An unmerged
+// branch is only deleted with Discard.
+type FinishWorktreeRequest struct {
+	PR          bool   `json:"pr,omitempty"`           // push and open a PR instead; clean up once it merges
+	NoMerge     bool   `json:"no_merge,omitempty"`     // skip the local merge
+	Into        string `json:"into,omitempty"`         // target branch (default branch when empty)
+	AutoMerge   bool   `json:"auto_merge,omitempty"`   // with pr: enable auto-merge
+	MergeMethod string `json:"merge_method,omitempty"` // with auto_merge: merge, squash (default) or rebase
+	KeepBranch  bool   `json:"keep_branch,omitempty"`
+	Force       bool   `json:"force,omitempty"`   // finish despite uncommitted changes
+	Discard     bool   `json:"discard,omitempty"` // delete the branch even if it has unmerged commits
+}
+
+// FinishWorktreeResponse is returned by POST /api/v1/sessions/{id}/finish.
+type FinishWorktreeResponse struct {
+	SessionID     string `json:"session_id"`
+	Branch        string `json:"branch"`
+	MergedInto    string `json:"merged_into,omitempty"`
+	BranchDeleted bool   `json:"branch_deleted"`
+	PRNumber      int    `json:"pr_number,omitempty"`
+	PRURL         string `json:"pr_url,omitempty"`
+	PendingFinish bool   `json:"pending_finish,omitempty"` // cleanup waits for the PR to merge
+	Warning       string `json:"warning,omitempty"`
+}
+
+// ForkSessionRequest is the JSON body for POST /api/v1/sessions/{id}/fork.
+type ForkSessionRequest struct {
+	Title  string `json:"title,omitempty"`  // default: "<title> (fork)"
+	Group  string `json:"group,omitempty"`  // default: the source session's group
+	Branch string `json:"branch,omitempty"` // fork into a new worktree on this branch (created if missing)
+}
+
+// WaitResponse is returned by GET /api/v1/sessions/{id}/wait.
+type WaitResponse struct {
+	SessionID     string  `json:"session_id"`
+	Title         string  `json:"title"`
+	Status        string  `json:"status"`
+	TimedOut      bool    `json:"timed_out"`
+	WaitedSeconds float64 `json:"waited_seconds"`
+	LastResponse  string  `json:"last_response,omitempty"` // agent's last message, once the wait matched
+}
+
 // TodoResponse is the JSON representation of a todo returned by the API.
 type TodoResponse struct {
 	ID          string    `json:"id"`
@@ -109,25 +208,61 @@ type TodoResponse struct {
 	Status      string    `json:"status"`
 	SessionID   string    `json:"session_id,omitempty"`
 	Order       int       `json:"order"`
+	BlockedBy   []string  `json:"blocked_by,omitempty"`
+	Blocked     bool      `json:"blocked"` // true while any blocked_by todo is not done
+	AutoStart   bool      `json:"auto_start"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // CreateTodoRequest is the JSON body for POST /api/v1/todos.
 type CreateTodoRequest struct {
-	ProjectPath string `json:"project_path"`
-	Title       string `json:"title"`
-	Description string `json:"description,omitempty"`
-	Prompt      string `json:"prompt,omitempty"`
+	ProjectPath string   `json:"project_path"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Prompt      string   `json:"prompt,omitempty"`
+	BlockedBy   []string `json:"blocked_by,omitempty"`
+	AutoStart   bool     `json:"auto_start,omitempty"`
 }
 
 // UpdateTodoRequest is the JSON body for PATCH /api/v1/todos/{id}.
 type UpdateTodoRequest struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Prompt      *string `json:"prompt,omitempty"`
-	Status      *string `json:"status,omitempty"`
-	SessionID   *string `json:"session_id,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Prompt      *string   `json:"prompt,omitempty"`
+	Status      *string   `json:"status,omitempty"`
+	SessionID   *string   `json:"session_id,omitempty"`
+	BlockedBy   *[]string `json:"blocked_by,omitempty"`
+	AutoStart   *bool     `json:"auto_start,omitempty"`
+}
+
+// ScheduleResponse is the JSON representation of a scheduled job.
+type ScheduleResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Action    string     `json:"action"` // send | todo
+	SessionID string     `json:"session_id,omitempty"`
+	TodoID    string     `json:"todo_id,omitempty"`
+	Message   string     `json:"message,omitempty"`
+	Cron      string     `json:"cron,omitempty"`
+	RunAt     *time.Time `json:"run_at,omitempty"`
+	Enabled   bool       `json:"enabled"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreateScheduleRequest is the JSON body for POST /api/v1/schedules.
+// Set session_id (send message) or todo_id (start a worktree session from
+// the todo), and cron or at.
+type CreateScheduleRequest struct {
+	Name      string `json:"name,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	TodoID    string `json:"todo_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Cron      string `json:"cron,omitempty"`
+	At        string `json:"at,omitempty"` // HH:MM, duration, "YYYY-MM-DD HH:MM" or RFC 3339
 }
 
 // ProjectResponse is the JSON representation of a project returned by the API.
@@ -160,6 +295,99 @@ type StatusResponse struct {
 	ByStatus  map[string]int `json:"by_status"`
 }
 
+// AuthResponse is returned by GET /api/v1/auth.
+type AuthResponse struct {
+	AuthRequired  bool   `json:"auth_required"`
+	Authenticated bool   `json:"authenticated"`
+	Scope         string `json:"scope,omitempty"` // read | control | admin
+}
+
+// ConversationResponse is returned by GET /api/v1/sessions/{id}/conversation:
+// the session's parsed transcript with turns, tool calls, subagents and
+// token usage.
+type ConversationResponse struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	*session.Conversation
+}
+
+// TimelineResponse is returned by GET /api/v1/sessions/{id}/timeline.
+type TimelineResponse struct {
+	SessionID   string               `json:"session_id"`
+	Since       time.Time            `json:"since"`
+	Until       time.Time            `json:"until"`
+	Transitions []TimelineTransition `json:"transitions"`
+	Totals      map[string]float64   `json:"totals_seconds"` // time per status inside the window
+}
+
+// TimelineTransition is one status change in a TimelineResponse.
+type TimelineTransition struct {
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	Source          string    `json:"source"` // hook | poller
+	At              time.Time `json:"at"`
+	DurationSeconds float64   `json:"duration_seconds"` // time spent in To
+}
+
+// UsageResponse is returned by GET /api/v1/usage.
+type UsageResponse struct {
+	Since   time.Time  `json:"since"`
+	Until   time.Time  `json:"until"`
+	GroupBy []string   `json:"group_by"`
+	Rows    []UsageRow `json:"rows"` // highest estimated cost first
+	Total   UsageRow   `json:"total"`
+}
+
+// UsageRow is the token usage of one group in a UsageResponse. Dimensions
+// not listed in group_by are omitted.
+type UsageRow struct {
+	Project          string  `json:"project,omitempty"`
+	Branch           string  `json:"branch,omitempty"`
+	Day              string  `json:"day,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Sessions         int     `json:"sessions"`
+	Turns            int     `json:"turns"`
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	CacheReadTokens  int     `json:"cache_read_tokens"`
+	CacheWriteTokens int     `json:"cache_write_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost_usd"`
+}
+
+// SearchRequest holds the parameters of GET /api/v1/search.
+type SearchRequest struct {
+	Query   string // text to find
+	Project string // project name from projects.toml, or a directory
+	Since   string // same forms as the timeline's since
+	Fuzzy   bool
+	Limit   int // default 20
+}
+
+// SearchResponse is returned by GET /api/v1/search.
+type SearchResponse struct {
+	Query    string                 `json:"query"`
+	Fuzzy    bool                   `json:"fuzzy"`
+	Tier     string                 `json:"tier"`     // instant | balanced
+	Indexing bool                   `json:"indexing"` // initial indexing still running; results may be incomplete
+	Results  []SearchResultResponse `json:"results"`  // best match first
+}
+
+// SearchResultResponse is one matching conversation in a SearchResponse.
+// SessionID and SessionTitle are set when a Hangar session owns it.
+type SearchResultResponse struct {
+	ConversationID string    `json:"conversation_id"` // the agent's session ID
+	Tool           string    `json:"tool"`            // claude, gemini, codex or opencode
+	FilePath       string    `json:"file_path"`       // transcript file
+	CWD            string    `json:"cwd,omitempty"`
+	Summary        string    `json:"summary,omitempty"` // first user message
+	Snippet        string    `json:"snippet,omitempty"` // text around the first match
+	Score          int       `json:"score"`
+	ModifiedAt     time.Time `json:"modified_at"`
+	SessionID      string    `json:"session_id,omitempty"`
+	SessionTitle   string    `json:"session_title,omitempty"`
+}
+
 // WsMessage is the envelope for all WebSocket messages (both directions).
 type WsMessage struct {
 	Type string `json:"type"`
@@ -190,6 +418,23 @@ type SessionOutputData struct {
 	Output    string `json:"output"`
 }
 
+// WsSessionStatusData is the WS event payload for session_status events,
+// sent whenever a session's status changes.
+type WsSessionStatusData struct {
+	SessionID      string `json:"session_id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// WsSubscribeData is the payload of client "subscribe"/"unsubscribe" commands.
+// Events defaults to both session_output and session_status. Clients receive
+// session_output only for subscribed sessions, and session_status for every
+// session until they subscribe to session_status explicitly.
+type WsSubscribeData struct {
+	SessionIDs []string `json:"session_ids"`
+	Events     []string `json:"events,omitempty"`
+}
+
 // WsHookChangedData is the data payload for the hook_changed WS event.
 type WsHookChangedData struct {
 	InstanceID    string `json:"instance_id"`
@@ -197,6 +442,13 @@ type WsHookChangedData struct {
 	Status        string `json:"status"`
 }
 
+// WsPRsChangedData is the data payload for the prs_changed WS event, sent
+// when a GitHub webhook reports changes to PRs.
+type WsPRsChangedData struct {
+	Repo    string `json:"repo"`
+	Numbers []int  `json:"numbers"`
+}
+
 // PRInfo holds pull-request metadata for a session, sourced from the TUI's
 // PR cache. All fields are omitempty so the object is absent when no PR exists.
 type PRInfo struct {
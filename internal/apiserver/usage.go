@@ -0,0 +1,89 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// handleUsage serves GET /api/v1/usage?since=&project=&group_by=&format=.
+// since defaults to 30 days; group_by is a comma-separated subset of
+// project,branch,day,model (default all); format=csv returns CSV.
+func (s *APIServer) handleUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	sinceStr := q.Get("since")
+	if sinceStr == "" {
+		sinceStr = "30d"
+	}
+	since, err := session.ParseSince(sinceStr, time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	groupBy, err := session.ParseUsageGroupBy(q.Get("group_by"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid format %q (use json or csv)", format))
+		return
+	}
+
+	// Project names are cosmetic; fall back to group paths if projects.toml
+	// cannot be read.
+	projects, _ := session.LoadProjects()
+	report := session.AggregateUsage(s.instances(), session.UsageOptions{
+		Since:    since,
+		Project:  q.Get("project"),
+		GroupBy:  groupBy,
+		Projects: projects,
+	})
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="hangar-usage.csv"`)
+		w.WriteHeader(http.StatusOK)
+		_ = report.WriteCSV(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, usageToResponse(report))
+}
+
+func usageToResponse(report *session.UsageReport) UsageResponse {
+	resp := UsageResponse{
+		Since:   report.Since,
+		Until:   report.Until,
+		GroupBy: report.GroupBy,
+		Rows:    make([]UsageRow, 0, len(report.Records)),
+		Total:   usageRowFromRecord(&report.Total),
+	}
+	for i := range report.Records {
+		resp.Rows = append(resp.Rows, usageRowFromRecord(&report.Records[i]))
+	}
+	return resp
+}
+
+func usageRowFromRecord(rec *session.UsageRecord) UsageRow {
+	return UsageRow{
+		Project:          rec.Project,
+		Branch:           rec.Branch,
+		Day:              rec.Day,
+		Model:            rec.Model,
+		Sessions:         rec.Sessions,
+		Turns:            rec.Turns,
+		InputTokens:      rec.InputTokens,
+		OutputTokens:     rec.OutputTokens,
+		CacheReadTokens:  rec.CacheReadTokens,
+		CacheWriteTokens: rec.CacheWriteTokens,
+		TotalTokens:      rec.TotalTokens(),
+		EstimatedCost:    rec.EstimatedCost,
+	}
+}
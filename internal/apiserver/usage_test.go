@@ -0,0 +1,73 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_Usage(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	inst := session.NewInstanceWithGroupAndTool("usage", t.TempDir(), "my-app", "claude")
+	inst.ClaudeSessionID = "usage-sess"
+	projectPath, err := filepath.EvalSymlinks(inst.ProjectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(configDir, "projects", session.ConvertToClaudeDirName(projectPath))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","timestamp":"` + time.Now().UTC().Format(time.RFC3339) +
+		`","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1000,"output_tokens":500}}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "usage-sess.jsonl"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/usage?since=1h&group_by=project,model", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.UsageResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(resp.Rows))
+	}
+	row := resp.Rows[0]
+	if row.Project != "my-app" || row.Model != "claude-sonnet-4-20250514" || row.TotalTokens != 1500 || row.Branch != "" {
+		t.Errorf("unexpected row: %+v", row)
+	}
+	if resp.Total.Sessions != 1 || resp.Total.EstimatedCost <= 0 {
+		t.Errorf("unexpected total: %+v", resp.Total)
+	}
+
+	rr = httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/usage?format=csv&group_by=day", nil))
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Body.String(), "day,sessions,") {
+		t.Errorf("csv: status %d body %q", rr.Code, rr.Body.String())
+	}
+
+	for _, path := range []string{"/api/v1/usage?since=bogus", "/api/v1/usage?group_by=repo", "/api/v1/usage?format=xml"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", path, rr.Code)
+		}
+	}
+}
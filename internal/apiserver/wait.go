@@ -0,0 +1,120 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+const (
+	defaultWaitTimeout = 10 * time.Minute
+	maxWaitTimeout     = time.Hour
+)
+
+// statusChanged returns a channel that is closed at the next session status
+// change seen by the output streamer.
+func (s *APIServer) statusChanged() <-chan struct{} {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	return s.statusCh
+}
+
+// signalStatusChange wakes every pending wait request.
+func (s *APIServer) signalStatusChange() {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	close(s.statusCh)
+	s.statusCh = make(chan struct{})
+}
+
+// parseWaitTimeout parses a wait timeout: a Go duration ("600s", "10m") or
+// a number of seconds. Empty means defaultWaitTimeout; values are capped at
+// maxWaitTimeout.
+func parseWaitTimeout(v string) (time.Duration, bool) {
+	if v == "" {
+		return defaultWaitTimeout, true
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, false
+		}
+		d = time.Duration(n) * time.Second
+	}
+	if d <= 0 {
+		return 0, false
+	}
+	return min(d, maxWaitTimeout), true
+}
+
+// handleSessionWait serves GET /api/v1/sessions/{id}/wait?status=waiting,idle&timeout=600s&changed=true.
+// It long-polls until the session reaches one of the statuses (default
+// waiting, idle or error) and responds with the final status and, on a
+// match, the agent's last response. changed=true ignores a matching status
+// until the session has left it, for waits started right after a send.
+func (s *APIServer) handleSessionWait(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.PathValue("id")
+	inst := s.findInstance(id)
+	if inst == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	q := r.URL.Query()
+	statuses, err := session.ParseWaitStatuses(q.Get("status"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	timeout, ok := parseWaitTimeout(q.Get("timeout"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid timeout")
+		return
+	}
+
+	// The server's WriteTimeout would cut a long poll short.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 30*time.Second))
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	start := time.Now()
+	status, matched := session.WaitForStatus(ctx, func() session.Status {
+		if cur := s.findInstance(id); cur != nil {
+			inst = cur
+		}
+		return inst.GetStatusThreadSafe()
+	}, session.WaitOptions{
+		Statuses: statuses,
+		Changed:  q.Get("changed") == "true",
+		Wake:     s.statusChanged,
+		Poll:     statusPollInterval,
+	})
+	if r.Context().Err() != nil {
+		return // client went away
+	}
+	if s.findInstance(id) == nil {
+		writeError(w, http.StatusNotFound, "session was deleted")
+		return
+	}
+
+	resp := WaitResponse{
+		SessionID:     inst.ID,
+		Title:         inst.Title,
+		Status:        string(status),
+		TimedOut:      !matched,
+		WaitedSeconds: time.Since(start).Round(time.Millisecond).Seconds(),
+	}
+	if matched {
+		if last, err := inst.GetLastResponse(); err == nil {
+			resp.LastResponse = last.Content
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,44 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func TestAPIServer_SessionWait(t *testing.T) {
+	inst := session.NewInstance("worker", t.TempDir())
+	inst.SetStatusThreadSafe(session.StatusRunning)
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	wait := func(query string) (*httptest.ResponseRecorder, apiserver.WaitResponse) {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+inst.ID+"/wait?"+query, nil))
+		var resp apiserver.WaitResponse
+		_ = json.NewDecoder(rr.Body).Decode(&resp)
+		return rr, resp
+	}
+
+	if rr, resp := wait("status=running"); rr.Code != http.StatusOK || resp.TimedOut || resp.Status != "running" {
+		t.Errorf("matching wait = %d %+v", rr.Code, resp)
+	}
+	if rr, resp := wait("timeout=100ms"); rr.Code != http.StatusOK || !resp.TimedOut || resp.Status != "running" {
+		t.Errorf("timed-out wait = %d %+v", rr.Code, resp)
+	}
+	for _, q := range []string{"status=done", "timeout=soon", "timeout=-5"} {
+		if rr, _ := wait(q); rr.Code != http.StatusBadRequest {
+			t.Errorf("wait?%s = %d, want 400", q, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/missing/wait", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing session = %d, want 404", rr.Code)
+	}
+}
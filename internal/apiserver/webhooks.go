@@ -0,0 +1,88 @@
+package apiserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/sjoeboo/hangar/internal/pr"
+)
+
+// maxWebhookBody caps the size of a webhook delivery. GitHub sends at most
+// 25MB, but the events hangar handles are far smaller.
+const maxWebhookBody = 5 << 20
+
+// handleGitHubWebhook serves POST /api/v1/webhooks/github. It verifies the
+// X-Hub-Signature-256 HMAC against [api] github_webhook_secret, applies
+// pull_request, pull_request_review, check_suite and status events to the
+// cached PRs, and tells clients which PRs changed. Other events are
+// acknowledged and ignored.
+func (s *APIServer) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.cfg.GitHubWebhookSecret == "" {
+		writeError(w, http.StatusNotFound, "GitHub webhooks are disabled (set [api] github_webhook_secret)")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	if len(body) > maxWebhookBody {
+		writeError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return
+	}
+	if !validWebhookSignature(s.cfg.GitHubWebhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, "invalid webhook signature")
+		return
+	}
+	if s.prManager == nil {
+		writeError(w, http.StatusServiceUnavailable, "PR manager not available")
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	ev, err := pr.ParseGitHubWebhook(event, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if ev == nil {
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "ignored"})
+		return
+	}
+	numbers := s.prManager.ApplyWebhook(ev)
+	slog.Debug("apiserver_github_webhook",
+		slog.String("event", event), slog.String("action", ev.Action),
+		slog.String("repo", ev.Repo), slog.Any("numbers", numbers))
+
+	select {
+	case s.hub.broadcast <- WsMessage{Type: "prs_changed", Data: WsPRsChangedData{Repo: ev.Repo, Numbers: numbers}}:
+	default:
+		// hub not running or full — skip broadcast
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// validWebhookSignature reports whether header is the "sha256=<hex>" HMAC
+// of body under secret, as GitHub sends in X-Hub-Signature-256.
+func validWebhookSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
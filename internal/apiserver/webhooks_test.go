@@ -0,0 +1,60 @@
+package apiserver_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/pr"
+)
+
+func TestAPIServer_GitHubWebhook(t *testing.T) {
+	watcher := newTestWatcher(t)
+	mgr := pr.New()
+	defer mgr.Stop()
+	mgr.SetSessionPR("s1", &pr.PR{Number: 4, Repo: "o/r", State: "OPEN", HeadBranch: "feat/a"})
+	srv := apiserver.New(apiserver.APIConfig{Port: 0, GitHubWebhookSecret: "s3cret"}, watcher, nil, nil, nil, mgr, "", "test")
+
+	deliver := func(event, body, secret string) *httptest.ResponseRecorder {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+		return rr
+	}
+	merged := `{"action":"closed","pull_request":{"number":4,"state":"closed","merged":true,"head":{"ref":"feat/a"}},
+	  "repository":{"html_url":"https://github.com/o/r"}}`
+
+	if rr := deliver("pull_request", merged, "wrong"); rr.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature = %d, want 401", rr.Code)
+	}
+	if p, _ := mgr.GetSessionPR("s1"); p.State != "OPEN" {
+		t.Fatalf("unsigned delivery changed the PR: %+v", p)
+	}
+	if rr := deliver("ping", `{"zen":"hi"}`, "s3cret"); rr.Code != http.StatusAccepted {
+		t.Errorf("ping = %d, want 202", rr.Code)
+	}
+	if rr := deliver("pull_request", merged, "s3cret"); rr.Code != http.StatusOK {
+		t.Fatalf("pull_request = %d (%s)", rr.Code, rr.Body.String())
+	}
+	if p, _ := mgr.GetSessionPR("s1"); p.State != "MERGED" {
+		t.Errorf("session PR = %+v, want MERGED", p)
+	}
+	if rr := deliver("status", `{"branches":[]}`, "s3cret"); rr.Code != http.StatusBadRequest {
+		t.Errorf("payload without repository = %d, want 400", rr.Code)
+	}
+
+	// Without a configured secret the endpoint is off.
+	srv = apiserver.New(apiserver.APIConfig{Port: 0}, watcher, nil, nil, nil, mgr, "", "test")
+	if rr := deliver("pull_request", merged, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("no secret = %d, want 404", rr.Code)
+	}
+}
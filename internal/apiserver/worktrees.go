@@ -0,0 +1,365 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/git"
+	internalprs "github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// sessionDir returns the directory git operations on inst run in: the
+// worktree for worktree sessions, the project path otherwise.
+func sessionDir(inst *session.Instance) string {
+	if inst.IsWorktree() {
+		return inst.WorktreePath
+	}
+	return inst.ProjectPath
+}
+
+// handleSessionDiff serves GET /api/v1/sessions/{id}/diff?summary=true.
+// The diff covers the branch's commits since its base plus uncommitted
+// changes, like the TUI's diff view.
+func (s *APIServer) handleSessionDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	inst := s.findInstance(r.PathValue("id"))
+	if inst == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	dir := sessionDir(inst)
+	raw, err := git.FetchDiff(dir)
+	if errors.Is(err, git.ErrNotGitRepo) {
+		writeError(w, http.StatusBadRequest, "session is not in a git repository")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := SessionDiffResponse{SessionID: inst.ID, Path: dir, Summary: git.DiffSummary(raw)}
+	if r.URL.Query().Get("summary") != "true" {
+		resp.Diff = raw
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleSessionFinish serves POST /api/v1/sessions/{id}/finish, the API
+// counterpart of "hangar worktree finish".
+func (s *APIServer) handleSessionFinish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	inst := s.findInstance(r.PathValue("id"))
+	if inst == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	var req FinishWorktreeRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+	if !inst.IsWorktree() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("session '%s' is not in a worktree", inst.Title))
+		return
+	}
+	switch {
+	case req.PR && req.NoMerge:
+		writeError(w, http.StatusBadRequest, "pr and no_merge are mutually exclusive")
+		return
+	case req.AutoMerge && !req.PR:
+		writeError(w, http.StatusBadRequest, "auto_merge requires pr")
+		return
+	}
+	switch req.MergeMethod {
+	case "", "merge", "squash", "rebase":
+	default:
+		writeError(w, http.StatusBadRequest, "invalid merge_method: must be merge, squash, or rebase")
+		return
+	}
+
+	if !req.Force {
+		dirty, err := git.HasUncommittedChanges(inst.WorktreePath)
+		if err != nil {
+			if _, statErr := os.Stat(inst.WorktreePath); !os.IsNotExist(statErr) {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to check worktree status: %v", err))
+				return
+			}
+		}
+		if dirty {
+			writeError(w, http.StatusConflict, "worktree has uncommitted changes (set force to override)")
+			return
+		}
+	}
+
+	if req.PR {
+		s.finishViaPR(w, inst, req)
+		return
+	}
+
+	resp := FinishWorktreeResponse{SessionID: inst.ID, Branch: inst.WorktreeBranch}
+	if !req.NoMerge {
+		target := req.Into
+		if target == "" {
+			if target, err = git.GetDefaultBranch(inst.WorktreeRepoRoot); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("could not determine target branch: %v", err))
+				return
+			}
+		}
+		if target == inst.WorktreeBranch {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("cannot merge branch '%s' into itself", target))
+			return
+		}
+		if err := git.MergeInto(inst.WorktreeRepoRoot, target, inst.WorktreeBranch); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		resp.MergedInto = target
+	}
+
+	if _, statErr := os.Stat(inst.WorktreePath); !os.IsNotExist(statErr) {
+		if err := git.RemoveWorktree(inst.WorktreeRepoRoot, inst.WorktreePath, req.Force); err != nil {
+			resp.Warning = fmt.Sprintf("failed to remove worktree: %v", err)
+		}
+	}
+	_ = git.PruneWorktrees(inst.WorktreeRepoRoot)
+	if !req.KeepBranch {
+		if err := git.DeleteBranch(inst.WorktreeRepoRoot, inst.WorktreeBranch, req.Discard); err != nil {
+			resp.Warning = fmt.Sprintf("failed to delete branch: %v", err)
+		} else {
+			resp.BranchDeleted = true
+		}
+	}
+	if inst.Exists() {
+		_ = inst.Kill()
+	}
+
+	storage, err := session.NewStorageWithProfile(s.profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage error: %v", err))
+		return
+	}
+	defer storage.Close()
+	if err := storage.DeleteInstance(inst.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("delete error: %v", err))
+		return
+	}
+	s.sessionDeleted(inst.ID)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// finishViaPR pushes inst's branch and opens a PR for it; a FinishWatcher
+// removes the worktree and session once the PR merges.
+func (s *APIServer) finishViaPR(w http.ResponseWriter, inst *session.Instance, req FinishWorktreeRequest) {
+	ghPath := ""
+	if s.prManager != nil {
+		ghPath = s.prManager.GHPath()
+	}
+	if ghPath == "" {
+		ghPath, _ = exec.LookPath("gh")
+	}
+	if ghPath == "" {
+		writeError(w, http.StatusServiceUnavailable, "gh CLI not available")
+		return
+	}
+	storage, err := session.NewStorageWithProfile(s.profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage error: %v", err))
+		return
+	}
+	defer storage.Close()
+
+	p, err := internalprs.FinishViaPR(ghPath, storage, inst, internalprs.FinishOptions{
+		Base:        req.Into,
+		AutoMerge:   req.AutoMerge,
+		MergeMethod: req.MergeMethod,
+		KeepBranch:  req.KeepBranch,
+	})
+	if p == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to open PR: %v", err))
+		return
+	}
+	resp := FinishWorktreeResponse{
+		SessionID:     inst.ID,
+		Branch:        inst.WorktreeBranch,
+		PRNumber:      p.Number,
+		PRURL:         p.URL,
+		PendingFinish: true,
+	}
+	if err != nil {
+		resp.Warning = err.Error()
+	}
+	if s.prManager != nil {
+		s.prManager.SetSessionPR(inst.ID, p)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleSessionFork serves POST /api/v1/sessions/{id}/fork. It starts a new
+// session that continues the source's Claude (or OpenCode) conversation,
+// optionally in a new worktree.
+func (s *APIServer) handleSessionFork(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	source := s.findInstance(r.PathValue("id"))
+	if source == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	var req ForkSessionRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+	if source.Tool != "claude" && source.Tool != "opencode" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("session '%s' cannot be forked (tool: %s)", source.Title, source.Tool))
+		return
+	}
+	if source.Tool == "opencode" && req.Branch != "" {
+		writeError(w, http.StatusBadRequest, "forking OpenCode sessions into a worktree is not supported")
+		return
+	}
+	// Try to capture the Claude session ID from tmux if it is missing.
+	if source.Tool == "claude" && source.ClaudeSessionID == "" && source.Exists() {
+		source.PostStartSync(2 * time.Second)
+	}
+	if !source.CanFork() {
+		writeError(w, http.StatusConflict, fmt.Sprintf("session '%s' cannot be forked: no active conversation", source.Title))
+		return
+	}
+	if req.Title == "" {
+		req.Title = source.Title + " (fork)"
+	}
+	if req.Group == "" {
+		req.Group = source.GroupPath
+	}
+
+	var opts *session.ClaudeOptions
+	removeWorktree := func() {}
+	if req.Branch != "" {
+		if opts, removeWorktree, err = forkWorktreeOptions(source, req.Branch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var forked *session.Instance
+	if source.Tool == "opencode" {
+		forked, _, err = source.CreateForkedOpenCodeInstance(req.Title, req.Group)
+	} else {
+		forked, _, err = source.CreateForkedInstanceWithOptions(req.Title, req.Group, opts)
+	}
+	if err != nil {
+		removeWorktree()
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create fork: %v", err))
+		return
+	}
+
+	storage, err := session.NewStorageWithProfile(s.profile)
+	if err != nil {
+		removeWorktree()
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage error: %v", err))
+		return
+	}
+	defer storage.Close()
+	existing, err := storage.Load()
+	if err != nil {
+		removeWorktree()
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("load error: %v", err))
+		return
+	}
+	if err := storage.Save(append(existing, forked)); err != nil {
+		removeWorktree()
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("save error: %v", err))
+		return
+	}
+	if err := forked.Start(); err != nil {
+		if err := storage.DeleteInstance(forked.ID); err != nil {
+			slog.Warn("apiserver_fork_cleanup_failed", slog.String("id", forked.ID), slog.String("error", err.Error()))
+		}
+		removeWorktree()
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("start error: %v", err))
+		return
+	}
+	if forked.Tool == "opencode" {
+		go forked.DetectOpenCodeSession()
+	}
+
+	s.sessionCreated(forked)
+	writeJSON(w, http.StatusCreated, sessionToResponse(forked, s.getPRInfoFor))
+}
+
+// forkWorktreeOptions creates a worktree on branch in source's repository and
+// returns Claude options that start a fork inside it, plus a function that
+// removes the worktree again (and the branch, if it was created here) for
+// when the fork cannot be created.
+func forkWorktreeOptions(source *session.Instance, branch string) (*session.ClaudeOptions, func(), error) {
+	if !git.IsGitRepo(source.ProjectPath) {
+		return nil, nil, fmt.Errorf("session path is not a git repository")
+	}
+	repoRoot, err := git.GetWorktreeBaseRoot(source.ProjectPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get repo root: %w", err)
+	}
+	wtSettings := session.GetWorktreeSettings()
+	worktreePath := git.WorktreePath(git.WorktreePathOptions{
+		Branch:    branch,
+		Location:  wtSettings.DefaultLocation,
+		RepoDir:   repoRoot,
+		SessionID: git.GeneratePathID(),
+		Template:  wtSettings.Template(),
+	})
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	newBranch := !git.BranchExists(repoRoot, branch)
+	if err := git.CreateWorktree(repoRoot, worktreePath, branch); err != nil {
+		return nil, nil, err
+	}
+	remove := func() {
+		if err := git.RemoveWorktree(repoRoot, worktreePath, true); err != nil {
+			slog.Warn("apiserver_fork_cleanup_failed", slog.String("path", worktreePath), slog.String("error", err.Error()))
+		}
+		if newBranch {
+			_ = git.DeleteBranch(repoRoot, branch, true)
+		}
+	}
+
+	config, _ := session.LoadUserConfig()
+	opts := session.NewClaudeOptions(config)
+	opts.WorkDir = worktreePath
+	opts.WorktreePath = worktreePath
+	opts.WorktreeRepoRoot = repoRoot
+	opts.WorktreeBranch = branch
+	return opts, remove, nil
+}
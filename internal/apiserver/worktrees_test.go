@@ -0,0 +1,169 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/apiserver"
+	"github.com/sjoeboo/hangar/internal/git"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// worktreeSession creates a repo on main with a worktree on branch "feat"
+// holding one committed change, and returns a session for the worktree.
+func worktreeSession(t *testing.T) *session.Instance {
+	t.Helper()
+	repo := t.TempDir()
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(repo, "init", "--initial-branch=main")
+	if err := os.WriteFile(filepath.Join(repo, "hello.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(repo, "add", ".")
+	run(repo, "commit", "-m", "initial")
+
+	wt := filepath.Join(t.TempDir(), "feat")
+	if err := git.CreateWorktree(repo, wt, "feat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "hello.txt"), []byte("hello\nworld\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(wt, "commit", "-am", "add world")
+
+	inst := session.NewInstance("feat", wt)
+	inst.WorktreePath = wt
+	inst.WorktreeRepoRoot = repo
+	inst.WorktreeBranch = "feat"
+	return inst
+}
+
+func TestAPIServer_SessionDiff(t *testing.T) {
+	inst := worktreeSession(t)
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+inst.ID+"/diff", nil))
+	var resp apiserver.SessionDiffResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Summary != "1 file, +1 -0" || !strings.Contains(resp.Diff, "+world") {
+		t.Errorf("diff = %+v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/"+inst.ID+"/diff?summary=true", nil))
+	resp = apiserver.SessionDiffResponse{}
+	_ = json.NewDecoder(rr.Body).Decode(&resp)
+	if resp.Summary != "1 file, +1 -0" || resp.Diff != "" {
+		t.Errorf("summary-only diff = %+v", resp)
+	}
+}
+
+func TestAPIServer_FinishWorktree(t *testing.T) {
+	inst := worktreeSession(t)
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	do := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+inst.ID+"/finish", strings.NewReader(body)))
+		return rr
+	}
+
+	for body, want := range map[string]int{
+		`{"auto_merge":true}`:         http.StatusBadRequest,
+		`{"pr":true,"no_merge":true}`: http.StatusBadRequest,
+		`{"into":"feat"}`:             http.StatusBadRequest,
+	} {
+		if rr := do(body); rr.Code != want {
+			t.Errorf("POST %s = %d, want %d (%s)", body, rr.Code, want, rr.Body.String())
+		}
+	}
+
+	_ = os.WriteFile(filepath.Join(inst.WorktreePath, "dirty.txt"), []byte("x"), 0644)
+	if rr := do(``); rr.Code != http.StatusConflict {
+		t.Errorf("finish with uncommitted changes = %d, want 409 (%s)", rr.Code, rr.Body.String())
+	}
+	_ = os.Remove(filepath.Join(inst.WorktreePath, "dirty.txt"))
+
+	rr := do(``)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("finish = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.FinishWorktreeResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.MergedInto != "main" || !resp.BranchDeleted || resp.Warning != "" {
+		t.Errorf("finish = %+v", resp)
+	}
+	if _, err := os.Stat(inst.WorktreePath); !os.IsNotExist(err) {
+		t.Error("worktree should be removed")
+	}
+	if git.BranchExists(inst.WorktreeRepoRoot, "feat") {
+		t.Error("branch should be deleted")
+	}
+	if data, _ := os.ReadFile(filepath.Join(inst.WorktreeRepoRoot, "hello.txt")); string(data) != "hello\nworld\n" {
+		t.Errorf("main not merged: %q", data)
+	}
+}
+
+func TestAPIServer_FinishWorktreeKeepsUnmergedBranch(t *testing.T) {
+	inst := worktreeSession(t)
+	getInstances := func() []*session.Instance { return []*session.Instance{inst} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+inst.ID+"/finish", strings.NewReader(`{"no_merge":true,"force":true}`)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("finish = %d (%s)", rr.Code, rr.Body.String())
+	}
+	var resp apiserver.FinishWorktreeResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.MergedInto != "" || resp.BranchDeleted || resp.Warning == "" {
+		t.Errorf("finish = %+v, want the unmerged branch kept with a warning", resp)
+	}
+	if !git.BranchExists(inst.WorktreeRepoRoot, "feat") {
+		t.Error("unmerged branch was deleted without discard")
+	}
+}
+
+func TestAPIServer_ForkSession(t *testing.T) {
+	shell := session.NewInstance("shell", t.TempDir())
+	claude := session.NewInstanceWithTool("claude", t.TempDir(), "claude")
+	getInstances := func() []*session.Instance { return []*session.Instance{shell, claude} }
+	srv := apiserver.New(apiserver.APIConfig{Port: 0}, newTestWatcher(t), getInstances, nil, nil, nil, "", "test")
+
+	for id, want := range map[string]int{
+		"missing": http.StatusNotFound,
+		shell.ID:  http.StatusBadRequest, // not an agent session
+		claude.ID: http.StatusConflict,   // no conversation to fork yet
+	} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/sessions/"+id+"/fork", strings.NewReader(`{}`)))
+		if rr.Code != want {
+			t.Errorf("fork %s = %d, want %d (%s)", id, rr.Code, want, rr.Body.String())
+		}
+	}
+}
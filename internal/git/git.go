@@ -405,6 +405,20 @@ func MergeBranch(repoDir, branchName string) error {
 	return nil
 }
 
+// MergeInto checks out target in repoDir and merges branchName into it.
+// A failed merge is aborted so the repository is left clean.
+func MergeInto(repoDir, target, branchName string) error {
+	cmd := exec.Command("git", "-C", repoDir, "checkout", target)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to checkout %s: %s", target, strings.TrimSpace(string(output)))
+	}
+	if err := MergeBranch(repoDir, branchName); err != nil {
+		_ = exec.Command("git", "-C", repoDir, "merge", "--abort").Run()
+		return fmt.Errorf("%w (aborted)", err)
+	}
+	return nil
+}
+
 // DeleteBranch deletes a local branch. If force is true, uses -D (force delete).
 func DeleteBranch(repoDir, branchName string, force bool) error {
 	flag := "-d"
@@ -450,6 +464,25 @@ func FetchBranch(repoDir, branch string) error {
 	return nil
 }
 
+// PushBranch pushes a local branch to origin and sets it as the upstream.
+// Runs: git push -u origin <branch>
+func PushBranch(repoDir, branch string) error {
+	const timeout = 2 * time.Minute
+	const waitDelay = 5 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoDir, "push", "-u", "origin", branch)
+	cmd.WaitDelay = waitDelay
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("push branch %q failed: %s: %w",
+			branch, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
 // UpdateBaseBranch fast-forward pulls the base branch in repoPath.
 // Returns nil if the pull succeeds or there is nothing to pull.
 // Returns an error on failure so callers can warn but continue.
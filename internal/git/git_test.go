@@ -1167,3 +1167,33 @@ func TestFetchBranch_InvalidBranch(t *testing.T) {
 		t.Fatal("expected error fetching from repo with no remote, got nil")
 	}
 }
+
+func TestPushBranch(t *testing.T) {
+	remote := t.TempDir()
+	if out, err := exec.Command("git", "init", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %s %v", out, err)
+	}
+	dir := t.TempDir()
+	createTestRepo(t, dir)
+	if out, err := exec.Command("git", "-C", dir, "remote", "add", "origin", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git remote add: %s %v", out, err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "branch", "feature").CombinedOutput(); err != nil {
+		t.Fatalf("git branch: %s %v", out, err)
+	}
+
+	if err := PushBranch(dir, "feature"); err != nil {
+		t.Fatalf("PushBranch: %v", err)
+	}
+	if err := exec.Command("git", "-C", remote, "rev-parse", "--verify", "refs/heads/feature").Run(); err != nil {
+		t.Error("branch was not pushed to origin")
+	}
+	out, _ := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "feature@{upstream}").Output()
+	if strings.TrimSpace(string(out)) != "origin/feature" {
+		t.Errorf("upstream = %q, want origin/feature", strings.TrimSpace(string(out)))
+	}
+
+	if err := PushBranch(dir, "missing"); err == nil {
+		t.Error("expected error pushing a missing branch")
+	}
+}
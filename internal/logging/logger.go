@@ -22,6 +22,7 @@ const (
 	CompPool    = "pool"
 	CompHTTP    = "http"
 	CompWeb     = "web"
+	CompSched   = "sched"
 )
 
 // Config holds logging configuration.
@@ -2,55 +2,166 @@ package mcpserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
+const (
+	// defaultTimeout bounds ordinary API calls.
+	defaultTimeout = 10 * time.Second
+	// slowTimeout bounds calls that shell out to git or gh (pushing a
+	// branch, opening a PR, fetching PR detail).
+	slowTimeout = 2 * time.Minute
+)
+
 // Client wraps the Hangar REST API.
 type Client struct {
-	base string
-	http *http.Client
+	base  string
+	token func() string // returns the bearer token; may be nil or return "" when auth is off
+	http  *http.Client
 }
 
 // NewClient creates a client pointing at the given base URL.
-func NewClient(base string) *Client {
+// token is called per request so a restarted server's new local token is
+// picked up; the result is sent as a bearer token when non-empty.
+func NewClient(base string, token func() string) *Client {
 	return &Client{
-		base: base,
-		http: &http.Client{Timeout: 10 * time.Second},
+		base:  base,
+		token: token,
+		http:  &http.Client{},
 	}
 }
 
-// get performs a GET request and decodes the JSON response into v.
-func (c *Client) get(path string, v any) error {
-	resp, err := c.http.Get(c.base + path)
+// NewHandlerClient creates a client that serves requests with h directly,
+// without a network round trip.
+func NewHandlerClient(h http.Handler) *Client {
+	return &Client{
+		base: "http://hangar.internal",
+		http: &http.Client{Transport: handlerTransport{h}},
+	}
+}
+
+// withToken returns a copy of c that authenticates with token.
+func (c *Client) withToken(token string) *Client {
+	cc := *c
+	cc.token = func() string { return token }
+	return &cc
+}
+
+// handlerTransport is an http.RoundTripper that calls a handler in-process.
+type handlerTransport struct {
+	h http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := &responseRecorder{header: http.Header{}, code: http.StatusOK}
+	t.h.ServeHTTP(rec, req)
+	return &http.Response{
+		StatusCode:    rec.code,
+		Status:        http.StatusText(rec.code),
+		Header:        rec.header,
+		Body:          io.NopCloser(&rec.body),
+		ContentLength: int64(rec.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// responseRecorder buffers a handler's response for handlerTransport.
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+	wrote  bool
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if !r.wrote {
+		r.code, r.wrote = code, true
+	}
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.body.Write(b)
+}
+
+// do sends a request with the auth header set and returns the response body.
+// Responses with status >= 400 are returned as errors including the body.
+func (c *Client) do(method, path string, body any) ([]byte, error) {
+	return c.doTimeout(defaultTimeout, method, path, body)
+}
+
+// doTimeout is do with an explicit timeout.
+func (c *Client) doTimeout(timeout time.Duration, method, path string, body any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != nil {
+		if token := c.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := c.http.Do(req)
 	if err != nil {
-		return fmt.Errorf("GET %s: %w", path, err)
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
 	}
 	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
+	rb, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("GET %s: HTTP %d: %s", path, resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, string(rb))
+	}
+	return rb, nil
+}
+
+// get performs a GET request and decodes the JSON response into v.
+func (c *Client) get(path string, v any) error {
+	body, err := c.do(http.MethodGet, path, nil)
+	if err != nil {
+		return err
 	}
 	return json.Unmarshal(body, v)
 }
 
 // post performs a POST request with a JSON body and decodes the response into v (v may be nil).
 func (c *Client) post(path string, body any, v any) error {
-	b, err := json.Marshal(body)
+	rb, err := c.do(http.MethodPost, path, body)
 	if err != nil {
 		return err
 	}
-	resp, err := c.http.Post(c.base+path, "application/json", bytes.NewReader(b))
-	if err != nil {
-		return fmt.Errorf("POST %s: %w", path, err)
+	if v != nil {
+		return json.Unmarshal(rb, v)
 	}
-	defer resp.Body.Close()
-	rb, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("POST %s: HTTP %d: %s", path, resp.StatusCode, string(rb))
+	return nil
+}
+
+// slow performs a request allowed to take up to slowTimeout and decodes the
+// response into v (v may be nil).
+func (c *Client) slow(method, path string, body any, v any) error {
+	rb, err := c.doTimeout(slowTimeout, method, path, body)
+	if err != nil {
+		return err
 	}
 	if v != nil {
 		return json.Unmarshal(rb, v)
@@ -60,24 +171,10 @@ func (c *Client) post(path string, body any, v any) error {
 
 // patch performs a PATCH request.
 func (c *Client) patch(path string, body any, v any) error {
-	b, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest(http.MethodPatch, c.base+path, bytes.NewReader(b))
+	rb, err := c.do(http.MethodPatch, path, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return fmt.Errorf("PATCH %s: %w", path, err)
-	}
-	defer resp.Body.Close()
-	rb, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("PATCH %s: HTTP %d: %s", path, resp.StatusCode, string(rb))
-	}
 	if v != nil {
 		return json.Unmarshal(rb, v)
 	}
@@ -86,20 +183,8 @@ func (c *Client) patch(path string, body any, v any) error {
 
 // del performs a DELETE request.
 func (c *Client) del(path string) error {
-	req, err := http.NewRequest(http.MethodDelete, c.base+path, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return fmt.Errorf("DELETE %s: %w", path, err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("DELETE %s: HTTP %d: %s", path, resp.StatusCode, string(b))
-	}
-	return nil
+	_, err := c.do(http.MethodDelete, path, nil)
+	return err
 }
 
 // ListSessions returns all sessions.
@@ -133,6 +218,62 @@ func (c *Client) SendMessage(id, message string) error {
 	return c.post("/api/v1/sessions/"+id+"/send", body, nil)
 }
 
+// Broadcast sends message to every session matching the selector and
+// returns the per-session delivery results.
+func (c *Client) Broadcast(message string, sessionIDs []string, group, status, project string) (map[string]any, error) {
+	body := map[string]any{
+		"message":     message,
+		"session_ids": sessionIDs,
+		"group":       group,
+		"status":      status,
+		"project":     project,
+	}
+	var result map[string]any
+	err := c.post("/api/v1/sessions/broadcast", body, &result)
+	return result, err
+}
+
+// WaitForSession blocks until the session reaches one of statuses (a
+// comma-separated list; empty = waiting, idle or error) or timeout passes,
+// and returns the final status and the agent's last response.
+func (c *Client) WaitForSession(id, statuses string, timeout time.Duration, changed bool) (map[string]any, error) {
+	q := url.Values{"timeout": {timeout.String()}}
+	if statuses != "" {
+		q.Set("status", statuses)
+	}
+	if changed {
+		q.Set("changed", "true")
+	}
+	rb, err := c.doTimeout(timeout+30*time.Second, http.MethodGet, "/api/v1/sessions/"+id+"/wait?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	err = json.Unmarshal(rb, &result)
+	return result, err
+}
+
+// SearchConversations searches agent conversation transcripts. project,
+// since and limit are optional filters (empty or 0 to skip).
+func (c *Client) SearchConversations(query, project, since string, fuzzy bool, limit int) (map[string]any, error) {
+	q := url.Values{"q": {query}}
+	if project != "" {
+		q.Set("project", project)
+	}
+	if since != "" {
+		q.Set("since", since)
+	}
+	if fuzzy {
+		q.Set("fuzzy", "true")
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var result map[string]any
+	err := c.slow(http.MethodGet, "/api/v1/search?"+q.Encode(), nil, &result)
+	return result, err
+}
+
 // StartSession starts a session (with optional initial message).
 func (c *Client) StartSession(id, message string) error {
 	body := map[string]string{}
@@ -152,12 +293,15 @@ func (c *Client) RestartSession(id string) error {
 	return c.post("/api/v1/sessions/"+id+"/restart", nil, nil)
 }
 
-// CreateSession creates a new session.
-func (c *Client) CreateSession(title, path, tool string) (map[string]any, error) {
-	body := map[string]string{"title": title, "project_path": path}
+// CreateSession creates a new session, optionally from a config.toml template.
+func (c *Client) CreateSession(title, path, tool, template string) (map[string]any, error) {
+	body := map[string]string{"title": title, "path": path}
 	if tool != "" {
 		body["tool"] = tool
 	}
+	if template != "" {
+		body["template"] = template
+	}
 	var result map[string]any
 	err := c.post("/api/v1/sessions", body, &result)
 	return result, err
@@ -195,3 +339,79 @@ func (c *Client) UpdateTodo(id string, fields map[string]any) error {
 func (c *Client) DeleteTodo(id string) error {
 	return c.del("/api/v1/todos/" + id)
 }
+
+// ListPRs returns the PR dashboard: all, mine, review_requested and
+// sessions (keyed by session ID).
+func (c *Client) ListPRs() (map[string]any, error) {
+	var result map[string]any
+	err := c.get("/api/v1/prs", &result)
+	return result, err
+}
+
+// prQuery returns the repo/number query string used by the /prs endpoints.
+func prQuery(repo string, number int) string {
+	return "?" + url.Values{"repo": {repo}, "number": {strconv.Itoa(number)}}.Encode()
+}
+
+// GetPRDetail returns a PR's comments, reviews, files and diff.
+func (c *Client) GetPRDetail(repo string, number int) (map[string]any, error) {
+	var result map[string]any
+	err := c.slow(http.MethodGet, "/api/v1/prs/detail"+prQuery(repo, number), nil, &result)
+	return result, err
+}
+
+// ReviewPR submits a review: action is approve, request_changes or comment.
+func (c *Client) ReviewPR(repo string, number int, action, body string) error {
+	req := map[string]string{"action": action, "body": body}
+	return c.slow(http.MethodPost, "/api/v1/prs/review"+prQuery(repo, number), req, nil)
+}
+
+// CommentPR adds a PR comment, inline when path and line are set.
+func (c *Client) CommentPR(repo string, number int, body, path string, line int) error {
+	req := map[string]any{"body": body, "path": path, "line": line}
+	return c.slow(http.MethodPost, "/api/v1/prs/comment"+prQuery(repo, number), req, nil)
+}
+
+// MergePR merges a PR, or with action "auto" or "disable_auto" turns
+// auto-merge on or off.
+func (c *Client) MergePR(repo string, number int, action, method string, deleteBranch, force bool) error {
+	req := map[string]any{"action": action, "method": method, "delete_branch": deleteBranch, "force": force}
+	return c.slow(http.MethodPost, "/api/v1/prs/merge"+prQuery(repo, number), req, nil)
+}
+
+// AddressPRFeedback sends a PR's unresolved review comments and failed
+// check logs to its session, starting a worktree session when none is
+// linked.
+func (c *Client) AddressPRFeedback(repo string, number int) (map[string]any, error) {
+	var result map[string]any
+	err := c.slow(http.MethodPost, "/api/v1/prs/feedback"+prQuery(repo, number), nil, &result)
+	return result, err
+}
+
+// GetSessionDiff returns a session's diff and its summary line.
+func (c *Client) GetSessionDiff(id string, summaryOnly bool) (map[string]any, error) {
+	path := "/api/v1/sessions/" + id + "/diff"
+	if summaryOnly {
+		path += "?summary=true"
+	}
+	var result map[string]any
+	err := c.slow(http.MethodGet, path, nil, &result)
+	return result, err
+}
+
+// FinishWorktree finishes a worktree session; fields is the JSON body of
+// POST /api/v1/sessions/{id}/finish.
+func (c *Client) FinishWorktree(id string, fields map[string]any) (map[string]any, error) {
+	var result map[string]any
+	err := c.slow(http.MethodPost, "/api/v1/sessions/"+id+"/finish", fields, &result)
+	return result, err
+}
+
+// ForkSession forks a session's conversation into a new session, in a new
+// worktree when branch is set.
+func (c *Client) ForkSession(id, title, group, branch string) (map[string]any, error) {
+	body := map[string]string{"title": title, "group": group, "branch": branch}
+	var result map[string]any
+	err := c.slow(http.MethodPost, "/api/v1/sessions/"+id+"/fork", body, &result)
+	return result, err
+}
@@ -1,28 +1,47 @@
 package mcpserver
 
 import (
+	"context"
+	"net/http"
+	"strings"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// Server is the Hangar MCP server, exposing session and todo tools over stdio.
+// Server is the Hangar MCP server, exposing session, todo, PR and worktree
+// tools over stdio (hangar mcp-server) or streamable HTTP (/mcp on the API
+// server).
 type Server struct {
 	mcpServer *server.MCPServer
 	client    *Client
 }
 
 // New creates a new MCP server backed by the Hangar REST API at baseURL.
-func New(baseURL, version string) *Server {
+// token supplies the bearer token for servers with [api] require_auth enabled.
+func New(baseURL string, token func() string, version string) *Server {
+	return newServer(NewClient(baseURL, token), version)
+}
+
+// NewInProcess creates an MCP server for mounting on the API server itself.
+// Tool calls are served by api directly instead of over the network.
+func NewInProcess(api http.Handler, version string) *Server {
+	return newServer(NewHandlerClient(api), version)
+}
+
+func newServer(client *Client, version string) *Server {
 	s := &Server{
 		mcpServer: server.NewMCPServer(
 			"hangar",
 			version,
 			server.WithToolCapabilities(true),
 		),
-		client: NewClient(baseURL),
+		client: client,
 	}
 	s.registerSessionTools()
 	s.registerTodoTools()
+	s.registerPRTools()
+	s.registerWorktreeTools()
 	return s
 }
 
@@ -31,6 +50,31 @@ func (s *Server) Run() error {
 	return server.ServeStdio(s.mcpServer)
 }
 
+// HTTPHandler returns the streamable HTTP transport, served at /mcp. Each
+// tool call is made with the bearer token of the MCP request, so the
+// caller's API key scope applies to every tool just as on the REST API.
+func (s *Server) HTTPHandler() http.Handler {
+	return server.NewStreamableHTTPServer(s.mcpServer,
+		server.WithEndpointPath("/mcp"),
+		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			return context.WithValue(ctx, tokenCtxKey{}, strings.TrimSpace(token))
+		}),
+	)
+}
+
+// tokenCtxKey carries the bearer token of an HTTP MCP request.
+type tokenCtxKey struct{}
+
+// api returns the REST client for a tool call: the caller's token for HTTP
+// requests, the server's own credentials for stdio.
+func (s *Server) api(ctx context.Context) *Client {
+	if token, ok := ctx.Value(tokenCtxKey{}).(string); ok {
+		return s.client.withToken(token)
+	}
+	return s.client
+}
+
 // addTool is a convenience for registering a tool on the underlying MCP server.
 func (s *Server) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
 	s.mcpServer.AddTool(tool, handler)
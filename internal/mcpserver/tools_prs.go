@@ -0,0 +1,248 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxDiffBytes is the default cap on diff text returned by a tool, so a large
+// change does not flood the agent's context.
+const maxDiffBytes = 50000
+
+func (s *Server) registerPRTools() {
+	s.addTool(
+		mcp.NewTool("hangar_list_prs",
+			mcp.WithDescription("List pull requests tracked by Hangar: session PRs, your PRs and PRs awaiting your review, with state, review decision and check counts"),
+			mcp.WithString("source", mcp.Description("all (default), mine, review_requested or sessions")),
+			mcp.WithString("state", mcp.Description("Only PRs in this state: OPEN, DRAFT, MERGED or CLOSED")),
+			mcp.WithBoolean("failing_checks", mcp.Description("Only PRs with at least one failing check")),
+		),
+		s.handleListPRs,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_pr_detail",
+			mcp.WithDescription("Get a pull request's comments, reviews, changed files and mergeability"),
+			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository as owner/repo (host/owner/repo for GitHub Enterprise)")),
+			mcp.WithNumber("number", mcp.Required(), mcp.Description("PR number")),
+			mcp.WithBoolean("include_diff", mcp.Description("Include the diff text (truncated to max_bytes)")),
+			mcp.WithNumber("max_bytes", mcp.Description("Maximum diff bytes to return (default 50000)")),
+		),
+		s.handlePRDetail,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_pr_review",
+			mcp.WithDescription("Submit a review on a pull request"),
+			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository as owner/repo")),
+			mcp.WithNumber("number", mcp.Required(), mcp.Description("PR number")),
+			mcp.WithString("action", mcp.Required(), mcp.Description("approve, request_changes or comment")),
+			mcp.WithString("body", mcp.Description("Review body (required for request_changes and comment)")),
+		),
+		s.handlePRReview,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_pr_comment",
+			mcp.WithDescription("Comment on a pull request, optionally on a specific file and line"),
+			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository as owner/repo")),
+			mcp.WithNumber("number", mcp.Required(), mcp.Description("PR number")),
+			mcp.WithString("body", mcp.Required(), mcp.Description("Comment text")),
+			mcp.WithString("path", mcp.Description("File path for an inline comment")),
+			mcp.WithNumber("line", mcp.Description("Line number for an inline comment")),
+		),
+		s.handlePRComment,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_pr_merge",
+			mcp.WithDescription("Merge a pull request, or turn auto-merge on or off. Refuses drafts and PRs with conflicts, and PRs with failing or pending checks unless force is set"),
+			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository as owner/repo")),
+			mcp.WithNumber("number", mcp.Required(), mcp.Description("PR number")),
+			mcp.WithString("action", mcp.Description("merge (default) merges now, auto enables auto-merge, disable_auto turns it off")),
+			mcp.WithString("method", mcp.Description("squash (default), merge or rebase")),
+			mcp.WithBoolean("delete_branch", mcp.Description("Delete the head branch after merging")),
+			mcp.WithBoolean("force", mcp.Description("Merge even though checks are failing or still running")),
+		),
+		s.handlePRMerge,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_pr_address_feedback",
+			mcp.WithDescription("Send a pull request's unresolved review comments and failed CI logs to the session working on it, starting a worktree session on the PR branch if none is linked"),
+			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository as owner/repo")),
+			mcp.WithNumber("number", mcp.Required(), mcp.Description("PR number")),
+		),
+		s.handlePRAddressFeedback,
+	)
+}
+
+func (s *Server) handleListPRs(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source := req.GetString("source", "all")
+	dashboard, err := s.api(ctx).ListPRs()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list PRs: %v", err)), nil
+	}
+	var prs []any
+	switch source {
+	case "all", "mine", "review_requested":
+		prs, _ = dashboard[source].([]any)
+	case "sessions":
+		bySession, _ := dashboard["sessions"].(map[string]any)
+		ids := make([]string, 0, len(bySession))
+		for id := range bySession {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			prs = append(prs, bySession[id])
+		}
+	default:
+		return mcp.NewToolResultError("source must be all, mine, review_requested or sessions"), nil
+	}
+	state := strings.ToUpper(req.GetString("state", ""))
+	failing := req.GetBool("failing_checks", false)
+
+	result := make([]any, 0, len(prs))
+	for _, p := range prs {
+		m, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if state != "" && prState(m) != state {
+			continue
+		}
+		if failing {
+			if n, _ := m["checks_failed"].(float64); n == 0 {
+				continue
+			}
+		}
+		result = append(result, m)
+	}
+	return jsonResult(result)
+}
+
+// prState returns a PR's state, reporting open drafts as DRAFT.
+func prState(m map[string]any) string {
+	state, _ := m["state"].(string)
+	if draft, _ := m["is_draft"].(bool); draft && state == "OPEN" {
+		return "DRAFT"
+	}
+	return state
+}
+
+// requirePR returns the repo and number arguments of a PR tool call.
+func requirePR(req mcp.CallToolRequest) (string, int, error) {
+	repo, err := req.RequireString("repo")
+	if err != nil {
+		return "", 0, err
+	}
+	number, err := req.RequireInt("number")
+	if err != nil {
+		return "", 0, err
+	}
+	if number <= 0 {
+		return "", 0, fmt.Errorf("number must be positive")
+	}
+	return repo, number, nil
+}
+
+func (s *Server) handlePRDetail(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repo, number, err := requirePR(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	detail, err := s.api(ctx).GetPRDetail(repo, number)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get PR detail: %v", err)), nil
+	}
+	if req.GetBool("include_diff", false) {
+		diff, _ := detail["diff_content"].(string)
+		detail["diff_content"] = truncateDiff(diff, req.GetInt("max_bytes", maxDiffBytes))
+	} else {
+		delete(detail, "diff_content")
+	}
+	return jsonResult(detail)
+}
+
+func (s *Server) handlePRReview(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repo, number, err := requirePR(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	action, err := req.RequireString("action")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.api(ctx).ReviewPR(repo, number, action, req.GetString("body", "")); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to review PR: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Review submitted on %s#%d", repo, number)), nil
+}
+
+func (s *Server) handlePRComment(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repo, number, err := requirePR(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	body, err := req.RequireString("body")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.api(ctx).CommentPR(repo, number, body, req.GetString("path", ""), req.GetInt("line", 0)); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to comment on PR: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Comment added to %s#%d", repo, number)), nil
+}
+
+func (s *Server) handlePRMerge(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repo, number, err := requirePR(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	action := req.GetString("action", "merge")
+	err = s.api(ctx).MergePR(repo, number, action, req.GetString("method", "squash"), req.GetBool("delete_branch", false), req.GetBool("force", false))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to merge PR: %v", err)), nil
+	}
+	switch action {
+	case "auto":
+		return mcp.NewToolResultText(fmt.Sprintf("Auto-merge enabled on %s#%d", repo, number)), nil
+	case "disable_auto":
+		return mcp.NewToolResultText(fmt.Sprintf("Auto-merge disabled on %s#%d", repo, number)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Merged %s#%d", repo, number)), nil
+}
+
+func (s *Server) handlePRAddressFeedback(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	repo, number, err := requirePR(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	result, err := s.api(ctx).AddressPRFeedback(repo, number)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to address PR feedback: %v", err)), nil
+	}
+	title, _ := result["session_title"].(string)
+	if created, _ := result["created"].(bool); created {
+		return mcp.NewToolResultText(fmt.Sprintf("Started session '%s' with the feedback on %s#%d", title, repo, number)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Sent the feedback on %s#%d to session '%s'", repo, number, title)), nil
+}
+
+// truncateDiff cuts diff to at most max bytes at a line boundary and notes
+// how much was left out.
+func truncateDiff(diff string, max int) string {
+	if max <= 0 || len(diff) <= max {
+		return diff
+	}
+	cut := strings.LastIndexByte(diff[:max], '\n') + 1
+	if cut == 0 {
+		cut = max
+	}
+	return diff[:cut] + fmt.Sprintf("\n[diff truncated: %d of %d bytes shown]\n", cut, len(diff))
+}
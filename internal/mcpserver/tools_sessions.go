@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -42,6 +44,41 @@ func (s *Server) registerSessionTools() {
 		s.handleSendMessage,
 	)
 
+	s.addTool(
+		mcp.NewTool("hangar_broadcast",
+			mcp.WithDescription("Send the same message to every session matching a selector (combined with AND) and report per-session delivery"),
+			mcp.WithString("message", mcp.Required(), mcp.Description("Message text to send")),
+			mcp.WithString("group", mcp.Description("Group path; includes its subgroups")),
+			mcp.WithString("status", mcp.Description("Session status: running, waiting, idle or error")),
+			mcp.WithString("project", mcp.Description("Project name from projects.toml")),
+			mcp.WithString("session_ids", mcp.Description("Comma-separated session IDs")),
+		),
+		s.handleBroadcast,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_wait_for_session",
+			mcp.WithDescription("Wait until a session reaches a status (by default waiting, idle or error, i.e. the agent has stopped working) and return the final status and the agent's last response. Use after hangar_send_message instead of polling"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Session ID")),
+			mcp.WithString("status", mcp.Description("Comma-separated statuses to wait for, e.g. \"waiting,idle\"")),
+			mcp.WithNumber("timeout_seconds", mcp.Description("Give up after this many seconds (default 600, max 3600)")),
+			mcp.WithBoolean("changed", mcp.Description("Ignore a matching status until the session has left it; set this right after sending a prompt")),
+		),
+		s.handleWaitForSession,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_search_conversations",
+			mcp.WithDescription("Search past Claude, Gemini, Codex and OpenCode conversations across all projects, e.g. to find the conversation where a bug was fixed. Returns matching transcripts with the agent, a snippet, the working directory and, when a Hangar session owns the conversation, its session ID"),
+			mcp.WithString("query", mcp.Required(), mcp.Description("Text to search for")),
+			mcp.WithString("project", mcp.Description("Only conversations in this project (name from projects.toml, or a directory)")),
+			mcp.WithString("since", mcp.Description("Only conversations active since then: a duration like 24h or 7d, \"today\", YYYY-MM-DD or RFC 3339")),
+			mcp.WithBoolean("fuzzy", mcp.Description("Typo-tolerant matching instead of exact substring")),
+			mcp.WithNumber("limit", mcp.Description("Maximum results (default 20)")),
+		),
+		s.handleSearchConversations,
+	)
+
 	s.addTool(
 		mcp.NewTool("hangar_start_session",
 			mcp.WithDescription("Start a stopped session, optionally with an initial message"),
@@ -72,46 +109,47 @@ func (s *Server) registerSessionTools() {
 			mcp.WithDescription("Create a new Hangar session"),
 			mcp.WithString("title", mcp.Required(), mcp.Description("Session title")),
 			mcp.WithString("path", mcp.Required(), mcp.Description("Project path for the session")),
-			mcp.WithString("tool", mcp.Description("Tool to use (default: claude)")),
+			mcp.WithString("tool", mcp.Description("Tool to use (default: claude, or the template's tool)")),
+			mcp.WithString("template", mcp.Description("Session template from config.toml [templates.<name>]: sets tool, Claude options, MCPs, worktree, group and initial prompt")),
 		),
 		s.handleCreateSession,
 	)
 }
 
-func (s *Server) handleListSessions(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	sessions, err := s.client.ListSessions()
+func (s *Server) handleListSessions(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessions, err := s.api(ctx).ListSessions()
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to list sessions: %v", err)), nil
 	}
 	return jsonResult(sessions)
 }
 
-func (s *Server) handleGetSession(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleGetSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	session, err := s.client.GetSession(id)
+	session, err := s.api(ctx).GetSession(id)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get session: %v", err)), nil
 	}
 	return jsonResult(session)
 }
 
-func (s *Server) handleGetOutput(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleGetOutput(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	lines := req.GetInt("lines", 50)
-	output, err := s.client.GetSessionOutput(id, lines)
+	output, err := s.api(ctx).GetSessionOutput(id, lines)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get output: %v", err)), nil
 	}
 	return jsonResult(output)
 }
 
-func (s *Server) handleSendMessage(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleSendMessage(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -120,47 +158,91 @@ func (s *Server) handleSendMessage(_ context.Context, req mcp.CallToolRequest) (
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := s.client.SendMessage(id, message); err != nil {
+	if err := s.api(ctx).SendMessage(id, message); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to send message: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Message sent successfully"), nil
 }
 
-func (s *Server) handleStartSession(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleBroadcast(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	message, err := req.RequireString("message")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var ids []string
+	for _, id := range strings.Split(req.GetString("session_ids", ""), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	result, err := s.api(ctx).Broadcast(message, ids,
+		req.GetString("group", ""), req.GetString("status", ""), req.GetString("project", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to broadcast: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleWaitForSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	timeout := time.Duration(req.GetInt("timeout_seconds", 600)) * time.Second
+	result, err := s.api(ctx).WaitForSession(id, req.GetString("status", ""), timeout, req.GetBool("changed", false))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to wait for session: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleSearchConversations(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := req.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	result, err := s.api(ctx).SearchConversations(query, req.GetString("project", ""), req.GetString("since", ""), req.GetBool("fuzzy", false), req.GetInt("limit", 0))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search conversations: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleStartSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	message := req.GetString("message", "")
-	if err := s.client.StartSession(id, message); err != nil {
+	if err := s.api(ctx).StartSession(id, message); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to start session: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Session started"), nil
 }
 
-func (s *Server) handleStopSession(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleStopSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := s.client.StopSession(id); err != nil {
+	if err := s.api(ctx).StopSession(id); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to stop session: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Session stopped"), nil
 }
 
-func (s *Server) handleRestartSession(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleRestartSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := s.client.RestartSession(id); err != nil {
+	if err := s.api(ctx).RestartSession(id); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to restart session: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Session restarted"), nil
 }
 
-func (s *Server) handleCreateSession(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleCreateSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	title, err := req.RequireString("title")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -170,7 +252,8 @@ func (s *Server) handleCreateSession(_ context.Context, req mcp.CallToolRequest)
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	tool := req.GetString("tool", "")
-	session, err := s.client.CreateSession(title, path, tool)
+	template := req.GetString("template", "")
+	session, err := s.api(ctx).CreateSession(title, path, tool, template)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to create session: %v", err)), nil
 	}
@@ -3,6 +3,7 @@ package mcpserver
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -28,11 +29,13 @@ func (s *Server) registerTodoTools() {
 
 	s.addTool(
 		mcp.NewTool("hangar_update_todo",
-			mcp.WithDescription("Update a todo item's status, title, or description"),
+			mcp.WithDescription("Update a todo item's status, title, description or dependencies"),
 			mcp.WithString("id", mcp.Required(), mcp.Description("Todo ID")),
 			mcp.WithString("status", mcp.Description("New status (todo, doing, done)")),
 			mcp.WithString("title", mcp.Description("New title")),
 			mcp.WithString("description", mcp.Description("New description")),
+			mcp.WithString("blocked_by", mcp.Description("Comma-separated IDs of todos in the same project that must be done first (empty string clears)")),
+			mcp.WithBoolean("auto_start", mcp.Description("Start a worktree session with the todo's prompt automatically once it is unblocked")),
 		),
 		s.handleUpdateTodo,
 	)
@@ -46,16 +49,16 @@ func (s *Server) registerTodoTools() {
 	)
 }
 
-func (s *Server) handleListTodos(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleListTodos(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	project := req.GetString("project", "")
-	todos, err := s.client.ListTodos(project)
+	todos, err := s.api(ctx).ListTodos(project)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to list todos: %v", err)), nil
 	}
 	return jsonResult(todos)
 }
 
-func (s *Server) handleCreateTodo(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleCreateTodo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	project, err := req.RequireString("project")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -65,14 +68,14 @@ func (s *Server) handleCreateTodo(_ context.Context, req mcp.CallToolRequest) (*
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	description := req.GetString("description", "")
-	todo, err := s.client.CreateTodo(project, title, description)
+	todo, err := s.api(ctx).CreateTodo(project, title, description)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to create todo: %v", err)), nil
 	}
 	return jsonResult(todo)
 }
 
-func (s *Server) handleUpdateTodo(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleUpdateTodo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
@@ -87,21 +90,33 @@ func (s *Server) handleUpdateTodo(_ context.Context, req mcp.CallToolRequest) (*
 	if v := req.GetString("description", ""); v != "" {
 		fields["description"] = v
 	}
+	if v, ok := req.GetArguments()["blocked_by"].(string); ok {
+		ids := []string{}
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		fields["blocked_by"] = ids
+	}
+	if v, ok := req.GetArguments()["auto_start"].(bool); ok {
+		fields["auto_start"] = v
+	}
 	if len(fields) == 0 {
 		return mcp.NewToolResultError("no fields to update"), nil
 	}
-	if err := s.client.UpdateTodo(id, fields); err != nil {
+	if err := s.api(ctx).UpdateTodo(id, fields); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to update todo: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Todo updated"), nil
 }
 
-func (s *Server) handleDeleteTodo(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleDeleteTodo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id, err := req.RequireString("id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := s.client.DeleteTodo(id); err != nil {
+	if err := s.api(ctx).DeleteTodo(id); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to delete todo: %v", err)), nil
 	}
 	return mcp.NewToolResultText("Todo deleted"), nil
@@ -0,0 +1,98 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) registerWorktreeTools() {
+	s.addTool(
+		mcp.NewTool("hangar_get_diff",
+			mcp.WithDescription("Show what a session changed: its branch's commits since the base branch plus uncommitted changes, with a summary line like \"3 files, +40 -12\""),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Session ID")),
+			mcp.WithBoolean("summary_only", mcp.Description("Return only the summary line")),
+			mcp.WithNumber("max_bytes", mcp.Description("Maximum diff bytes to return (default 50000)")),
+		),
+		s.handleGetDiff,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_finish_worktree",
+			mcp.WithDescription("Finish a worktree session. By default merges the branch locally into the target branch, then removes the worktree, branch and session; set pr to push and open a pull request instead (cleanup happens once it merges). A branch with unmerged commits is kept unless discard is set"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Session ID")),
+			mcp.WithBoolean("pr", mcp.Description("Push the branch and open a PR; the worktree and session are removed once it merges")),
+			mcp.WithBoolean("no_merge", mcp.Description("Skip the local merge; an unmerged branch is then kept unless discard is set")),
+			mcp.WithString("into", mcp.Description("Target branch for the PR or merge (default: the repository's default branch)")),
+			mcp.WithBoolean("auto_merge", mcp.Description("With pr, enable auto-merge")),
+			mcp.WithString("merge_method", mcp.Description("With auto_merge: merge, squash (default) or rebase")),
+			mcp.WithBoolean("keep_branch", mcp.Description("Keep the local branch")),
+			mcp.WithBoolean("force", mcp.Description("Finish even with uncommitted changes")),
+			mcp.WithBoolean("discard", mcp.Description("Delete the branch even if its commits are not merged; they are lost")),
+		),
+		s.handleFinishWorktree,
+	)
+
+	s.addTool(
+		mcp.NewTool("hangar_fork_session",
+			mcp.WithDescription("Fork a Claude or OpenCode session into a new session that continues the same conversation, optionally in a new git worktree"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Session ID to fork")),
+			mcp.WithString("title", mcp.Description("Title for the fork (default: \"<title> (fork)\")")),
+			mcp.WithString("group", mcp.Description("Group path for the fork (default: the source session's group)")),
+			mcp.WithString("branch", mcp.Description("Create the fork in a new worktree on this branch (created if it does not exist)")),
+		),
+		s.handleForkSession,
+	)
+}
+
+func (s *Server) handleGetDiff(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	diff, err := s.api(ctx).GetSessionDiff(id, req.GetBool("summary_only", false))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get diff: %v", err)), nil
+	}
+	if raw, ok := diff["diff"].(string); ok {
+		diff["diff"] = truncateDiff(raw, req.GetInt("max_bytes", maxDiffBytes))
+	}
+	return jsonResult(diff)
+}
+
+func (s *Server) handleFinishWorktree(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	fields := map[string]any{}
+	for _, key := range []string{"pr", "no_merge", "auto_merge", "keep_branch", "force", "discard"} {
+		if v := req.GetBool(key, false); v {
+			fields[key] = v
+		}
+	}
+	for _, key := range []string{"into", "merge_method"} {
+		if v := req.GetString(key, ""); v != "" {
+			fields[key] = v
+		}
+	}
+	result, err := s.api(ctx).FinishWorktree(id, fields)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to finish worktree: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleForkSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	session, err := s.api(ctx).ForkSession(id,
+		req.GetString("title", ""), req.GetString("group", ""), req.GetString("branch", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fork session: %v", err)), nil
+	}
+	return jsonResult(session)
+}
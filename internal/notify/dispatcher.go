@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+var notifyLog = logging.ForComponent(logging.CompNotif)
+
+// maxDeliveryLogSize is the size at which the delivery log is rotated to
+// notifications.log.1, replacing the previous one.
+const maxDeliveryLogSize = 5 << 20
+
+// Dispatcher fans events out to the configured sinks. Deliveries run in the
+// background, are retried with exponential backoff and are recorded in the
+// delivery log.
+type Dispatcher struct {
+	sinks   []session.NotificationSink
+	logPath string
+	logMax  int64 // rotate the delivery log once it reaches this size
+	client  *http.Client
+	backoff time.Duration // delay before the first retry; doubles each time
+
+	mu sync.Mutex // serialises delivery log writes
+	wg sync.WaitGroup
+}
+
+// New creates a Dispatcher for sinks.
+func New(sinks []session.NotificationSink) *Dispatcher {
+	return &Dispatcher{
+		sinks:   sinks,
+		logPath: deliveryLogPath(),
+		logMax:  maxDeliveryLogSize,
+		client:  &http.Client{},
+		backoff: 2 * time.Second,
+	}
+}
+
+// FromConfig creates a Dispatcher for the sinks in config.toml.
+func FromConfig() *Dispatcher {
+	return New(session.GetNotificationsSettings().Sinks)
+}
+
+// HasSinks reports whether any sink is configured.
+func (d *Dispatcher) HasSinks() bool {
+	return len(d.sinks) > 0
+}
+
+// Send delivers ev to every matching sink in the background.
+func (d *Dispatcher) Send(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	for _, sink := range d.sinks {
+		if !sinkMatches(sink, ev) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(sink session.NotificationSink) {
+			defer d.wg.Done()
+			d.deliverWithRetry(sink, ev)
+		}(sink)
+	}
+}
+
+// Test delivers ev to every sink synchronously, ignoring their filters, and
+// returns the outcome per sink name (nil on success).
+func (d *Dispatcher) Test(ev Event) map[string]error {
+	results := make(map[string]error, len(d.sinks))
+	for _, sink := range d.sinks {
+		results[sink.GetName()] = d.deliverWithRetry(sink, ev)
+	}
+	return results
+}
+
+// Wait blocks until all pending deliveries have finished.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// deliverWithRetry tries a delivery up to 1+retries times and returns the
+// last error.
+func (d *Dispatcher) deliverWithRetry(sink session.NotificationSink, ev Event) error {
+	var err error
+	attempts := 1 + sink.GetRetries()
+	delay := d.backoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sink.GetTimeout())
+		err = deliver(ctx, d.client, sink, ev)
+		cancel()
+		if err == nil {
+			d.logDelivery(sink, ev, attempt, nil)
+			return nil
+		}
+		if attempt < attempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	notifyLog.Warn("sink_delivery_failed",
+		slog.String("sink", sink.GetName()),
+		slog.String("event", ev.Type),
+		slog.String("error", err.Error()))
+	d.logDelivery(sink, ev, attempts, err)
+	return err
+}
+
+// sinkMatches applies the sink's events and statuses filters to ev.
+func sinkMatches(sink session.NotificationSink, ev Event) bool {
+	if len(sink.Events) > 0 && !containsFold(sink.Events, ev.Type) {
+		return false
+	}
+	if ev.Type == EventStatus && len(sink.Statuses) > 0 && !containsFold(sink.Statuses, ev.Status) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliveryRecord is one line of the delivery log.
+type deliveryRecord struct {
+	Time     time.Time `json:"time"`
+	Sink     string    `json:"sink"`
+	Event    string    `json:"event"`
+	Title    string    `json:"title"`
+	Attempts int       `json:"attempts"`
+	OK       bool      `json:"ok"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (d *Dispatcher) logDelivery(sink session.NotificationSink, ev Event, attempts int, err error) {
+	rec := deliveryRecord{
+		Time:     time.Now(),
+		Sink:     sink.GetName(),
+		Event:    ev.Type,
+		Title:    ev.Title,
+		Attempts: attempts,
+		OK:       err == nil,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	line, mErr := json.Marshal(rec)
+	if mErr != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(d.logPath), 0o755); err != nil {
+		return
+	}
+	if info, err := os.Stat(d.logPath); err == nil && info.Size() >= d.logMax {
+		_ = os.Rename(d.logPath, d.logPath+".1")
+	}
+	f, err := os.OpenFile(d.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(line, '\n'))
+}
+
+func deliveryLogPath() string {
+	dir, err := session.GetHangarDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), ".hangar", "logs", "notifications.log")
+	}
+	return filepath.Join(dir, "logs", "notifications.log")
+}
@@ -0,0 +1,104 @@
+// Package notify delivers Hangar events to the outgoing notification sinks
+// configured under [[notifications.sinks]]: HTTP webhooks, ntfy and Gotify
+// servers, and shell commands. Events are session status transitions, PR
+// state changes and todo completions.
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// Event types, as used in a sink's events filter.
+const (
+	EventStatus = "status"
+	EventPR     = "pr"
+	EventTodo   = "todo"
+)
+
+// Event is what sinks receive. Webhook templates see these fields; command
+// sinks get it as JSON on stdin.
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Title   string    `json:"title"`   // short headline
+	Message string    `json:"message"` // one-line description
+	URL     string    `json:"url,omitempty"`
+
+	// Status events
+	Profile    string `json:"profile,omitempty"`
+	SessionID  string `json:"session_id,omitempty"`
+	Session    string `json:"session,omitempty"` // session title
+	FromStatus string `json:"from_status,omitempty"`
+	Status     string `json:"status,omitempty"`
+
+	// PR events
+	Repo     string `json:"repo,omitempty"`
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRState  string `json:"pr_state,omitempty"`
+
+	// Todo events
+	TodoID  string `json:"todo_id,omitempty"`
+	Todo    string `json:"todo,omitempty"` // todo title
+	Project string `json:"project,omitempty"`
+}
+
+// StatusEvent builds an event for a session status transition.
+func StatusEvent(e session.TransitionNotificationEvent) Event {
+	return Event{
+		Type:       EventStatus,
+		Time:       e.Timestamp,
+		Title:      fmt.Sprintf("%s is %s", e.ChildTitle, e.ToStatus),
+		Message:    fmt.Sprintf("Session '%s' went from %s to %s", e.ChildTitle, e.FromStatus, e.ToStatus),
+		Profile:    e.Profile,
+		SessionID:  e.ChildSessionID,
+		Session:    e.ChildTitle,
+		FromStatus: e.FromStatus,
+		Status:     e.ToStatus,
+	}
+}
+
+// PREvent builds an event for a session PR that changed to p.State.
+func PREvent(p *pr.PR) Event {
+	return Event{
+		Type:      EventPR,
+		Time:      time.Now(),
+		Title:     fmt.Sprintf("PR #%d %s", p.Number, prStateVerb(p.State)),
+		Message:   fmt.Sprintf("%s #%d %s: %s", p.Repo, p.Number, prStateVerb(p.State), p.Title),
+		URL:       p.URL,
+		SessionID: p.SessionID,
+		Repo:      p.Repo,
+		PRNumber:  p.Number,
+		PRState:   p.State,
+	}
+}
+
+// TodoEvent builds an event for a todo that moved to done.
+func TodoEvent(t *session.Todo) Event {
+	return Event{
+		Type:      EventTodo,
+		Time:      time.Now(),
+		Title:     "Todo done",
+		Message:   fmt.Sprintf("Todo done: %s", t.Title),
+		SessionID: t.SessionID,
+		TodoID:    t.ID,
+		Todo:      t.Title,
+		Project:   t.ProjectPath,
+	}
+}
+
+func prStateVerb(state string) string {
+	switch state {
+	case "MERGED":
+		return "merged"
+	case "CLOSED":
+		return "closed"
+	case "DRAFT":
+		return "opened as draft"
+	default:
+		return "opened"
+	}
+}
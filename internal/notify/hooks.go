@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// Enable registers d for the session transitions and todo completions seen
+// by this process. It does nothing if no sink is configured.
+func (d *Dispatcher) Enable() {
+	if !d.HasSinks() {
+		return
+	}
+	session.RegisterOnTransition(func(e session.TransitionNotificationEvent) {
+		d.Send(StatusEvent(e))
+	})
+	session.RegisterOnTodoDone(func(t *session.Todo) {
+		d.Send(TodoEvent(t))
+	})
+}
+
+// WatchPRs sends a pr event whenever a session's PR changes state until ctx
+// is cancelled. The first state seen for each session is only recorded, so
+// starting up does not replay every open PR. The TUI and "hangar web" both
+// watch the same sessions' PRs, so events are only sent while isLeader (if
+// non-nil) reports true; otherwise they are recorded and dropped.
+func (d *Dispatcher) WatchPRs(ctx context.Context, m *pr.Manager, isLeader func() bool) {
+	if !d.HasSinks() {
+		return
+	}
+	w := &prWatcher{seen: make(map[string]string)}
+	kick := make(chan struct{}, 1)
+	m.RegisterOnChange(func() {
+		select {
+		case kick <- struct{}{}:
+		default: // already queued
+		}
+	})
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-kick:
+				events := w.changes(m.GetSessionPRs())
+				if len(events) == 0 || (isLeader != nil && !isLeader()) {
+					continue
+				}
+				for _, ev := range events {
+					d.Send(ev)
+				}
+			}
+		}
+	}()
+}
+
+// prWatcher tracks the last PR state seen per session.
+type prWatcher struct {
+	mu   sync.Mutex
+	seen map[string]string // sessionID -> PR state
+}
+
+// changes returns events for session PRs whose state differs from the last
+// one seen, and records the new states.
+func (w *prWatcher) changes(prs map[string]*pr.PR) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var events []Event
+	for sid, p := range prs {
+		state := "" // no PR yet
+		if p != nil {
+			state = p.State
+		}
+		prev, known := w.seen[sid]
+		w.seen[sid] = state
+		if known && p != nil && prev != state {
+			events = append(events, PREvent(p))
+		}
+	}
+	return events
+}
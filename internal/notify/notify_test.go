@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/pr"
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+func testEvent() Event {
+	return StatusEvent(session.TransitionNotificationEvent{
+		ChildSessionID: "abc",
+		ChildTitle:     "api-fix",
+		Profile:        "default",
+		FromStatus:     "running",
+		ToStatus:       "waiting",
+		Timestamp:      time.Now(),
+	})
+}
+
+func TestDeliver_HTTPSinks(t *testing.T) {
+	type request struct {
+		path    string
+		headers http.Header
+		body    string
+	}
+	var got request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = request{path: r.URL.Path, headers: r.Header, body: string(body)}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		sink  session.NotificationSink
+		check func(t *testing.T, r request)
+	}{
+		{
+			name: "webhook template",
+			sink: session.NotificationSink{Type: "webhook", URL: srv.URL, Token: "secret",
+				Body: `{"text": {{json .Message}}}`, Headers: map[string]string{"X-Team": "core"}},
+			check: func(t *testing.T, r request) {
+				if r.body != `{"text": "Session 'api-fix' went from running to waiting"}` {
+					t.Errorf("body = %s", r.body)
+				}
+				if r.headers.Get("Authorization") != "Bearer secret" || r.headers.Get("X-Team") != "core" {
+					t.Errorf("headers = %v", r.headers)
+				}
+			},
+		},
+		{
+			name: "webhook default body",
+			sink: session.NotificationSink{Type: "webhook", URL: srv.URL},
+			check: func(t *testing.T, r request) {
+				var ev Event
+				if err := json.Unmarshal([]byte(r.body), &ev); err != nil || ev.SessionID != "abc" || ev.Status != "waiting" {
+					t.Errorf("body = %s (%v)", r.body, err)
+				}
+			},
+		},
+		{
+			name: "ntfy",
+			sink: session.NotificationSink{Type: "ntfy", URL: srv.URL + "/topic"},
+			check: func(t *testing.T, r request) {
+				if r.path != "/topic" || r.headers.Get("Title") != "api-fix is waiting" || r.headers.Get("Priority") != "high" {
+					t.Errorf("ntfy request = %+v", r)
+				}
+			},
+		},
+		{
+			name: "gotify",
+			sink: session.NotificationSink{Type: "gotify", URL: srv.URL + "/", Token: "app"},
+			check: func(t *testing.T, r request) {
+				if r.path != "/message" || r.headers.Get("X-Gotify-Key") != "app" || !strings.Contains(r.body, `"title":"api-fix is waiting"`) {
+					t.Errorf("gotify request = %+v", r)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = request{}
+			if err := deliver(context.Background(), srv.Client(), tt.sink, testEvent()); err != nil {
+				t.Fatalf("deliver: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestDeliver_Command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event.json")
+	sink := session.NotificationSink{Type: "command", Command: `cat > "` + out + `"; test "$HANGAR_EVENT_TYPE" = status`}
+	if err := deliver(context.Background(), nil, sink, testEvent()); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || !strings.Contains(string(data), `"session":"api-fix"`) {
+		t.Errorf("stdin = %s (%v)", data, err)
+	}
+
+	sink.Command = "echo boom >&2; exit 3"
+	if err := deliver(context.Background(), nil, sink, testEvent()); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("failing command error = %v", err)
+	}
+}
+
+func TestDispatcher_RetryAndLog(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	retries := 1
+	d := New([]session.NotificationSink{
+		{Name: "flaky", Type: "webhook", URL: srv.URL},
+		{Name: "broken", Type: "webhook", URL: srv.URL + "/x", Retries: &retries},
+		{Name: "prs-only", Type: "webhook", URL: srv.URL, Events: []string{EventPR}},
+	})
+	d.logPath = filepath.Join(t.TempDir(), "notifications.log")
+	d.backoff = time.Millisecond
+
+	// The server fails twice, then succeeds: within "flaky"'s 3 retries.
+	if err := d.deliverWithRetry(d.sinks[0], testEvent()); err != nil {
+		t.Fatalf("flaky sink should succeed after retries: %v", err)
+	}
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	if err := d.deliverWithRetry(d.sinks[1], testEvent()); err == nil || !strings.Contains(err.Error(), "410") {
+		t.Fatalf("broken sink error = %v", err)
+	}
+
+	data, err := os.ReadFile(d.logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2:\n%s", len(lines), data)
+	}
+	var first, second deliveryRecord
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	if !first.OK || first.Attempts != 3 || first.Sink != "flaky" {
+		t.Errorf("first record = %+v", first)
+	}
+	if second.OK || second.Attempts != 2 || second.Error == "" {
+		t.Errorf("second record = %+v", second)
+	}
+
+	// A full log is rotated before the next record.
+	d.logMax = int64(len(data))
+	d.logDelivery(d.sinks[0], testEvent(), 1, nil)
+	if rotated, err := os.ReadFile(d.logPath + ".1"); err != nil || len(rotated) != len(data) {
+		t.Errorf("rotated log = %d bytes, %v; want the full log", len(rotated), err)
+	}
+	if data, _ := os.ReadFile(d.logPath); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("log after rotation:\n%s", data)
+	}
+
+	if sinkMatches(d.sinks[2], testEvent()) {
+		t.Error("pr-only sink should not match a status event")
+	}
+	if sinkMatches(session.NotificationSink{Statuses: []string{"error"}}, testEvent()) {
+		t.Error("error-only sink should not match a waiting event")
+	}
+}
+
+func TestPRWatcher_Changes(t *testing.T) {
+	w := &prWatcher{seen: make(map[string]string)}
+	open := &pr.PR{Number: 7, Title: "Fix login", State: "OPEN", Repo: "o/r", SessionID: "s1"}
+
+	if evs := w.changes(map[string]*pr.PR{"s1": open, "s2": nil}); len(evs) != 0 {
+		t.Fatalf("first observation produced events: %+v", evs)
+	}
+	opened := &pr.PR{Number: 8, Title: "Add API", State: "OPEN", Repo: "o/r", SessionID: "s2"}
+	merged := *open
+	merged.State = "MERGED"
+	evs := w.changes(map[string]*pr.PR{"s1": &merged, "s2": opened})
+	if len(evs) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(evs), evs)
+	}
+	titles := evs[0].Title + "|" + evs[1].Title
+	if !strings.Contains(titles, "PR #7 merged") || !strings.Contains(titles, "PR #8 opened") {
+		t.Errorf("titles = %s", titles)
+	}
+	if evs := w.changes(map[string]*pr.PR{"s1": &merged, "s2": opened}); len(evs) != 0 {
+		t.Errorf("unchanged PRs produced events: %+v", evs)
+	}
+}
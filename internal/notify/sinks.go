@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// deliver sends ev to sink once.
+func deliver(ctx context.Context, client *http.Client, sink session.NotificationSink, ev Event) error {
+	switch sink.Type {
+	case "webhook":
+		body, err := webhookBody(sink.Body, ev)
+		if err != nil {
+			return err
+		}
+		return post(ctx, client, sink, sink.URL, "application/json", body, nil)
+	case "ntfy":
+		headers := map[string]string{"Title": ev.Title, "Tags": ev.Type}
+		if ev.URL != "" {
+			headers["Click"] = ev.URL
+		}
+		if ev.Status == string(session.StatusWaiting) || ev.Status == string(session.StatusError) {
+			headers["Priority"] = "high"
+		}
+		return post(ctx, client, sink, sink.URL, "text/plain", []byte(ev.Message), headers)
+	case "gotify":
+		body, _ := json.Marshal(map[string]any{"title": ev.Title, "message": ev.Message, "priority": 5})
+		url := strings.TrimRight(sink.URL, "/") + "/message"
+		return post(ctx, client, sink, url, "application/json", body, map[string]string{"X-Gotify-Key": sink.Token})
+	case "command":
+		return runCommand(ctx, sink.Command, ev)
+	default:
+		return fmt.Errorf("unknown sink type '%s' (want webhook, ntfy, gotify or command)", sink.Type)
+	}
+}
+
+// webhookBody renders the sink's body template, or the event as JSON when
+// no template is set.
+func webhookBody(tmpl string, ev Event) ([]byte, error) {
+	if tmpl == "" {
+		return json.Marshal(ev)
+	}
+	t, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func post(ctx context.Context, client *http.Client, sink session.NotificationSink, url, contentType string, body []byte, headers map[string]string) error {
+	if url == "" {
+		return fmt.Errorf("url is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if sink.Token != "" && sink.Type != "gotify" {
+		req.Header.Set("Authorization", "Bearer "+sink.Token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range sink.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// runCommand runs command with the event JSON on stdin. HANGAR_EVENT_TYPE,
+// HANGAR_EVENT_TITLE and HANGAR_EVENT_MESSAGE are set for simple scripts.
+func runCommand(ctx context.Context, command string, ev Event) error {
+	if command == "" {
+		return fmt.Errorf("command is required")
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"HANGAR_EVENT_TYPE="+ev.Type,
+		"HANGAR_EVENT_TITLE="+ev.Title,
+		"HANGAR_EVENT_MESSAGE="+ev.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
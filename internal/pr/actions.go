@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -64,6 +65,111 @@ func ConvertToDraft(ghPath, repo string, number int) error {
 	return runGH(ghPath, repo, args)
 }
 
+// Create opens a PR from head into base for the repository checked out in dir
+// and returns its URL and number.
+func Create(ghPath, dir, base, head, title, body string) (url string, number int, err error) {
+	cmd := exec.Command(ghPath, "pr", "create",
+		"--base", base, "--head", head, "--title", title, "--body", body)
+	cmd.Dir = dir
+	if host := ghHostFromDir(dir); host != "" && host != "github.com" {
+		cmd.Env = append(os.Environ(), "GH_HOST="+host)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", 0, fmt.Errorf("gh pr create: %w\n%s", err, string(out))
+	}
+	// gh prints the new PR's URL as the last line of output.
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	url = strings.TrimSpace(lines[len(lines)-1])
+	number, err = strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return url, 0, fmt.Errorf("gh pr create: unexpected output %q", url)
+	}
+	return url, number, nil
+}
+
+// MergeMethods are the ways a PR can be merged, named as gh pr merge's flags.
+var MergeMethods = []string{"squash", "merge", "rebase"}
+
+// ValidMergeMethod reports whether method is one of MergeMethods.
+func ValidMergeMethod(method string) bool {
+	for _, m := range MergeMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge merges the PR now. method is "merge", "squash" or "rebase";
+// deleteBranch deletes the head branch once it is merged.
+func Merge(ghPath, repo string, number int, method string, deleteBranch bool) error {
+	args := []string{"pr", "merge", itoa(number), "--repo", repoArg(repo), "--" + method}
+	if deleteBranch {
+		args = append(args, "--delete-branch")
+	}
+	return runGH(ghPath, repo, args)
+}
+
+// EnableAutoMerge turns on auto-merge for the PR so GitHub merges it once
+// required reviews and checks pass. method is "merge", "squash" or "rebase";
+// deleteBranch deletes the head branch after the merge.
+func EnableAutoMerge(ghPath, repo string, number int, method string, deleteBranch bool) error {
+	args := []string{"pr", "merge", itoa(number), "--repo", repoArg(repo), "--auto", "--" + method}
+	if deleteBranch {
+		args = append(args, "--delete-branch")
+	}
+	return runGH(ghPath, repo, args)
+}
+
+// DisableAutoMerge turns auto-merge off again.
+func DisableAutoMerge(ghPath, repo string, number int) error {
+	args := []string{"pr", "merge", itoa(number), "--repo", repoArg(repo), "--disable-auto"}
+	return runGH(ghPath, repo, args)
+}
+
+// MergeCheck reports whether the PR in d can be merged, now or with
+// auto-merge when auto is set. blocker is why it cannot be: it is not open,
+// is a draft or has conflicts. warning is a reason to confirm first: failing
+// checks, or, for an immediate merge, pending checks or branch protection
+// that GitHub's auto-merge would wait out.
+func MergeCheck(d *PRDetail, auto bool) (blocker, warning string) {
+	switch {
+	case d.State == "MERGED":
+		return "PR is already merged", ""
+	case d.State == "CLOSED":
+		return "PR is closed", ""
+	case d.IsDraft || d.State == "DRAFT":
+		return "PR is a draft; mark it ready first", ""
+	case d.Mergeability == "CONFLICTING":
+		return "PR has merge conflicts with " + d.BaseBranch, ""
+	}
+	var warnings []string
+	if d.ChecksFailed > 0 {
+		warnings = append(warnings, plural(d.ChecksFailed, "check")+" failing")
+	}
+	if !auto {
+		if d.ChecksPending > 0 {
+			warnings = append(warnings, plural(d.ChecksPending, "check")+" still running")
+		}
+		switch d.MergeState {
+		case "BLOCKED":
+			warnings = append(warnings, "branch protection requirements are not met")
+		case "BEHIND":
+			warnings = append(warnings, "branch is behind "+d.BaseBranch)
+		}
+	}
+	return "", strings.Join(warnings, "; ")
+}
+
+// plural formats n with noun, adding an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return itoa(n) + " " + noun + "s"
+}
+
 // runGH executes a gh command, setting GH_HOST if the repo is on a GHE instance.
 func runGH(ghPath, repo string, args []string) error {
 	cmd := exec.Command(ghPath, args...)
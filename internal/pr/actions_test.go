@@ -0,0 +1,33 @@
+package pr
+
+import "testing"
+
+func TestMergeCheck(t *testing.T) {
+	open := func(mod func(d *PRDetail)) *PRDetail {
+		d := &PRDetail{PR: PR{State: "OPEN", BaseBranch: "main"}, Mergeability: "MERGEABLE", MergeState: "CLEAN"}
+		if mod != nil {
+			mod(d)
+		}
+		return d
+	}
+	tests := []struct {
+		name             string
+		detail           *PRDetail
+		auto             bool
+		blocker, warning string
+	}{
+		{"clean", open(nil), false, "", ""},
+		{"merged", open(func(d *PRDetail) { d.State = "MERGED" }), false, "PR is already merged", ""},
+		{"draft", open(func(d *PRDetail) { d.IsDraft = true }), true, "PR is a draft; mark it ready first", ""},
+		{"conflicts", open(func(d *PRDetail) { d.Mergeability = "CONFLICTING" }), true, "PR has merge conflicts with main", ""},
+		{"failing", open(func(d *PRDetail) { d.ChecksFailed = 2 }), true, "", "2 checks failing"},
+		{"pending now", open(func(d *PRDetail) { d.ChecksPending = 1; d.MergeState = "BLOCKED" }), false, "", "1 check still running; branch protection requirements are not met"},
+		{"pending auto", open(func(d *PRDetail) { d.ChecksPending = 1; d.MergeState = "BLOCKED" }), true, "", ""},
+	}
+	for _, tt := range tests {
+		blocker, warning := MergeCheck(tt.detail, tt.auto)
+		if blocker != tt.blocker || warning != tt.warning {
+			t.Errorf("%s: MergeCheck = %q, %q; want %q, %q", tt.name, blocker, warning, tt.blocker, tt.warning)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBatchSize caps how many branches or PRs go into one GraphQL query, to
+// stay well within GitHub's query complexity limits.
+const maxBatchSize = 50
+
+// SessionLookup identifies a worktree session whose PR is looked up by the
+// branch checked out in it.
+type SessionLookup struct {
+	SessionID string
+	Dir       string
+	Repo      string // "owner/repo" or "host/owner/repo"
+	Branch    string
+}
+
+// SessionBatcher is implemented by providers that can look up the PRs of
+// many session branches at once. The result is keyed by session ID, with a
+// nil PR for branches that have none; sessions whose lookup failed are left
+// out.
+type SessionBatcher interface {
+	SessionPRs(lookups []SessionLookup) (map[string]*PR, error)
+}
+
+// ghPRFragment selects the PR fields shown in badges and lists, including
+// the head commit's checks, for the batched GraphQL queries.
+const ghPRFragment = `fragment pr on PullRequest {
+  number
+  title
+  state
+  url
+  isDraft
+  author { login }
+  headRefName
+  baseRefName
+  reviewDecision
+  createdAt
+  updatedAt
+  commits(last: 1) {
+    nodes {
+      commit {
+        statusCheckRollup {
+          contexts(first: 100) {
+            nodes {
+              __typename
+              ... on CheckRun { status conclusion }
+              ... on StatusContext { state }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// ghGraphPR is a PR as selected by ghPRFragment.
+type ghGraphPR struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	URL     string `json:"url"`
+	IsDraft bool   `json:"isDraft"`
+	Author  *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	HeadRefName    string    `json:"headRefName"`
+	BaseRefName    string    `json:"baseRefName"`
+	ReviewDecision string    `json:"reviewDecision"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	Commits        struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						Nodes []struct {
+							Typename   string `json:"__typename"`
+							Status     string `json:"status"`
+							Conclusion string `json:"conclusion"`
+							State      string `json:"state"`
+						} `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// checks converts the head commit's check runs and commit statuses to the
+// shape parseChecks counts.
+func (g *ghGraphPR) checks() []ghCheck {
+	var out []ghCheck
+	for _, n := range g.Commits.Nodes {
+		if n.Commit.StatusCheckRollup == nil {
+			continue
+		}
+		for _, c := range n.Commit.StatusCheckRollup.Contexts.Nodes {
+			if c.Typename != "StatusContext" {
+				out = append(out, ghCheck{Status: c.Status, Conclusion: c.Conclusion})
+				continue
+			}
+			switch c.State {
+			case "SUCCESS":
+				out = append(out, ghCheck{Status: "COMPLETED", Conclusion: "SUCCESS"})
+			case "PENDING", "EXPECTED":
+				out = append(out, ghCheck{Status: "PENDING"})
+			default: // FAILURE, ERROR
+				out = append(out, ghCheck{Status: "COMPLETED", Conclusion: "FAILURE"})
+			}
+		}
+	}
+	return out
+}
+
+// toPR converts the GraphQL PR into hangar's PR shape.
+func (g *ghGraphPR) toPR(repo string) *PR {
+	p := &PR{
+		Number:         g.Number,
+		Title:          g.Title,
+		State:          stateFromSearchResult(g.State, g.IsDraft),
+		IsDraft:        g.IsDraft,
+		URL:            g.URL,
+		Repo:           repo,
+		HeadBranch:     g.HeadRefName,
+		BaseBranch:     g.BaseRefName,
+		ReviewDecision: g.ReviewDecision,
+		CreatedAt:      g.CreatedAt,
+		UpdatedAt:      g.UpdatedAt,
+	}
+	if g.Author != nil {
+		p.Author = g.Author.Login
+	}
+	parseChecks(p, g.checks())
+	return p
+}
+
+// SessionPRs looks up the PRs of all session branches with one GraphQL
+// query per repo (per maxBatchSize branches), instead of one `gh pr view`
+// per session.
+func (g *GitHubProvider) SessionPRs(lookups []SessionLookup) (map[string]*PR, error) {
+	byRepo := make(map[string][]SessionLookup)
+	for _, l := range lookups {
+		byRepo[l.Repo] = append(byRepo[l.Repo], l)
+	}
+	out := make(map[string]*PR, len(lookups))
+	var lastErr error
+	for repo, repoLookups := range byRepo {
+		// Several sessions may share a branch; query each branch once.
+		var branches []string
+		seen := make(map[string]bool)
+		for _, l := range repoLookups {
+			if !seen[l.Branch] {
+				seen[l.Branch] = true
+				branches = append(branches, l.Branch)
+			}
+		}
+		found := make(map[string]*PR)
+		failed := false
+		for start := 0; start < len(branches); start += maxBatchSize {
+			chunk := branches[start:min(start+maxBatchSize, len(branches))]
+			prs, err := g.branchPRs(repo, chunk)
+			if err != nil {
+				lastErr = err
+				failed = true
+				break
+			}
+			for b, p := range prs {
+				found[b] = p
+			}
+		}
+		if failed {
+			continue
+		}
+		for _, l := range repoLookups {
+			var p *PR
+			if bp := found[l.Branch]; bp != nil {
+				cp := *bp
+				cp.Source = SourceSession
+				cp.SessionID = l.SessionID
+				p = &cp
+			}
+			out[l.SessionID] = p
+		}
+	}
+	if len(out) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return out, nil
+}
+
+// branchPRs returns the PR of each branch in repo, keyed by branch, leaving
+// out branches without one.
+func (g *GitHubProvider) branchPRs(repo string, branches []string) (map[string]*PR, error) {
+	owner, name, ok := strings.Cut(repoArg(repo), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo %q", repo)
+	}
+	args := []string{"api", "graphql",
+		"-f", "query=" + branchPRsQuery(len(branches)),
+		"-f", "owner=" + owner,
+		"-f", "name=" + name,
+	}
+	for i, b := range branches {
+		args = append(args, "-f", fmt.Sprintf("b%d=%s", i, b))
+	}
+	out, err := ghOutput(g.GHPath, repo, args)
+	if err != nil {
+		return nil, err
+	}
+	return parseBranchPRs(out, repo, branches)
+}
+
+// branchPRsQuery builds a query that looks up n branches ($b0..$bn-1) of one
+// repo, each aliased by its index.
+func branchPRsQuery(n int) string {
+	var b strings.Builder
+	b.WriteString("query($owner: String!, $name: String!")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, ", $b%d: String!", i)
+	}
+	b.WriteString(") {\n  repository(owner: $owner, name: $name) {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "    b%d: pullRequests(headRefName: $b%d, first: 5, orderBy: {field: UPDATED_AT, direction: DESC}) { nodes { ...pr } }\n", i, i)
+	}
+	b.WriteString("  }\n}\n")
+	b.WriteString(ghPRFragment)
+	return b.String()
+}
+
+// parseBranchPRs decodes the response to branchPRsQuery. A branch's open PR
+// wins over closed or merged ones; otherwise the most recently updated does.
+func parseBranchPRs(data []byte, repo string, branches []string) (map[string]*PR, error) {
+	var raw struct {
+		Data struct {
+			Repository map[string]*struct {
+				Nodes []ghGraphPR `json:"nodes"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Errors) > 0 {
+		return nil, fmt.Errorf("gh api graphql: %s", raw.Errors[0].Message)
+	}
+	if raw.Data.Repository == nil {
+		return nil, fmt.Errorf("repository %s not found", repo)
+	}
+	out := make(map[string]*PR)
+	for i, branch := range branches {
+		conn := raw.Data.Repository["b"+itoa(i)]
+		if conn == nil || len(conn.Nodes) == 0 {
+			continue
+		}
+		pick := &conn.Nodes[0]
+		for j := range conn.Nodes {
+			if conn.Nodes[j].State == "OPEN" {
+				pick = &conn.Nodes[j]
+				break
+			}
+		}
+		out[branch] = pick.toPR(repo)
+	}
+	return out, nil
+}
+
+// enrichChecksForPRs fills in the checks and review decision of search
+// results, which `gh search prs` does not return, with one GraphQL query per
+// host (per maxBatchSize PRs). PRs are updated in place; a failed query
+// leaves its PRs without checks.
+func enrichChecksForPRs(ghPath string, prs []*PR) {
+	byHost := make(map[string][]*PR)
+	for _, p := range prs {
+		host := hostFromRepo(p.Repo)
+		byHost[host] = append(byHost[host], p)
+	}
+	for _, hostPRs := range byHost {
+		sort.Slice(hostPRs, func(i, j int) bool { return hostPRs[i].Key() < hostPRs[j].Key() })
+		for start := 0; start < len(hostPRs); start += maxBatchSize {
+			chunk := hostPRs[start:min(start+maxBatchSize, len(hostPRs))]
+			query, args := prChecksQuery(chunk)
+			out, err := ghOutput(ghPath, chunk[0].Repo, append([]string{"api", "graphql", "-f", "query=" + query}, args...))
+			if err != nil {
+				continue
+			}
+			_ = applyPRChecks(out, chunk)
+		}
+	}
+}
+
+// prChecksQuery builds a query for the checks and review decision of prs,
+// which share a host. Each PR is aliased pN inside its repo's rN.
+func prChecksQuery(prs []*PR) (query string, args []string) {
+	var repos []string
+	repoIdx := make(map[string]int)
+	for _, p := range prs {
+		if _, ok := repoIdx[p.Repo]; !ok {
+			repoIdx[p.Repo] = len(repos)
+			repos = append(repos, p.Repo)
+		}
+	}
+	var vars []string
+	for ri := range repos {
+		vars = append(vars, fmt.Sprintf("$o%d: String!, $n%d: String!", ri, ri))
+	}
+	var b strings.Builder
+	b.WriteString("query(" + strings.Join(vars, ", ") + ") {\n")
+	for ri, repo := range repos {
+		owner, name, _ := strings.Cut(repoArg(repo), "/")
+		args = append(args, "-f", fmt.Sprintf("o%d=%s", ri, owner), "-f", fmt.Sprintf("n%d=%s", ri, name))
+		fmt.Fprintf(&b, "  r%d: repository(owner: $o%d, name: $n%d) {\n", ri, ri, ri)
+		for pi, p := range prs {
+			if p.Repo == repo {
+				fmt.Fprintf(&b, "    p%d: pullRequest(number: %d) { ...pr }\n", pi, p.Number)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString(ghPRFragment)
+	return b.String(), args
+}
+
+// applyPRChecks decodes the response to prChecksQuery into prs.
+func applyPRChecks(data []byte, prs []*PR) error {
+	var raw struct {
+		Data map[string]map[string]*ghGraphPR `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	repoIdx := make(map[string]int)
+	for _, p := range prs {
+		if _, ok := repoIdx[p.Repo]; !ok {
+			repoIdx[p.Repo] = len(repoIdx)
+		}
+	}
+	for pi, p := range prs {
+		repo := raw.Data["r"+itoa(repoIdx[p.Repo])]
+		g := repo["p"+itoa(pi)]
+		if g == nil {
+			continue
+		}
+		p.HasChecks, p.ChecksPassed, p.ChecksFailed, p.ChecksPending = false, 0, 0, 0
+		parseChecks(p, g.checks())
+		if g.ReviewDecision != "" {
+			p.ReviewDecision = g.ReviewDecision
+		}
+	}
+	return nil
+}
+
+// lookupSession resolves the repo and checked-out branch of a worktree.
+// It is a variable so tests can stub out git.
+var lookupSession = func(dir string) (repo, branch string) {
+	repo = repoFromDir(dir)
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return repo, ""
+	}
+	branch = strings.TrimSpace(string(out))
+	if branch == "HEAD" { // detached
+		branch = ""
+	}
+	return repo, branch
+}
@@ -0,0 +1,247 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+const branchPRsResponse = `{"data":{"repository":{
+  "b0":{"nodes":[
+    {"number":3,"title":"Old attempt","state":"CLOSED","url":"https://github.com/o/r/pull/3","headRefName":"feat/a"},
+    {"number":7,"title":"Add A","state":"OPEN","isDraft":true,"url":"https://github.com/o/r/pull/7",
+     "author":{"login":"alice"},"headRefName":"feat/a","baseRefName":"main","reviewDecision":"REVIEW_REQUIRED",
+     "updatedAt":"2026-10-02T10:00:00Z",
+     "commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{"nodes":[
+       {"__typename":"CheckRun","status":"COMPLETED","conclusion":"SUCCESS"},
+       {"__typename":"CheckRun","status":"IN_PROGRESS","conclusion":""},
+       {"__typename":"StatusContext","state":"FAILURE"}
+     ]}}}}]}}
+  ]},
+  "b1":{"nodes":[]}
+}}}`
+
+func TestParseBranchPRs(t *testing.T) {
+	prs, err := parseBranchPRs([]byte(branchPRsResponse), "o/r", []string{"feat/a", "feat/b"})
+	if err != nil {
+		t.Fatalf("parseBranchPRs: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d PRs, want 1 (feat/b has none)", len(prs))
+	}
+	p := prs["feat/a"]
+	if p == nil || p.Number != 7 || p.State != "DRAFT" || p.Author != "alice" || p.Repo != "o/r" || p.UpdatedAt.IsZero() {
+		t.Fatalf("feat/a = %+v, want the open PR #7", p)
+	}
+	if !p.HasChecks || p.ChecksPassed != 1 || p.ChecksPending != 1 || p.ChecksFailed != 1 {
+		t.Errorf("checks = %+v", p)
+	}
+
+	if _, err := parseBranchPRs([]byte(`{"data":{"repository":null},"errors":[{"message":"Could not resolve"}]}`), "o/r", nil); err == nil ||
+		!strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("err = %v, want the GraphQL error", err)
+	}
+}
+
+func TestBranchPRsQuery(t *testing.T) {
+	q := branchPRsQuery(2)
+	for _, want := range []string{"$b0: String!, $b1: String!", "b1: pullRequests(headRefName: $b1", "fragment pr on PullRequest"} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query missing %q:\n%s", want, q)
+		}
+	}
+}
+
+func TestPRChecksQuery(t *testing.T) {
+	prs := []*PR{
+		{Number: 1, Repo: "o/a"},
+		{Number: 2, Repo: "o/b"},
+		{Number: 3, Repo: "o/a", ReviewDecision: "APPROVED"},
+	}
+	q, args := prChecksQuery(prs)
+	for _, want := range []string{"r0: repository(owner: $o0, name: $n0)", "p2: pullRequest(number: 3)", "r1: repository"} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query missing %q:\n%s", want, q)
+		}
+	}
+	if got := strings.Join(args, " "); got != "-f o0=o -f n0=a -f o1=o -f n1=b" {
+		t.Errorf("args = %s", got)
+	}
+
+	resp := `{"data":{
+	  "r0":{"p0":{"reviewDecision":"CHANGES_REQUESTED","commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{"nodes":[
+	    {"__typename":"StatusContext","state":"SUCCESS"}]}}}}]}},"p2":null},
+	  "r1":{"p1":{"commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}}}
+	}}`
+	if err := applyPRChecks([]byte(resp), prs); err != nil {
+		t.Fatalf("applyPRChecks: %v", err)
+	}
+	if !prs[0].HasChecks || prs[0].ChecksPassed != 1 || prs[0].ReviewDecision != "CHANGES_REQUESTED" {
+		t.Errorf("pr 1 = %+v", prs[0])
+	}
+	if prs[1].HasChecks {
+		t.Errorf("pr 2 = %+v, want no checks", prs[1])
+	}
+	if prs[2].ReviewDecision != "APPROVED" {
+		t.Errorf("pr 3 = %+v, want it untouched", prs[2])
+	}
+}
+
+type memSessionPRStore struct {
+	mu   sync.Mutex
+	rows map[string]*statedb.SessionPRRow
+}
+
+func (s *memSessionPRStore) LoadSessionPRs() ([]*statedb.SessionPRRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*statedb.SessionPRRow
+	for _, r := range s.rows {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memSessionPRStore) SaveSessionPR(row *statedb.SessionPRRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows[row.SessionID] = row
+	return nil
+}
+
+func (s *memSessionPRStore) saves() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.rows)
+}
+
+func TestManagerBatchesSessionPRs(t *testing.T) {
+	orig := lookupSession
+	defer func() { lookupSession = orig }()
+	lookupSession = func(dir string) (string, string) {
+		if dir == "/wt/detached" {
+			return "o/r", ""
+		}
+		return "o/r", strings.TrimPrefix(dir, "/wt/")
+	}
+
+	gh := NewFakeProvider(ProviderGitHub)
+	updated := time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC)
+	gh.Sessions["/wt/a"] = &PR{Number: 1, Repo: "o/r", URL: "https://github.com/o/r/pull/1", State: "OPEN", UpdatedAt: updated}
+	store := &memSessionPRStore{rows: make(map[string]*statedb.SessionPRRow)}
+
+	m := New()
+	defer m.Stop()
+	m.SetProvider(gh)
+	m.LoadSessionPRCache(store)
+	changes := 0
+	m.RegisterOnChange(func() { changes++ })
+
+	// refetch queues s1 again, bypassing the TTL.
+	refetch := func() {
+		m.mu.Lock()
+		m.pendingSessions["s1"] = "/wt/a"
+		m.mu.Unlock()
+	}
+
+	m.UpdateSessionPR("s1", "/wt/a")
+	m.UpdateSessionPR("s2", "/wt/b")
+	m.UpdateSessionPR("s3", "/wt/detached")
+	m.flushSessionPRs()
+
+	if got := strings.Join(gh.Calls(), ","); got != "batch 2" {
+		t.Errorf("calls = %s, want one batch of the 2 sessions on a branch", got)
+	}
+	if p, _ := m.GetSessionPR("s1"); p == nil || p.Number != 1 || p.SessionID != "s1" {
+		t.Errorf("s1 = %+v", p)
+	}
+	for _, id := range []string{"s2", "s3"} {
+		if p, ok := m.GetSessionPR(id); !ok || p != nil {
+			t.Errorf("%s should be fetched with no PR", id)
+		}
+	}
+	if changes != 1 || store.saves() != 3 {
+		t.Errorf("changes = %d, saves = %d; want 1 and 3", changes, store.saves())
+	}
+
+	// An unchanged PR is neither saved again nor notified.
+	store.rows = make(map[string]*statedb.SessionPRRow)
+	refetch()
+	m.flushSessionPRs()
+	if changes != 1 || store.saves() != 0 {
+		t.Errorf("after unchanged fetch: changes = %d, saves = %d", changes, store.saves())
+	}
+
+	// A new manager restores the saved PR with its fetch time.
+	refetch()
+	gh.Sessions["/wt/a"].UpdatedAt = updated.Add(time.Hour)
+	m.flushSessionPRs()
+	m2 := New()
+	defer m2.Stop()
+	m2.LoadSessionPRCache(store)
+	if p, _ := m2.GetSessionPR("s1"); p == nil || p.Number != 1 || !p.UpdatedAt.Equal(updated.Add(time.Hour)) {
+		t.Errorf("restored s1 = %+v", p)
+	}
+	if _, ok := m2.SessionPRStaleAt("s1"); !ok {
+		t.Error("restored s1 should keep its fetch time")
+	}
+}
+
+// hookProvider runs during on every batched lookup.
+type hookProvider struct {
+	*FakeProvider
+	during func()
+}
+
+func (p *hookProvider) SessionPRs(lookups []SessionLookup) (map[string]*PR, error) {
+	p.during()
+	return p.FakeProvider.SessionPRs(lookups)
+}
+
+func TestManagerSessionPRsInFlight(t *testing.T) {
+	orig := lookupSession
+	defer func() { lookupSession = orig }()
+	lookupSession = func(dir string) (string, string) { return "o/r", "a" }
+
+	gh := &hookProvider{FakeProvider: NewFakeProvider(ProviderGitHub)}
+	m := New()
+	defer m.Stop()
+	m.SetProvider(gh)
+	queued := func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		_, ok := m.pendingSessions["s1"]
+		return ok
+	}
+
+	// A poll while the lookup runs does not queue the session again.
+	gh.during = func() {
+		m.UpdateSessionPR("s1", "/wt/a")
+		if queued() {
+			t.Error("in-flight session was queued again")
+		}
+	}
+	m.UpdateSessionPR("s1", "/wt/a")
+	m.flushSessionPRs()
+
+	// A forced refresh queues a fresh session; a failed lookup waits out the
+	// TTL like a successful one.
+	gh.during = func() {}
+	gh.Err = errors.New("gh: rate limited")
+	m.RefreshSessionPR("s1", "/wt/a")
+	if !queued() {
+		t.Fatal("RefreshSessionPR did not queue a fresh session")
+	}
+	m.flushSessionPRs()
+	m.UpdateSessionPR("s1", "/wt/a")
+	if queued() {
+		t.Error("failed lookup was queued again before the TTL")
+	}
+	if got := strings.Join(gh.Calls(), ","); got != "batch 1,batch 1" {
+		t.Errorf("calls = %s, want two batches", got)
+	}
+}
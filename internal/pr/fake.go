@@ -0,0 +1,132 @@
+package pr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FakeProvider is an in-memory Provider for tests. It serves the PRs it is
+// given and records the actions called on it as "action repo#number"
+// strings, e.g. "merge:squash o/r#4".
+type FakeProvider struct {
+	ProviderName string
+	Mine         []*PR
+	Review       []*PR
+	Sessions     map[string]*PR       // keyed by dir
+	Details      map[string]*PRDetail // keyed by "repo#number"
+	Err          error                // returned by every call when set
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// NewFakeProvider returns an empty FakeProvider named name.
+func NewFakeProvider(name string) *FakeProvider {
+	return &FakeProvider{
+		ProviderName: name,
+		Sessions:     make(map[string]*PR),
+		Details:      make(map[string]*PRDetail),
+	}
+}
+
+// Calls returns the actions recorded so far.
+func (f *FakeProvider) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func (f *FakeProvider) record(action, repo string, number int) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, fmt.Sprintf("%s %s#%d", action, repo, number))
+	f.mu.Unlock()
+	return f.Err
+}
+
+func (f *FakeProvider) Name() string { return f.ProviderName }
+
+func (f *FakeProvider) SearchMine(hosts []string) ([]*PR, error) {
+	return f.Mine, f.Err
+}
+
+func (f *FakeProvider) SearchReviewRequested(hosts []string) ([]*PR, error) {
+	return f.Review, f.Err
+}
+
+func (f *FakeProvider) SessionPR(dir, sessionID string) (*PR, error) {
+	p, ok := f.Sessions[dir]
+	if !ok || f.Err != nil {
+		return nil, f.Err
+	}
+	cp := *p
+	cp.Source = SourceSession
+	cp.SessionID = sessionID
+	return &cp, nil
+}
+
+// SessionPRs serves Sessions by dir and records one "batch N" call per
+// lookup batch.
+func (f *FakeProvider) SessionPRs(lookups []SessionLookup) (map[string]*PR, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, fmt.Sprintf("batch %d", len(lookups)))
+	f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	out := make(map[string]*PR, len(lookups))
+	for _, l := range lookups {
+		out[l.SessionID], _ = f.SessionPR(l.Dir, l.SessionID)
+	}
+	return out, nil
+}
+
+func (f *FakeProvider) Detail(repo string, number int) (*PRDetail, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	d, ok := f.Details[repo+"#"+itoa(number)]
+	if !ok {
+		return nil, fmt.Errorf("no PR %s#%d", repo, number)
+	}
+	return d, nil
+}
+
+func (f *FakeProvider) Approve(repo string, number int, body string) error {
+	return f.record("approve", repo, number)
+}
+
+func (f *FakeProvider) RequestChanges(repo string, number int, body string) error {
+	return f.record("request_changes", repo, number)
+}
+
+func (f *FakeProvider) Comment(repo string, number int, body, path string, line int) error {
+	return f.record("comment", repo, number)
+}
+
+func (f *FakeProvider) Close(repo string, number int) error {
+	return f.record("close", repo, number)
+}
+
+func (f *FakeProvider) Reopen(repo string, number int) error {
+	return f.record("reopen", repo, number)
+}
+
+func (f *FakeProvider) Ready(repo string, number int) error {
+	return f.record("ready", repo, number)
+}
+
+func (f *FakeProvider) Draft(repo string, number int) error {
+	return f.record("draft", repo, number)
+}
+
+func (f *FakeProvider) Merge(repo string, number int, method string, deleteBranch bool) error {
+	return f.record("merge:"+method, repo, number)
+}
+
+func (f *FakeProvider) EnableAutoMerge(repo string, number int, method string, deleteBranch bool) error {
+	return f.record("auto:"+method, repo, number)
+}
+
+func (f *FakeProvider) DisableAutoMerge(repo string, number int) error {
+	return f.record("disable_auto", repo, number)
+}
@@ -0,0 +1,387 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxFeedbackLogBytes caps how much of each failed run's log goes into a
+// feedback prompt. The end of the log, where the error is, is kept.
+const maxFeedbackLogBytes = 12 * 1024
+
+// maxFeedbackHunkLines caps the diff context shown for a review thread. The
+// hunk GitHub stores ends at the commented line, so its tail is kept.
+const maxFeedbackHunkLines = 12
+
+// Feedback is what a PR's reviewers and CI are asking the author to fix.
+type Feedback struct {
+	Threads      []ReviewThread // unresolved review threads
+	Reviews      []Review       // reviews requesting changes, with a body
+	FailedChecks []FailedCheck
+	RunLogs      []RunLog // failed job output of the GitHub Actions runs behind FailedChecks
+}
+
+// ReviewThread is an inline review conversation on one line of the diff.
+type ReviewThread struct {
+	Path     string
+	Line     int
+	Outdated bool   // the line has changed since the comment was made
+	DiffHunk string // diff context ending at the commented line
+	Comments []Comment
+}
+
+// FailedCheck is a CI check or commit status that did not pass.
+type FailedCheck struct {
+	Name       string
+	Conclusion string
+	URL        string
+}
+
+// RunLog is the failed job output of one GitHub Actions run.
+type RunLog struct {
+	RunID string
+	Log   string
+}
+
+// Empty reports whether there is nothing to address.
+func (f *Feedback) Empty() bool {
+	return f == nil || (len(f.Threads) == 0 && len(f.Reviews) == 0 && len(f.FailedChecks) == 0)
+}
+
+// feedbackQuery loads a PR's review threads, change requests and the checks
+// on its head commit in one GraphQL call; `gh pr view --json` reports
+// neither thread resolution nor check URLs.
+const feedbackQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved
+          isOutdated
+          path
+          line
+          originalLine
+          comments(first: 50) {
+            nodes { databaseId author { login } body createdAt diffHunk }
+          }
+        }
+      }
+      reviews(last: 50, states: [CHANGES_REQUESTED]) {
+        nodes { author { login } state body createdAt }
+      }
+      commits(last: 1) {
+        nodes {
+          commit {
+            statusCheckRollup {
+              contexts(first: 100) {
+                nodes {
+                  __typename
+                  ... on CheckRun { name status conclusion detailsUrl }
+                  ... on StatusContext { context state targetUrl }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// FetchFeedback collects the PR's unresolved review comments, change
+// requests and failed checks, with the failed job logs of GitHub Actions
+// runs fetched through `gh run view --log-failed`. A log that cannot be
+// fetched is left out rather than failing the whole call.
+func FetchFeedback(ghPath, repo string, number int) (*Feedback, error) {
+	owner, name, ok := strings.Cut(repoArg(repo), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo %q", repo)
+	}
+	out, err := ghOutput(ghPath, repo, []string{"api", "graphql",
+		"-f", "query=" + feedbackQuery,
+		"-F", "owner=" + owner,
+		"-F", "name=" + name,
+		"-F", "number=" + itoa(number),
+	})
+	if err != nil {
+		return nil, err
+	}
+	fb, err := parseFeedback(out)
+	if err != nil {
+		return nil, err
+	}
+	for _, runID := range failedRunIDs(fb.FailedChecks) {
+		log, err := ghOutput(ghPath, repo, []string{"run", "view", runID, "--repo", repoArg(repo), "--log-failed"})
+		if err != nil || len(log) == 0 {
+			continue
+		}
+		fb.RunLogs = append(fb.RunLogs, RunLog{RunID: runID, Log: tailBytes(string(log), maxFeedbackLogBytes)})
+	}
+	return fb, nil
+}
+
+// parseFeedback decodes the response to feedbackQuery.
+func parseFeedback(data []byte) (*Feedback, error) {
+	type ghAuthor struct {
+		Login string `json:"login"`
+	}
+	var raw struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							IsResolved   bool   `json:"isResolved"`
+							IsOutdated   bool   `json:"isOutdated"`
+							Path         string `json:"path"`
+							Line         int    `json:"line"`
+							OriginalLine int    `json:"originalLine"`
+							Comments     struct {
+								Nodes []struct {
+									DatabaseID int64     `json:"databaseId"`
+									Author     ghAuthor  `json:"author"`
+									Body       string    `json:"body"`
+									CreatedAt  time.Time `json:"createdAt"`
+									DiffHunk   string    `json:"diffHunk"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+					Reviews struct {
+						Nodes []struct {
+							Author    ghAuthor  `json:"author"`
+							State     string    `json:"state"`
+							Body      string    `json:"body"`
+							CreatedAt time.Time `json:"createdAt"`
+						} `json:"nodes"`
+					} `json:"reviews"`
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								StatusCheckRollup *struct {
+									Contexts struct {
+										Nodes []struct {
+											Typename   string `json:"__typename"`
+											Name       string `json:"name"`
+											Status     string `json:"status"`
+											Conclusion string `json:"conclusion"`
+											DetailsURL string `json:"detailsUrl"`
+											Context    string `json:"context"`
+											State      string `json:"state"`
+											TargetURL  string `json:"targetUrl"`
+										} `json:"nodes"`
+									} `json:"contexts"`
+								} `json:"statusCheckRollup"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Errors) > 0 {
+		return nil, fmt.Errorf("gh api graphql: %s", raw.Errors[0].Message)
+	}
+	pr := raw.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("PR not found")
+	}
+
+	fb := &Feedback{}
+	for _, t := range pr.ReviewThreads.Nodes {
+		if t.IsResolved || len(t.Comments.Nodes) == 0 {
+			continue
+		}
+		thread := ReviewThread{Path: t.Path, Line: t.Line, Outdated: t.IsOutdated}
+		if thread.Line == 0 {
+			thread.Line = t.OriginalLine
+		}
+		thread.DiffHunk = t.Comments.Nodes[0].DiffHunk
+		for _, c := range t.Comments.Nodes {
+			thread.Comments = append(thread.Comments, Comment{
+				ID:        c.DatabaseID,
+				Author:    c.Author.Login,
+				Body:      c.Body,
+				CreatedAt: c.CreatedAt,
+				Path:      t.Path,
+				Line:      thread.Line,
+			})
+		}
+		fb.Threads = append(fb.Threads, thread)
+	}
+	for _, r := range pr.Reviews.Nodes {
+		if strings.TrimSpace(r.Body) == "" {
+			continue
+		}
+		fb.Reviews = append(fb.Reviews, Review{
+			Author:    r.Author.Login,
+			State:     r.State,
+			Body:      r.Body,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	for _, c := range pr.Commits.Nodes {
+		if c.Commit.StatusCheckRollup == nil {
+			continue
+		}
+		for _, n := range c.Commit.StatusCheckRollup.Contexts.Nodes {
+			switch n.Typename {
+			case "CheckRun":
+				if n.Status != "COMPLETED" {
+					continue
+				}
+				switch n.Conclusion {
+				case "SUCCESS", "SKIPPED", "NEUTRAL":
+					continue
+				}
+				fb.FailedChecks = append(fb.FailedChecks, FailedCheck{Name: n.Name, Conclusion: n.Conclusion, URL: n.DetailsURL})
+			case "StatusContext":
+				if n.State != "FAILURE" && n.State != "ERROR" {
+					continue
+				}
+				fb.FailedChecks = append(fb.FailedChecks, FailedCheck{Name: n.Context, Conclusion: n.State, URL: n.TargetURL})
+			}
+		}
+	}
+	return fb, nil
+}
+
+// actionsRunPattern finds the run ID in a GitHub Actions check URL such as
+// https://github.com/owner/repo/actions/runs/123/job/456.
+var actionsRunPattern = regexp.MustCompile(`/actions/runs/(\d+)`)
+
+// failedRunIDs returns the distinct GitHub Actions runs behind checks, in
+// order. Checks from other CI systems have no run to fetch logs from.
+func failedRunIDs(checks []FailedCheck) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range checks {
+		m := actionsRunPattern.FindStringSubmatch(c.URL)
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		ids = append(ids, m[1])
+	}
+	return ids
+}
+
+// FeedbackPrompt turns fb into instructions for the agent working on p's
+// branch.
+func FeedbackPrompt(p *PR, fb *Feedback) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address the feedback on PR #%d", p.Number)
+	if p.Title != "" {
+		fmt.Fprintf(&b, " %q", p.Title)
+	}
+	if p.HeadBranch != "" {
+		fmt.Fprintf(&b, " (branch %s)", p.HeadBranch)
+	}
+	b.WriteString(".\n")
+	if p.URL != "" {
+		b.WriteString(p.URL + "\n")
+	}
+
+	if len(fb.Threads) > 0 {
+		b.WriteString("\n## Unresolved review comments\n")
+		for _, t := range fb.Threads {
+			b.WriteString("\n### " + t.Path)
+			if t.Line > 0 {
+				b.WriteString(":" + itoa(t.Line))
+			}
+			if t.Outdated {
+				b.WriteString(" (outdated: the code has changed since)")
+			}
+			b.WriteString("\n")
+			if hunk := tailLines(t.DiffHunk, maxFeedbackHunkLines); hunk != "" {
+				b.WriteString("```diff\n" + hunk + "\n```\n")
+			}
+			for _, c := range t.Comments {
+				writeQuoted(&b, c.Author, c.Body)
+			}
+		}
+	}
+
+	if len(fb.Reviews) > 0 {
+		b.WriteString("\n## Changes requested\n\n")
+		for _, r := range fb.Reviews {
+			writeQuoted(&b, r.Author, r.Body)
+		}
+	}
+
+	if len(fb.FailedChecks) > 0 {
+		b.WriteString("\n## Failed checks\n\n")
+		for _, c := range fb.FailedChecks {
+			fmt.Fprintf(&b, "- %s: %s", c.Name, strings.ToLower(c.Conclusion))
+			if c.URL != "" {
+				b.WriteString(" (" + c.URL + ")")
+			}
+			b.WriteString("\n")
+		}
+		for _, l := range fb.RunLogs {
+			fmt.Fprintf(&b, "\n### Failed job log, run %s\n```\n%s\n```\n", l.RunID, strings.TrimRight(l.Log, "\n"))
+		}
+	}
+
+	b.WriteString("\nFix each item on this branch, run the relevant tests, then commit and push. " +
+		"If a review comment should not be addressed in code, explain why instead of changing it.")
+	return b.String()
+}
+
+// writeQuoted writes "- author: body" with body's continuation lines indented.
+func writeQuoted(b *strings.Builder, author, body string) {
+	if author == "" {
+		author = "ghost"
+	}
+	body = strings.ReplaceAll(strings.TrimSpace(body), "\n", "\n  ")
+	fmt.Fprintf(b, "- %s: %s\n", author, body)
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tailBytes returns the end of s, at most max bytes, starting on a line.
+func tailBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[len(s)-max:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return "[earlier output truncated]\n" + s
+}
+
+// ghOutput executes a gh command like runGH and returns its stdout.
+func ghOutput(ghPath, repo string, args []string) ([]byte, error) {
+	cmd := exec.Command(ghPath, args...)
+	if host := hostFromRepo(repo); host != "" && host != "github.com" {
+		cmd.Env = append(os.Environ(), "GH_HOST="+host)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var stderr string
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("gh %s: %w\n%s", strings.Join(args[:2], " "), err, stderr)
+	}
+	return out, nil
+}
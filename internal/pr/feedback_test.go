@@ -0,0 +1,102 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+)
+
+const feedbackResponse = `{"data":{"repository":{"pullRequest":{
+  "reviewThreads":{"nodes":[
+    {"isResolved":false,"isOutdated":false,"path":"internal/auth/token.go","line":42,"originalLine":40,
+     "comments":{"nodes":[
+       {"databaseId":1,"author":{"login":"alice"},"body":"This leaks the refresh token.\nLog the ID instead.","createdAt":"2026-10-01T10:00:00Z","diffHunk":"@@ -38,3 +38,5 @@\n func refresh() {\n+\tlog.Print(tok)"},
+       {"databaseId":2,"author":{"login":"bob"},"body":"+1","createdAt":"2026-10-01T11:00:00Z","diffHunk":"@@ -38,3 +38,5 @@"}]}},
+    {"isResolved":true,"path":"README.md","line":3,"comments":{"nodes":[{"author":{"login":"alice"},"body":"typo"}]}},
+    {"isResolved":false,"isOutdated":true,"path":"main.go","line":0,"originalLine":7,
+     "comments":{"nodes":[{"author":null,"body":"Handle the error."}]}}]},
+  "reviews":{"nodes":[
+    {"author":{"login":"alice"},"state":"CHANGES_REQUESTED","body":"Please add tests."},
+    {"author":{"login":"carol"},"state":"CHANGES_REQUESTED","body":""}]},
+  "commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{"nodes":[
+    {"__typename":"CheckRun","name":"test","status":"COMPLETED","conclusion":"FAILURE","detailsUrl":"https://github.com/o/r/actions/runs/123/job/1"},
+    {"__typename":"CheckRun","name":"lint","status":"COMPLETED","conclusion":"FAILURE","detailsUrl":"https://github.com/o/r/actions/runs/123/job/2"},
+    {"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS","detailsUrl":"https://github.com/o/r/actions/runs/124/job/3"},
+    {"__typename":"CheckRun","name":"e2e","status":"IN_PROGRESS","conclusion":"","detailsUrl":"https://github.com/o/r/actions/runs/125/job/4"},
+    {"__typename":"StatusContext","context":"ci/jenkins","state":"ERROR","targetUrl":"https://jenkins.example.com/9"}]}}}}]}
+}}}}`
+
+func TestParseFeedback(t *testing.T) {
+	fb, err := parseFeedback([]byte(feedbackResponse))
+	if err != nil {
+		t.Fatalf("parseFeedback: %v", err)
+	}
+	if len(fb.Threads) != 2 {
+		t.Fatalf("threads = %+v, want the 2 unresolved ones", fb.Threads)
+	}
+	if th := fb.Threads[0]; th.Path != "internal/auth/token.go" || th.Line != 42 || len(th.Comments) != 2 || !strings.Contains(th.DiffHunk, "log.Print(tok)") {
+		t.Errorf("thread = %+v", th)
+	}
+	if th := fb.Threads[1]; th.Line != 7 || !th.Outdated {
+		t.Errorf("outdated thread = %+v, want line 7 from originalLine", th)
+	}
+	if len(fb.Reviews) != 1 || fb.Reviews[0].Author != "alice" {
+		t.Errorf("reviews = %+v, want only the one with a body", fb.Reviews)
+	}
+	var names []string
+	for _, c := range fb.FailedChecks {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "test,lint,ci/jenkins" {
+		t.Errorf("failed checks = %s", got)
+	}
+	if got := strings.Join(failedRunIDs(fb.FailedChecks), ","); got != "123" {
+		t.Errorf("failedRunIDs = %s, want 123", got)
+	}
+
+	if _, err := parseFeedback([]byte(`{"data":null,"errors":[{"message":"Could not resolve to a Repository"}]}`)); err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("GraphQL error = %v", err)
+	}
+}
+
+func TestFeedbackPrompt(t *testing.T) {
+	fb, err := parseFeedback([]byte(feedbackResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb.RunLogs = []RunLog{{RunID: "123", Log: "test\tRun go test\t--- FAIL: TestRefresh\n"}}
+	p := &PR{Number: 12, Title: "Refresh tokens", HeadBranch: "feat/refresh", URL: "https://github.com/o/r/pull/12"}
+
+	prompt := FeedbackPrompt(p, fb)
+	for _, want := range []string{
+		`PR #12 "Refresh tokens" (branch feat/refresh)`,
+		"### internal/auth/token.go:42\n```diff\n",
+		"- alice: This leaks the refresh token.\n  Log the ID instead.\n",
+		"### main.go:7 (outdated",
+		"- ghost: Handle the error.",
+		"## Changes requested\n\n- alice: Please add tests.",
+		"- test: failure (https://github.com/o/r/actions/runs/123/job/1)",
+		"- ci/jenkins: error",
+		"### Failed job log, run 123\n```\ntest\tRun go test\t--- FAIL: TestRefresh\n```",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt lacks %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "typo") {
+		t.Error("prompt includes a resolved thread")
+	}
+
+	if !(&Feedback{}).Empty() || fb.Empty() {
+		t.Error("Empty() is wrong")
+	}
+}
+
+func TestTailBytes(t *testing.T) {
+	if got := tailBytes("short", 10); got != "short" {
+		t.Errorf("tailBytes(short) = %q", got)
+	}
+	got := tailBytes("line one\nline two\nline three\n", 15)
+	if got != "[earlier output truncated]\nline three\n" {
+		t.Errorf("tailBytes = %q", got)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -34,51 +33,6 @@ func DetectGHUser(ghPath string) string {
 	return strings.TrimSpace(string(out))
 }
 
-// FetchMyPRs fetches open PRs authored by the current gh user.
-// extraHost is an optional additional GHE host to also search.
-// Results from github.com and extraHost are merged (deduplicated by URL).
-func FetchMyPRs(ghPath, extraHost string) ([]*PR, error) {
-	return fetchSearchBothHosts(ghPath, extraHost, "--author", "@me")
-}
-
-// FetchReviewRequestedPRs fetches open PRs where review has been requested from the current user.
-// extraHost is an optional additional GHE host to also search.
-// Results from github.com and extraHost are merged (deduplicated by URL).
-func FetchReviewRequestedPRs(ghPath, extraHost string) ([]*PR, error) {
-	return fetchSearchBothHosts(ghPath, extraHost, "--review-requested", "@me")
-}
-
-// fetchSearchBothHosts runs fetchSearchPRs against github.com and, if extraHost
-// is set, against the GHE host too. Results are merged and deduplicated by URL.
-func fetchSearchBothHosts(ghPath, extraHost, filterFlag, filterValue string) ([]*PR, error) {
-	seen := make(map[string]*PR)
-
-	// Always search github.com (no GH_HOST override)
-	if prs, err := fetchSearchPRs(ghPath, "", filterFlag, filterValue); err == nil {
-		for _, p := range prs {
-			seen[p.URL] = p
-		}
-	}
-
-	// Also search GHE host if known
-	// Later entries with the same URL overwrite earlier ones; ensures freshest data wins.
-	if extraHost != "" && extraHost != "github.com" {
-		if prs, err := fetchSearchPRs(ghPath, extraHost, filterFlag, filterValue); err == nil {
-			for _, p := range prs {
-				if _, exists := seen[p.URL]; !exists {
-					seen[p.URL] = p
-				}
-			}
-		}
-	}
-
-	out := make([]*PR, 0, len(seen))
-	for _, p := range seen {
-		out = append(out, p)
-	}
-	return out, nil
-}
-
 // fetchSearchPRs runs `gh search prs` with the given filter flag and parses the results.
 func fetchSearchPRs(ghPath, ghHost, filterFlag, filterValue string) ([]*PR, error) {
 	cmd := exec.Command(ghPath, "search", "prs",
@@ -161,51 +115,6 @@ func repoWithHostFromURL(prURL, nameWithOwner string) string {
 	return nameWithOwner
 }
 
-// enrichChecksForPRs fetches statusCheckRollup for each PR via `gh pr view`
-// in parallel (up to 5 concurrent). PRs are updated in place.
-func enrichChecksForPRs(ghPath string, prs []*PR) {
-	const concurrency = 5
-	sem := make(chan struct{}, concurrency)
-	var wg sync.WaitGroup
-	for _, p := range prs {
-		p := p
-		wg.Add(1)
-		sem <- struct{}{}
-		go func() {
-			defer wg.Done()
-			defer func() { <-sem }()
-			type ghCheckResult struct {
-				StatusCheckRollup []ghCheck `json:"statusCheckRollup"`
-				ReviewDecision    string    `json:"reviewDecision"`
-			}
-			ghRepo := repoArg(p.Repo)
-			ghHost := hostFromRepo(p.Repo)
-			cmd := exec.Command(ghPath, "pr", "view", itoa(p.Number),
-				"--repo", ghRepo,
-				"--json", "statusCheckRollup,reviewDecision",
-			)
-			if ghHost != "" && ghHost != "github.com" {
-				env := append([]string(nil), os.Environ()...)
-				env = append(env, "GH_HOST="+ghHost)
-				cmd.Env = env
-			}
-			out, err := cmd.Output()
-			if err != nil {
-				return
-			}
-			var res ghCheckResult
-			if err := json.Unmarshal(out, &res); err != nil {
-				return
-			}
-			parseChecks(p, res.StatusCheckRollup)
-			if res.ReviewDecision != "" {
-				p.ReviewDecision = res.ReviewDecision
-			}
-		}()
-	}
-	wg.Wait()
-}
-
 // FetchSessionPR fetches PR info for a single worktree session directory.
 // Returns nil (no error) if there is no PR for the current branch.
 func FetchSessionPR(ghPath, worktreePath, sessionID string) (*PR, error) {
@@ -312,6 +221,9 @@ func FetchDetail(ghPath, repo string, number int) (*PRDetail, error) {
 		Author            ghAuthor        `json:"author"`
 		IsDraft           bool            `json:"isDraft"`
 		MergeStateStatus  string          `json:"mergeStateStatus"`
+		AutoMergeRequest  *struct {
+			MergeMethod string `json:"mergeMethod"`
+		} `json:"autoMergeRequest"`
 		ReviewDecision    string          `json:"reviewDecision"`
 		HeadRefName       string          `json:"headRefName"`
 		BaseRefName       string          `json:"baseRefName"`
@@ -335,7 +247,7 @@ func FetchDetail(ghPath, repo string, number int) (*PRDetail, error) {
 
 	cmd := exec.Command(ghPath, "pr", "view", itoa(number),
 		"--repo", ghRepo,
-		"--json", "number,title,body,state,url,author,isDraft,mergeStateStatus,reviewDecision,statusCheckRollup,comments,reviews,files,headRefName,baseRefName,createdAt,updatedAt",
+		"--json", "number,title,body,state,url,author,isDraft,mergeStateStatus,autoMergeRequest,reviewDecision,statusCheckRollup,comments,reviews,files,headRefName,baseRefName,createdAt,updatedAt",
 	)
 	if ghHost != "" && ghHost != "github.com" {
 		env = append([]string(nil), os.Environ()...)
@@ -385,6 +297,10 @@ func FetchDetail(ghPath, repo string, number int) (*PRDetail, error) {
 
 	detail := &PRDetail{PR: *p}
 
+	detail.MergeState = raw.MergeStateStatus
+	if raw.AutoMergeRequest != nil {
+		detail.AutoMerge = strings.ToLower(raw.AutoMergeRequest.MergeMethod)
+	}
 	switch raw.MergeStateStatus {
 	case "CLEAN":
 		detail.Mergeability = "MERGEABLE"
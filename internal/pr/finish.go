@@ -0,0 +1,279 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/git"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// maxFinishSummary caps the agent response quoted in a finish PR's body.
+const maxFinishSummary = 8000
+
+// FinishOptions controls FinishViaPR.
+type FinishOptions struct {
+	Base        string // branch to open the PR against; default branch when empty
+	AutoMerge   bool   // enable auto-merge on the PR
+	MergeMethod string // merge, squash or rebase (default squash)
+	KeepBranch  bool   // keep the local branch when the session is torn down
+}
+
+// FinishViaPR pushes inst's worktree branch, opens a PR for it (or reuses the
+// branch's open PR) and records a pending finish, so a FinishWatcher tears
+// the worktree and session down once the PR is merged. A worktree with
+// uncommitted changes is refused, since they would be left out of the PR and
+// block the teardown. If enabling auto-merge fails the PR is still returned,
+// along with the error.
+func FinishViaPR(ghPath string, storage *session.Storage, inst *session.Instance, opts FinishOptions) (*PR, error) {
+	if !inst.IsWorktree() {
+		return nil, fmt.Errorf("session '%s' is not in a worktree", inst.Title)
+	}
+	if ghPath == "" {
+		return nil, fmt.Errorf("gh not found")
+	}
+	base := opts.Base
+	if base == "" {
+		var err error
+		if base, err = git.GetDefaultBranch(inst.WorktreeRepoRoot); err != nil {
+			return nil, fmt.Errorf("could not determine base branch: %w", err)
+		}
+	}
+	if base == inst.WorktreeBranch {
+		return nil, fmt.Errorf("cannot open a PR from '%s' into itself", base)
+	}
+	dirty, err := git.HasUncommittedChanges(inst.WorktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worktree status: %w", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("worktree has uncommitted changes; commit or stash them before opening a PR")
+	}
+	if err := git.PushBranch(inst.WorktreePath, inst.WorktreeBranch); err != nil {
+		return nil, err
+	}
+
+	p, _ := FetchSessionPR(ghPath, inst.WorktreePath, inst.ID)
+	if p == nil || p.State == "CLOSED" {
+		todo, _ := storage.FindTodoBySessionID(inst.ID)
+		var lastResponse string
+		if resp, err := inst.GetLastResponse(); err == nil {
+			lastResponse = resp.Content
+		}
+		title, body := FinishTitleBody(inst.Title, todo, lastResponse)
+		url, number, err := Create(ghPath, inst.WorktreePath, base, inst.WorktreeBranch, title, body)
+		if err != nil {
+			return nil, err
+		}
+		p = &PR{
+			Number:     number,
+			Title:      title,
+			State:      "OPEN",
+			URL:        url,
+			Repo:       repoFromDir(inst.WorktreePath),
+			HeadBranch: inst.WorktreeBranch,
+			BaseBranch: base,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			Source:     SourceSession,
+			SessionID:  inst.ID,
+		}
+	}
+
+	if err := storage.SavePendingFinish(&statedb.PendingFinishRow{
+		SessionID:  inst.ID,
+		PRNumber:   p.Number,
+		PRURL:      p.URL,
+		KeepBranch: opts.KeepBranch,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return p, fmt.Errorf("record pending finish: %w", err)
+	}
+
+	if opts.AutoMerge && p.State != "MERGED" {
+		method := opts.MergeMethod
+		if method == "" {
+			method = "squash"
+		}
+		if err := EnableAutoMerge(ghPath, p.Repo, p.Number, method, false); err != nil {
+			return p, fmt.Errorf("enable auto-merge: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// FinishTitleBody builds the title and body of a finish PR from the session
+// title, the session's linked todo (may be nil) and the agent's last response.
+func FinishTitleBody(sessionTitle string, todo *session.Todo, lastResponse string) (title, body string) {
+	title = sessionTitle
+	var b strings.Builder
+	if todo != nil {
+		b.WriteString("## Task\n\n**" + todo.Title + "**\n\n")
+		if d := strings.TrimSpace(todo.Description); d != "" {
+			b.WriteString(d + "\n\n")
+		}
+	}
+	if s := strings.TrimSpace(lastResponse); s != "" {
+		if r := []rune(s); len(r) > maxFinishSummary {
+			s = string(r[:maxFinishSummary]) + "…"
+		}
+		b.WriteString("## Summary\n\n" + s + "\n\n")
+	}
+	b.WriteString(fmt.Sprintf("_Opened by hangar from session %q._", sessionTitle))
+	return title, b.String()
+}
+
+// finishStore is the subset of session.Storage the finish watcher uses.
+type finishStore interface {
+	LoadPendingFinishes() ([]*statedb.PendingFinishRow, error)
+	SavePendingFinish(row *statedb.PendingFinishRow) error
+	ClaimPendingFinish(sessionID string) (bool, error)
+	Load() ([]*session.Instance, error)
+	DeleteInstance(id string) error
+	Close() error
+}
+
+// FinishWatcher completes worktree finishes started with FinishViaPR: once
+// the Manager sees a pending session's PR as MERGED it removes the worktree,
+// deletes the branch (unless kept), kills the tmux session and removes the
+// session. A PR closed without merging cancels the pending finish and leaves
+// the session alone.
+type FinishWatcher struct {
+	manager *Manager
+	kick    chan struct{}
+
+	// onMerged replaces the default session removal (e.g. so the TUI can
+	// remove the session through its own state). It runs after the worktree
+	// has been torn down.
+	onMerged func(inst *session.Instance, keepBranch bool)
+
+	// Overridable in tests.
+	openStore func() (finishStore, error)
+	teardown  func(inst *session.Instance, keepBranch bool) error
+}
+
+// NewFinishWatcher creates a watcher for m's session PRs and profile's sessions.
+func NewFinishWatcher(m *Manager, profile string) *FinishWatcher {
+	return &FinishWatcher{
+		manager: m,
+		kick:    make(chan struct{}, 1),
+		openStore: func() (finishStore, error) {
+			return session.NewStorageWithProfile(profile)
+		},
+		teardown: teardownWorktree,
+	}
+}
+
+// OnMerged makes the watcher call fn instead of deleting the torn-down
+// session itself. Call before Start.
+func (w *FinishWatcher) OnMerged(fn func(inst *session.Instance, keepBranch bool)) {
+	w.onMerged = fn
+}
+
+// Start registers with the manager and checks pending finishes on every PR
+// change until ctx is cancelled.
+func (w *FinishWatcher) Start(ctx context.Context) {
+	w.manager.RegisterOnChange(func() {
+		select {
+		case w.kick <- struct{}{}:
+		default: // already queued
+		}
+	})
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.kick:
+				w.Check()
+			}
+		}
+	}()
+}
+
+// Check finishes every pending session whose PR is merged and drops pending
+// finishes whose PR was closed or whose session no longer exists.
+func (w *FinishWatcher) Check() {
+	store, err := w.openStore()
+	if err != nil {
+		slog.Warn("pr_finish: open storage failed", "err", err)
+		return
+	}
+	defer store.Close()
+
+	pending, err := store.LoadPendingFinishes()
+	if err != nil || len(pending) == 0 {
+		return
+	}
+	instances, err := store.Load()
+	if err != nil {
+		slog.Warn("pr_finish: load sessions failed", "err", err)
+		return
+	}
+	byID := make(map[string]*session.Instance, len(instances))
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+	prs := w.manager.GetSessionPRs()
+
+	for _, pf := range pending {
+		inst := byID[pf.SessionID]
+		p := prs[pf.SessionID]
+		switch {
+		case inst == nil:
+			// Session was removed by hand; nothing left to finish.
+			_, _ = store.ClaimPendingFinish(pf.SessionID)
+		case p == nil || p.Number != pf.PRNumber:
+			continue // not fetched yet
+		case p.State == "CLOSED":
+			if ok, _ := store.ClaimPendingFinish(pf.SessionID); ok {
+				slog.Info("pr_finish: PR closed, finish cancelled", "session", pf.SessionID, "pr", p.Number)
+			}
+		case p.State == "MERGED":
+			if ok, err := store.ClaimPendingFinish(pf.SessionID); err != nil || !ok {
+				continue // claimed by another process
+			}
+			slog.Info("pr_finish: PR merged, finishing", "session", pf.SessionID, "pr", p.Number)
+			if err := w.teardown(inst, pf.KeepBranch); err != nil {
+				// Put the finish back so it is retried on the next check,
+				// e.g. once untracked files are cleaned up.
+				slog.Warn("pr_finish: teardown failed; will retry", "session", pf.SessionID, "err", err)
+				if err := store.SavePendingFinish(pf); err != nil {
+					slog.Warn("pr_finish: restore pending finish failed", "session", pf.SessionID, "err", err)
+				}
+				continue
+			}
+			if w.onMerged != nil {
+				w.onMerged(inst, pf.KeepBranch)
+				continue
+			}
+			if err := store.DeleteInstance(inst.ID); err != nil {
+				slog.Warn("pr_finish: delete session failed", "session", pf.SessionID, "err", err)
+			}
+		}
+	}
+}
+
+// teardownWorktree removes a finished session's worktree and branch and kills
+// its tmux session. The branch is force-deleted because squash and rebase
+// merges leave it unmerged locally.
+func teardownWorktree(inst *session.Instance, keepBranch bool) error {
+	if _, err := os.Stat(inst.WorktreePath); err == nil {
+		if err := git.RemoveWorktree(inst.WorktreeRepoRoot, inst.WorktreePath, false); err != nil {
+			return err
+		}
+	}
+	_ = git.PruneWorktrees(inst.WorktreeRepoRoot)
+	if !keepBranch {
+		_ = git.DeleteBranch(inst.WorktreeRepoRoot, inst.WorktreeBranch, true)
+	}
+	if inst.Exists() {
+		_ = inst.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+type fakeFinishStore struct {
+	pending   map[string]*statedb.PendingFinishRow
+	instances []*session.Instance
+	deleted   []string
+}
+
+func (f *fakeFinishStore) LoadPendingFinishes() ([]*statedb.PendingFinishRow, error) {
+	var rows []*statedb.PendingFinishRow
+	for _, r := range f.pending {
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func (f *fakeFinishStore) SavePendingFinish(row *statedb.PendingFinishRow) error {
+	f.pending[row.SessionID] = row
+	return nil
+}
+
+func (f *fakeFinishStore) ClaimPendingFinish(sessionID string) (bool, error) {
+	_, ok := f.pending[sessionID]
+	delete(f.pending, sessionID)
+	return ok, nil
+}
+
+func (f *fakeFinishStore) Load() ([]*session.Instance, error) { return f.instances, nil }
+
+func (f *fakeFinishStore) DeleteInstance(id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeFinishStore) Close() error { return nil }
+
+func TestFinishWatcher(t *testing.T) {
+	store := &fakeFinishStore{
+		pending: map[string]*statedb.PendingFinishRow{
+			"open":   {SessionID: "open", PRNumber: 1},
+			"merged": {SessionID: "merged", PRNumber: 2, KeepBranch: true},
+			"closed": {SessionID: "closed", PRNumber: 3},
+			"gone":   {SessionID: "gone", PRNumber: 4},
+		},
+		instances: []*session.Instance{{ID: "open"}, {ID: "merged"}, {ID: "closed"}},
+	}
+	m := New()
+	w := NewFinishWatcher(m, "_test")
+	w.openStore = func() (finishStore, error) { return store, nil }
+	var tornDown []string
+	w.teardown = func(inst *session.Instance, keepBranch bool) error {
+		if !keepBranch {
+			t.Errorf("teardown(%s): keepBranch = false, want true", inst.ID)
+		}
+		tornDown = append(tornDown, inst.ID)
+		return nil
+	}
+
+	m.SetSessionPR("open", &PR{Number: 1, State: "OPEN"})
+	m.SetSessionPR("merged", &PR{Number: 2, State: "MERGED"})
+	m.SetSessionPR("closed", &PR{Number: 3, State: "CLOSED"})
+	w.Check()
+
+	if len(tornDown) != 1 || tornDown[0] != "merged" {
+		t.Errorf("torn down = %v, want [merged]", tornDown)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "merged" {
+		t.Errorf("deleted = %v, want [merged]", store.deleted)
+	}
+	if len(store.pending) != 1 || store.pending["open"] == nil {
+		t.Errorf("pending = %v, want only open", store.pending)
+	}
+
+	// Merging later finishes the remaining session.
+	m.SetSessionPR("open", &PR{Number: 1, State: "MERGED"})
+	store.pending["open"].KeepBranch = true
+	w.Check()
+	if len(tornDown) != 2 || len(store.pending) != 0 {
+		t.Errorf("after merge: torn down = %v, pending = %v", tornDown, store.pending)
+	}
+}
+
+func TestFinishWatcherTeardownFailure(t *testing.T) {
+	store := &fakeFinishStore{
+		pending:   map[string]*statedb.PendingFinishRow{"merged": {SessionID: "merged", PRNumber: 2}},
+		instances: []*session.Instance{{ID: "merged"}},
+	}
+	m := New()
+	w := NewFinishWatcher(m, "_test")
+	w.openStore = func() (finishStore, error) { return store, nil }
+	fail := true
+	w.teardown = func(*session.Instance, bool) error {
+		if fail {
+			return errors.New("worktree contains untracked files")
+		}
+		return nil
+	}
+	var merged []string
+	w.OnMerged(func(inst *session.Instance, _ bool) { merged = append(merged, inst.ID) })
+
+	m.SetSessionPR("merged", &PR{Number: 2, State: "MERGED"})
+	w.Check()
+	if store.pending["merged"] == nil || len(merged) != 0 {
+		t.Fatalf("after failed teardown: pending = %v, merged = %v; want the finish kept", store.pending, merged)
+	}
+
+	fail = false
+	w.Check()
+	if len(store.pending) != 0 || len(merged) != 1 {
+		t.Errorf("after retry: pending = %v, merged = %v", store.pending, merged)
+	}
+}
+
+func TestFinishTitleBody(t *testing.T) {
+	todo := &session.Todo{Title: "Add login", Description: "OAuth via GitHub"}
+	title, body := FinishTitleBody("login-work", todo, "Implemented the OAuth flow.")
+	if title != "login-work" {
+		t.Errorf("title = %q", title)
+	}
+	for _, want := range []string{"**Add login**", "OAuth via GitHub", "## Summary", "Implemented the OAuth flow.", `"login-work"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	_, body = FinishTitleBody("x", nil, "")
+	if strings.Contains(body, "## Task") || strings.Contains(body, "## Summary") {
+		t.Errorf("empty sections should be omitted:\n%s", body)
+	}
+
+	_, body = FinishTitleBody("x", nil, strings.Repeat("a", maxFinishSummary+10))
+	if !strings.Contains(body, "…") {
+		t.Error("long summary should be truncated")
+	}
+}
@@ -0,0 +1,634 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitLabProvider implements Provider for GitLab merge requests with the glab
+// CLI. Everything goes through `glab api --hostname`, so it works against
+// gitlab.com and self-managed instances alike and needs no checkout.
+type GitLabProvider struct {
+	GlabPath string
+
+	mu    sync.Mutex
+	users map[string]string // host → username
+}
+
+// NewGitLabProvider returns a Provider that runs the glab binary at glabPath.
+func NewGitLabProvider(glabPath string) *GitLabProvider {
+	return &GitLabProvider{GlabPath: glabPath, users: make(map[string]string)}
+}
+
+func (g *GitLabProvider) Name() string { return ProviderGitLab }
+
+// glUser is a user reference in GitLab API responses.
+type glUser struct {
+	Username string `json:"username"`
+}
+
+// glMergeRequest is the subset of a GitLab merge request hangar uses.
+type glMergeRequest struct {
+	IID                       int       `json:"iid"`
+	Title                     string    `json:"title"`
+	Description               string    `json:"description"`
+	State                     string    `json:"state"`
+	WebURL                    string    `json:"web_url"`
+	Author                    glUser    `json:"author"`
+	Draft                     bool      `json:"draft"`
+	SourceBranch              string    `json:"source_branch"`
+	TargetBranch              string    `json:"target_branch"`
+	UserNotesCount            int       `json:"user_notes_count"`
+	DetailedMergeStatus       string    `json:"detailed_merge_status"`
+	HasConflicts              bool      `json:"has_conflicts"`
+	MergeWhenPipelineSucceeds bool      `json:"merge_when_pipeline_succeeds"`
+	Squash                    bool      `json:"squash"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+	HeadPipeline              *struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+// glJob is a CI job of a merge request's head pipeline.
+type glJob struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+// glNote is a comment on a merge request; diff notes carry a position.
+type glNote struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	Author    glUser    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	System    bool      `json:"system"`
+	Position  *struct {
+		NewPath string `json:"new_path"`
+		NewLine int    `json:"new_line"`
+	} `json:"position"`
+}
+
+// glApprovals is a merge request's approval state.
+type glApprovals struct {
+	Approved      bool `json:"approved"`
+	ApprovalsLeft int  `json:"approvals_left"`
+	ApprovedBy    []struct {
+		User glUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+// glChange is one file of a merge request's diff.
+type glChange struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+func (g *GitLabProvider) SearchMine(hosts []string) ([]*PR, error) {
+	return g.search(hosts, func(string) (string, error) {
+		return "merge_requests?scope=created_by_me&state=opened&per_page=50", nil
+	})
+}
+
+func (g *GitLabProvider) SearchReviewRequested(hosts []string) ([]*PR, error) {
+	return g.search(hosts, func(host string) (string, error) {
+		user, err := g.user(host)
+		if err != nil {
+			return "", err
+		}
+		return "merge_requests?scope=all&state=opened&per_page=50&reviewer_username=" + url.QueryEscape(user), nil
+	})
+}
+
+// search lists merge requests on each host with the endpoint built by
+// endpoint and fills in their pipeline checks.
+func (g *GitLabProvider) search(hosts []string, endpoint func(host string) (string, error)) ([]*PR, error) {
+	var out []*PR
+	var lastErr error
+	ok := false
+	for _, host := range hosts {
+		path, err := endpoint(host)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := g.api(host, "GET", path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		prs, err := parseGitLabMRs(data, "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ok = true
+		out = append(out, prs...)
+	}
+	if !ok && lastErr != nil {
+		return nil, lastErr
+	}
+
+	const concurrency = 5
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, p := range out {
+		p := p
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if mr, err := g.mergeRequest(p.Repo, p.Number); err == nil {
+				g.applyPipeline(p, mr)
+			}
+		}()
+	}
+	wg.Wait()
+	return out, nil
+}
+
+// user returns the username glab is authenticated as on host.
+func (g *GitLabProvider) user(host string) (string, error) {
+	g.mu.Lock()
+	user, ok := g.users[host]
+	g.mu.Unlock()
+	if ok {
+		return user, nil
+	}
+	data, err := g.api(host, "GET", "user")
+	if err != nil {
+		return "", err
+	}
+	var u glUser
+	if err := json.Unmarshal(data, &u); err != nil {
+		return "", err
+	}
+	if u.Username == "" {
+		return "", fmt.Errorf("glab: not authenticated on %s", host)
+	}
+	g.mu.Lock()
+	g.users[host] = u.Username
+	g.mu.Unlock()
+	return u.Username, nil
+}
+
+func (g *GitLabProvider) SessionPR(dir, sessionID string) (*PR, error) {
+	repo := repoFromDir(dir)
+	if repo == "" {
+		return nil, nil
+	}
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return nil, nil
+	}
+	branch := strings.TrimSpace(string(out))
+	data, err := g.api(hostFromRepo(repo), "GET", glProjectPath(repo)+"/merge_requests?state=all&order_by=updated_at&per_page=1&source_branch="+url.QueryEscape(branch))
+	if err != nil {
+		return nil, err
+	}
+	prs, err := parseGitLabMRs(data, repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil // no MR for the branch
+	}
+	mr, err := g.mergeRequest(repo, prs[0].Number)
+	if err != nil {
+		return nil, err
+	}
+	p := mr.toPR(repo)
+	g.applyPipeline(p, mr)
+	if appr, err := g.approvals(repo, p.Number); err == nil {
+		p.ReviewDecision = appr.reviewDecision()
+	}
+	p.Source = SourceSession
+	p.SessionID = sessionID
+	return p, nil
+}
+
+func (g *GitLabProvider) Detail(repo string, number int) (*PRDetail, error) {
+	mr, err := g.mergeRequest(repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var notes []glNote
+	if data, err := g.api(hostFromRepo(repo), "GET", glMRPath(repo, number)+"/notes?sort=asc&order_by=created_at&per_page=100"); err == nil {
+		_ = json.Unmarshal(data, &notes)
+	}
+	appr, _ := g.approvals(repo, number)
+	var changes struct {
+		Changes []glChange `json:"changes"`
+	}
+	if data, err := g.api(hostFromRepo(repo), "GET", glMRPath(repo, number)+"/changes"); err == nil {
+		_ = json.Unmarshal(data, &changes)
+	}
+
+	detail := gitlabDetail(mr, notes, appr, changes.Changes, repo)
+	g.applyPipeline(&detail.PR, mr)
+	return detail, nil
+}
+
+func (g *GitLabProvider) Approve(repo string, number int, body string) error {
+	if _, err := g.api(hostFromRepo(repo), "POST", glMRPath(repo, number)+"/approve"); err != nil {
+		return err
+	}
+	if body != "" {
+		return g.note(repo, number, body)
+	}
+	return nil
+}
+
+// RequestChanges revokes the user's approval, if any, and posts body as a
+// comment: GitLab has no "request changes" review state of its own.
+func (g *GitLabProvider) RequestChanges(repo string, number int, body string) error {
+	if body == "" {
+		body = "Changes requested."
+	}
+	_, _ = g.api(hostFromRepo(repo), "POST", glMRPath(repo, number)+"/unapprove")
+	return g.note(repo, number, body)
+}
+
+func (g *GitLabProvider) Comment(repo string, number int, body, path string, line int) error {
+	if path != "" && line > 0 {
+		body = fmt.Sprintf("**%s:%d**\n\n%s", path, line, body)
+	}
+	return g.note(repo, number, body)
+}
+
+func (g *GitLabProvider) Close(repo string, number int) error {
+	_, err := g.api(hostFromRepo(repo), "PUT", glMRPath(repo, number), "state_event=close")
+	return err
+}
+
+func (g *GitLabProvider) Reopen(repo string, number int) error {
+	_, err := g.api(hostFromRepo(repo), "PUT", glMRPath(repo, number), "state_event=reopen")
+	return err
+}
+
+func (g *GitLabProvider) Ready(repo string, number int) error {
+	return g.setDraft(repo, number, false)
+}
+
+func (g *GitLabProvider) Draft(repo string, number int) error {
+	return g.setDraft(repo, number, true)
+}
+
+// setDraft marks the merge request draft or ready by its title prefix.
+func (g *GitLabProvider) setDraft(repo string, number int, draft bool) error {
+	mr, err := g.mergeRequest(repo, number)
+	if err != nil {
+		return err
+	}
+	title := gitlabUndraftTitle(mr.Title)
+	if draft {
+		title = "Draft: " + title
+	}
+	_, err = g.api(hostFromRepo(repo), "PUT", glMRPath(repo, number), "title="+title)
+	return err
+}
+
+// Merge merges the merge request now. GitLab sets merge commits versus
+// fast-forward per project, so method only chooses whether to squash.
+func (g *GitLabProvider) Merge(repo string, number int, method string, deleteBranch bool) error {
+	fields, err := glMergeFields(method, deleteBranch)
+	if err != nil {
+		return err
+	}
+	_, err = g.api(hostFromRepo(repo), "PUT", glMRPath(repo, number)+"/merge", fields...)
+	return err
+}
+
+func (g *GitLabProvider) EnableAutoMerge(repo string, number int, method string, deleteBranch bool) error {
+	fields, err := glMergeFields(method, deleteBranch)
+	if err != nil {
+		return err
+	}
+	fields = append(fields, "merge_when_pipeline_succeeds=true")
+	_, err = g.api(hostFromRepo(repo), "PUT", glMRPath(repo, number)+"/merge", fields...)
+	return err
+}
+
+func (g *GitLabProvider) DisableAutoMerge(repo string, number int) error {
+	_, err := g.api(hostFromRepo(repo), "POST", glMRPath(repo, number)+"/cancel_merge_when_pipeline_succeeds")
+	return err
+}
+
+// glMergeFields returns the merge endpoint's fields for method.
+func glMergeFields(method string, deleteBranch bool) ([]string, error) {
+	if method == "rebase" {
+		return nil, fmt.Errorf("GitLab sets rebase merging per project; merge with %q or %q", "merge", "squash")
+	}
+	return []string{
+		fmt.Sprintf("squash=%t", method == "squash"),
+		fmt.Sprintf("should_remove_source_branch=%t", deleteBranch),
+	}, nil
+}
+
+// mergeRequest fetches a single merge request.
+func (g *GitLabProvider) mergeRequest(repo string, number int) (*glMergeRequest, error) {
+	data, err := g.api(hostFromRepo(repo), "GET", glMRPath(repo, number))
+	if err != nil {
+		return nil, err
+	}
+	var mr glMergeRequest
+	if err := json.Unmarshal(data, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// approvals fetches a merge request's approval state.
+func (g *GitLabProvider) approvals(repo string, number int) (*glApprovals, error) {
+	data, err := g.api(hostFromRepo(repo), "GET", glMRPath(repo, number)+"/approvals")
+	if err != nil {
+		return nil, err
+	}
+	var appr glApprovals
+	if err := json.Unmarshal(data, &appr); err != nil {
+		return nil, err
+	}
+	return &appr, nil
+}
+
+// applyPipeline counts the jobs of the merge request's head pipeline as its
+// checks, falling back to the pipeline's own status if they can't be listed.
+func (g *GitLabProvider) applyPipeline(p *PR, mr *glMergeRequest) {
+	if mr.HeadPipeline == nil {
+		return
+	}
+	data, err := g.api(hostFromRepo(p.Repo), "GET", fmt.Sprintf("%s/pipelines/%d/jobs?per_page=100", glProjectPath(p.Repo), mr.HeadPipeline.ID))
+	var jobs []glJob
+	if err == nil {
+		_ = json.Unmarshal(data, &jobs)
+	}
+	if len(jobs) == 0 {
+		jobs = []glJob{{Name: "pipeline", Status: mr.HeadPipeline.Status}}
+	}
+	applyGitLabJobs(p, jobs)
+}
+
+// note posts a comment on the merge request.
+func (g *GitLabProvider) note(repo string, number int, body string) error {
+	_, err := g.api(hostFromRepo(repo), "POST", glMRPath(repo, number)+"/notes", "body="+body)
+	return err
+}
+
+// api runs `glab api` against host and returns the response body. fields
+// are "key=value" pairs sent as string parameters.
+func (g *GitLabProvider) api(host, method, path string, fields ...string) ([]byte, error) {
+	if host == "" {
+		host = "gitlab.com"
+	}
+	args := []string{"api", "--hostname", host, "-X", method, path}
+	for _, f := range fields {
+		args = append(args, "-f", f)
+	}
+	out, err := exec.Command(g.GlabPath, args...).Output()
+	if err != nil {
+		var stderr string
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("glab api %s %s: %w\n%s", method, path, err, stderr)
+	}
+	return out, nil
+}
+
+// glProjectPath returns the API path of repo's project, addressed by its
+// URL-encoded full path, e.g. "projects/group%2Fsub%2Frepo".
+func glProjectPath(repo string) string {
+	return "projects/" + url.PathEscape(repoArg(repo))
+}
+
+// glMRPath returns the API path of merge request number in repo.
+func glMRPath(repo string, number int) string {
+	return glProjectPath(repo) + "/merge_requests/" + itoa(number)
+}
+
+// glRepoFromURL returns "host/group/repo" for a merge request web URL, e.g.
+// "https://gitlab.com/group/sub/repo/-/merge_requests/5" → "gitlab.com/group/sub/repo".
+func glRepoFromURL(webURL string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		if after, ok := strings.CutPrefix(webURL, scheme); ok {
+			repo, _, found := strings.Cut(after, "/-/merge_requests/")
+			if found {
+				return repo
+			}
+		}
+	}
+	return ""
+}
+
+// parseGitLabMRs parses a merge request list. repo is used for every entry
+// when set; otherwise each one's repo comes from its web URL.
+func parseGitLabMRs(data []byte, repo string) ([]*PR, error) {
+	var mrs []glMergeRequest
+	if err := json.Unmarshal(data, &mrs); err != nil {
+		return nil, err
+	}
+	prs := make([]*PR, 0, len(mrs))
+	for i := range mrs {
+		r := repo
+		if r == "" {
+			r = glRepoFromURL(mrs[i].WebURL)
+		}
+		prs = append(prs, mrs[i].toPR(r))
+	}
+	return prs, nil
+}
+
+// toPR converts the merge request into hangar's PR shape.
+func (mr *glMergeRequest) toPR(repo string) *PR {
+	return &PR{
+		Number:       mr.IID,
+		Title:        mr.Title,
+		Body:         mr.Description,
+		State:        gitlabState(mr.State, mr.Draft),
+		IsDraft:      mr.Draft,
+		URL:          mr.WebURL,
+		Repo:         repo,
+		Author:       mr.Author.Username,
+		HeadBranch:   mr.SourceBranch,
+		BaseBranch:   mr.TargetBranch,
+		CommentCount: mr.UserNotesCount,
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+	}
+}
+
+// gitlabState maps a GitLab merge request state to hangar's GitHub-style one.
+func gitlabState(state string, draft bool) string {
+	switch state {
+	case "opened":
+		if draft {
+			return "DRAFT"
+		}
+		return "OPEN"
+	case "merged":
+		return "MERGED"
+	default: // closed, locked
+		return "CLOSED"
+	}
+}
+
+// gitlabMergeState maps GitLab's detailed_merge_status to the GitHub
+// mergeStateStatus values MergeCheck understands.
+func gitlabMergeState(mr *glMergeRequest) string {
+	if mr.HasConflicts {
+		return "DIRTY"
+	}
+	switch mr.DetailedMergeStatus {
+	case "mergeable":
+		return "CLEAN"
+	case "conflict":
+		return "DIRTY"
+	case "need_rebase":
+		return "BEHIND"
+	case "draft_status":
+		return "DRAFT"
+	case "", "checking", "unchecked", "preparing", "approvals_syncing":
+		return "UNKNOWN"
+	default:
+		return "BLOCKED"
+	}
+}
+
+// reviewDecision maps approval state to a GitHub-style review decision.
+func (a *glApprovals) reviewDecision() string {
+	switch {
+	case a == nil:
+		return ""
+	case a.Approved && a.ApprovalsLeft == 0:
+		return "APPROVED"
+	case a.ApprovalsLeft > 0:
+		return "REVIEW_REQUIRED"
+	}
+	return ""
+}
+
+// applyGitLabJobs counts pipeline jobs into p's check totals.
+func applyGitLabJobs(p *PR, jobs []glJob) {
+	p.HasChecks = len(jobs) > 0
+	p.ChecksPassed, p.ChecksFailed, p.ChecksPending = 0, 0, 0
+	for _, j := range jobs {
+		switch j.Status {
+		case "success", "skipped", "manual":
+			p.ChecksPassed++
+		case "failed", "canceled":
+			if j.AllowFailure {
+				p.ChecksPassed++
+			} else {
+				p.ChecksFailed++
+			}
+		default:
+			p.ChecksPending++
+		}
+	}
+}
+
+// gitlabDetail assembles a PRDetail from a merge request and its notes,
+// approvals and changed files. Checks are applied separately.
+func gitlabDetail(mr *glMergeRequest, notes []glNote, appr *glApprovals, changes []glChange, repo string) *PRDetail {
+	detail := &PRDetail{PR: *mr.toPR(repo)}
+	detail.ReviewDecision = appr.reviewDecision()
+	detail.MergeState = gitlabMergeState(mr)
+	switch detail.MergeState {
+	case "CLEAN":
+		detail.Mergeability = "MERGEABLE"
+	case "DIRTY":
+		detail.Mergeability = "CONFLICTING"
+	default:
+		detail.Mergeability = "UNKNOWN"
+	}
+	if mr.MergeWhenPipelineSucceeds {
+		detail.AutoMerge = "merge"
+		if mr.Squash {
+			detail.AutoMerge = "squash"
+		}
+	}
+
+	for _, n := range notes {
+		if n.System {
+			continue
+		}
+		c := Comment{ID: n.ID, Author: n.Author.Username, Body: n.Body, CreatedAt: n.CreatedAt}
+		if n.Position != nil {
+			c.Path = n.Position.NewPath
+			c.Line = n.Position.NewLine
+		}
+		detail.Comments = append(detail.Comments, c)
+	}
+	if appr != nil {
+		for _, a := range appr.ApprovedBy {
+			detail.Reviews = append(detail.Reviews, Review{Author: a.User.Username, State: "APPROVED"})
+		}
+	}
+
+	// Cap the diff at 512 KB, as for GitHub.
+	const maxDiffBytes = 512 * 1024
+	var diff strings.Builder
+	for _, c := range changes {
+		fc := FileChange{Path: c.NewPath, Status: "MODIFIED"}
+		oldName, newName := "a/"+c.OldPath, "b/"+c.NewPath
+		switch {
+		case c.NewFile:
+			fc.Status = "ADDED"
+			oldName = "/dev/null"
+		case c.DeletedFile:
+			fc.Status = "DELETED"
+			newName = "/dev/null"
+		case c.RenamedFile:
+			fc.Status = "RENAMED"
+		}
+		for _, line := range strings.Split(c.Diff, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				fc.Additions++
+			case strings.HasPrefix(line, "-"):
+				fc.Deletions++
+			}
+		}
+		detail.Files = append(detail.Files, fc)
+		fmt.Fprintf(&diff, "diff --git a/%s b/%s\n--- %s\n+++ %s\n%s", c.OldPath, c.NewPath, oldName, newName, c.Diff)
+		if !strings.HasSuffix(c.Diff, "\n") {
+			diff.WriteString("\n")
+		}
+	}
+	if diff.Len() > maxDiffBytes {
+		detail.DiffContent = diff.String()[:maxDiffBytes] + "\n[Diff truncated — too large to display in full]"
+	} else {
+		detail.DiffContent = diff.String()
+	}
+	return detail
+}
+
+// gitlabUndraftTitle strips GitLab's draft markers from a title.
+func gitlabUndraftTitle(title string) string {
+	for {
+		lower := strings.ToLower(title)
+		trimmed := false
+		for _, prefix := range []string{"draft:", "[draft]", "(draft)", "wip:"} {
+			if strings.HasPrefix(lower, prefix) {
+				title = strings.TrimSpace(title[len(prefix):])
+				trimmed = true
+				break
+			}
+		}
+		if !trimmed {
+			return title
+		}
+	}
+}
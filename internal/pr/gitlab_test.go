@@ -0,0 +1,120 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+)
+
+const gitlabMRList = `[
+  {"iid":5,"title":"Draft: Add SSO","description":"Adds SSO.","state":"opened","draft":true,
+   "web_url":"https://gitlab.example.com/platform/auth/sso/-/merge_requests/5",
+   "author":{"username":"alice"},"source_branch":"feat/sso","target_branch":"main","user_notes_count":3,
+   "created_at":"2026-10-01T10:00:00Z","updated_at":"2026-10-02T10:00:00Z"},
+  {"iid":9,"title":"Fix login","state":"merged","web_url":"http://gitlab.com/o/r/-/merge_requests/9","author":{"username":"bob"}}
+]`
+
+func TestParseGitLabMRs(t *testing.T) {
+	prs, err := parseGitLabMRs([]byte(gitlabMRList), "")
+	if err != nil {
+		t.Fatalf("parseGitLabMRs: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("got %d PRs, want 2", len(prs))
+	}
+	p := prs[0]
+	if p.Number != 5 || p.Repo != "gitlab.example.com/platform/auth/sso" || p.State != "DRAFT" || !p.IsDraft ||
+		p.Author != "alice" || p.HeadBranch != "feat/sso" || p.BaseBranch != "main" || p.CommentCount != 3 || p.UpdatedAt.IsZero() {
+		t.Errorf("PR = %+v", p)
+	}
+	if p := prs[1]; p.Repo != "gitlab.com/o/r" || p.State != "MERGED" {
+		t.Errorf("PR = %+v", p)
+	}
+
+	prs, _ = parseGitLabMRs([]byte(gitlabMRList), "gitlab.com/fixed/repo")
+	if prs[0].Repo != "gitlab.com/fixed/repo" {
+		t.Errorf("repo = %q, want the one passed in", prs[0].Repo)
+	}
+}
+
+func TestGitLabDetail(t *testing.T) {
+	mr := &glMergeRequest{IID: 5, Title: "Add SSO", State: "opened", DetailedMergeStatus: "mergeable",
+		MergeWhenPipelineSucceeds: true, Squash: true, SourceBranch: "feat/sso", TargetBranch: "main"}
+	notes := []glNote{
+		{ID: 1, Body: "Looks good", Author: glUser{"bob"}},
+		{ID: 2, Body: "added 1 commit", System: true},
+		{ID: 3, Body: "Rename this", Author: glUser{"carol"}},
+	}
+	notes[2].Position = &struct {
+		NewPath string `json:"new_path"`
+		NewLine int    `json:"new_line"`
+	}{"sso.go", 12}
+	appr := &glApprovals{Approved: true}
+	appr.ApprovedBy = append(appr.ApprovedBy, struct {
+		User glUser `json:"user"`
+	}{glUser{"bob"}})
+	changes := []glChange{
+		{OldPath: "sso.go", NewPath: "sso.go", NewFile: true, Diff: "@@ -0,0 +1,2 @@\n+package auth\n+\n"},
+		{OldPath: "old.go", NewPath: "old.go", DeletedFile: true, Diff: "@@ -1 +0,0 @@\n-package auth"},
+	}
+
+	d := gitlabDetail(mr, notes, appr, changes, "gitlab.com/o/r")
+	if d.Mergeability != "MERGEABLE" || d.MergeState != "CLEAN" || d.AutoMerge != "squash" || d.ReviewDecision != "APPROVED" {
+		t.Errorf("detail = %+v", d)
+	}
+	if len(d.Comments) != 2 || d.Comments[1].Path != "sso.go" || d.Comments[1].Line != 12 {
+		t.Errorf("comments = %+v, want the 2 non-system notes", d.Comments)
+	}
+	if len(d.Reviews) != 1 || d.Reviews[0].Author != "bob" || d.Reviews[0].State != "APPROVED" {
+		t.Errorf("reviews = %+v", d.Reviews)
+	}
+	if len(d.Files) != 2 || d.Files[0].Status != "ADDED" || d.Files[0].Additions != 2 || d.Files[1].Status != "DELETED" || d.Files[1].Deletions != 1 {
+		t.Errorf("files = %+v", d.Files)
+	}
+	if !strings.Contains(d.DiffContent, "diff --git a/sso.go b/sso.go\n--- /dev/null\n+++ b/sso.go\n@@") ||
+		!strings.HasSuffix(d.DiffContent, "-package auth\n") {
+		t.Errorf("diff = %q", d.DiffContent)
+	}
+
+	mr.HasConflicts = true
+	if d := gitlabDetail(mr, nil, nil, nil, "gitlab.com/o/r"); d.Mergeability != "CONFLICTING" || d.ReviewDecision != "" {
+		t.Errorf("conflicting detail = %+v", d)
+	}
+	for status, want := range map[string]string{"need_rebase": "BEHIND", "not_approved": "BLOCKED", "checking": "UNKNOWN"} {
+		if got := gitlabMergeState(&glMergeRequest{DetailedMergeStatus: status}); got != want {
+			t.Errorf("gitlabMergeState(%s) = %s, want %s", status, got, want)
+		}
+	}
+}
+
+func TestApplyGitLabJobs(t *testing.T) {
+	p := &PR{}
+	applyGitLabJobs(p, []glJob{
+		{Status: "success"}, {Status: "skipped"}, {Status: "failed"},
+		{Status: "failed", AllowFailure: true}, {Status: "running"}, {Status: "pending"},
+	})
+	if !p.HasChecks || p.ChecksPassed != 3 || p.ChecksFailed != 1 || p.ChecksPending != 2 {
+		t.Errorf("checks = %+v", p)
+	}
+}
+
+func TestGitLabHelpers(t *testing.T) {
+	if got := glMRPath("gitlab.example.com/platform/auth/sso", 5); got != "projects/platform%2Fauth%2Fsso/merge_requests/5" {
+		t.Errorf("glMRPath = %s", got)
+	}
+	for title, want := range map[string]string{
+		"Draft: Add SSO":   "Add SSO",
+		"[Draft] Add SSO":  "Add SSO",
+		"(draft) WIP: SSO": "SSO",
+		"Add SSO":          "Add SSO",
+	} {
+		if got := gitlabUndraftTitle(title); got != want {
+			t.Errorf("gitlabUndraftTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+	if fields, err := glMergeFields("squash", true); err != nil || strings.Join(fields, ",") != "squash=true,should_remove_source_branch=true" {
+		t.Errorf("glMergeFields = %v, %v", fields, err)
+	}
+	if _, err := glMergeFields("rebase", false); err == nil {
+		t.Error("glMergeFields(rebase) should fail")
+	}
+}
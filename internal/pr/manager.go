@@ -2,11 +2,17 @@ package pr
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"os/exec"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
 )
 
 const (
@@ -16,24 +22,56 @@ const (
 	sessionPRTTL = 60 * time.Second
 	// detailTTL controls how long a fetched PRDetail stays cached.
 	detailTTL = 5 * time.Minute
+	// sessionBatchDelay is how long UpdateSessionPR waits to collect other
+	// sessions into the same batched lookup.
+	sessionBatchDelay = 250 * time.Millisecond
+	// webhookRefreshDelay coalesces the list refreshes triggered by a burst
+	// of webhook deliveries (e.g. one check_suite per CI app).
+	webhookRefreshDelay = 5 * time.Second
 )
 
+// SessionPRStore persists session PRs so badges show immediately after a
+// restart. *session.Storage implements it.
+type SessionPRStore interface {
+	LoadSessionPRs() ([]*statedb.SessionPRRow, error)
+	SaveSessionPR(row *statedb.SessionPRRow) error
+}
+
 // Manager is the central PR data layer. It maintains three PR lists:
 //   - sessionPRs: per-session, updated externally via UpdateSessionPR
 //   - myPRs: PRs authored by the current gh user
 //   - reviewPRs: PRs where the current user has been asked to review
 //
 // Background goroutines refresh myPRs and reviewPRs every 5 minutes.
-// All public methods are safe for concurrent use.
+// PRs are fetched through the Provider serving each repo's host (GitHub via
+// gh, GitLab via glab). All public methods are safe for concurrent use.
 type Manager struct {
 	mu     sync.RWMutex
 	ghPath string
 	ghUser string
 
+	// providers by name, and hostname → provider name overrides
+	providers     map[string]Provider
+	hostProviders map[string]string
+
 	// per-session PR state (keyed by sessionID)
 	sessionPRs    map[string]*PR
 	sessionFetched map[string]time.Time
 
+	// sessions waiting for the next batched lookup (sessionID → worktree path)
+	pendingSessions map[string]string
+	flushScheduled  bool
+	store           SessionPRStore
+
+	// sessions queued or being looked up, so callers polling faster than a
+	// lookup takes do not queue them again
+	inFlight map[string]bool
+
+	// last lookup of each session, matched against webhook events
+	sessionLookups map[string]SessionLookup
+	// a webhook-triggered list refresh is pending
+	webhookRefreshScheduled bool
+
 	// global lists
 	myPRs              []*PR
 	reviewPRs          []*PR
@@ -60,8 +98,13 @@ type Manager struct {
 func New() *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
+		providers:       make(map[string]Provider),
+		hostProviders:   make(map[string]string),
 		sessionPRs:      make(map[string]*PR),
 		sessionFetched:  make(map[string]time.Time),
+		pendingSessions: make(map[string]string),
+		inFlight:        make(map[string]bool),
+		sessionLookups:  make(map[string]SessionLookup),
 		detailCache:     make(map[string]*PRDetail),
 		detailFetchedAt: make(map[string]time.Time),
 		refreshCh:       make(chan struct{}, 1),
@@ -79,29 +122,79 @@ func (m *Manager) TriggerRefresh() {
 	}
 }
 
-// Start initialises gh detection and begins background refresh loops.
+// SetProvider registers p under its Name, replacing any provider of that name.
+func (m *Manager) SetProvider(p Provider) {
+	m.mu.Lock()
+	m.providers[p.Name()] = p
+	m.mu.Unlock()
+}
+
+// SetHostProviders sets which provider serves each git remote hostname, as
+// configured in [pr_providers]. Hosts not listed use GitHub, except
+// gitlab.com.
+func (m *Manager) SetHostProviders(hostProviders map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hostProviders = make(map[string]string, len(hostProviders))
+	for host, name := range hostProviders {
+		m.hostProviders[strings.ToLower(host)] = name
+	}
+}
+
+// ProviderFor returns the provider serving repo ("owner/repo" or
+// "host/owner/repo"), or nil when its CLI is not installed.
+func (m *Manager) ProviderFor(repo string) Provider {
+	return m.providerForHost(hostFromRepo(repo))
+}
+
+// ProviderNameFor returns the name of the provider configured for repo,
+// whether or not its CLI is installed.
+func (m *Manager) ProviderNameFor(repo string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return providerNameForHost(hostFromRepo(repo), m.hostProviders)
+}
+
+// providerForHost returns the provider serving host, or nil.
+func (m *Manager) providerForHost(host string) Provider {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.providers[providerNameForHost(host, m.hostProviders)]
+}
+
+// Start detects the gh and glab CLIs and begins background refresh loops.
 // It is non-blocking; the loops run as goroutines.
 func (m *Manager) Start() {
+	if cfg, err := session.LoadUserConfig(); err == nil && cfg != nil {
+		m.SetHostProviders(cfg.PRProviders)
+	}
 	ghPath, err := exec.LookPath("gh")
-	if err != nil {
-		slog.Warn("pr_manager: gh not found; PR features disabled")
+	if err == nil {
+		m.mu.Lock()
+		m.ghPath = ghPath
+		m.mu.Unlock()
+		m.SetProvider(NewGitHubProvider(ghPath))
+	}
+	glabPath, glabErr := exec.LookPath("glab")
+	if glabErr == nil {
+		m.SetProvider(NewGitLabProvider(glabPath))
+	}
+	if err != nil && glabErr != nil {
+		slog.Warn("pr_manager: neither gh nor glab found; PR features disabled")
 		return
 	}
-	m.mu.Lock()
-	m.ghPath = ghPath
-	m.mu.Unlock()
 
 	// Detect gh user asynchronously to avoid blocking startup
 	go func() {
-		user := DetectGHUser(ghPath)
-		m.mu.Lock()
-		m.ghUser = user
-		m.mu.Unlock()
-		slog.Debug("pr_manager: gh user detected", "user", user)
-
-		if user == "" {
-			slog.Warn("pr_manager: gh user empty; skipping global PR refresh")
-			return
+		if ghPath != "" {
+			user := DetectGHUser(ghPath)
+			m.mu.Lock()
+			m.ghUser = user
+			m.mu.Unlock()
+			slog.Debug("pr_manager: gh user detected", "user", user)
+			if user == "" {
+				slog.Warn("pr_manager: gh user empty; skipping GitHub PR lists")
+			}
 		}
 
 		// Initial fetch of global lists
@@ -236,6 +329,19 @@ func (m *Manager) GetSessionPR(sessionID string) (pr *PR, exists bool) {
 	return p, ok
 }
 
+// SessionForPR returns the ID of the session whose worktree branch has the
+// given PR open, or "" when no session is linked to it.
+func (m *Manager) SessionForPR(repo string, number int) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for sessionID, p := range m.sessionPRs {
+		if p != nil && p.Repo == repo && p.Number == number {
+			return sessionID
+		}
+	}
+	return ""
+}
+
 // SessionPRStaleAt returns when the session PR entry was last fetched.
 // exists is false if nothing has been cached yet.
 func (m *Manager) SessionPRStaleAt(sessionID string) (fetchedAt time.Time, exists bool) {
@@ -248,41 +354,231 @@ func (m *Manager) SessionPRStaleAt(sessionID string) (fetchedAt time.Time, exist
 // SetSessionPR stores a (possibly nil) PR for a session directly.
 // Used when the caller has already done the gh fetch (e.g. TUI migration path).
 func (m *Manager) SetSessionPR(sessionID string, p *PR) {
+	now := time.Now()
 	m.mu.Lock()
 	m.sessionPRs[sessionID] = p
-	m.sessionFetched[sessionID] = time.Now()
+	m.sessionFetched[sessionID] = now
+	store := m.store
 	m.mu.Unlock()
+	saveSessionPR(store, sessionID, p, now)
 	m.notifyChange()
 }
 
-// UpdateSessionPR triggers an async fetch for the given worktree session.
-// If a fresh result is already cached (within sessionPRTTL), this is a no-op.
+// LoadSessionPRCache restores session PRs saved by an earlier run from
+// store and saves fetched ones to it from now on. Entries keep their fetch
+// time, so stale ones are re-fetched on the next UpdateSessionPR.
+func (m *Manager) LoadSessionPRCache(store SessionPRStore) {
+	rows, err := store.LoadSessionPRs()
+	if err != nil {
+		slog.Debug("pr_manager: load session PR cache", "err", err)
+	}
+	m.mu.Lock()
+	m.store = store
+	for _, row := range rows {
+		if _, fetched := m.sessionFetched[row.SessionID]; fetched {
+			continue // already fetched in this run
+		}
+		var p *PR
+		if row.Data != "" {
+			p = &PR{}
+			if err := json.Unmarshal([]byte(row.Data), p); err != nil {
+				continue
+			}
+		}
+		m.sessionPRs[row.SessionID] = p
+		m.sessionFetched[row.SessionID] = row.FetchedAt
+	}
+	m.mu.Unlock()
+	if len(rows) > 0 {
+		m.notifyChange()
+	}
+}
+
+// saveSessionPR writes a session's PR to store, if there is one.
+func saveSessionPR(store SessionPRStore, sessionID string, p *PR, fetchedAt time.Time) {
+	if store == nil {
+		return
+	}
+	row := &statedb.SessionPRRow{SessionID: sessionID, FetchedAt: fetchedAt}
+	if p != nil {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return
+		}
+		row.Data = string(data)
+		row.UpdatedAt = p.UpdatedAt
+	}
+	if err := store.SaveSessionPR(row); err != nil {
+		slog.Debug("pr_manager: save session PR", "session", sessionID, "err", err)
+	}
+}
+
+// UpdateSessionPR schedules a fetch for the given worktree session.
+// If a fresh result is already cached (within sessionPRTTL), or a lookup is
+// already queued or running, this is a no-op. Sessions requested within
+// sessionBatchDelay of each other are looked up together, in one query per
+// repo where the provider supports it.
 func (m *Manager) UpdateSessionPR(sessionID, worktreePath string) {
-	ghPath := m.GHPath()
-	if ghPath == "" {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.providers) == 0 || m.inFlight[sessionID] {
 		return
 	}
+	if fetchedAt, ok := m.sessionFetched[sessionID]; ok && time.Since(fetchedAt) < sessionPRTTL {
+		return // still fresh
+	}
+	m.queueSessionLocked(sessionID, worktreePath)
+}
 
-	m.mu.RLock()
-	fetchedAt, hasFetch := m.sessionFetched[sessionID]
-	m.mu.RUnlock()
+// RefreshSessionPR is UpdateSessionPR ignoring the cache: the session is
+// looked up in the next batch even if its PR is fresh or a lookup is
+// already running.
+func (m *Manager) RefreshSessionPR(sessionID, worktreePath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.providers) == 0 {
+		return
+	}
+	m.queueSessionLocked(sessionID, worktreePath)
+}
 
-	if hasFetch && time.Since(fetchedAt) < sessionPRTTL {
-		return // still fresh
+// queueSessionLocked adds a session to the next batched lookup. m.mu must
+// be held.
+func (m *Manager) queueSessionLocked(sessionID, worktreePath string) {
+	m.pendingSessions[sessionID] = worktreePath
+	m.inFlight[sessionID] = true
+	if !m.flushScheduled {
+		m.flushScheduled = true
+		time.AfterFunc(sessionBatchDelay, m.flushSessionPRs)
 	}
+}
 
-	go func() {
-		p, err := FetchSessionPR(ghPath, worktreePath, sessionID)
-		if err != nil {
-			slog.Debug("pr_manager: session PR fetch error", "session", sessionID, "err", err)
-			return
-		}
+// flushSessionPRs looks up the PRs of all pending sessions, grouped by
+// provider. Results whose PR has not changed since the cached one are not
+// re-saved and do not fire change callbacks.
+func (m *Manager) flushSessionPRs() {
+	m.mu.Lock()
+	pending := m.pendingSessions
+	m.pendingSessions = make(map[string]string)
+	m.flushScheduled = false
+	m.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	defer func() {
 		m.mu.Lock()
-		m.sessionPRs[sessionID] = p
-		m.sessionFetched[sessionID] = time.Now()
+		for sessionID := range pending {
+			if _, queued := m.pendingSessions[sessionID]; !queued {
+				delete(m.inFlight, sessionID)
+			}
+		}
 		m.mu.Unlock()
-		m.notifyChange()
 	}()
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	results := make(map[string]*PR, len(pending))
+	byProvider := make(map[Provider][]SessionLookup)
+	var lookups []SessionLookup
+	for sessionID, dir := range pending {
+		repo, branch := lookupSession(dir)
+		if repo == "" || branch == "" {
+			results[sessionID] = nil // not a branch with a remote: no PR
+			continue
+		}
+		provider := m.ProviderFor(repo)
+		if provider == nil {
+			continue
+		}
+		l := SessionLookup{SessionID: sessionID, Dir: dir, Repo: repo, Branch: branch}
+		byProvider[provider] = append(byProvider[provider], l)
+		lookups = append(lookups, l)
+	}
+	for provider, lookups := range byProvider {
+		if batcher, ok := provider.(SessionBatcher); ok {
+			found, err := batcher.SessionPRs(lookups)
+			if err != nil {
+				slog.Debug("pr_manager: batched session PR fetch error", "provider", provider.Name(), "err", err)
+			}
+			for sessionID, p := range found {
+				results[sessionID] = p
+			}
+			continue
+		}
+		for _, l := range lookups {
+			p, err := provider.SessionPR(l.Dir, l.SessionID)
+			if err != nil {
+				slog.Debug("pr_manager: session PR fetch error", "session", l.SessionID, "err", err)
+				continue
+			}
+			results[l.SessionID] = p
+		}
+	}
+
+	now := time.Now()
+	changed, newHost := false, false
+	m.mu.Lock()
+	store := m.store
+	for _, l := range lookups {
+		m.sessionLookups[l.SessionID] = l
+	}
+	knownHosts := make(map[string]bool)
+	for _, p := range m.sessionPRs {
+		if p != nil {
+			knownHosts[hostFromRepo(p.Repo)] = true
+		}
+	}
+	// Failed lookups wait out the TTL too, so an outage is not retried on
+	// every poll.
+	for sessionID := range pending {
+		if _, ok := results[sessionID]; !ok {
+			m.sessionFetched[sessionID] = now
+		}
+	}
+	var toSave []string
+	for sessionID, p := range results {
+		old, known := m.sessionPRs[sessionID]
+		m.sessionFetched[sessionID] = now
+		if known && samePRStatus(old, p) {
+			continue
+		}
+		if p != nil && !knownHosts[hostFromRepo(p.Repo)] {
+			newHost = true
+		}
+		m.sessionPRs[sessionID] = p
+		toSave = append(toSave, sessionID)
+		changed = true
+	}
+	saved := make(map[string]*PR, len(toSave))
+	for _, sessionID := range toSave {
+		saved[sessionID] = m.sessionPRs[sessionID]
+	}
+	m.mu.Unlock()
+
+	for sessionID, p := range saved {
+		saveSessionPR(store, sessionID, p, now)
+	}
+	if changed {
+		m.notifyChange()
+	}
+	// The Mine/ReviewRequested lists search the hosts of session PRs, so
+	// re-run them when a session PR appears on a new one (e.g. a GHE host).
+	if newHost {
+		m.TriggerRefresh()
+	}
+}
+
+// samePRStatus reports whether b shows nothing new over a: the same PR with
+// the same updated-at, state, checks and review decision. Check runs do not
+// bump a PR's updated-at, so they are compared separately.
+func samePRStatus(a, b *PR) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.URL == b.URL && a.UpdatedAt.Equal(b.UpdatedAt) && a.State == b.State &&
+		a.ReviewDecision == b.ReviewDecision && a.HasChecks == b.HasChecks &&
+		a.ChecksPassed == b.ChecksPassed && a.ChecksFailed == b.ChecksFailed && a.ChecksPending == b.ChecksPending
 }
 
 // FetchDetail returns full PR detail. It is fetched lazily and cached for detailTTL.
@@ -299,12 +595,12 @@ func (m *Manager) FetchDetail(repo string, number int) (*PRDetail, error) {
 		return detail, nil
 	}
 
-	ghPath := m.GHPath()
-	if ghPath == "" {
+	provider := m.ProviderFor(repo)
+	if provider == nil {
 		return nil, nil
 	}
 
-	d, err := FetchDetail(ghPath, repo, number)
+	d, err := provider.Detail(repo, number)
 	if err != nil {
 		return nil, err
 	}
@@ -317,8 +613,82 @@ func (m *Manager) FetchDetail(repo string, number int) (*PRDetail, error) {
 	return d, nil
 }
 
+// ApplyWebhook brings the cached PRs up to date with a GitHub webhook event.
+// Matching PRs get the state carried by pull_request events straight away,
+// their cached details are dropped, the sessions on the event's branches
+// are re-fetched in the next batch, and the Mine/ReviewRequested lists are
+// refreshed shortly after. It returns the numbers of the PRs affected.
+func (m *Manager) ApplyWebhook(ev *WebhookEvent) []int {
+	numbers := slices.Clone(ev.Numbers)
+	updated, listHit := false, false
+	saved := make(map[string]*PR)
+	m.mu.Lock()
+	store := m.store
+	for id, p := range m.sessionPRs {
+		if ev.matches(p) {
+			m.sessionPRs[id] = ev.applyTo(p)
+			saved[id] = m.sessionPRs[id]
+			numbers = append(numbers, p.Number)
+			updated = true
+		}
+	}
+	for _, list := range [][]*PR{m.myPRs, m.reviewPRs} {
+		for i, p := range list {
+			if ev.matches(p) {
+				list[i] = ev.applyTo(p)
+				numbers = append(numbers, p.Number)
+				updated, listHit = true, true
+			}
+		}
+	}
+	slices.Sort(numbers)
+	numbers = slices.Compact(numbers)
+	for _, n := range numbers {
+		key := ev.Repo + "#" + itoa(n)
+		delete(m.detailCache, key)
+		delete(m.detailFetchedAt, key)
+	}
+
+	// Re-fetch the sessions on the event's branches, which also links a
+	// session to a PR just opened from it.
+	for id, l := range m.sessionLookups {
+		if !strings.EqualFold(l.Repo, ev.Repo) {
+			continue
+		}
+		p := m.sessionPRs[id]
+		if slices.Contains(ev.Branches, l.Branch) || (p != nil && slices.Contains(numbers, p.Number)) {
+			m.queueSessionLocked(id, l.Dir)
+		}
+	}
+
+	// List membership and checks come from the searches, so re-run them
+	// when a listed PR changed or a PR was opened, closed or re-requested.
+	if (listHit || ev.Event == "pull_request") && !m.webhookRefreshScheduled {
+		m.webhookRefreshScheduled = true
+		time.AfterFunc(webhookRefreshDelay, func() {
+			m.mu.Lock()
+			m.webhookRefreshScheduled = false
+			m.mu.Unlock()
+			m.TriggerRefresh()
+		})
+	}
+	fetchedAt := make(map[string]time.Time, len(saved))
+	for id := range saved {
+		fetchedAt[id] = m.sessionFetched[id]
+	}
+	m.mu.Unlock()
+
+	for id, p := range saved {
+		saveSessionPR(store, id, p, fetchedAt[id])
+	}
+	if updated {
+		m.notifyChange()
+	}
+	return numbers
+}
+
 // InvalidateDetail removes a cached PRDetail so the next call to FetchDetail
-// re-fetches from GitHub (e.g. after posting a review).
+// re-fetches from the provider (e.g. after posting a review).
 func (m *Manager) InvalidateDetail(repo string, number int) {
 	key := repo + "#" + itoa(number)
 	m.mu.Lock()
@@ -327,36 +697,86 @@ func (m *Manager) InvalidateDetail(repo string, number int) {
 	m.mu.Unlock()
 }
 
-// inferGHHost returns the dominant GH host from known session PRs, or "".
-// Used to set GH_HOST when running gh search prs on GHE instances.
-func (m *Manager) inferGHHost() string {
+// hostsFor returns the hosts other than github.com served by the named
+// provider: those configured in [pr_providers] plus those of session PRs.
+func (m *Manager) hostsFor(name string) []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(host string) {
+		if host == "" || host == "github.com" || seen[host] {
+			return
+		}
+		if providerNameForHost(host, m.hostProviders) == name {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	for host := range m.hostProviders {
+		add(host)
+	}
 	for _, p := range m.sessionPRs {
 		if p != nil {
-			if h := hostFromRepo(p.Repo); h != "" && h != "github.com" {
-				return h
+			add(strings.ToLower(hostFromRepo(p.Repo)))
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// searchAll runs search on every provider with the hosts it serves and
+// merges the results, tagged with source. A provider whose search fails
+// keeps its entries from previous. ok is false when no provider could be
+// searched, so the caller keeps its previous list.
+func (m *Manager) searchAll(source PRSource, previous []*PR, search func(Provider, []string) ([]*PR, error)) (prs []*PR, ok bool) {
+	m.mu.RLock()
+	providers := make([]Provider, 0, len(m.providers))
+	for _, p := range m.providers {
+		providers = append(providers, p)
+	}
+	ghUser := m.ghUser
+	hostProviders := m.hostProviders
+	m.mu.RUnlock()
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name() < providers[j].Name() })
+
+	for _, provider := range providers {
+		hosts := m.hostsFor(provider.Name())
+		switch provider.Name() {
+		case ProviderGitHub:
+			if ghUser == "" {
+				continue // gh is not authenticated
+			}
+		case ProviderGitLab:
+			if len(hosts) == 0 {
+				continue // no GitLab remotes known yet
+			}
+		}
+		found, err := search(provider, hosts)
+		if err != nil {
+			slog.Debug("pr_manager: search error", "provider", provider.Name(), "source", source, "err", err)
+			for _, p := range previous {
+				if providerNameForHost(hostFromRepo(p.Repo), hostProviders) == provider.Name() {
+					prs = append(prs, p)
+				}
 			}
+			continue
 		}
+		ok = true
+		for _, p := range found {
+			p.Source = source
+		}
+		prs = append(prs, found...)
 	}
-	return ""
+	return prs, ok
 }
 
 // refreshMyPRs fetches the current user's open PRs and updates the cache.
 func (m *Manager) refreshMyPRs() {
-	ghPath := m.GHPath()
-	if ghPath == "" {
-		return
-	}
-	prs, err := FetchMyPRs(ghPath, m.inferGHHost())
-	if err != nil {
-		slog.Debug("pr_manager: fetch my PRs error", "err", err)
+	prs, ok := m.searchAll(SourceMine, m.GetMine(), Provider.SearchMine)
+	if !ok {
 		return
 	}
-	for _, p := range prs {
-		p.Source = SourceMine
-	}
-	enrichChecksForPRs(ghPath, prs)
 	m.mu.Lock()
 	m.myPRs = prs
 	m.myPRsLastFetch = time.Now()
@@ -367,19 +787,10 @@ func (m *Manager) refreshMyPRs() {
 
 // refreshReviewPRs fetches PRs where review was requested from the current user.
 func (m *Manager) refreshReviewPRs() {
-	ghPath := m.GHPath()
-	if ghPath == "" {
+	prs, ok := m.searchAll(SourceReviewRequested, m.GetReviewRequested(), Provider.SearchReviewRequested)
+	if !ok {
 		return
 	}
-	prs, err := FetchReviewRequestedPRs(ghPath, m.inferGHHost())
-	if err != nil {
-		slog.Debug("pr_manager: fetch review PRs error", "err", err)
-		return
-	}
-	for _, p := range prs {
-		p.Source = SourceReviewRequested
-	}
-	enrichChecksForPRs(ghPath, prs)
 	m.mu.Lock()
 	m.reviewPRs = prs
 	m.reviewPRsLastFetch = time.Now()
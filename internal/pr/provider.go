@@ -0,0 +1,158 @@
+package pr
+
+import "strings"
+
+// Provider is a code host the PR layer talks to: GitHub through the gh CLI
+// or GitLab through glab. Repos are passed in hangar's "owner/repo" or
+// "host/owner/repo" form; numbers are PR numbers (merge request IIDs on
+// GitLab).
+type Provider interface {
+	// Name returns the provider's config name, e.g. "github" or "gitlab".
+	Name() string
+
+	// SearchMine returns open PRs authored by the current user.
+	// hosts are the non-default hosts of this provider known to hangar.
+	SearchMine(hosts []string) ([]*PR, error)
+	// SearchReviewRequested returns open PRs awaiting the current user's review.
+	SearchReviewRequested(hosts []string) ([]*PR, error)
+	// SessionPR returns the PR for the branch checked out in dir, or nil
+	// (no error) when the branch has none.
+	SessionPR(dir, sessionID string) (*PR, error)
+	// Detail returns full PR detail including diff, comments and reviews.
+	Detail(repo string, number int) (*PRDetail, error)
+
+	Approve(repo string, number int, body string) error
+	RequestChanges(repo string, number int, body string) error
+	Comment(repo string, number int, body, path string, line int) error
+	Close(repo string, number int) error
+	Reopen(repo string, number int) error
+	Ready(repo string, number int) error
+	Draft(repo string, number int) error
+	Merge(repo string, number int, method string, deleteBranch bool) error
+	EnableAutoMerge(repo string, number int, method string, deleteBranch bool) error
+	DisableAutoMerge(repo string, number int) error
+}
+
+// Provider names, as used in the [pr_providers] config section.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// ProviderNames lists the providers hangar knows about.
+var ProviderNames = []string{ProviderGitHub, ProviderGitLab}
+
+// providerNameForHost returns the provider serving host. hostProviders maps
+// hostnames to provider names (from [pr_providers]); gitlab.com is GitLab
+// and everything else GitHub unless configured otherwise.
+func providerNameForHost(host string, hostProviders map[string]string) string {
+	host = strings.ToLower(host)
+	if name, ok := hostProviders[host]; ok && name != "" {
+		return name
+	}
+	if host == "gitlab.com" {
+		return ProviderGitLab
+	}
+	return ProviderGitHub
+}
+
+// GitHubProvider implements Provider with the gh CLI.
+type GitHubProvider struct {
+	GHPath string
+}
+
+// NewGitHubProvider returns a Provider that runs the gh binary at ghPath.
+func NewGitHubProvider(ghPath string) *GitHubProvider {
+	return &GitHubProvider{GHPath: ghPath}
+}
+
+func (g *GitHubProvider) Name() string { return ProviderGitHub }
+
+func (g *GitHubProvider) SearchMine(hosts []string) ([]*PR, error) {
+	return g.search(hosts, "--author")
+}
+
+func (g *GitHubProvider) SearchReviewRequested(hosts []string) ([]*PR, error) {
+	return g.search(hosts, "--review-requested")
+}
+
+// search runs `gh search prs` against github.com and each GHE host, merges
+// the results by URL and fills in their checks and review decisions.
+func (g *GitHubProvider) search(hosts []string, filterFlag string) ([]*PR, error) {
+	seen := make(map[string]*PR)
+	var lastErr error
+	ok := false
+	for _, host := range append([]string{""}, hosts...) {
+		if host == "github.com" {
+			continue
+		}
+		prs, err := fetchSearchPRs(g.GHPath, host, filterFlag, "@me")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ok = true
+		for _, p := range prs {
+			if _, exists := seen[p.URL]; !exists {
+				seen[p.URL] = p
+			}
+		}
+	}
+	if !ok {
+		return nil, lastErr
+	}
+	out := make([]*PR, 0, len(seen))
+	for _, p := range seen {
+		out = append(out, p)
+	}
+	enrichChecksForPRs(g.GHPath, out)
+	return out, nil
+}
+
+func (g *GitHubProvider) SessionPR(dir, sessionID string) (*PR, error) {
+	return FetchSessionPR(g.GHPath, dir, sessionID)
+}
+
+func (g *GitHubProvider) Detail(repo string, number int) (*PRDetail, error) {
+	return FetchDetail(g.GHPath, repo, number)
+}
+
+func (g *GitHubProvider) Approve(repo string, number int, body string) error {
+	return Approve(g.GHPath, repo, number, body)
+}
+
+func (g *GitHubProvider) RequestChanges(repo string, number int, body string) error {
+	return RequestChanges(g.GHPath, repo, number, body)
+}
+
+func (g *GitHubProvider) Comment(repo string, number int, body, path string, line int) error {
+	return AddComment(g.GHPath, repo, number, body, path, line)
+}
+
+func (g *GitHubProvider) Close(repo string, number int) error {
+	return Close(g.GHPath, repo, number)
+}
+
+func (g *GitHubProvider) Reopen(repo string, number int) error {
+	return Reopen(g.GHPath, repo, number)
+}
+
+func (g *GitHubProvider) Ready(repo string, number int) error {
+	return ConvertToReady(g.GHPath, repo, number)
+}
+
+func (g *GitHubProvider) Draft(repo string, number int) error {
+	return ConvertToDraft(g.GHPath, repo, number)
+}
+
+func (g *GitHubProvider) Merge(repo string, number int, method string, deleteBranch bool) error {
+	return Merge(g.GHPath, repo, number, method, deleteBranch)
+}
+
+func (g *GitHubProvider) EnableAutoMerge(repo string, number int, method string, deleteBranch bool) error {
+	return EnableAutoMerge(g.GHPath, repo, number, method, deleteBranch)
+}
+
+func (g *GitHubProvider) DisableAutoMerge(repo string, number int) error {
+	return DisableAutoMerge(g.GHPath, repo, number)
+}
@@ -0,0 +1,80 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProviderNameForHost(t *testing.T) {
+	hosts := map[string]string{"gitlab.corp.example.com": "gitlab", "code.example.com": "github"}
+	for host, want := range map[string]string{
+		"":                        ProviderGitHub,
+		"github.com":              ProviderGitHub,
+		"ghe.example.com":         ProviderGitHub,
+		"gitlab.com":              ProviderGitLab,
+		"GitLab.Corp.Example.com": ProviderGitLab,
+		"code.example.com":        ProviderGitHub,
+	} {
+		if got := providerNameForHost(host, hosts); got != want {
+			t.Errorf("providerNameForHost(%q) = %s, want %s", host, got, want)
+		}
+	}
+}
+
+func TestManagerRoutesByProvider(t *testing.T) {
+	gh := NewFakeProvider(ProviderGitHub)
+	gh.Mine = []*PR{{Number: 1, Repo: "o/r", URL: "https://github.com/o/r/pull/1"}}
+	gh.Details["o/r#1"] = &PRDetail{PR: PR{Number: 1, Title: "from github"}}
+	gl := NewFakeProvider(ProviderGitLab)
+	gl.Mine = []*PR{{Number: 2, Repo: "gitlab.corp/g/r", URL: "https://gitlab.corp/g/r/-/merge_requests/2"}}
+	gl.Details["gitlab.corp/g/r#2"] = &PRDetail{PR: PR{Number: 2, Title: "from gitlab"}}
+
+	m := New()
+	defer m.Stop()
+	m.SetProvider(gh)
+	m.SetProvider(gl)
+	m.SetHostProviders(map[string]string{"gitlab.corp": "gitlab"})
+	m.ghUser = "me"
+
+	if p := m.ProviderFor("o/r"); p != gh {
+		t.Errorf("ProviderFor(o/r) = %v, want github", p)
+	}
+	if p := m.ProviderFor("gitlab.corp/g/r"); p != gl {
+		t.Errorf("ProviderFor(gitlab.corp/g/r) = %v, want gitlab", p)
+	}
+	if d, err := m.FetchDetail("gitlab.corp/g/r", 2); err != nil || d.Title != "from gitlab" {
+		t.Errorf("FetchDetail = %+v, %v", d, err)
+	}
+
+	m.refreshMyPRs()
+	var got []string
+	for _, p := range m.GetMine() {
+		if p.Source != SourceMine {
+			t.Errorf("PR %s#%d source = %v", p.Repo, p.Number, p.Source)
+		}
+		got = append(got, p.Repo)
+	}
+	if strings.Join(got, ",") != "o/r,gitlab.corp/g/r" {
+		t.Errorf("mine = %v, want PRs from both providers", got)
+	}
+
+	// A failing provider keeps its last results while the others refresh.
+	gl.Err = errors.New("glab failed")
+	gh.Mine = nil
+	m.refreshMyPRs()
+	if mine := m.GetMine(); len(mine) != 1 || mine[0].Repo != "gitlab.corp/g/r" {
+		t.Errorf("mine with gitlab failing = %+v", mine)
+	}
+
+	// Without glab installed, GitLab repos have no provider.
+	m2 := New()
+	defer m2.Stop()
+	m2.SetProvider(gh)
+	if p := m2.ProviderFor("gitlab.com/g/r"); p != nil {
+		t.Errorf("ProviderFor(gitlab.com/g/r) = %v, want nil", p)
+	}
+	if name := m2.ProviderNameFor("gitlab.com/g/r"); name != ProviderGitLab {
+		t.Errorf("ProviderNameFor = %s", name)
+	}
+}
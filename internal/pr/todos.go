@@ -0,0 +1,169 @@
+package pr
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+// todoStore is the subset of session.Storage the reconciler uses.
+type todoStore interface {
+	FindTodoBySessionID(sessionID string) (*session.Todo, error)
+	UpdateTodoStatus(id string, status session.TodoStatus, sessionID string) error
+	Close() error
+}
+
+// TodoReconciler moves todos linked to sessions along with their PR:
+// opened → in_review, merged → done, closed without merging → the project's
+// closed_pr_todo_status. It only reacts when a session's PR state changes, so
+// a card moved by hand stays put until the PR moves again. Projects opt out
+// with auto_advance_todos = false in projects.toml.
+type TodoReconciler struct {
+	manager *Manager
+	kick    chan struct{}
+
+	mu   sync.Mutex
+	seen map[string]string // sessionID -> PR state last reconciled
+
+	isLeader func() bool // set by Start; nil means always
+
+	// Overridable in tests.
+	openStore    func() (todoStore, error)
+	loadProjects func() ([]*session.Project, error)
+}
+
+// NewTodoReconciler creates a reconciler for m's session PRs and profile's todos.
+func NewTodoReconciler(m *Manager, profile string) *TodoReconciler {
+	return &TodoReconciler{
+		manager: m,
+		kick:    make(chan struct{}, 1),
+		seen:    make(map[string]string),
+		openStore: func() (todoStore, error) {
+			return session.NewStorageWithProfile(profile)
+		},
+		loadProjects: session.LoadProjects,
+	}
+}
+
+// Start registers with the manager and reconciles on every PR change until
+// ctx is cancelled. Work runs on its own goroutine because change callbacks
+// must not call back into the Manager.
+//
+// The TUI and "hangar web" both watch the same sessions' PRs, so todos are
+// only updated while isLeader (if non-nil) reports true; otherwise changes
+// are recorded and skipped, so that one process moves each todo once.
+//
+// The PR states already known (e.g. restored from the PR cache) are taken as
+// reconciled, so a restart does not re-apply old merges and closes over
+// cards moved by hand since.
+func (r *TodoReconciler) Start(ctx context.Context, isLeader func() bool) {
+	r.mu.Lock()
+	r.isLeader = isLeader
+	for sid, p := range r.manager.GetSessionPRs() {
+		if p != nil {
+			r.seen[sid] = p.State
+		}
+	}
+	r.mu.Unlock()
+	r.manager.RegisterOnChange(func() {
+		select {
+		case r.kick <- struct{}{}:
+		default: // already queued
+		}
+	})
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.kick:
+				r.Reconcile()
+			}
+		}
+	}()
+}
+
+// Reconcile applies PR state changes seen since the last call to linked todos.
+func (r *TodoReconciler) Reconcile() {
+	changed := make(map[string]string)
+	r.mu.Lock()
+	for sid, p := range r.manager.GetSessionPRs() {
+		if p != nil && r.seen[sid] != p.State {
+			changed[sid] = p.State
+		}
+	}
+	isLeader := r.isLeader
+	r.mu.Unlock()
+	if len(changed) == 0 {
+		return
+	}
+	if isLeader != nil && !isLeader() {
+		r.mu.Lock()
+		for sid, state := range changed {
+			r.seen[sid] = state
+		}
+		r.mu.Unlock()
+		return
+	}
+
+	store, err := r.openStore()
+	if err != nil {
+		slog.Warn("pr_todos: open storage failed", "err", err)
+		return
+	}
+	defer store.Close()
+	projects, _ := r.loadProjects()
+
+	for sid, state := range changed {
+		todo, err := store.FindTodoBySessionID(sid)
+		if err != nil {
+			continue // retry on the next change
+		}
+		r.mu.Lock()
+		r.seen[sid] = state
+		r.mu.Unlock()
+		if todo == nil {
+			continue
+		}
+
+		closedStatus := session.TodoStatusTodo
+		if p := session.ProjectForPath(projects, todo.ProjectPath); p != nil {
+			if !p.AdvancesTodos() {
+				continue
+			}
+			closedStatus = p.ClosedPRStatus()
+		}
+		next, ok := TodoStatusForPR(todo.Status, state, closedStatus)
+		if !ok {
+			continue
+		}
+		if err := store.UpdateTodoStatus(todo.ID, next, sid); err != nil {
+			slog.Warn("pr_todos: update failed", "todo", todo.ID, "err", err)
+			continue
+		}
+		slog.Debug("pr_todos: advanced", "todo", todo.ID, "pr_state", state, "status", next)
+	}
+}
+
+// TodoStatusForPR returns the status a todo currently in cur should move to
+// for a PR in state, or false if it should stay where it is. closedStatus is
+// used when an in-review todo's PR is closed without merging.
+func TodoStatusForPR(cur session.TodoStatus, state string, closedStatus session.TodoStatus) (session.TodoStatus, bool) {
+	switch state {
+	case "OPEN", "DRAFT":
+		if cur != session.TodoStatusInReview && cur != session.TodoStatusDone {
+			return session.TodoStatusInReview, true
+		}
+	case "MERGED":
+		if cur != session.TodoStatusDone {
+			return session.TodoStatusDone, true
+		}
+	case "CLOSED":
+		if cur == session.TodoStatusInReview && closedStatus != cur {
+			return closedStatus, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,154 @@
+package pr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sjoeboo/hangar/internal/session"
+)
+
+type fakeTodoStore struct {
+	todos map[string]*session.Todo // keyed by session ID
+}
+
+func (f *fakeTodoStore) FindTodoBySessionID(sessionID string) (*session.Todo, error) {
+	return f.todos[sessionID], nil
+}
+
+func (f *fakeTodoStore) UpdateTodoStatus(id string, status session.TodoStatus, sessionID string) error {
+	f.todos[sessionID].Status = status
+	return nil
+}
+
+func (f *fakeTodoStore) Close() error { return nil }
+
+func newTestReconciler(store *fakeTodoStore, projects ...*session.Project) (*TodoReconciler, *Manager) {
+	m := New()
+	r := NewTodoReconciler(m, "_test")
+	r.openStore = func() (todoStore, error) { return store, nil }
+	r.loadProjects = func() ([]*session.Project, error) { return projects, nil }
+	return r, m
+}
+
+func TestTodoReconciler(t *testing.T) {
+	todo := &session.Todo{ID: "t1", ProjectPath: "/repo", Status: session.TodoStatusInProgress, SessionID: "s1"}
+	store := &fakeTodoStore{todos: map[string]*session.Todo{"s1": todo}}
+	r, m := newTestReconciler(store)
+
+	step := func(state string, want session.TodoStatus) {
+		t.Helper()
+		m.SetSessionPR("s1", &PR{Number: 1, State: state, SessionID: "s1"})
+		r.Reconcile()
+		if todo.Status != want {
+			t.Errorf("after PR %s: status = %s, want %s", state, todo.Status, want)
+		}
+	}
+
+	step("OPEN", session.TodoStatusInReview)
+
+	// A manual move sticks while the PR state is unchanged.
+	todo.Status = session.TodoStatusInProgress
+	step("OPEN", session.TodoStatusInProgress)
+
+	step("DRAFT", session.TodoStatusInReview)
+	step("CLOSED", session.TodoStatusTodo)
+	step("OPEN", session.TodoStatusInReview)
+	step("MERGED", session.TodoStatusDone)
+}
+
+func TestTodoReconciler_StartSkipsKnownStates(t *testing.T) {
+	// The todo was moved back by hand after its PR merged in an earlier run.
+	todo := &session.Todo{ID: "t1", ProjectPath: "/repo", Status: session.TodoStatusInProgress, SessionID: "s1"}
+	store := &fakeTodoStore{todos: map[string]*session.Todo{"s1": todo}}
+	r, m := newTestReconciler(store)
+	m.SetSessionPR("s1", &PR{Number: 1, State: "MERGED", SessionID: "s1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Start(ctx, nil)
+	r.Reconcile()
+	if todo.Status != session.TodoStatusInProgress {
+		t.Errorf("status = %s, want the manual move kept after restart", todo.Status)
+	}
+
+	m.SetSessionPR("s1", &PR{Number: 2, State: "OPEN", SessionID: "s1"})
+	r.Reconcile()
+	if todo.Status != session.TodoStatusInReview {
+		t.Errorf("status = %s, want in_review once the PR state changes", todo.Status)
+	}
+}
+
+func TestTodoReconciler_FollowerSkips(t *testing.T) {
+	todo := &session.Todo{ID: "t1", ProjectPath: "/repo", Status: session.TodoStatusInProgress, SessionID: "s1"}
+	store := &fakeTodoStore{todos: map[string]*session.Todo{"s1": todo}}
+	r, m := newTestReconciler(store)
+	leader := false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Start(ctx, func() bool { return leader })
+	m.SetSessionPR("s1", &PR{Number: 1, State: "OPEN", SessionID: "s1"})
+	r.Reconcile()
+	if todo.Status != session.TodoStatusInProgress {
+		t.Errorf("status = %s, want it left to the leader", todo.Status)
+	}
+
+	// A change already seen as a follower is not applied after a takeover.
+	leader = true
+	r.Reconcile()
+	if todo.Status != session.TodoStatusInProgress {
+		t.Errorf("status = %s after takeover, want the seen change skipped", todo.Status)
+	}
+	m.SetSessionPR("s1", &PR{Number: 1, State: "MERGED", SessionID: "s1"})
+	r.Reconcile()
+	if todo.Status != session.TodoStatusDone {
+		t.Errorf("status = %s, want done once the leader sees the merge", todo.Status)
+	}
+}
+
+func TestTodoReconciler_ProjectSettings(t *testing.T) {
+	optOut := false
+	projects := []*session.Project{
+		{Name: "quiet", BaseDir: "/quiet", AutoAdvanceTodos: &optOut},
+		{Name: "strict", BaseDir: "/strict", ClosedPRTodoStatus: "orphaned"},
+	}
+	quiet := &session.Todo{ID: "q", ProjectPath: "/quiet", Status: session.TodoStatusInProgress, SessionID: "sq"}
+	strict := &session.Todo{ID: "s", ProjectPath: "/strict", Status: session.TodoStatusInReview, SessionID: "ss"}
+	store := &fakeTodoStore{todos: map[string]*session.Todo{"sq": quiet, "ss": strict}}
+	r, m := newTestReconciler(store, projects...)
+
+	m.SetSessionPR("sq", &PR{State: "MERGED"})
+	m.SetSessionPR("ss", &PR{State: "CLOSED"})
+	r.Reconcile()
+
+	if quiet.Status != session.TodoStatusInProgress {
+		t.Errorf("opted-out project: status = %s, want in_progress", quiet.Status)
+	}
+	if strict.Status != session.TodoStatusOrphaned {
+		t.Errorf("closed_pr_todo_status=orphaned: status = %s, want orphaned", strict.Status)
+	}
+}
+
+func TestTodoStatusForPR(t *testing.T) {
+	tests := []struct {
+		cur   session.TodoStatus
+		state string
+		want  session.TodoStatus
+		ok    bool
+	}{
+		{session.TodoStatusTodo, "OPEN", session.TodoStatusInReview, true},
+		{session.TodoStatusInReview, "OPEN", "", false},
+		{session.TodoStatusDone, "OPEN", "", false},
+		{session.TodoStatusInProgress, "MERGED", session.TodoStatusDone, true},
+		{session.TodoStatusDone, "MERGED", "", false},
+		{session.TodoStatusInReview, "CLOSED", session.TodoStatusTodo, true},
+		{session.TodoStatusInProgress, "CLOSED", "", false},
+		{session.TodoStatusDone, "CLOSED", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := TodoStatusForPR(tt.cur, tt.state, session.TodoStatusTodo)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("TodoStatusForPR(%s, %s) = %s, %v; want %s, %v", tt.cur, tt.state, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -48,6 +48,8 @@ type PR struct {
 type PRDetail struct {
 	PR
 	Mergeability string     `json:"mergeability,omitempty"`
+	MergeState   string     `json:"merge_state,omitempty"` // GitHub's mergeStateStatus: CLEAN, BLOCKED, BEHIND, DIRTY, UNSTABLE...
+	AutoMerge    string     `json:"auto_merge,omitempty"`  // merge method auto-merge will use, or "" when it is off
 	Comments     []Comment  `json:"comments"`
 	Reviews      []Review   `json:"reviews"`
 	Files        []FileChange `json:"files"`
@@ -0,0 +1,176 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookEvent is the part of a GitHub webhook delivery that hangar acts on:
+// the repo and the PRs or branches it concerns.
+type WebhookEvent struct {
+	Event    string // X-GitHub-Event header, e.g. "pull_request"
+	Action   string
+	Repo     string   // "owner/repo" or "host/owner/repo"
+	Numbers  []int    // PRs named by the event
+	Branches []string // head branches named by the event
+	PR       *PR      // the PR's new state, for pull_request events
+}
+
+// ghWebhookPR is a pull request as sent in webhook payloads.
+type ghWebhookPR struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"` // open, closed
+	Draft   bool   `json:"draft"`
+	Merged  bool   `json:"merged"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ghWebhookPayload holds the fields of the supported events' payloads.
+type ghWebhookPayload struct {
+	Action      string       `json:"action"`
+	PullRequest *ghWebhookPR `json:"pull_request"`
+	CheckSuite  *struct {
+		HeadBranch   string `json:"head_branch"`
+		PullRequests []struct {
+			Number int `json:"number"`
+			Head   struct {
+				Ref string `json:"ref"`
+			} `json:"head"`
+		} `json:"pull_requests"`
+	} `json:"check_suite"`
+	Branches []struct {
+		Name string `json:"name"`
+	} `json:"branches"` // status events
+	Repository *struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// ParseGitHubWebhook decodes a GitHub webhook delivery of the given event
+// type. It returns nil, nil for events hangar does not act on; the supported
+// ones are pull_request, pull_request_review, check_suite and status.
+func ParseGitHubWebhook(event string, payload []byte) (*WebhookEvent, error) {
+	switch event {
+	case "pull_request", "pull_request_review", "check_suite", "status":
+	default:
+		return nil, nil
+	}
+	var raw ghWebhookPayload
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", event, err)
+	}
+	if raw.Repository == nil {
+		return nil, fmt.Errorf("%s payload has no repository", event)
+	}
+	repo := repoFromWebURL(raw.Repository.HTMLURL)
+	if repo == "" {
+		return nil, fmt.Errorf("%s payload has an invalid repository URL %q", event, raw.Repository.HTMLURL)
+	}
+	ev := &WebhookEvent{Event: event, Action: raw.Action, Repo: repo}
+	switch event {
+	case "pull_request", "pull_request_review":
+		if raw.PullRequest == nil {
+			return nil, fmt.Errorf("%s payload has no pull_request", event)
+		}
+		ev.Numbers = []int{raw.PullRequest.Number}
+		ev.Branches = []string{raw.PullRequest.Head.Ref}
+		if event == "pull_request" {
+			ev.PR = raw.PullRequest.toPR(repo)
+		}
+	case "check_suite":
+		if cs := raw.CheckSuite; cs != nil {
+			for _, p := range cs.PullRequests {
+				ev.Numbers = append(ev.Numbers, p.Number)
+				ev.Branches = append(ev.Branches, p.Head.Ref)
+			}
+			ev.Branches = append(ev.Branches, cs.HeadBranch)
+		}
+	case "status":
+		for _, b := range raw.Branches {
+			ev.Branches = append(ev.Branches, b.Name)
+		}
+	}
+	slices.Sort(ev.Branches)
+	ev.Branches = slices.Compact(slices.DeleteFunc(ev.Branches, func(b string) bool { return b == "" }))
+	return ev, nil
+}
+
+// toPR converts the webhook PR into hangar's PR shape.
+func (p *ghWebhookPR) toPR(repo string) *PR {
+	state := strings.ToUpper(p.State)
+	if p.Merged {
+		state = "MERGED"
+	}
+	return &PR{
+		Number:     p.Number,
+		Title:      p.Title,
+		State:      stateFromSearchResult(state, p.Draft),
+		IsDraft:    p.Draft,
+		URL:        p.HTMLURL,
+		Repo:       repo,
+		HeadBranch: p.Head.Ref,
+		BaseBranch: p.Base.Ref,
+		Author:     p.User.Login,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+// repoFromWebURL returns "owner/repo" for a github.com repository URL, or
+// "host/owner/repo" for other (GHE) hosts.
+func repoFromWebURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	if strings.EqualFold(u.Host, "github.com") {
+		return parts[0] + "/" + parts[1]
+	}
+	return u.Host + "/" + parts[0] + "/" + parts[1]
+}
+
+// matches reports whether p is one of the PRs the event concerns.
+func (ev *WebhookEvent) matches(p *PR) bool {
+	if p == nil || !strings.EqualFold(p.Repo, ev.Repo) {
+		return false
+	}
+	return slices.Contains(ev.Numbers, p.Number) || slices.Contains(ev.Branches, p.HeadBranch)
+}
+
+// applyTo returns a copy of p updated with the PR state carried by the
+// event, or p itself when the event carries none. Checks, review decision
+// and session link are kept.
+func (ev *WebhookEvent) applyTo(p *PR) *PR {
+	if ev.PR == nil || ev.PR.Number != p.Number {
+		return p
+	}
+	cp := *p
+	cp.Title = ev.PR.Title
+	cp.State = ev.PR.State
+	cp.IsDraft = ev.PR.IsDraft
+	cp.BaseBranch = ev.PR.BaseBranch
+	if !ev.PR.UpdatedAt.IsZero() {
+		cp.UpdatedAt = ev.PR.UpdatedAt
+	}
+	return &cp
+}
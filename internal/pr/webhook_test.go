@@ -0,0 +1,89 @@
+package pr
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseGitHubWebhook(t *testing.T) {
+	ev, err := ParseGitHubWebhook("pull_request", []byte(`{"action":"closed",
+	  "pull_request":{"number":4,"title":"Add A","state":"closed","merged":true,"html_url":"https://github.com/o/r/pull/4",
+	    "user":{"login":"alice"},"head":{"ref":"feat/a"},"base":{"ref":"main"},"updated_at":"2026-10-02T10:00:00Z"},
+	  "repository":{"html_url":"https://github.com/o/r"}}`))
+	if err != nil {
+		t.Fatalf("pull_request: %v", err)
+	}
+	if ev.Repo != "o/r" || !slices.Equal(ev.Numbers, []int{4}) || !slices.Equal(ev.Branches, []string{"feat/a"}) ||
+		ev.PR == nil || ev.PR.State != "MERGED" || ev.PR.UpdatedAt.IsZero() {
+		t.Errorf("pull_request = %+v (PR %+v)", ev, ev.PR)
+	}
+
+	ev, err = ParseGitHubWebhook("check_suite", []byte(`{"action":"completed",
+	  "check_suite":{"head_branch":"feat/b","pull_requests":[{"number":7,"head":{"ref":"feat/b"}}]},
+	  "repository":{"html_url":"https://ghe.example.com/o/r"}}`))
+	if err != nil {
+		t.Fatalf("check_suite: %v", err)
+	}
+	if ev.Repo != "ghe.example.com/o/r" || !slices.Equal(ev.Numbers, []int{7}) || !slices.Equal(ev.Branches, []string{"feat/b"}) || ev.PR != nil {
+		t.Errorf("check_suite = %+v", ev)
+	}
+
+	ev, err = ParseGitHubWebhook("status", []byte(`{"state":"failure","branches":[{"name":"main"},{"name":"feat/c"}],
+	  "repository":{"html_url":"https://github.com/o/r"}}`))
+	if err != nil || len(ev.Numbers) != 0 || !slices.Equal(ev.Branches, []string{"feat/c", "main"}) {
+		t.Errorf("status = %+v, %v", ev, err)
+	}
+
+	if ev, err := ParseGitHubWebhook("push", []byte(`{}`)); ev != nil || err != nil {
+		t.Errorf("push = %+v, %v; want it ignored", ev, err)
+	}
+	if _, err := ParseGitHubWebhook("pull_request_review", []byte(`{"repository":{"html_url":"https://github.com/o/r"}}`)); err == nil {
+		t.Error("review without pull_request should fail")
+	}
+}
+
+func TestManagerApplyWebhook(t *testing.T) {
+	m := New()
+	defer m.Stop()
+	mine := &PR{Number: 4, Repo: "o/r", State: "OPEN", HeadBranch: "feat/a", ChecksPassed: 2, HasChecks: true}
+	other := &PR{Number: 5, Repo: "o/other", State: "OPEN", HeadBranch: "feat/a"}
+	m.myPRs = []*PR{mine, other}
+	m.detailCache["o/r#4"] = &PRDetail{PR: *mine}
+	m.sessionLookups["s1"] = SessionLookup{SessionID: "s1", Dir: "/wt/a", Repo: "o/r", Branch: "feat/a"}
+	m.sessionLookups["s2"] = SessionLookup{SessionID: "s2", Dir: "/wt/b", Repo: "o/r", Branch: "feat/b"}
+	changes := 0
+	m.RegisterOnChange(func() { changes++ })
+
+	ev := &WebhookEvent{Event: "pull_request", Action: "closed", Repo: "o/r", Numbers: []int{4}, Branches: []string{"feat/a"},
+		PR: &PR{Number: 4, Repo: "o/r", State: "MERGED", Title: "Add A"}}
+	if got := m.ApplyWebhook(ev); !slices.Equal(got, []int{4}) {
+		t.Errorf("ApplyWebhook = %v, want [4]", got)
+	}
+	got := m.GetMine()
+	if got[0].State != "MERGED" || got[0].Title != "Add A" || got[0].ChecksPassed != 2 {
+		t.Errorf("mine[0] = %+v, want the new state with checks kept", got[0])
+	}
+	if mine.State != "OPEN" {
+		t.Error("ApplyWebhook should not modify PRs handed out earlier")
+	}
+	if got[1].State != "OPEN" {
+		t.Errorf("PR in another repo changed: %+v", got[1])
+	}
+	if _, ok := m.detailCache["o/r#4"]; ok {
+		t.Error("detail should be invalidated")
+	}
+	m.mu.Lock()
+	var pending []string
+	for id := range m.pendingSessions {
+		pending = append(pending, id)
+	}
+	refresh := m.webhookRefreshScheduled
+	m.mu.Unlock()
+	if strings.Join(pending, ",") != "s1" || !refresh {
+		t.Errorf("pending = %v, refresh scheduled = %v; want s1 re-fetched and the lists refreshed", pending, refresh)
+	}
+	if changes != 1 {
+		t.Errorf("changes = %d, want 1", changes)
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Friday 2026-03-06 10:30 local.
+	base := time.Date(2026, 3, 6, 10, 30, 0, 0, time.Local)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 6, 10, 45, 0, 0, time.Local)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)}, // next Monday
+		{"0 9 * * mon-fri", time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)},
+		{"30 10 * * *", time.Date(2026, 3, 7, 10, 30, 0, 0, time.Local)}, // strictly after
+		{"0 2 1 * *", time.Date(2026, 4, 1, 2, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2026, 3, 6, 11, 0, 0, 0, time.Local)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local)}, // 7 = Sunday
+		// Both day fields restricted: either matches (the 10th, or any Sunday).
+		{"0 0 10 * sun", time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := c.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q.Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	c, _ := ParseCron("0 0 30 2 *")
+	if got := c.Next(base); !got.IsZero() {
+		t.Errorf("Feb 30 should never match, got %v", got)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "x * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
+
+func TestParseRunAt(t *testing.T) {
+	now := time.Date(2026, 3, 6, 10, 30, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"02:00", time.Date(2026, 3, 7, 2, 0, 0, 0, time.Local)},
+		{"11:00", time.Date(2026, 3, 6, 11, 0, 0, 0, time.Local)},
+		{"2h", now.Add(2 * time.Hour)},
+		{"2026-03-10 08:15", time.Date(2026, 3, 10, 8, 15, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := ParseRunAt(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseRunAt(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseRunAt(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"2026-01-01 00:00", "tomorrow", "-5m"} {
+		if _, err := ParseRunAt(in, now); err == nil {
+			t.Errorf("ParseRunAt(%q) should fail", in)
+		}
+	}
+}
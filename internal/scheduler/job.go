@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// Job actions.
+const (
+	// ActionSend sends Message to an existing session, starting it first if
+	// it is not running.
+	ActionSend = "send"
+	// ActionTodo starts a fresh worktree session from a todo, sending the
+	// todo's prompt (or Message, if set).
+	ActionTodo = "todo"
+)
+
+// Spec describes a job to create. Exactly one of Cron and At must be set.
+type Spec struct {
+	Name      string
+	Cron      string // cron expression, e.g. "0 9 * * 1-5"
+	At        string // one-shot time, see ParseRunAt
+	Action    string // ActionSend or ActionTodo
+	SessionID string
+	TodoID    string
+	Message   string
+}
+
+// NewJob validates spec and returns a schedule row ready to save, with its
+// first run computed relative to now.
+func NewJob(spec Spec, now time.Time) (*statedb.ScheduleRow, error) {
+	row := &statedb.ScheduleRow{
+		ID:        newJobID(),
+		Name:      strings.TrimSpace(spec.Name),
+		Cron:      strings.TrimSpace(spec.Cron),
+		Action:    spec.Action,
+		SessionID: spec.SessionID,
+		TodoID:    spec.TodoID,
+		Message:   strings.TrimSpace(spec.Message),
+		Enabled:   true,
+		CreatedAt: now,
+	}
+
+	switch row.Action {
+	case ActionSend:
+		if row.SessionID == "" {
+			return nil, fmt.Errorf("send jobs require a session")
+		}
+		if row.Message == "" {
+			return nil, fmt.Errorf("send jobs require a message")
+		}
+	case ActionTodo:
+		if row.TodoID == "" {
+			return nil, fmt.Errorf("todo jobs require a todo")
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %q (use %s or %s)", spec.Action, ActionSend, ActionTodo)
+	}
+
+	at := strings.TrimSpace(spec.At)
+	switch {
+	case row.Cron != "" && at != "":
+		return nil, fmt.Errorf("use either a cron expression or a one-shot time, not both")
+	case row.Cron != "":
+		c, err := ParseCron(row.Cron)
+		if err != nil {
+			return nil, err
+		}
+		row.NextRun = c.Next(now)
+		if row.NextRun.IsZero() {
+			return nil, fmt.Errorf("cron expression %q never matches", row.Cron)
+		}
+	case at != "":
+		t, err := ParseRunAt(at, now)
+		if err != nil {
+			return nil, err
+		}
+		row.RunAt = t.Truncate(time.Second)
+		row.NextRun = row.RunAt
+	default:
+		return nil, fmt.Errorf("a cron expression or a one-shot time is required")
+	}
+	return row, nil
+}
+
+// ParseRunAt parses a one-shot run time. It accepts a clock time ("02:00",
+// the next occurrence in local time), a duration from now ("90m", "2h"),
+// "YYYY-MM-DD HH:MM" in local time, or an RFC 3339 timestamp.
+func ParseRunAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(strings.TrimPrefix(s, "+")); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration %q must be positive", s)
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+	var t time.Time
+	var err error
+	if t, err = time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err != nil {
+		if t, err = time.Parse(time.RFC3339, s); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM, a duration like 2h, \"YYYY-MM-DD HH:MM\" or RFC 3339)", s)
+		}
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("time %s is in the past", t.Format("2006-01-02 15:04"))
+	}
+	return t, nil
+}
+
+// nextRun returns the run after a job fires at now: the next cron match, or
+// the zero time for one-shot jobs (which disables them).
+func nextRun(row *statedb.ScheduleRow, now time.Time) time.Time {
+	if row.Cron == "" {
+		return time.Time{}
+	}
+	c, err := ParseCron(row.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(now)
+}
+
+// Describe returns a short human-readable description of when a job runs.
+func Describe(row *statedb.ScheduleRow) string {
+	if row.Cron != "" {
+		return "cron " + row.Cron
+	}
+	return "once at " + row.RunAt.Local().Format("2006-01-02 15:04")
+}
+
+func newJobID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("sched-%s-%d", hex.EncodeToString(b), time.Now().Unix())
+}
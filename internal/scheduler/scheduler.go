@@ -0,0 +1,164 @@
+// Package scheduler runs cron-style and one-shot jobs stored in the
+// schedules table: sending a prompt to a session, or starting a fresh
+// worktree session from a todo. Every TUI process runs a Scheduler, but only
+// the primary instance (StateDB.ElectPrimary) executes jobs.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/session"
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+var schedLog = logging.ForComponent(logging.CompSched)
+
+const (
+	// tickInterval is how often due jobs are checked.
+	tickInterval = 15 * time.Second
+	// primaryTimeout matches the heartbeat staleness used for the
+	// single-instance gate in main.
+	primaryTimeout = 30 * time.Second
+	// missedRunGrace is how late a job may still fire, e.g. after the TUI was
+	// closed over its scheduled time. Older runs are skipped, not replayed.
+	missedRunGrace = time.Hour
+)
+
+// Scheduler executes due jobs for one profile.
+type Scheduler struct {
+	profile string
+	db      *statedb.StateDB
+
+	// Overridable in tests.
+	now       func() time.Time
+	isPrimary func() bool
+	execute   func(*statedb.ScheduleRow) error
+}
+
+// New creates a Scheduler for profile. db must be the process's registered
+// state database so primary election reflects this process's heartbeat.
+func New(profile string, db *statedb.StateDB) *Scheduler {
+	s := &Scheduler{profile: profile, db: db, now: time.Now}
+	s.isPrimary = func() bool {
+		ok, err := db.ElectPrimary(primaryTimeout)
+		return err == nil && ok
+	}
+	s.execute = s.executeJob
+	return s
+}
+
+// Run checks for due jobs until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// Tick claims and starts every job that is due. Jobs run in their own
+// goroutines because starting a session waits for the agent to be ready.
+// It returns the number of jobs started.
+func (s *Scheduler) Tick() int {
+	if !s.isPrimary() {
+		return 0
+	}
+	rows, err := s.db.LoadSchedules()
+	if err != nil {
+		schedLog.Warn("schedule_load_failed", slog.String("error", err.Error()))
+		return 0
+	}
+
+	now := s.now()
+	started := 0
+	for _, row := range rows {
+		if !row.Enabled || row.NextRun.IsZero() || row.NextRun.After(now) {
+			continue
+		}
+		claimed, err := s.db.ClaimScheduleRun(row.ID, row.NextRun, nextRun(row, now), now)
+		if err != nil || !claimed {
+			continue
+		}
+		if late := now.Sub(row.NextRun); late > missedRunGrace {
+			msg := fmt.Sprintf("skipped run missed at %s", row.NextRun.Local().Format("2006-01-02 15:04"))
+			schedLog.Info("schedule_missed", slog.String("id", row.ID), slog.Duration("late", late))
+			_ = s.db.SetScheduleError(row.ID, msg)
+			continue
+		}
+
+		started++
+		go func(row *statedb.ScheduleRow) {
+			errMsg := ""
+			if err := s.execute(row); err != nil {
+				errMsg = err.Error()
+				schedLog.Warn("schedule_failed", slog.String("id", row.ID), slog.String("action", row.Action), slog.String("error", errMsg))
+			} else {
+				schedLog.Info("schedule_ran", slog.String("id", row.ID), slog.String("action", row.Action))
+			}
+			_ = s.db.SetScheduleError(row.ID, errMsg)
+		}(row)
+	}
+	return started
+}
+
+// executeJob performs a job's action.
+func (s *Scheduler) executeJob(row *statedb.ScheduleRow) error {
+	storage, err := session.NewStorageWithProfile(s.profile)
+	if err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	defer storage.Close()
+
+	switch row.Action {
+	case ActionSend:
+		return sendToSession(storage, row.SessionID, row.Message)
+	case ActionTodo:
+		return startTodoSession(storage, row.TodoID, row.Message)
+	}
+	return fmt.Errorf("unknown action %q", row.Action)
+}
+
+// sendToSession delivers message to a session, starting it with the message
+// if its tmux session is not running.
+func sendToSession(storage *session.Storage, sessionID, message string) error {
+	instances, err := storage.Load()
+	if err != nil {
+		return fmt.Errorf("load sessions: %w", err)
+	}
+	for _, inst := range instances {
+		if inst.ID != sessionID {
+			continue
+		}
+		if inst.Exists() {
+			return inst.SendText(message)
+		}
+		return inst.StartWithMessage(message)
+	}
+	return fmt.Errorf("session %s not found", sessionID)
+}
+
+// startTodoSession creates a worktree session for a todo and links the todo
+// to it (see Storage.StartTodoSession).
+func startTodoSession(storage *session.Storage, todoID, message string) error {
+	todo, err := storage.LoadTodoByID(todoID)
+	if err != nil {
+		return fmt.Errorf("load todo: %w", err)
+	}
+	if todo == nil {
+		return fmt.Errorf("todo %s not found", todoID)
+	}
+	if todo.SessionID != "" {
+		return fmt.Errorf("todo %q already has a session", todo.Title)
+	}
+	_, err = storage.StartTodoSession(todo, message)
+	return err
+}
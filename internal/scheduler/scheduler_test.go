@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+func newTestScheduler(t *testing.T, now time.Time) (*Scheduler, chan string) {
+	t.Helper()
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ran := make(chan string, 8)
+	s := New("", db)
+	s.now = func() time.Time { return now }
+	s.isPrimary = func() bool { return true }
+	s.execute = func(row *statedb.ScheduleRow) error {
+		ran <- row.ID
+		return nil
+	}
+	return s, ran
+}
+
+func TestNewJob(t *testing.T) {
+	now := time.Date(2026, 3, 6, 10, 30, 0, 0, time.Local)
+	row, err := NewJob(Spec{Cron: "0 9 * * 1-5", Action: ActionSend, SessionID: "s1", Message: "/pr-review"}, now)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if want := time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local); !row.NextRun.Equal(want) || !row.Enabled {
+		t.Errorf("NextRun = %v, want %v", row.NextRun, want)
+	}
+
+	for name, spec := range map[string]Spec{
+		"no schedule":     {Action: ActionSend, SessionID: "s1", Message: "hi"},
+		"both schedules":  {Cron: "@daily", At: "2h", Action: ActionSend, SessionID: "s1", Message: "hi"},
+		"send no message": {Cron: "@daily", Action: ActionSend, SessionID: "s1"},
+		"todo no todo":    {At: "2h", Action: ActionTodo},
+		"bad action":      {At: "2h", Action: "explode"},
+	} {
+		if _, err := NewJob(spec, now); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSchedulerTick(t *testing.T) {
+	now := time.Date(2026, 3, 6, 9, 0, 20, 0, time.Local)
+	s, ran := newTestScheduler(t, now)
+
+	save := func(row *statedb.ScheduleRow) {
+		row.CreatedAt = now
+		row.Enabled = true
+		if err := s.db.SaveSchedule(row); err != nil {
+			t.Fatalf("SaveSchedule: %v", err)
+		}
+	}
+	save(&statedb.ScheduleRow{ID: "cron", Cron: "0 9 * * *", Action: ActionSend, NextRun: now.Truncate(time.Minute)})
+	save(&statedb.ScheduleRow{ID: "once", Action: ActionTodo, RunAt: now.Add(-time.Second), NextRun: now.Add(-time.Second)})
+	save(&statedb.ScheduleRow{ID: "later", Cron: "0 10 * * *", Action: ActionSend, NextRun: now.Add(time.Hour)})
+	save(&statedb.ScheduleRow{ID: "stale", Action: ActionSend, NextRun: now.Add(-2 * time.Hour)})
+
+	if n := s.Tick(); n != 2 {
+		t.Fatalf("Tick started %d jobs, want 2", n)
+	}
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-ran:
+			got[id] = true
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for jobs")
+		}
+	}
+	if !got["cron"] || !got["once"] {
+		t.Errorf("ran %v, want cron and once", got)
+	}
+
+	// A second tick at the same time runs nothing.
+	if n := s.Tick(); n != 0 {
+		t.Errorf("second Tick started %d jobs", n)
+	}
+
+	rows, _ := s.db.LoadSchedules()
+	byID := map[string]*statedb.ScheduleRow{}
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+	if want := time.Date(2026, 3, 7, 9, 0, 0, 0, time.Local); !byID["cron"].NextRun.Equal(want) {
+		t.Errorf("cron next run = %v, want %v", byID["cron"].NextRun, want)
+	}
+	if byID["once"].Enabled || byID["stale"].Enabled {
+		t.Error("one-shot jobs should be disabled after firing or being skipped")
+	}
+	if byID["stale"].LastError == "" {
+		t.Error("missed run should be recorded")
+	}
+}
+
+func TestSchedulerTick_NotPrimary(t *testing.T) {
+	now := time.Now()
+	s, _ := newTestScheduler(t, now)
+	calls := 0
+	s.isPrimary = func() bool {
+		calls++
+		return false
+	}
+	_ = s.db.SaveSchedule(&statedb.ScheduleRow{ID: "x", Action: ActionSend, Enabled: true, NextRun: now.Add(-time.Second), CreatedAt: now})
+	if n := s.Tick(); n != 0 || calls != 1 {
+		t.Errorf("non-primary Tick started %d jobs (election calls %d)", n, calls)
+	}
+}
@@ -0,0 +1,170 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// BroadcastSelector picks the sessions a broadcast message goes to. Set
+// fields are combined with AND; a selector with no fields set matches nothing.
+type BroadcastSelector struct {
+	SessionIDs []string // explicit sessions
+	Group      string   // group path, including its subgroups
+	Status     Status   // e.g. StatusWaiting
+	Project    string   // project name from projects.toml
+}
+
+// IsEmpty reports whether no selector field is set.
+func (s BroadcastSelector) IsEmpty() bool {
+	return len(s.SessionIDs) == 0 && s.Group == "" && s.Status == "" && s.Project == ""
+}
+
+// String describes the selector for status messages, e.g. "group=work status=waiting".
+func (s BroadcastSelector) String() string {
+	var parts []string
+	if len(s.SessionIDs) > 0 {
+		parts = append(parts, fmt.Sprintf("%d sessions", len(s.SessionIDs)))
+	}
+	if s.Group != "" {
+		parts = append(parts, "group="+s.Group)
+	}
+	if s.Status != "" {
+		parts = append(parts, "status="+string(s.Status))
+	}
+	if s.Project != "" {
+		parts = append(parts, "project="+s.Project)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SelectSessions returns the instances matching sel, in their original order.
+// projects resolves sel.Project; a project matches sessions filed under its
+// group or working inside its base directory (including worktrees).
+func SelectSessions(instances []*Instance, sel BroadcastSelector, projects []*Project) ([]*Instance, error) {
+	if sel.IsEmpty() {
+		return nil, fmt.Errorf("no sessions selected: set session IDs, a group, a status or a project")
+	}
+	var project *Project
+	if sel.Project != "" {
+		for _, p := range projects {
+			if strings.EqualFold(p.Name, sel.Project) {
+				project = p
+				break
+			}
+		}
+		if project == nil {
+			return nil, fmt.Errorf("project '%s' not found", sel.Project)
+		}
+	}
+	ids := make(map[string]bool, len(sel.SessionIDs))
+	for _, id := range sel.SessionIDs {
+		ids[id] = true
+	}
+
+	var matched []*Instance
+	for _, inst := range instances {
+		if len(ids) > 0 && !ids[inst.ID] {
+			continue
+		}
+		// Tower only receives broadcasts addressed to it by ID.
+		if len(ids) == 0 && inst.SessionType == "tower" {
+			continue
+		}
+		if sel.Group != "" && !inGroup(inst, sel.Group) {
+			continue
+		}
+		if sel.Status != "" && inst.Status != sel.Status {
+			continue
+		}
+		if project != nil && !inProject(inst, project) {
+			continue
+		}
+		matched = append(matched, inst)
+	}
+	return matched, nil
+}
+
+// inGroup reports whether inst is in group or one of its subgroups.
+func inGroup(inst *Instance, group string) bool {
+	return inst.GroupPath == group || strings.HasPrefix(inst.GroupPath, group+"/")
+}
+
+// inProject reports whether inst belongs to project p.
+func inProject(inst *Instance, p *Project) bool {
+	if inst.GroupPath == p.GroupPath() {
+		return true
+	}
+	base := filepath.Clean(ExpandPath(p.BaseDir))
+	for _, dir := range []string{inst.WorktreeRepoRoot, inst.ProjectPath} {
+		if dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if dir == base || strings.HasPrefix(dir, base+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// BroadcastResult reports delivery of a broadcast message to one session.
+type BroadcastResult struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	Sent      bool   `json:"sent"`
+	Error     string `json:"error,omitempty"`
+}
+
+// broadcastSend delivers text to one session. Overridable in tests.
+var broadcastSend = func(inst *Instance, text string) error {
+	if !inst.Exists() {
+		return fmt.Errorf("session is not running")
+	}
+	ts := inst.GetTmuxSession()
+	if ts == nil {
+		return fmt.Errorf("session has no tmux session")
+	}
+	return ts.SendKeysAndEnter(text)
+}
+
+// Broadcast sends text to every instance in parallel and returns one result
+// per instance, in the same order.
+func Broadcast(instances []*Instance, text string) []BroadcastResult {
+	results := make([]BroadcastResult, len(instances))
+	var wg sync.WaitGroup
+	for i, inst := range instances {
+		results[i] = BroadcastResult{SessionID: inst.ID, Title: inst.Title}
+		wg.Add(1)
+		go func(r *BroadcastResult, inst *Instance) {
+			defer wg.Done()
+			if err := broadcastSend(inst, text); err != nil {
+				r.Error = err.Error()
+				return
+			}
+			r.Sent = true
+		}(&results[i], inst)
+	}
+	wg.Wait()
+	return results
+}
+
+// BroadcastSummary formats results as "sent to N/M sessions", followed by
+// the sessions that failed.
+func BroadcastSummary(results []BroadcastResult) string {
+	sent := 0
+	var failed []string
+	for _, r := range results {
+		if r.Sent {
+			sent++
+		} else {
+			failed = append(failed, fmt.Sprintf("%s (%s)", r.Title, r.Error))
+		}
+	}
+	summary := fmt.Sprintf("sent to %d/%d sessions", sent, len(results))
+	if len(failed) > 0 {
+		summary += "; failed: " + strings.Join(failed, ", ")
+	}
+	return summary
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSelectSessions(t *testing.T) {
+	a := &Instance{ID: "a", Title: "a", GroupPath: "work", Status: StatusWaiting, ProjectPath: "/code/api"}
+	b := &Instance{ID: "b", Title: "b", GroupPath: "work/sub", Status: StatusRunning, ProjectPath: "/elsewhere",
+		WorktreeRepoRoot: "/code/api"}
+	c := &Instance{ID: "c", Title: "c", GroupPath: "play", Status: StatusWaiting, ProjectPath: "/code/web"}
+	tower := &Instance{ID: "t", Title: "Tower", SessionType: "tower", Status: StatusWaiting}
+	instances := []*Instance{a, b, c, tower}
+	projects := []*Project{{Name: "api", BaseDir: "/code/api"}}
+
+	ids := func(sel BroadcastSelector) string {
+		t.Helper()
+		got, err := SelectSessions(instances, sel, projects)
+		if err != nil {
+			t.Fatalf("SelectSessions(%v): %v", sel, err)
+		}
+		s := ""
+		for _, inst := range got {
+			s += inst.ID
+		}
+		return s
+	}
+
+	tests := []struct {
+		sel  BroadcastSelector
+		want string
+	}{
+		{BroadcastSelector{Group: "work"}, "ab"},
+		{BroadcastSelector{Status: StatusWaiting}, "ac"},
+		{BroadcastSelector{Group: "work", Status: StatusWaiting}, "a"},
+		{BroadcastSelector{Project: "API"}, "ab"},
+		{BroadcastSelector{SessionIDs: []string{"c", "b"}}, "bc"},
+		{BroadcastSelector{SessionIDs: []string{"t"}}, "t"},
+		{BroadcastSelector{Group: "wor"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.sel); got != tt.want {
+			t.Errorf("SelectSessions(%s) = %q, want %q", tt.sel, got, tt.want)
+		}
+	}
+
+	if _, err := SelectSessions(instances, BroadcastSelector{}, projects); err == nil {
+		t.Error("empty selector should fail")
+	}
+	if _, err := SelectSessions(instances, BroadcastSelector{Project: "nope"}, projects); err == nil {
+		t.Error("unknown project should fail")
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	orig := broadcastSend
+	defer func() { broadcastSend = orig }()
+	var mu sync.Mutex
+	got := map[string]string{}
+	broadcastSend = func(inst *Instance, text string) error {
+		if inst.ID == "down" {
+			return errors.New("session is not running")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		got[inst.ID] = text
+		return nil
+	}
+	results := Broadcast([]*Instance{{ID: "up", Title: "up"}, {ID: "down", Title: "down"}}, "git pull --rebase")
+
+	if !results[0].Sent || results[1].Sent || results[1].Error == "" {
+		t.Errorf("results = %+v", results)
+	}
+	if got["up"] != "git pull --rebase" {
+		t.Errorf("sent %q", got["up"])
+	}
+	if s := BroadcastSummary(results); s != "sent to 1/2 sessions; failed: down (session is not running)" {
+		t.Errorf("summary = %q", s)
+	}
+}
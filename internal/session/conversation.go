@@ -0,0 +1,608 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Conversation is a structured agent transcript: the turns of the main
+// thread plus, for Claude, the transcripts of subagents started with the
+// Task tool.
+type Conversation struct {
+	Tool           string             `json:"tool"`
+	ConversationID string             `json:"conversation_id,omitempty"` // the tool's own session ID
+	AgentID        string             `json:"agent_id,omitempty"`        // set on subagent transcripts
+	Path           string             `json:"path,omitempty"`            // transcript file
+	Turns          []ConversationTurn `json:"turns"`
+	Subagents      []*Conversation    `json:"subagents,omitempty"`
+	Usage          TokenUsage         `json:"usage"` // all turns, subagents included
+}
+
+// ConversationTurn is one message: a user prompt, or one model response with
+// the tool calls it made and the tokens it used.
+type ConversationTurn struct {
+	Role      string                 `json:"role"` // "user" or "assistant"
+	Timestamp time.Time              `json:"timestamp,omitempty"`
+	Text      string                 `json:"text,omitempty"`
+	Model     string                 `json:"model,omitempty"`
+	ToolCalls []ConversationToolCall `json:"tool_calls,omitempty"`
+	Usage     *TokenUsage            `json:"usage,omitempty"`
+}
+
+// ConversationToolCall is a tool invocation and, once it came back, its result.
+type ConversationToolCall struct {
+	ID      string          `json:"id,omitempty"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input,omitempty"`
+	Result  string          `json:"result,omitempty"`
+	IsError bool            `json:"is_error,omitempty"`
+}
+
+// TokenUsage counts tokens. Field names follow Claude's usage object; Gemini
+// and Codex counts are mapped onto them.
+type TokenUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+func (u *TokenUsage) add(o TokenUsage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheCreationInputTokens += o.CacheCreationInputTokens
+	u.CacheReadInputTokens += o.CacheReadInputTokens
+}
+
+// ConversationOptions filters a parsed conversation.
+type ConversationOptions struct {
+	Since          time.Time // drop turns before this time (zero keeps all)
+	MaxResultBytes int       // truncate tool results to this many bytes (0 = no limit)
+}
+
+// TranscriptPath returns the transcript file behind the session's
+// conversation: Claude JSONL, Gemini session JSON or Codex rollout JSONL.
+func (i *Instance) TranscriptPath() (string, error) {
+	switch i.Tool {
+	case "claude":
+		if path := i.GetJSONLPath(); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("no Claude transcript found for this session")
+	case "gemini":
+		return i.geminiSessionFile()
+	case "codex":
+		if i.CodexSessionID == "" {
+			return "", fmt.Errorf("no Codex session ID available for this instance")
+		}
+		if path := findCodexSessionFile(i.CodexSessionID); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("session file not found for ID: %s", i.CodexSessionID)
+	}
+	return "", fmt.Errorf("conversation transcripts are not available for %s sessions", i.Tool)
+}
+
+// GetConversation parses the session's transcript into a Conversation.
+func (i *Instance) GetConversation(opts ConversationOptions) (*Conversation, error) {
+	path, err := i.TranscriptPath()
+	if err != nil {
+		return nil, err
+	}
+	return ParseTranscript(i.Tool, path, opts)
+}
+
+// ParseTranscript parses the transcript file of the given tool.
+func ParseTranscript(tool, path string, opts ConversationOptions) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	var conv *Conversation
+	switch tool {
+	case "claude":
+		conv = parseClaudeConversation(data)
+		// Newer Claude Code versions write each subagent to its own file
+		// next to the main transcript.
+		subagentFiles, _ := filepath.Glob(filepath.Join(strings.TrimSuffix(path, ".jsonl"), "subagents", "*.jsonl"))
+		for _, f := range subagentFiles {
+			sub, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			parsed := parseClaudeConversation(sub)
+			if len(parsed.Turns) > 0 {
+				// Not marked as sidechain: the whole file is the subagent.
+				parsed.AgentID = strings.TrimPrefix(strings.TrimSuffix(filepath.Base(f), ".jsonl"), "agent-")
+				parsed.Subagents = append(parsed.Subagents, &Conversation{
+					Tool: parsed.Tool, ConversationID: parsed.ConversationID, AgentID: parsed.AgentID, Turns: parsed.Turns,
+				})
+			}
+			conv.mergeSubagents(parsed)
+		}
+	case "gemini":
+		if conv, err = parseGeminiConversation(data); err != nil {
+			return nil, err
+		}
+	case "codex":
+		conv = parseCodexConversation(data)
+	default:
+		return nil, fmt.Errorf("conversation transcripts are not available for %s sessions", tool)
+	}
+	conv.Path = path
+	conv.finish(opts)
+	return conv, nil
+}
+
+// finish applies opts and totals token usage, recursively.
+func (c *Conversation) finish(opts ConversationOptions) {
+	c.Usage = TokenUsage{}
+	turns := c.Turns[:0]
+	for _, t := range c.Turns {
+		if !opts.Since.IsZero() && !t.Timestamp.IsZero() && t.Timestamp.Before(opts.Since) {
+			continue
+		}
+		for j := range t.ToolCalls {
+			t.ToolCalls[j].Result = truncateResult(t.ToolCalls[j].Result, opts.MaxResultBytes)
+		}
+		if t.Usage != nil {
+			c.Usage.add(*t.Usage)
+		}
+		turns = append(turns, t)
+	}
+	c.Turns = turns
+	if c.Turns == nil {
+		c.Turns = []ConversationTurn{}
+	}
+
+	subagents := c.Subagents[:0]
+	for _, sub := range c.Subagents {
+		sub.finish(opts)
+		if len(sub.Turns) > 0 {
+			c.Usage.add(sub.Usage)
+			subagents = append(subagents, sub)
+		}
+	}
+	c.Subagents = subagents
+}
+
+// mergeSubagents moves other's subagent transcripts into c.
+func (c *Conversation) mergeSubagents(other *Conversation) {
+	for _, sub := range other.Subagents {
+		if existing := c.subagent(sub.AgentID); len(existing.Turns) == 0 {
+			*existing = *sub
+		}
+	}
+}
+
+// subagent returns the subagent transcript with the given ID, adding it if
+// needed.
+func (c *Conversation) subagent(agentID string) *Conversation {
+	for _, sub := range c.Subagents {
+		if sub.AgentID == agentID {
+			return sub
+		}
+	}
+	sub := &Conversation{Tool: c.Tool, ConversationID: c.ConversationID, AgentID: agentID}
+	c.Subagents = append(c.Subagents, sub)
+	return sub
+}
+
+func truncateResult(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	return s[:max] + fmt.Sprintf("\n[truncated: %d of %d bytes shown]", max, len(s))
+}
+
+// toolCallRef locates a tool call so its result, which arrives in a later
+// record, can be attached to it.
+type toolCallRef struct {
+	conv       *Conversation
+	turn, call int
+}
+
+// conversationBuilder assembles turns from transcript records.
+type conversationBuilder struct {
+	conv  *Conversation
+	calls map[string]toolCallRef
+}
+
+func newConversationBuilder(tool string) *conversationBuilder {
+	return &conversationBuilder{conv: &Conversation{Tool: tool}, calls: map[string]toolCallRef{}}
+}
+
+func (b *conversationBuilder) addTurn(conv *Conversation, turn ConversationTurn) *ConversationTurn {
+	conv.Turns = append(conv.Turns, turn)
+	return &conv.Turns[len(conv.Turns)-1]
+}
+
+func (b *conversationBuilder) addToolCall(conv *Conversation, call ConversationToolCall) {
+	if len(conv.Turns) == 0 {
+		return
+	}
+	turn := &conv.Turns[len(conv.Turns)-1]
+	turn.ToolCalls = append(turn.ToolCalls, call)
+	if call.ID != "" {
+		b.calls[call.ID] = toolCallRef{conv: conv, turn: len(conv.Turns) - 1, call: len(turn.ToolCalls) - 1}
+	}
+}
+
+func (b *conversationBuilder) setToolResult(id, result string, isError bool) {
+	ref, ok := b.calls[id]
+	if !ok {
+		return
+	}
+	call := &ref.conv.Turns[ref.turn].ToolCalls[ref.call]
+	call.Result = result
+	call.IsError = isError
+}
+
+// contentText extracts text from message content that is either a string or
+// an array of blocks/parts carrying a "text" field.
+func contentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, bl := range blocks {
+		switch {
+		case bl.Text != "":
+			parts = append(parts, bl.Text)
+		case bl.Type == "image":
+			parts = append(parts, "[image]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// scanJSONLines calls fn for every non-empty line of data.
+func scanJSONLines(data []byte, fn func(line []byte)) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	buf := make([]byte, 0, 1024*1024)
+	scanner.Buffer(buf, 64*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			fn(line)
+		}
+	}
+}
+
+// parseClaudeConversation parses a Claude JSONL transcript. A streamed
+// response is written as several records sharing one message ID; they are
+// merged into one turn. Tool results arrive in user records and are attached
+// to their tool call. Sidechain records belong to subagents.
+func parseClaudeConversation(data []byte) *Conversation {
+	type block struct {
+		Type      string          `json:"type"`
+		Text      string          `json:"text"`
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Input     json.RawMessage `json:"input"`
+		ToolUseID string          `json:"tool_use_id"`
+		Content   json.RawMessage `json:"content"`
+		IsError   bool            `json:"is_error"`
+	}
+	type record struct {
+		Type        string `json:"type"`
+		SessionID   string `json:"sessionId"`
+		Timestamp   string `json:"timestamp"`
+		IsSidechain bool   `json:"isSidechain"`
+		IsMeta      bool   `json:"isMeta"`
+		AgentID     string `json:"agentId"`
+		Message     struct {
+			ID      string          `json:"id"`
+			Role    string          `json:"role"`
+			Model   string          `json:"model"`
+			Content json.RawMessage `json:"content"`
+			Usage   *TokenUsage     `json:"usage"`
+		} `json:"message"`
+	}
+
+	b := newConversationBuilder("claude")
+	lastMessageID := map[*Conversation]string{}
+	scanJSONLines(data, func(line []byte) {
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return
+		}
+		if rec.Type != "user" && rec.Type != "assistant" {
+			return
+		}
+		if b.conv.ConversationID == "" {
+			b.conv.ConversationID = rec.SessionID
+		}
+		conv := b.conv
+		if rec.IsSidechain {
+			agentID := rec.AgentID
+			if agentID == "" {
+				agentID = "sidechain"
+			}
+			conv = b.conv.subagent(agentID)
+		}
+		ts, _ := time.Parse(time.RFC3339Nano, rec.Timestamp)
+
+		var blocks []block
+		if err := json.Unmarshal(rec.Message.Content, &blocks); err != nil {
+			var s string
+			if json.Unmarshal(rec.Message.Content, &s) != nil {
+				return
+			}
+			blocks = []block{{Type: "text", Text: s}}
+		}
+
+		if rec.Message.Role == "user" {
+			var texts []string
+			for _, bl := range blocks {
+				switch bl.Type {
+				case "tool_result":
+					b.setToolResult(bl.ToolUseID, contentText(bl.Content), bl.IsError)
+				case "text":
+					texts = append(texts, bl.Text)
+				}
+			}
+			if len(texts) > 0 && !rec.IsMeta {
+				b.addTurn(conv, ConversationTurn{Role: "user", Timestamp: ts, Text: strings.Join(texts, "\n")})
+				lastMessageID[conv] = ""
+			}
+			return
+		}
+
+		var turn *ConversationTurn
+		if id := rec.Message.ID; id != "" && id == lastMessageID[conv] && len(conv.Turns) > 0 {
+			turn = &conv.Turns[len(conv.Turns)-1]
+		} else {
+			turn = b.addTurn(conv, ConversationTurn{Role: "assistant", Timestamp: ts, Model: rec.Message.Model})
+			lastMessageID[conv] = rec.Message.ID
+		}
+		if rec.Message.Usage != nil {
+			// Streamed records repeat the usage; the last one is complete.
+			u := *rec.Message.Usage
+			turn.Usage = &u
+		}
+		for _, bl := range blocks {
+			switch bl.Type {
+			case "text":
+				if turn.Text != "" {
+					turn.Text += "\n"
+				}
+				turn.Text += bl.Text
+			case "tool_use":
+				b.addToolCall(conv, ConversationToolCall{ID: bl.ID, Name: bl.Name, Input: bl.Input})
+			}
+		}
+	})
+	return b.conv
+}
+
+// parseGeminiConversation parses a Gemini CLI session file.
+func parseGeminiConversation(data []byte) (*Conversation, error) {
+	var file struct {
+		SessionID string `json:"sessionId"`
+		Messages  []struct {
+			Timestamp string          `json:"timestamp"`
+			Type      string          `json:"type"` // "user", "gemini", "info" or "error"
+			Content   json.RawMessage `json:"content"`
+			Model     string          `json:"model"`
+			ToolCalls []struct {
+				ID            string          `json:"id"`
+				Name          string          `json:"name"`
+				Args          json.RawMessage `json:"args"`
+				Result        json.RawMessage `json:"result"`
+				ResultDisplay json.RawMessage `json:"resultDisplay"`
+				Status        string          `json:"status"`
+			} `json:"toolCalls"`
+			Tokens *struct {
+				Input    int `json:"input"`
+				Output   int `json:"output"`
+				Cached   int `json:"cached"`
+				Thoughts int `json:"thoughts"`
+			} `json:"tokens"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini session: %w", err)
+	}
+
+	b := newConversationBuilder("gemini")
+	b.conv.ConversationID = file.SessionID
+	for _, msg := range file.Messages {
+		ts, _ := time.Parse(time.RFC3339Nano, msg.Timestamp)
+		switch msg.Type {
+		case "user":
+			b.addTurn(b.conv, ConversationTurn{Role: "user", Timestamp: ts, Text: contentText(msg.Content)})
+		case "gemini":
+			turn := b.addTurn(b.conv, ConversationTurn{Role: "assistant", Timestamp: ts, Text: contentText(msg.Content), Model: msg.Model})
+			if msg.Tokens != nil {
+				turn.Usage = &TokenUsage{
+					InputTokens:          max(msg.Tokens.Input-msg.Tokens.Cached, 0),
+					OutputTokens:         msg.Tokens.Output + msg.Tokens.Thoughts,
+					CacheReadInputTokens: msg.Tokens.Cached,
+				}
+			}
+			for _, tc := range msg.ToolCalls {
+				result := contentText(tc.ResultDisplay)
+				if result == "" && len(tc.Result) > 0 && string(tc.Result) != "null" {
+					result = string(tc.Result)
+				}
+				b.addToolCall(b.conv, ConversationToolCall{
+					ID:      tc.ID,
+					Name:    tc.Name,
+					Input:   tc.Args,
+					Result:  result,
+					IsError: tc.Status == "error",
+				})
+			}
+		}
+	}
+	return b.conv, nil
+}
+
+// codexInjectedPrefixes mark user messages that Codex adds itself
+// (environment and AGENTS.md context) rather than prompts the user typed.
+var codexInjectedPrefixes = []string{"<environment_context>", "<user_instructions>", "# AGENTS.md instructions"}
+
+// parseCodexConversation parses a Codex rollout JSONL file. Items are
+// wrapped as {"type":"response_item","payload":{...}} in current versions
+// and written bare in older ones. A token_count event follows each model
+// call and closes the assistant turn it belongs to.
+func parseCodexConversation(data []byte) *Conversation {
+	type payload struct {
+		Type      string          `json:"type"`
+		ID        string          `json:"id"`
+		Role      string          `json:"role"`
+		Content   json.RawMessage `json:"content"`
+		Name      string          `json:"name"`
+		Arguments string          `json:"arguments"`
+		Input     string          `json:"input"`
+		CallID    string          `json:"call_id"`
+		Output    json.RawMessage `json:"output"`
+		Info      *struct {
+			LastTokenUsage struct {
+				InputTokens       int `json:"input_tokens"`
+				CachedInputTokens int `json:"cached_input_tokens"`
+				OutputTokens      int `json:"output_tokens"`
+			} `json:"last_token_usage"`
+		} `json:"info"`
+	}
+	type record struct {
+		Timestamp string          `json:"timestamp"`
+		Type      string          `json:"type"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+
+	b := newConversationBuilder("codex")
+	open := false // whether the last assistant turn still takes items
+	assistant := func(ts time.Time) {
+		if !open || len(b.conv.Turns) == 0 || b.conv.Turns[len(b.conv.Turns)-1].Role != "assistant" {
+			b.addTurn(b.conv, ConversationTurn{Role: "assistant", Timestamp: ts})
+			open = true
+		}
+	}
+	scanJSONLines(data, func(line []byte) {
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return
+		}
+		raw := rec.Payload
+		if len(raw) == 0 {
+			raw = line
+		}
+		var p payload
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return
+		}
+		ts, _ := time.Parse(time.RFC3339Nano, rec.Timestamp)
+
+		switch {
+		case rec.Type == "session_meta":
+			if b.conv.ConversationID == "" {
+				b.conv.ConversationID = p.ID
+			}
+		case p.Type == "token_count":
+			if p.Info != nil && open && len(b.conv.Turns) > 0 {
+				u := p.Info.LastTokenUsage
+				b.conv.Turns[len(b.conv.Turns)-1].Usage = &TokenUsage{
+					InputTokens:          max(u.InputTokens-u.CachedInputTokens, 0),
+					OutputTokens:         u.OutputTokens,
+					CacheReadInputTokens: u.CachedInputTokens,
+				}
+			}
+			open = false
+		case rec.Type == "event_msg":
+			// Other events repeat response items for the TUI.
+		case p.Type == "message" && p.Role == "user":
+			text := contentText(p.Content)
+			for _, prefix := range codexInjectedPrefixes {
+				if strings.HasPrefix(strings.TrimSpace(text), prefix) {
+					return
+				}
+			}
+			if text != "" {
+				b.addTurn(b.conv, ConversationTurn{Role: "user", Timestamp: ts, Text: text})
+				open = false
+			}
+		case p.Type == "message" && p.Role == "assistant":
+			assistant(ts)
+			turn := &b.conv.Turns[len(b.conv.Turns)-1]
+			if text := contentText(p.Content); text != "" {
+				if turn.Text != "" {
+					turn.Text += "\n"
+				}
+				turn.Text += text
+			}
+		case p.Type == "function_call" || p.Type == "custom_tool_call" || p.Type == "local_shell_call":
+			assistant(ts)
+			input := p.Arguments
+			if input == "" {
+				input = p.Input
+			}
+			call := ConversationToolCall{ID: p.CallID, Name: p.Name}
+			if json.Valid([]byte(input)) {
+				call.Input = json.RawMessage(input)
+			} else if input != "" {
+				call.Input, _ = json.Marshal(input)
+			}
+			if call.Name == "" {
+				call.Name = "shell"
+			}
+			b.addToolCall(b.conv, call)
+		case p.Type == "function_call_output" || p.Type == "custom_tool_call_output":
+			b.setToolResult(p.CallID, codexOutputText(p.Output), false)
+		}
+	})
+	return b.conv
+}
+
+// codexOutputText unwraps a Codex tool output, which is plain text or a JSON
+// string holding {"output": "...", "metadata": {...}}.
+func codexOutputText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return contentText(raw)
+	}
+	var wrapped struct {
+		Output *string `json:"output"`
+	}
+	if json.Unmarshal([]byte(s), &wrapped) == nil && wrapped.Output != nil {
+		return *wrapped.Output
+	}
+	return s
+}
+
+// findCodexSessionFile returns the newest rollout file under the Codex
+// sessions directory whose name contains sessionID, or "".
+func findCodexSessionFile(sessionID string) string {
+	var matches []string
+	_ = filepath.WalkDir(filepath.Join(getCodexHomeDir(), "sessions"), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(d.Name(), ".jsonl") && strings.Contains(d.Name(), sessionID) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if len(matches) == 0 {
+		return ""
+	}
+	// Paths are date-sharded (YYYY/MM/DD), so the last one is the newest.
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
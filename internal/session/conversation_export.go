@@ -0,0 +1,151 @@
+package session
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown renders the conversation as Markdown under the given title.
+// Tool calls are folded into <details> blocks so the prompts and answers
+// stay readable on GitHub.
+func (c *Conversation) WriteMarkdown(w io.Writer, title string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	fmt.Fprintf(&sb, "- Tool: %s\n", c.Tool)
+	if c.ConversationID != "" {
+		fmt.Fprintf(&sb, "- Conversation: `%s`\n", c.ConversationID)
+	}
+	fmt.Fprintf(&sb, "- Tokens: %s\n", c.Usage.summary())
+	writeMarkdownTurns(&sb, c.Turns, "##")
+	for _, sub := range c.Subagents {
+		fmt.Fprintf(&sb, "\n## Subagent %s\n\n- Tokens: %s\n", sub.AgentID, sub.Usage.summary())
+		writeMarkdownTurns(&sb, sub.Turns, "###")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMarkdownTurns(sb *strings.Builder, turns []ConversationTurn, heading string) {
+	for _, t := range turns {
+		fmt.Fprintf(sb, "\n%s %s\n\n", heading, t.heading())
+		if t.Text != "" {
+			sb.WriteString(strings.TrimSpace(t.Text))
+			sb.WriteString("\n")
+		}
+		for _, call := range t.ToolCalls {
+			label := call.Name
+			if call.IsError {
+				label += " (error)"
+			}
+			fmt.Fprintf(sb, "\n<details><summary>Tool: %s</summary>\n\n", template.HTMLEscapeString(label))
+			if len(call.Input) > 0 {
+				fmt.Fprintf(sb, "%s\n", fence(string(call.Input), "json"))
+			}
+			if call.Result != "" {
+				fmt.Fprintf(sb, "\nResult:\n\n%s\n", fence(call.Result, ""))
+			}
+			sb.WriteString("\n</details>\n")
+		}
+	}
+}
+
+// fence wraps s in a code fence longer than any backtick run inside it.
+func fence(s, lang string) string {
+	ticks := "```"
+	for strings.Contains(s, ticks) {
+		ticks += "`"
+	}
+	return ticks + lang + "\n" + strings.TrimRight(s, "\n") + "\n" + ticks
+}
+
+// heading returns e.g. "Assistant · 2026-03-01 09:00 · claude-sonnet-4 · 1,200 in / 300 out".
+func (t ConversationTurn) heading() string {
+	parts := []string{"User"}
+	if t.Role == "assistant" {
+		parts[0] = "Assistant"
+	}
+	if !t.Timestamp.IsZero() {
+		parts = append(parts, t.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+	if t.Model != "" {
+		parts = append(parts, t.Model)
+	}
+	if t.Usage != nil {
+		parts = append(parts, fmt.Sprintf("%s in / %s out", formatTokenCount(t.Usage.InputTokens+t.Usage.CacheReadInputTokens+t.Usage.CacheCreationInputTokens), formatTokenCount(t.Usage.OutputTokens)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// summary returns e.g. "1,200 in / 300 out (cache read 900, cache write 100)".
+func (u TokenUsage) summary() string {
+	s := fmt.Sprintf("%s in / %s out", formatTokenCount(u.InputTokens), formatTokenCount(u.OutputTokens))
+	if u.CacheReadInputTokens > 0 || u.CacheCreationInputTokens > 0 {
+		s += fmt.Sprintf(" (cache read %s, cache write %s)", formatTokenCount(u.CacheReadInputTokens), formatTokenCount(u.CacheCreationInputTokens))
+	}
+	return s
+}
+
+// formatTokenCount formats n with thousands separators.
+func formatTokenCount(n int) string {
+	s := fmt.Sprintf("%d", n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+var conversationHTML = template.Must(template.New("conversation").Funcs(template.FuncMap{
+	"heading": func(t ConversationTurn) string { return t.heading() },
+	"tokens":  func(u TokenUsage) string { return u.summary() },
+	"input":   func(c ConversationToolCall) string { return string(c.Input) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #1f2328; }
+.meta { color: #59636e; }
+.turn { border-left: 4px solid #d0d7de; margin: 1.5em 0; padding: 0 1em; }
+.turn.user { border-color: #0969da; }
+.turn.assistant { border-color: #8250df; }
+.turn h3 { font-size: 0.9em; color: #59636e; margin: 0 0 0.5em; }
+.text, pre { white-space: pre-wrap; word-wrap: break-word; }
+pre { background: #f6f8fa; padding: 0.75em; border-radius: 6px; font-size: 0.85em; }
+details { margin: 0.5em 0; }
+summary { cursor: pointer; font-family: monospace; }
+.error summary { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Conv.Tool}}{{with .Conv.ConversationID}} · {{.}}{{end}} · {{tokens .Conv.Usage}}</p>
+{{template "turns" .Conv.Turns}}
+{{range .Conv.Subagents}}
+<h2>Subagent {{.AgentID}}</h2>
+<p class="meta">{{tokens .Usage}}</p>
+{{template "turns" .Turns}}
+{{end}}
+</body>
+</html>
+{{define "turns"}}{{range .}}
+<div class="turn {{.Role}}">
+<h3>{{heading .}}</h3>
+{{with .Text}}<div class="text">{{.}}</div>{{end}}
+{{range .ToolCalls}}<details{{if .IsError}} class="error"{{end}}><summary>{{.Name}}</summary>
+{{with input .}}<pre>{{.}}</pre>{{end}}
+{{with .Result}}<pre>{{.}}</pre>{{end}}
+</details>
+{{end}}</div>
+{{end}}{{end}}
+`))
+
+// WriteHTML renders the conversation as a self-contained HTML page.
+func (c *Conversation) WriteHTML(w io.Writer, title string) error {
+	return conversationHTML.Execute(w, struct {
+		Title string
+		Conv  *Conversation
+	}{title, c})
+}
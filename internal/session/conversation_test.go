@@ -0,0 +1,134 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const claudeTranscript = `{"type":"summary","summary":"Fix login"}
+{"type":"user","sessionId":"abc","timestamp":"2026-03-01T09:00:00Z","message":{"role":"user","content":"Fix the login bug"}}
+{"type":"user","sessionId":"abc","timestamp":"2026-03-01T09:00:00Z","isMeta":true,"message":{"role":"user","content":"<local-command-caveat>"}}
+{"type":"assistant","sessionId":"abc","timestamp":"2026-03-01T09:00:05Z","message":{"id":"m1","role":"assistant","model":"claude-sonnet-4","content":[{"type":"text","text":"Looking at auth.go."}],"usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":100}}}
+{"type":"assistant","sessionId":"abc","timestamp":"2026-03-01T09:00:06Z","message":{"id":"m1","role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"auth.go"}}],"usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":100}}}
+{"type":"user","sessionId":"abc","timestamp":"2026-03-01T09:00:07Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"package auth"}]}]}}
+{"type":"assistant","sessionId":"abc","timestamp":"2026-03-01T09:00:08Z","isSidechain":true,"agentId":"sub1","message":{"id":"s1","role":"assistant","content":[{"type":"text","text":"Subagent here"}],"usage":{"input_tokens":5,"output_tokens":5}}}
+{"type":"assistant","sessionId":"abc","timestamp":"2026-03-01T09:01:00Z","message":{"id":"m2","role":"assistant","model":"claude-sonnet-4","content":[{"type":"text","text":"Fixed."}],"usage":{"input_tokens":30,"output_tokens":4}}}
+`
+
+func TestParseClaudeConversation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.jsonl")
+	if err := os.WriteFile(path, []byte(claudeTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conv, err := ParseTranscript("claude", path, ConversationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.ConversationID != "abc" || len(conv.Turns) != 3 {
+		t.Fatalf("conversation = %+v", conv)
+	}
+	first := conv.Turns[1]
+	if first.Text != "Looking at auth.go." || len(first.ToolCalls) != 1 || first.Model != "claude-sonnet-4" {
+		t.Errorf("streamed turn = %+v", first)
+	}
+	if call := first.ToolCalls[0]; call.Name != "Read" || call.Result != "package auth" || string(call.Input) != `{"file_path":"auth.go"}` {
+		t.Errorf("tool call = %+v", call)
+	}
+	if first.Usage == nil || first.Usage.OutputTokens != 20 {
+		t.Errorf("streamed usage = %+v, want the last record's", first.Usage)
+	}
+	if len(conv.Subagents) != 1 || conv.Subagents[0].AgentID != "sub1" || conv.Subagents[0].Turns[0].Text != "Subagent here" {
+		t.Errorf("subagents = %+v", conv.Subagents)
+	}
+	if conv.Usage.InputTokens != 45 || conv.Usage.OutputTokens != 29 || conv.Usage.CacheReadInputTokens != 100 {
+		t.Errorf("total usage = %+v", conv.Usage)
+	}
+
+	since, _ := time.Parse(time.RFC3339, "2026-03-01T09:00:30Z")
+	conv, _ = ParseTranscript("claude", path, ConversationOptions{Since: since})
+	if len(conv.Turns) != 1 || conv.Turns[0].Text != "Fixed." || len(conv.Subagents) != 0 {
+		t.Errorf("since filter = %+v", conv)
+	}
+}
+
+func TestParseGeminiConversation(t *testing.T) {
+	conv, err := parseGeminiConversation([]byte(`{"sessionId":"g1","messages":[
+		{"type":"user","timestamp":"2026-03-01T09:00:00Z","content":"List files"},
+		{"type":"info","content":"ignored"},
+		{"type":"gemini","timestamp":"2026-03-01T09:00:02Z","model":"gemini-2.5-pro","content":"Here they are",
+		 "toolCalls":[{"id":"c1","name":"list_directory","args":{"path":"."},"resultDisplay":"a.go\nb.go","status":"success"}],
+		 "tokens":{"input":100,"output":10,"cached":40,"thoughts":5}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.ConversationID != "g1" || len(conv.Turns) != 2 {
+		t.Fatalf("conversation = %+v", conv)
+	}
+	turn := conv.Turns[1]
+	if turn.Role != "assistant" || turn.Model != "gemini-2.5-pro" || turn.ToolCalls[0].Result != "a.go\nb.go" {
+		t.Errorf("turn = %+v", turn)
+	}
+	if *turn.Usage != (TokenUsage{InputTokens: 60, OutputTokens: 15, CacheReadInputTokens: 40}) {
+		t.Errorf("usage = %+v", turn.Usage)
+	}
+}
+
+func TestParseCodexConversation(t *testing.T) {
+	conv := parseCodexConversation([]byte(`{"timestamp":"2026-03-01T09:00:00Z","type":"session_meta","payload":{"id":"x1","cwd":"/repo"}}
+{"timestamp":"2026-03-01T09:00:00Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>cwd</environment_context>"}]}}
+{"timestamp":"2026-03-01T09:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"Run the tests"}]}}
+{"timestamp":"2026-03-01T09:00:01Z","type":"event_msg","payload":{"type":"user_message","message":"Run the tests"}}
+{"timestamp":"2026-03-01T09:00:02Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{\"command\":[\"go\",\"test\"]}","call_id":"c1"}}
+{"timestamp":"2026-03-01T09:00:03Z","type":"response_item","payload":{"type":"function_call_output","call_id":"c1","output":"{\"output\":\"ok\",\"metadata\":{\"exit_code\":0}}"}}
+{"timestamp":"2026-03-01T09:00:03Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"input_tokens":50,"cached_input_tokens":20,"output_tokens":7}}}}
+{"timestamp":"2026-03-01T09:00:04Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"All tests pass."}]}}
+{"timestamp":"2026-03-01T09:00:04Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"input_tokens":60,"cached_input_tokens":50,"output_tokens":5}}}}
+`))
+	conv.finish(ConversationOptions{})
+	if conv.ConversationID != "x1" || len(conv.Turns) != 3 {
+		t.Fatalf("conversation = %+v", conv)
+	}
+	if call := conv.Turns[1].ToolCalls[0]; call.Name != "shell" || call.Result != "ok" {
+		t.Errorf("tool call = %+v", call)
+	}
+	if conv.Turns[2].Text != "All tests pass." || conv.Turns[2].Usage.OutputTokens != 5 {
+		t.Errorf("final turn = %+v", conv.Turns[2])
+	}
+	if conv.Usage != (TokenUsage{InputTokens: 40, OutputTokens: 12, CacheReadInputTokens: 70}) {
+		t.Errorf("usage = %+v", conv.Usage)
+	}
+}
+
+func TestConversationExport(t *testing.T) {
+	conv := parseClaudeConversation([]byte(claudeTranscript))
+	conv.finish(ConversationOptions{MaxResultBytes: 7})
+
+	var md strings.Builder
+	if err := conv.WriteMarkdown(&md, "login-fix"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# login-fix", "Fix the login bug", "<summary>Tool: Read</summary>", "package\n[truncated: 7 of 12 bytes shown]", "## Subagent sub1", "45 in / 29 out"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var html strings.Builder
+	if err := conv.WriteHTML(&html, "<login>"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<title>&lt;login&gt;</title>") || !strings.Contains(html.String(), `<div class="turn assistant">`) {
+		t.Errorf("html = %s", html.String())
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	for n, want := range map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -4200: "-4,200"} {
+		if got := formatTokenCount(n); got != want {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
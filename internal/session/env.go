@@ -14,7 +14,8 @@ import (
 //  1. Global [shell].env_files (in order)
 //  2. [shell].init_script (for direnv, nvm, etc.)
 //  3. Tool-specific env_file ([claude].env_file, [gemini].env_file, [tools.X].env_file)
-//  4. Inline env vars from [tools.X].env (highest priority)
+//  4. Inline env vars from [tools.X].env
+//  5. The session's own Env, e.g. from a template (highest priority)
 func (i *Instance) buildEnvSourceCommand() string {
 	var sources []string
 	config, _ := LoadUserConfig()
@@ -49,11 +50,16 @@ func (i *Instance) buildEnvSourceCommand() string {
 		sources = append(sources, buildSourceCmd(resolved, ignoreMissing))
 	}
 
-	// 4. Inline env vars from [tools.X].env (highest priority)
+	// 4. Inline env vars from [tools.X].env
 	if inlineEnv := i.getToolInlineEnv(); inlineEnv != "" {
 		sources = append(sources, inlineEnv)
 	}
 
+	// 5. The session's own env vars (highest priority)
+	if sessionEnv := exportEnv(i.Env); sessionEnv != "" {
+		sources = append(sources, sessionEnv)
+	}
+
 	if len(sources) == 0 {
 		return ""
 	}
@@ -124,16 +130,24 @@ func isFilePath(s string) bool {
 
 // getToolInlineEnv returns shell export commands for inline env vars from [tools.X].env.
 // Returns empty string if the tool has no inline env vars defined.
-// Keys are sorted for deterministic output. Single quotes in values are escaped.
 func (i *Instance) getToolInlineEnv() string {
 	def := GetToolDef(i.Tool)
-	if def == nil || len(def.Env) == 0 {
+	if def == nil {
+		return ""
+	}
+	return exportEnv(def.Env)
+}
+
+// exportEnv returns shell export commands for env joined with &&, or "".
+// Keys are sorted for deterministic output. Single quotes in values are escaped.
+func exportEnv(env map[string]string) string {
+	if len(env) == 0 {
 		return ""
 	}
 
 	// Sort keys for deterministic ordering
-	keys := make([]string, 0, len(def.Env))
-	for k := range def.Env {
+	keys := make([]string, 0, len(env))
+	for k := range env {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
@@ -141,7 +155,7 @@ func (i *Instance) getToolInlineEnv() string {
 	// Build export statements with single-quote escaping
 	exports := make([]string, 0, len(keys))
 	for _, k := range keys {
-		v := def.Env[k]
+		v := env[k]
 		// Escape single quotes: replace ' with '\'' (end quote, escaped quote, start quote)
 		escaped := strings.ReplaceAll(v, "'", "'\\''")
 		exports = append(exports, fmt.Sprintf("export %s='%s'", k, escaped))
@@ -19,6 +19,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/sahilm/fuzzy"
 	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/statedb"
 	"golang.org/x/time/rate"
 )
 
@@ -28,8 +29,9 @@ var searchLog = logging.ForComponent(logging.CompSession)
 type SearchTier int
 
 const (
-	TierInstant  SearchTier = iota // < 100MB, full in-memory
-	TierBalanced                   // 100MB-500MB, on-demand scan to cap memory
+	TierInstant    SearchTier = iota // < 100MB, full in-memory
+	TierBalanced                     // 100MB-500MB, on-demand scan to cap memory
+	TierPersistent                   // on-disk SQLite FTS5 index, opt-in via tier = "persistent"
 )
 
 // TierThresholdInstant is the max size for instant tier (100MB)
@@ -38,11 +40,12 @@ const TierThresholdInstant = 100 * 1024 * 1024
 // TierThresholdBalanced is the max size for balanced tier (500MB)
 const TierThresholdBalanced = 500 * 1024 * 1024
 
-// SearchEntry represents a searchable Claude session
+// SearchEntry represents a searchable agent conversation
 type SearchEntry struct {
-	SessionID string    // Claude session UUID
-	FilePath  string    // Path to .jsonl file
-	CWD       string    // Project working directory
+	SessionID string    // The agent's session ID
+	Tool      string    // Agent that wrote the transcript: claude, gemini, codex or opencode
+	FilePath  string    // Path to the transcript file
+	CWD       string    // Project working directory ("" for Gemini, which stores only a hash)
 	Summary   string    // First user message or summary
 	ModTime   time.Time // File modification time
 	FileSize  int64     // File size in bytes
@@ -384,56 +387,31 @@ func parseClaudeJSONL(filePath string, data []byte, includeContent bool) (*Searc
 	return entry, nil
 }
 
-// parseClaudeJSONLHead reads only the first 32KB of a JSONL file to extract metadata.
-// Used in TierBalanced mode to avoid reading entire files (which can be 100s of MB).
-func parseClaudeJSONLHead(filePath string) (*SearchEntry, error) {
-	f, err := os.Open(filePath)
+// transcriptHeadBytes is how much of an appendable transcript is read for
+// its metadata in the balanced tier, which avoids reading whole files (which
+// can be 100s of MB).
+const transcriptHeadBytes = 32 * 1024
+
+// parseTranscriptHead parses the metadata of path without its content.
+func parseTranscriptHead(src TranscriptSource, path string) (*SearchEntry, error) {
+	if !src.Appendable() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return src.Parse(path, data, false)
+	}
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-
-	entry := &SearchEntry{FilePath: filePath}
-	scanner := bufio.NewScanner(io.LimitReader(f, 32*1024))
-	buf := make([]byte, 0, 32*1024)
-	scanner.Buffer(buf, 32*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-		var record claudeJSONLRecord
-		if err := json.Unmarshal(line, &record); err != nil {
-			continue
-		}
-		if entry.SessionID == "" && record.SessionID != "" {
-			entry.SessionID = record.SessionID
-		}
-		if entry.CWD == "" && record.CWD != "" {
-			entry.CWD = record.CWD
-		}
-		if entry.Summary == "" && record.Summary != "" {
-			entry.Summary = record.Summary
-		}
-		if entry.Summary == "" && record.Type == "user" && len(record.Message) > 0 {
-			var msg claudeMessage
-			if err := json.Unmarshal(record.Message, &msg); err == nil {
-				var contentStr string
-				if err := json.Unmarshal(msg.Content, &contentStr); err == nil {
-					if len(contentStr) > 200 {
-						entry.Summary = contentStr[:200] + "..."
-					} else {
-						entry.Summary = contentStr
-					}
-				}
-			}
-		}
-		if entry.SessionID != "" && entry.Summary != "" {
-			break
-		}
+	data, err := io.ReadAll(io.LimitReader(f, transcriptHeadBytes))
+	if err != nil {
+		return nil, err
 	}
-	return entry, nil
+	// A line cut off at the limit fails to parse and is skipped.
+	return src.Parse(path, data, false)
 }
 
 // DetectTier determines the appropriate search tier based on data size
@@ -451,6 +429,8 @@ func TierName(tier SearchTier) string {
 		return "instant"
 	case TierBalanced:
 		return "balanced"
+	case TierPersistent:
+		return "persistent"
 	default:
 		return "unknown"
 	}
@@ -467,8 +447,8 @@ type SearchResult struct {
 // GlobalSearchIndex manages the searchable session index
 type GlobalSearchIndex struct {
 	// Configuration
-	config    GlobalSearchSettings
-	claudeDir string
+	config  GlobalSearchSettings
+	sources []TranscriptSource
 
 	// Index data (protected by atomic pointer for lock-free reads)
 	entries atomic.Pointer[[]SearchEntry]
@@ -502,6 +482,12 @@ type GlobalSearchIndex struct {
 	lastQuery   string
 	lastResults []*SearchResult
 	lastQueryMu sync.Mutex
+
+	// Full-text index for the persistent tier
+	db         *statedb.SearchDB
+	ftsMu      sync.Mutex             // serializes file indexing so chunks are not read twice
+	entryPos   map[string]int         // index of each file in entries; guarded by ftsMu
+	newEntries map[string]SearchEntry // indexed but not yet published; guarded by ftsMu
 }
 
 // FileTracker tracks file state for incremental updates
@@ -536,7 +522,7 @@ func NewGlobalSearchIndex(claudeDir string, config GlobalSearchSettings) (*Globa
 
 	idx := &GlobalSearchIndex{
 		config:           config,
-		claudeDir:        claudeDir,
+		sources:          transcriptSources(claudeDir, config.Sources),
 		fileTrackers:     make(map[string]*FileTracker),
 		limiter:          rate.NewLimiter(rate.Limit(config.IndexRateLimit), 5),
 		memoryLimitBytes: memLimitBytes,
@@ -548,59 +534,49 @@ func NewGlobalSearchIndex(claudeDir string, config GlobalSearchSettings) (*Globa
 	emptyEntries := make([]SearchEntry, 0)
 	idx.entries.Store(&emptyEntries)
 
-	// Measure data size and determine tier
-	projectsDir := filepath.Join(claudeDir, "projects")
-	totalSize, err := measureDataSize(projectsDir, config.RecentDays)
-	if err != nil {
-		// Don't fail if projects dir doesn't exist, just use empty index
-		if !os.IsNotExist(err) {
-			cancel()
-			return nil, err
-		}
-	}
-
 	// Determine tier (respect config override)
 	switch config.Tier {
 	case "instant":
 		idx.tier = TierInstant
 	case "balanced":
 		idx.tier = TierBalanced
+	case "persistent":
+		idx.tier = TierPersistent
+		if err := idx.openIndexDB(); err != nil {
+			cancel()
+			return nil, err
+		}
 	case "disabled":
 		cancel()
 		return nil, nil
 	default:
-		idx.tier = DetectTier(totalSize)
+		// Measure data size to pick a tier
+		idx.tier = DetectTier(idx.measureDataSize())
 	}
 
 	// Start file watcher
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
+		idx.closeIndexDB()
 		cancel()
 		return nil, err
 	}
 	idx.watcher = watcher
 
-	// Watch the projects directory (create if doesn't exist check)
-	if _, err := os.Stat(projectsDir); err == nil {
-		if err := watcher.Add(projectsDir); err != nil {
-			searchLog.Warn("global_search_watch_failed", slog.String("error", err.Error()))
-		}
-
-		// Only watch project-level directories (depth 1 under projects/).
-		// Previously watched ALL subdirectories (884 dirs including tool-results/,
-		// subagents/, etc.) which leaked ~7000 kqueue file descriptors and caused
-		// hangar to balloon to 6+ GB RSS until macOS killed it.
-		// JSONL files only exist at the project level, not in subdirectories.
-		dirEntries, _ := os.ReadDir(projectsDir)
-		for _, de := range dirEntries {
-			if de.IsDir() {
-				_ = watcher.Add(filepath.Join(projectsDir, de.Name()))
-			}
+	// Watch each source's transcript directories. Only the directories a
+	// source asks for are watched: watching ALL subdirectories of Claude's
+	// projects (884 dirs including tool-results/, subagents/, etc.) leaked
+	// ~7000 kqueue file descriptors and caused hangar to balloon to 6+ GB RSS
+	// until macOS killed it.
+	for _, src := range idx.sources {
+		if _, err := os.Stat(src.Root()); err == nil {
+			idx.watchTree(src, src.Root())
 		}
 	}
 
-	// Set loading state
-	idx.loading.Store(true)
+	// Set loading state. A persistent index that already has files answers
+	// searches while it catches up, so it does not count as loading.
+	idx.loading.Store(idx.tier != TierPersistent || idx.EntryCount() == 0)
 
 	// Start background workers
 	idx.wg.Add(2)
@@ -610,47 +586,111 @@ func NewGlobalSearchIndex(claudeDir string, config GlobalSearchSettings) (*Globa
 	return idx, nil
 }
 
-// measureDataSize calculates total size of JSONL files
-func measureDataSize(projectsDir string, recentDays int) (int64, error) {
+// measureDataSize calculates the total size of recent transcripts
+func (idx *GlobalSearchIndex) measureDataSize() int64 {
 	var totalSize int64
 	cutoff := time.Time{}
-	if recentDays > 0 {
-		cutoff = time.Now().AddDate(0, 0, -recentDays)
+	if idx.config.RecentDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -idx.config.RecentDays)
+	}
+	idx.walkTranscripts(func(_ TranscriptSource, _ string, info os.FileInfo) {
+		if cutoff.IsZero() || !info.ModTime().Before(cutoff) {
+			totalSize += info.Size()
+		}
+	})
+	return totalSize
+}
+
+// walkTranscripts calls fn for every transcript file of every source,
+// until the index is closed.
+func (idx *GlobalSearchIndex) walkTranscripts(fn func(src TranscriptSource, path string, info os.FileInfo)) {
+	for _, src := range idx.sources {
+		root := src.Root()
+		_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if rel, err := filepath.Rel(root, path); err != nil || !src.WalkDir(rel) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !src.Match(path) {
+				return nil
+			}
+			if idx.ctx.Err() != nil {
+				return filepath.SkipAll
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			fn(src, path, info)
+			return nil
+		})
 	}
+}
 
-	err := filepath.WalkDir(projectsDir, func(path string, d os.DirEntry, err error) error {
+// watchTree watches dir and the directories below it that src asks for,
+// and returns the transcripts found in them.
+func (idx *GlobalSearchIndex) watchTree(src TranscriptSource, dir string) []string {
+	var files []string
+	root := src.Root()
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
-		if d.IsDir() {
-			name := d.Name()
-			if name == "tool-results" || name == "subagents" {
-				return filepath.SkipDir
+		if !d.IsDir() {
+			if src.Match(path) {
+				files = append(files, path)
 			}
 			return nil
 		}
-		if !strings.HasSuffix(path, ".jsonl") {
-			return nil
-		}
-		info, err := d.Info()
-		if err != nil {
-			return nil
+		rel, err := filepath.Rel(root, path)
+		if err != nil || !src.WalkDir(rel) {
+			return filepath.SkipDir
 		}
-		if !cutoff.IsZero() && info.ModTime().Before(cutoff) {
-			return nil
+		if src.WatchDir(rel) {
+			if err := idx.watcher.Add(path); err != nil {
+				searchLog.Warn("global_search_watch_failed", slog.String("path", path), slog.String("error", err.Error()))
+			}
 		}
-		totalSize += info.Size()
 		return nil
 	})
+	return files
+}
 
-	return totalSize, err
+// sourceFor returns the source whose root holds path, or nil.
+func (idx *GlobalSearchIndex) sourceFor(path string) TranscriptSource {
+	for _, src := range idx.sources {
+		if strings.HasPrefix(path, src.Root()+string(filepath.Separator)) {
+			return src
+		}
+	}
+	return nil
+}
+
+// source returns the source for tool, or nil.
+func (idx *GlobalSearchIndex) source(tool string) TranscriptSource {
+	for _, src := range idx.sources {
+		if src.Tool() == tool {
+			return src
+		}
+	}
+	return nil
 }
 
 // initialLoad loads all session files on startup
 func (idx *GlobalSearchIndex) initialLoad() {
 	defer idx.wg.Done()
 
-	projectsDir := filepath.Join(idx.claudeDir, "projects")
+	if idx.tier == TierPersistent {
+		idx.catchUpIndexDB()
+		idx.loading.Store(false)
+		return
+	}
+
 	cutoff := time.Time{}
 	if idx.config.RecentDays > 0 {
 		cutoff = time.Now().AddDate(0, 0, -idx.config.RecentDays)
@@ -659,60 +699,27 @@ func (idx *GlobalSearchIndex) initialLoad() {
 	var entries []SearchEntry
 	includeContent := idx.tier == TierInstant
 
-	_ = filepath.WalkDir(projectsDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		if d.IsDir() {
-			// Skip subdirectories that never contain JSONL files.
-			// This avoids traversing thousands of tool-results/ and subagents/ dirs.
-			name := d.Name()
-			if name == "tool-results" || name == "subagents" {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		if !strings.HasSuffix(path, ".jsonl") {
-			return nil
-		}
-
-		// Check cancellation
-		select {
-		case <-idx.ctx.Done():
-			return filepath.SkipAll
-		default:
-		}
-
-		info, err := d.Info()
-		if err != nil {
-			return nil
-		}
-
+	idx.walkTranscripts(func(src TranscriptSource, path string, info os.FileInfo) {
 		// Check recency
 		if !cutoff.IsZero() && info.ModTime().Before(cutoff) {
-			return nil
-		}
-
-		// Only UUID-named files (skip agent-*.jsonl)
-		baseName := filepath.Base(path)
-		if !isUUIDFileName(baseName) {
-			return nil
+			return
 		}
 
-		// Parse file: for metadata-only mode, read just the head (first 32KB)
+		// Parse file: for metadata-only mode, read just the head
 		var entry *SearchEntry
+		var err error
 		if !includeContent {
-			entry, err = parseClaudeJSONLHead(path)
+			entry, err = parseTranscriptHead(src, path)
 		} else {
 			var data []byte
 			data, err = os.ReadFile(path)
 			if err != nil {
-				return nil
+				return
 			}
-			entry, err = parseClaudeJSONL(path, data, true)
+			entry, err = src.Parse(path, data, true)
 		}
 		if err != nil || entry == nil || entry.SessionID == "" {
-			return nil
+			return
 		}
 
 		entry.ModTime = info.ModTime()
@@ -734,8 +741,6 @@ func (idx *GlobalSearchIndex) initialLoad() {
 			LastMod:    info.ModTime(),
 		}
 		idx.trackerMu.Unlock()
-
-		return nil
 	})
 
 	// Store entries and mark loading complete
@@ -763,6 +768,21 @@ func (idx *GlobalSearchIndex) watcherLoop() {
 	debounce := make(map[string]*time.Timer)
 	debounceMu := sync.Mutex{}
 
+	// Debounce: wait 300ms after last event for this file
+	schedule := func(path string) {
+		debounceMu.Lock()
+		if timer, exists := debounce[path]; exists {
+			timer.Stop()
+		}
+		debounce[path] = time.AfterFunc(300*time.Millisecond, func() {
+			idx.updateFile(path)
+			debounceMu.Lock()
+			delete(debounce, path)
+			debounceMu.Unlock()
+		})
+		debounceMu.Unlock()
+	}
+
 	for {
 		select {
 		case <-idx.ctx.Done():
@@ -778,26 +798,29 @@ func (idx *GlobalSearchIndex) watcherLoop() {
 				return
 			}
 
-			// Only care about writes and creates for .jsonl files
-			if !strings.HasSuffix(event.Name, ".jsonl") {
+			// Only care about writes and creates under a source
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
 				continue
 			}
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+			src := idx.sourceFor(event.Name)
+			if src == nil {
 				continue
 			}
 
-			// Debounce: wait 300ms after last event for this file
-			debounceMu.Lock()
-			if timer, exists := debounce[event.Name]; exists {
-				timer.Stop()
+			// New project or day directories: watch them and index
+			// transcripts written before the watch was in place.
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					for _, path := range idx.watchTree(src, event.Name) {
+						schedule(path)
+					}
+					continue
+				}
+			}
+
+			if src.Match(event.Name) {
+				schedule(event.Name)
 			}
-			debounce[event.Name] = time.AfterFunc(300*time.Millisecond, func() {
-				idx.updateFile(event.Name)
-				debounceMu.Lock()
-				delete(debounce, event.Name)
-				debounceMu.Unlock()
-			})
-			debounceMu.Unlock()
 
 		case err, ok := <-idx.watcher.Errors:
 			if !ok {
@@ -810,7 +833,8 @@ func (idx *GlobalSearchIndex) watcherLoop() {
 
 // updateFile handles incremental update for a single file
 func (idx *GlobalSearchIndex) updateFile(path string) {
-	if !isUUIDFileName(filepath.Base(path)) {
+	src := idx.sourceFor(path)
+	if src == nil || !src.Match(path) {
 		return
 	}
 
@@ -819,12 +843,18 @@ func (idx *GlobalSearchIndex) updateFile(path string) {
 		return // File deleted, ignore for now
 	}
 
+	if idx.tier == TierPersistent {
+		idx.indexFileDB(src, path, info)
+		idx.publishEntries()
+		return
+	}
+
 	includeContent := idx.tier == TierInstant
 	idx.trackerMu.RLock()
 	tracker, exists := idx.fileTrackers[path]
 	idx.trackerMu.RUnlock()
 
-	if exists && info.Size() < tracker.LastSize {
+	if exists && (info.Size() < tracker.LastSize || !src.Appendable()) {
 		// File was truncated/replaced, do full reload of this file
 		tracker = nil
 	}
@@ -883,7 +913,7 @@ func (idx *GlobalSearchIndex) updateFile(path string) {
 	}
 
 	// Parse and update
-	entry, err := parseClaudeJSONL(path, data, includeContent)
+	entry, err := src.Parse(path, data, includeContent)
 	if err != nil || entry.SessionID == "" {
 		return
 	}
@@ -907,9 +937,18 @@ func (idx *GlobalSearchIndex) updateFile(path string) {
 			updated.FileSize = entry.FileSize
 
 			if includeContent && entry.hasContent() {
-				newData := entry.content.CopyData()
-				idx.currentMemoryBytes.Add(int64(len(newData) * 2)) // data + lowered copy
-				updated.appendContent(newData)
+				if tracker == nil {
+					// Reparsed from the start: replace the content.
+					if updated.hasContent() {
+						idx.currentMemoryBytes.Add(-updated.content.Size())
+					}
+					idx.currentMemoryBytes.Add(entry.content.Size())
+					updated.content = entry.content
+				} else {
+					newData := entry.content.CopyData()
+					idx.currentMemoryBytes.Add(int64(len(newData) * 2)) // data + lowered copy
+					updated.appendContent(newData)
+				}
 			}
 
 			newEntries = append(newEntries, updated)
@@ -1006,8 +1045,11 @@ func (idx *GlobalSearchIndex) Search(query string) []*SearchResult {
 		return nil
 	}
 
-	if idx.tier == TierBalanced {
+	switch idx.tier {
+	case TierBalanced:
 		return idx.searchOnDisk(query)
+	case TierPersistent:
+		return idx.searchIndexDB(query, nil, maxIndexDBResults)
 	}
 
 	entries := idx.entries.Load()
@@ -1096,6 +1138,100 @@ func (idx *GlobalSearchIndex) FuzzySearch(query string) []*SearchResult {
 	return results
 }
 
+// SearchQuery is a search with filters, for Query.
+type SearchQuery struct {
+	Text  string
+	Fuzzy bool      // typo-tolerant matching (FuzzySearch) instead of substring
+	Dirs  []string  // keep conversations whose working directory is one of these or below
+	Since time.Time // keep conversations modified at or after this time
+	Limit int       // maximum results (0 = all)
+}
+
+// Query runs Search or FuzzySearch and applies q's filters, keeping the
+// best matches first.
+func (idx *GlobalSearchIndex) Query(q SearchQuery) []*SearchResult {
+	var results []*SearchResult
+	switch {
+	case q.Fuzzy:
+		results = idx.FuzzySearch(q.Text)
+	case idx.tier == TierPersistent && q.Text != "":
+		// Filter while reading the index, before its result cap applies.
+		limit := maxIndexDBResults
+		if q.Limit > 0 && q.Limit < limit {
+			limit = q.Limit
+		}
+		return idx.searchIndexDB(q.Text, q.keeps, limit)
+	default:
+		results = idx.Search(q.Text)
+	}
+	// Search may keep results as its query cache, so filter into a copy.
+	var out []*SearchResult
+	for _, r := range results {
+		if !q.keeps(r.Entry) {
+			continue
+		}
+		out = append(out, r)
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// keeps reports whether e passes q's Dirs and Since filters.
+func (q SearchQuery) keeps(e *SearchEntry) bool {
+	if !q.Since.IsZero() && e.ModTime.Before(q.Since) {
+		return false
+	}
+	return len(q.Dirs) == 0 || entryInDirs(e, q.Dirs)
+}
+
+// entryInDirs reports whether e's conversation ran in one of dirs or below.
+// Gemini records only a hash of the directory, which is compared with the
+// hash of each dir itself.
+func entryInDirs(e *SearchEntry, dirs []string) bool {
+	if e.CWD != "" || e.Tool != "gemini" {
+		return pathUnderAny(e.CWD, dirs)
+	}
+	hash := geminiProjectHash(e.FilePath)
+	for _, dir := range dirs {
+		if HashProjectPath(dir) == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// pathUnderAny reports whether path is one of dirs or inside one of them.
+func pathUnderAny(path string, dirs []string) bool {
+	if path == "" {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitLoaded blocks until the initial index load finishes or ctx is done,
+// and reports whether the load finished.
+func (idx *GlobalSearchIndex) WaitLoaded(ctx context.Context) bool {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for idx.loading.Load() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
 // GetTier returns the current search tier
 func (idx *GlobalSearchIndex) GetTier() SearchTier {
 	return idx.tier
@@ -1122,6 +1258,7 @@ func (idx *GlobalSearchIndex) Close() {
 		idx.watcher.Close()
 	}
 	idx.wg.Wait()
+	idx.closeIndexDB()
 
 	// Release all content memory
 	emptyEntries := make([]SearchEntry, 0)
@@ -1235,7 +1372,7 @@ func (idx *GlobalSearchIndex) searchOnDisk(query string) []*SearchResult {
 		go func() {
 			defer wg.Done()
 			for entry := range jobs {
-				matchCount, snippet := scanFileForQuery(entry.FilePath, queryLower, 60)
+				matchCount, snippet := idx.scanEntryForQuery(entry, queryLower, 60)
 				if matchCount > 0 {
 					hits <- searchHit{entry: entry, count: matchCount, snippet: snippet}
 				}
@@ -1306,6 +1443,29 @@ func (idx *GlobalSearchIndex) resetQueryCache() {
 	idx.lastQueryMu.Unlock()
 }
 
+// scanEntryForQuery counts matches in entry's transcript on disk. Claude
+// transcripts are streamed line by line; other agents' are parsed whole.
+func (idx *GlobalSearchIndex) scanEntryForQuery(entry *SearchEntry, queryLower string, windowSize int) (int, string) {
+	src := idx.source(entry.Tool)
+	if src == nil || src.Tool() == "claude" {
+		return scanFileForQuery(entry.FilePath, queryLower, windowSize)
+	}
+	data, err := os.ReadFile(entry.FilePath)
+	if err != nil {
+		return 0, ""
+	}
+	parsed, err := src.Parse(entry.FilePath, data, true)
+	if err != nil || !parsed.hasContent() {
+		return 0, ""
+	}
+	content := parsed.ContentString()
+	matchCount := strings.Count(strings.ToLower(content), queryLower)
+	if matchCount == 0 {
+		return 0, ""
+	}
+	return matchCount, snippetFromText(content, queryLower, windowSize)
+}
+
 func scanFileForQuery(path string, queryLower string, windowSize int) (int, string) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,281 @@
+package session
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// maxIndexDBResults caps how many files one persistent-tier search returns.
+const maxIndexDBResults = 500
+
+// indexDBPublishEvery is how many files the catch-up scan indexes between
+// making their entries visible to searches.
+const indexDBPublishEvery = 200
+
+// openIndexDB opens the persistent index and loads its file list, so that
+// searches are answered from disk before the catch-up scan finishes.
+func (idx *GlobalSearchIndex) openIndexDB() error {
+	path := idx.config.IndexPath
+	if path == "" {
+		dir, err := GetHangarDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(dir, "search.db")
+	}
+	db, err := statedb.OpenSearch(ExpandPath(path))
+	if err != nil {
+		return err
+	}
+	files, err := db.SearchFiles()
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	entries := make([]SearchEntry, 0, len(files))
+	idx.entryPos = make(map[string]int, len(files))
+	idx.newEntries = make(map[string]SearchEntry)
+	for _, f := range files {
+		idx.entryPos[f.Path] = len(entries)
+		entries = append(entries, searchFileEntry(f))
+		idx.fileTrackers[f.Path] = &FileTracker{
+			Path:       f.Path,
+			LastOffset: f.Offset,
+			LastSize:   f.Size,
+			LastMod:    f.ModTime,
+		}
+	}
+	idx.entries.Store(&entries)
+	idx.db = db
+	return nil
+}
+
+// closeIndexDB waits for in-flight indexing and closes the database.
+func (idx *GlobalSearchIndex) closeIndexDB() {
+	idx.ftsMu.Lock()
+	defer idx.ftsMu.Unlock()
+	if idx.db != nil {
+		idx.db.Close()
+	}
+}
+
+// catchUpIndexDB indexes whatever changed on disk since the index was last
+// updated. Unlike the in-memory tiers it ignores RecentDays: all history
+// is indexed once and kept, even after an agent prunes old transcripts.
+func (idx *GlobalSearchIndex) catchUpIndexDB() {
+	indexed := 0
+	idx.walkTranscripts(func(src TranscriptSource, path string, info os.FileInfo) {
+		if !idx.fileChanged(path, info) {
+			return
+		}
+		if err := idx.limiter.Wait(idx.ctx); err != nil {
+			return
+		}
+		idx.indexFileDB(src, path, info)
+		if indexed++; indexed%indexDBPublishEvery == 0 {
+			idx.publishEntries()
+		}
+	})
+	idx.publishEntries()
+}
+
+// fileChanged reports whether path differs from what the index last saw.
+func (idx *GlobalSearchIndex) fileChanged(path string, info os.FileInfo) bool {
+	idx.trackerMu.RLock()
+	defer idx.trackerMu.RUnlock()
+	tracker, ok := idx.fileTrackers[path]
+	return !ok || tracker.LastSize != info.Size() || !tracker.LastMod.Equal(info.ModTime())
+}
+
+// indexFileDB indexes the complete lines appended to path since its tracked
+// offset, or the whole file when it is new, was truncated or is not
+// appendable. Its entry is visible to searches after publishEntries.
+func (idx *GlobalSearchIndex) indexFileDB(src TranscriptSource, path string, info os.FileInfo) {
+	idx.ftsMu.Lock()
+	defer idx.ftsMu.Unlock()
+	if idx.db == nil || !idx.fileChanged(path, info) {
+		return
+	}
+
+	idx.trackerMu.RLock()
+	tracker := idx.fileTrackers[path]
+	idx.trackerMu.RUnlock()
+	var offset int64
+	reset := tracker == nil || info.Size() < tracker.LastOffset || !src.Appendable()
+	if !reset {
+		offset = tracker.LastOffset
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return
+	}
+	// Leave a half-written last line for the next update.
+	if src.Appendable() {
+		if end := bytes.LastIndexByte(data, '\n'); end >= 0 {
+			data = data[:end+1]
+		} else {
+			data = nil
+		}
+	}
+
+	parsed, _ := src.Parse(path, data, true)
+	entry := SearchEntry{FilePath: path, Tool: src.Tool()}
+	if !reset {
+		if old := idx.findEntry(path); old != nil {
+			entry = *old
+		}
+	}
+	if parsed != nil {
+		if entry.SessionID == "" {
+			entry.SessionID = parsed.SessionID
+		}
+		if entry.CWD == "" {
+			entry.CWD = parsed.CWD
+		}
+		if entry.Summary == "" {
+			entry.Summary = parsed.Summary
+		}
+	}
+	if entry.SessionID == "" {
+		return // nothing useful yet; retry on the next write
+	}
+	entry.ModTime = info.ModTime()
+	entry.FileSize = info.Size()
+
+	var content string
+	if parsed != nil && parsed.hasContent() {
+		content = string(parsed.content.CopyData())
+	}
+	row := statedb.SearchFileRow{
+		Path:      path,
+		SessionID: entry.SessionID,
+		Tool:      entry.Tool,
+		CWD:       entry.CWD,
+		Summary:   entry.Summary,
+		ModTime:   entry.ModTime,
+		Size:      entry.FileSize,
+		Offset:    offset + int64(len(data)),
+	}
+	if err := idx.db.IndexSearchFile(row, content, reset); err != nil {
+		searchLog.Warn("global_search_index_failed", slog.String("path", path), slog.String("error", err.Error()))
+		return
+	}
+
+	idx.trackerMu.Lock()
+	idx.fileTrackers[path] = &FileTracker{
+		Path:       path,
+		LastOffset: row.Offset,
+		LastSize:   row.Size,
+		LastMod:    row.ModTime,
+	}
+	idx.trackerMu.Unlock()
+	idx.storeEntry(entry)
+}
+
+// findEntry returns the indexed entry for path, or nil. ftsMu must be held.
+func (idx *GlobalSearchIndex) findEntry(path string) *SearchEntry {
+	if e, ok := idx.newEntries[path]; ok {
+		return &e
+	}
+	if i, ok := idx.entryPos[path]; ok {
+		return &(*idx.entries.Load())[i]
+	}
+	return nil
+}
+
+// storeEntry queues e to replace or add the entry for e.FilePath at the
+// next publishEntries. ftsMu must be held.
+func (idx *GlobalSearchIndex) storeEntry(e SearchEntry) {
+	idx.newEntries[e.FilePath] = e
+}
+
+// publishEntries makes the queued entries visible to searches, copying the
+// entry list once for all of them.
+func (idx *GlobalSearchIndex) publishEntries() {
+	idx.ftsMu.Lock()
+	defer idx.ftsMu.Unlock()
+	if len(idx.newEntries) == 0 {
+		return
+	}
+	old := idx.entries.Load()
+	entries := make([]SearchEntry, len(*old), len(*old)+len(idx.newEntries))
+	copy(entries, *old)
+	for path, e := range idx.newEntries {
+		if i, ok := idx.entryPos[path]; ok {
+			entries[i] = e
+		} else {
+			idx.entryPos[path] = len(entries)
+			entries = append(entries, e)
+		}
+	}
+	clear(idx.newEntries)
+	idx.entries.Store(&entries)
+}
+
+// searchIndexDB runs query against the full-text index, ranked by bm25, and
+// returns up to limit files for which keep (if not nil) returns true. Text
+// that is not valid FTS5 syntax, such as "main.go", is searched as a phrase.
+func (idx *GlobalSearchIndex) searchIndexDB(query string, keep func(*SearchEntry) bool, limit int) []*SearchResult {
+	if idx.db == nil {
+		return nil
+	}
+	var keepRow func(*statedb.SearchHitRow) bool
+	if keep != nil {
+		keepRow = func(h *statedb.SearchHitRow) bool {
+			entry := searchFileEntry(h.SearchFileRow)
+			return keep(&entry)
+		}
+	}
+	hits, err := idx.db.SearchContent(query, limit, keepRow)
+	if err != nil {
+		hits, err = idx.db.SearchContent(ftsPhrase(query), limit, keepRow)
+		if err != nil {
+			searchLog.Warn("global_search_query_failed", slog.String("error", err.Error()))
+			return nil
+		}
+	}
+	results := make([]*SearchResult, 0, len(hits))
+	for _, h := range hits {
+		entry := searchFileEntry(h.SearchFileRow)
+		results = append(results, &SearchResult{
+			Entry:   &entry,
+			Score:   int(math.Round(-h.Rank * 100)),
+			Snippet: h.Snippet,
+		})
+	}
+	return results
+}
+
+// ftsPhrase quotes s as a single FTS5 phrase.
+func ftsPhrase(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func searchFileEntry(f statedb.SearchFileRow) SearchEntry {
+	return SearchEntry{
+		SessionID: f.SessionID,
+		Tool:      f.Tool,
+		FilePath:  f.Path,
+		CWD:       f.CWD,
+		Summary:   f.Summary,
+		ModTime:   f.ModTime,
+		FileSize:  f.Size,
+	}
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -181,6 +182,7 @@ func TestGlobalSearchIndexInstantTier(t *testing.T) {
 		MemoryLimitMB:  100,
 		RecentDays:     0, // All sessions
 		IndexRateLimit: 100,
+		Sources:        []string{"claude"},
 	}
 
 	index, err := NewGlobalSearchIndex(tmpDir, config)
@@ -284,6 +286,7 @@ func TestGlobalSearchIndexBalancedTier(t *testing.T) {
 		MemoryLimitMB:  1, // Very low limit
 		RecentDays:     0,
 		IndexRateLimit: 100,
+		Sources:        []string{"claude"},
 	}
 
 	index, err := NewGlobalSearchIndex(tmpDir, config)
@@ -321,6 +324,7 @@ func TestGlobalSearchIndexTierAutoDetect(t *testing.T) {
 		Tier:           "auto",
 		MemoryLimitMB:  100,
 		IndexRateLimit: 100,
+		Sources:        []string{"claude"},
 	}
 
 	index, _ := NewGlobalSearchIndex(tmpDir, config)
@@ -334,3 +338,215 @@ func TestGlobalSearchIndexTierAutoDetect(t *testing.T) {
 		t.Errorf("Expected instant tier for small data, got %v", TierName(index.GetTier()))
 	}
 }
+
+func TestGlobalSearchIndexQueryFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "projects", "-Users-test")
+	_ = os.MkdirAll(projectDir, 0755)
+
+	write := func(id, cwd, text string, modTime time.Time) {
+		line := fmt.Sprintf(`{"sessionId":%q,"type":"user","message":{"role":"user","content":%q},"cwd":%q}`, id, text, cwd)
+		path := filepath.Join(projectDir, id+".jsonl")
+		if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_ = os.Chtimes(path, modTime, modTime)
+	}
+	now := time.Now()
+	write("11111111-1111-1111-1111-111111111111", "/src/api", "fix the auth bug in auth middleware", now)
+	write("22222222-2222-2222-2222-222222222222", "/src/api-worktrees/login", "auth bug again, auth everywhere, auth", now)
+	write("33333333-3333-3333-3333-333333333333", "/src/web", "auth bug in the web app", now.AddDate(0, 0, -10))
+
+	index, err := NewGlobalSearchIndex(tmpDir, GlobalSearchSettings{Enabled: true, Tier: "instant", IndexRateLimit: 100, Sources: []string{"claude"}})
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer index.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if !index.WaitLoaded(ctx) {
+		t.Fatal("index did not finish loading")
+	}
+
+	ids := func(results []*SearchResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Entry.SessionID[:1])
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		q    SearchQuery
+		want string
+	}{
+		{"all, best first", SearchQuery{Text: "auth"}, "2,1,3"},
+		{"since", SearchQuery{Text: "auth", Since: now.AddDate(0, 0, -1)}, "2,1"},
+		{"dir excludes sibling prefix", SearchQuery{Text: "auth", Dirs: []string{"/src/api"}}, "1"},
+		{"several dirs", SearchQuery{Text: "auth", Dirs: []string{"/src/api", "/src/web"}}, "1,3"},
+		{"limit", SearchQuery{Text: "auth", Limit: 1}, "2"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ids(index.Query(tt.q)), ","); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGlobalSearchIndexPersistentTier(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "projects", "-src-api")
+	_ = os.MkdirAll(projectDir, 0755)
+	path := filepath.Join(projectDir, "e5f6a7b8-c9d0-1234-ef56-789012345678.jsonl")
+	first := `{"sessionId":"e5f6a7b8-c9d0-1234-ef56-789012345678","type":"user","message":{"role":"user","content":"fix the authentication bug"},"cwd":"/src/api"}` + "\n"
+	if err := os.WriteFile(path, []byte(first), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := GlobalSearchSettings{
+		Enabled:        true,
+		Tier:           "persistent",
+		IndexRateLimit: 100,
+		IndexPath:      filepath.Join(t.TempDir(), "search.db"),
+		Sources:        []string{"claude"},
+	}
+	open := func() *GlobalSearchIndex {
+		t.Helper()
+		index, err := NewGlobalSearchIndex(tmpDir, config)
+		if err != nil {
+			t.Fatalf("Failed to create index: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if !index.WaitLoaded(ctx) {
+			t.Fatal("index did not finish loading")
+		}
+		return index
+	}
+	index := open()
+	if index.GetTier() != TierPersistent {
+		t.Fatalf("tier = %s", TierName(index.GetTier()))
+	}
+
+	for query, want := range map[string]int{
+		"authentication":       1,
+		`"authentication bug"`: 1,
+		"auth*":                1,
+		"auth NOT bug":         0,
+		"bugs":                 1, // stemmed
+		"auth-bug":             0, // not FTS5 syntax; searched as a phrase
+		"refresh":              0,
+	} {
+		if got := len(index.Search(query)); got != want {
+			t.Errorf("Search(%q) = %d results, want %d", query, got, want)
+		}
+	}
+	results := index.Search("authentication")
+	if len(results) == 1 && (results[0].Entry.CWD != "/src/api" || results[0].Snippet == "") {
+		t.Errorf("result = %+v", results[0].Entry)
+	}
+	if got := len(index.Query(SearchQuery{Text: "authentication", Dirs: []string{"/src/web"}})); got != 0 {
+		t.Errorf("Query in /src/web = %d results, want 0", got)
+	}
+	if got := len(index.Query(SearchQuery{Text: "authentication", Dirs: []string{"/src"}})); got != 1 {
+		t.Errorf("Query in /src = %d results, want 1", got)
+	}
+
+	// Appended lines are indexed from the tracked offset; a half-written
+	// line waits for the next update.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"sessionId":"e5f6a7b8-c9d0-1234-ef56-789012345678","type":"assistant","message":{"role":"assistant","content":"fixed the token refresh"}}` + "\n" + `{"sessionId":"e5f6a7b8`)
+	f.Close()
+	index.updateFile(path)
+	if got := len(index.Search("refresh")); got != 1 {
+		t.Errorf("after append, refresh = %d results", got)
+	}
+	index.Close()
+
+	// A reopened index answers from disk without reloading: it still has the
+	// conversation after the transcript is gone.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	index = open()
+	defer index.Close()
+	if index.EntryCount() != 1 || len(index.Search("refresh")) != 1 {
+		t.Errorf("reopened index: %d entries", index.EntryCount())
+	}
+}
+
+func TestGlobalSearchIndexOtherAgents(t *testing.T) {
+	home := t.TempDir()
+	geminiConfigDirOverride = filepath.Join(home, ".gemini")
+	defer func() { geminiConfigDirOverride = "" }()
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	write := func(path, data string) {
+		t.Helper()
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	geminiProject := t.TempDir()
+	geminiPath := filepath.Join(home, ".gemini", "tmp", HashProjectPath(geminiProject), "chats", "session-2026-10-16T09-00-g1.json")
+	write(geminiPath, `{"sessionId":"g1","messages":[{"type":"user","content":"why is the parser slow"},{"type":"gemini","content":"the tokenizer allocates"}]}`)
+
+	codexPath := filepath.Join(home, ".codex", "sessions", "2026", "10", "16", "rollout-2026-10-16T09-00-00-c0dec0de-0000-4000-8000-000000000001.jsonl")
+	write(codexPath, `{"timestamp":"2026-10-16T09:00:00Z","type":"session_meta","payload":{"id":"c0dec0de-0000-4000-8000-000000000001","cwd":"/src/ci"}}
+{"timestamp":"2026-10-16T09:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"the flaky test again"}]}}
+`)
+
+	storage := filepath.Join(home, "data", "opencode", "storage")
+	write(filepath.Join(storage, "session", "p1", "ses_oc1.json"), `{"id":"ses_oc1","directory":"/src/web","title":"Dark mode toggle"}`)
+	write(filepath.Join(storage, "message", "ses_oc1", "msg_001.json"), `{"id":"msg_001","sessionID":"ses_oc1","role":"user","time":{"created":1760605200000}}`)
+	write(filepath.Join(storage, "part", "msg_001", "prt_001.json"), `{"id":"prt_001","messageID":"msg_001","type":"text","text":"add a dark mode toggle to settings"}`)
+
+	index, err := NewGlobalSearchIndex(t.TempDir(), GlobalSearchSettings{Enabled: true, Tier: "instant", IndexRateLimit: 100})
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer index.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if !index.WaitLoaded(ctx) || index.EntryCount() != 3 {
+		t.Fatalf("EntryCount = %d, want 3", index.EntryCount())
+	}
+
+	one := func(query string) *SearchEntry {
+		t.Helper()
+		results := index.Search(query)
+		if len(results) != 1 {
+			t.Fatalf("Search(%q) = %d results, want 1", query, len(results))
+		}
+		return results[0].Entry
+	}
+	if e := one("tokenizer"); e.Tool != "gemini" || e.SessionID != "g1" || e.Summary != "why is the parser slow" {
+		t.Errorf("gemini entry = %+v", e)
+	}
+	if e := one("flaky"); e.Tool != "codex" || e.SessionID != "c0dec0de-0000-4000-8000-000000000001" || e.CWD != "/src/ci" {
+		t.Errorf("codex entry = %+v", e)
+	}
+	if e := one("settings"); e.Tool != "opencode" || e.SessionID != "ses_oc1" || e.CWD != "/src/web" || e.Summary != "Dark mode toggle" {
+		t.Errorf("opencode entry = %+v", e)
+	}
+	if got := index.Query(SearchQuery{Text: "tokenizer", Dirs: []string{geminiProject}}); len(got) != 1 {
+		t.Errorf("gemini project filter = %d results, want 1", len(got))
+	}
+
+	// Codex lines are appended; Gemini rewrites the whole file.
+	f, _ := os.OpenFile(codexPath, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"timestamp":"2026-10-16T09:00:02Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"retried the pipeline"}]}}` + "\n")
+	f.Close()
+	index.updateFile(codexPath)
+	if e := one("pipeline"); !strings.Contains(e.ContentString(), "flaky") {
+		t.Errorf("appended codex content = %q", e.ContentString())
+	}
+	write(geminiPath, `{"sessionId":"g1","messages":[{"type":"user","content":"why is the lexer slow"}]}`)
+	index.updateFile(geminiPath)
+	if got := len(index.Search("tokenizer")); got != 0 {
+		t.Errorf("rewritten gemini file still matches tokenizer")
+	}
+	one("lexer")
+}
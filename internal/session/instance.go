@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/statedb"
 	"github.com/sjoeboo/hangar/internal/tmux"
 )
 
@@ -110,12 +111,17 @@ type Instance struct {
 	// SessionType distinguishes special session types (e.g., "tower" for tower sessions)
 	SessionType string `json:"session_type,omitempty"`
 
+	// Env holds extra environment variables exported before the tool starts
+	// (e.g. from a session template). They override all configured env.
+	Env map[string]string `json:"env,omitempty"`
+
 	tmuxSession *tmux.Session // Internal tmux session
 
 	// Hook-based status detection (set by StatusFileWatcher from Claude Code hooks)
 	hookStatus     string    // running, idle, waiting, dead (empty = no hook data)
 	hookSessionID  string    // Session ID from hook payload
 	hookLastUpdate time.Time // When hook status was last received
+	statusSource   string    // statedb.StatusSourceHook or StatusSourcePoller: what set Status last
 
 	// mu protects fields written by backgroundStatusUpdate and read by the TUI goroutine.
 	// Use GetStatus()/SetStatus() and GetTool()/SetTool() for thread-safe access.
@@ -1462,6 +1468,13 @@ func (i *Instance) StartWithMessage(message string) error {
 	return nil
 }
 
+// SendMessageWhenReady waits for a freshly (re)started agent to be ready and
+// sends message, so it is not typed into the terminal while the agent is
+// still loading. It blocks until the message is delivered or up to a minute.
+func (i *Instance) SendMessageWhenReady(message string) error {
+	return i.sendMessageWhenReady(message)
+}
+
 // sendMessageWhenReady waits for the agent to be ready and sends the message
 // Uses the existing status detection system which is robust and works for all tools
 //
@@ -1798,6 +1811,7 @@ func hookFastPathFreshnessForTool(tool, hookStatus string) time.Duration {
 func (i *Instance) UpdateStatus() error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
+	i.statusSource = statedb.StatusSourcePoller
 
 	// Short grace period for tmux initialization (not Claude startup)
 	// Use lastStartTime for accuracy on restarts, fallback to CreatedAt
@@ -1858,6 +1872,7 @@ func (i *Instance) UpdateStatus() error {
 	if (i.Tool == "claude" || i.Tool == "codex") &&
 		i.hookStatus != "" &&
 		time.Since(i.hookLastUpdate) < hookFastPathFreshnessForTool(i.Tool, i.hookStatus) {
+		i.statusSource = statedb.StatusSourceHook
 		switch i.hookStatus {
 		case "running":
 			i.Status = StatusRunning
@@ -2152,6 +2167,17 @@ func (i *Instance) UpdateHookStatus(status *HookStatus) {
 	}
 }
 
+// GetStatusSource reports what produced the current status on the last
+// UpdateStatus: statedb.StatusSourceHook or statedb.StatusSourcePoller.
+func (i *Instance) GetStatusSource() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.statusSource == "" {
+		return statedb.StatusSourcePoller
+	}
+	return i.statusSource
+}
+
 // GetHookStatus returns the current hook-based status and its freshness.
 // Freshness window is tool-specific.
 func (i *Instance) GetHookStatus() (string, bool) {
@@ -2853,9 +2879,25 @@ func parseGeminiLatestUserPrompt(data []byte) (string, error) {
 
 // getGeminiLastResponse extracts the last assistant message from Gemini's JSON file
 func (i *Instance) getGeminiLastResponse() (*ResponseOutput, error) {
+	sessionFile, err := i.geminiSessionFile()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read and parse the JSON file
+	data, err := os.ReadFile(sessionFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	return parseGeminiLastAssistantMessage(data)
+}
+
+// geminiSessionFile returns the Gemini session file for the stored session ID.
+func (i *Instance) geminiSessionFile() (string, error) {
 	// Require stored session ID - no fallback to file scanning
 	if i.GeminiSessionID == "" || len(i.GeminiSessionID) < 8 {
-		return nil, fmt.Errorf("no Gemini session ID available for this instance")
+		return "", fmt.Errorf("no Gemini session ID available for this instance")
 	}
 
 	sessionsDir := GetGeminiSessionsDir(i.ProjectPath)
@@ -2873,17 +2915,9 @@ func (i *Instance) getGeminiLastResponse() (*ResponseOutput, error) {
 	}
 
 	if len(files) == 0 {
-		return nil, fmt.Errorf("session file not found for ID: %s", i.GeminiSessionID)
-	}
-	sessionFile := files[0]
-
-	// Read and parse the JSON file
-	data, err := os.ReadFile(sessionFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read session file: %w", err)
+		return "", fmt.Errorf("session file not found for ID: %s", i.GeminiSessionID)
 	}
-
-	return parseGeminiLastAssistantMessage(data)
+	return files[0], nil
 }
 
 // parseGeminiLastAssistantMessage parses a Gemini JSON file to extract the last assistant message
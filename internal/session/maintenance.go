@@ -11,10 +11,14 @@ import (
 	"time"
 
 	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/statedb"
 )
 
 var maintLog = logging.ForComponent(logging.CompSession)
 
+// statusHistoryRetention bounds how long status transitions are kept.
+const statusHistoryRetention = 90 * 24 * time.Hour
+
 // MaintenanceResult holds the outcome of a maintenance run.
 type MaintenanceResult struct {
 	PrunedLogs       int
@@ -37,6 +41,7 @@ func RunMaintenance() MaintenanceResult {
 	prunedLogs := pruneGeminiLogs(geminiDir)
 	prunedBackups := cleanupDeckBackups(filepath.Join(deckDir, "profiles"))
 	archivedSessions := archiveBloatedSessions(deckDir)
+	pruneStatusHistory()
 
 	return MaintenanceResult{
 		PrunedLogs:       prunedLogs,
@@ -78,6 +83,23 @@ func StartMaintenanceWorker(ctx context.Context, onComplete func(MaintenanceResu
 	}()
 }
 
+// pruneStatusHistory drops status transitions older than statusHistoryRetention
+// from the global state database (a no-op when none is open).
+func pruneStatusHistory() {
+	db := statedb.GetGlobal()
+	if db == nil {
+		return
+	}
+	n, err := db.PruneStatusHistory(time.Now().Add(-statusHistoryRetention))
+	if err != nil {
+		maintLog.Warn("status_history_prune_failed", slog.String("error", err.Error()))
+		return
+	}
+	if n > 0 {
+		maintLog.Info("status_history_pruned", slog.Int64("rows", n))
+	}
+}
+
 // pruneGeminiLogs deletes .txt files found directly inside ~/.gemini/tmp/*/
 // directories, but NOT inside chats/ subdirectories.
 func pruneGeminiLogs(baseDir string) int {
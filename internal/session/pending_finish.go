@@ -0,0 +1,39 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// SavePendingFinish records that a worktree session should be torn down once
+// its pull request is merged.
+func (s *Storage) SavePendingFinish(row *statedb.PendingFinishRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return fmt.Errorf("storage database not initialized")
+	}
+	return s.db.SavePendingFinish(row)
+}
+
+// LoadPendingFinishes returns all sessions waiting for their PR to merge.
+func (s *Storage) LoadPendingFinishes() ([]*statedb.PendingFinishRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return nil, fmt.Errorf("storage database not initialized")
+	}
+	return s.db.LoadPendingFinishes()
+}
+
+// ClaimPendingFinish removes a session's pending finish, returning false if
+// another process already claimed it.
+func (s *Storage) ClaimPendingFinish(sessionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return false, fmt.Errorf("storage database not initialized")
+	}
+	return s.db.ClaimPendingFinish(sessionID)
+}
@@ -17,6 +17,42 @@ type Project struct {
 	BaseDir    string `toml:"base_dir"`
 	BaseBranch string `toml:"base_branch"`
 	Order      int    `toml:"order,omitempty"`
+
+	// AutoAdvanceTodos controls whether linked todos follow their session's
+	// PR state (opened → in_review, merged → done). Defaults to true.
+	AutoAdvanceTodos *bool `toml:"auto_advance_todos,omitempty"`
+	// ClosedPRTodoStatus is where an in-review todo goes when its PR is closed
+	// without merging: "todo" (default) or "orphaned".
+	ClosedPRTodoStatus string `toml:"closed_pr_todo_status,omitempty"`
+}
+
+// GroupPath returns the group path that sessions in this project are filed under.
+func (p *Project) GroupPath() string {
+	return projectSlug(p.Name)
+}
+
+// AdvancesTodos reports whether todos in this project follow PR state.
+func (p *Project) AdvancesTodos() bool {
+	return p.AutoAdvanceTodos == nil || *p.AutoAdvanceTodos
+}
+
+// ClosedPRStatus returns the todo status for a PR closed without merging.
+func (p *Project) ClosedPRStatus() TodoStatus {
+	if p.ClosedPRTodoStatus == string(TodoStatusOrphaned) {
+		return TodoStatusOrphaned
+	}
+	return TodoStatusTodo
+}
+
+// ProjectForPath returns the project whose base directory is path, or nil.
+func ProjectForPath(projects []*Project, path string) *Project {
+	path = filepath.Clean(path)
+	for _, p := range projects {
+		if filepath.Clean(ExpandPath(p.BaseDir)) == path {
+			return p
+		}
+	}
+	return nil
 }
 
 // projectsFile is the on-disk format for ~/.hangar/projects.toml
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// SaveSessionPR caches the last known pull request of a worktree session.
+func (s *Storage) SaveSessionPR(row *statedb.SessionPRRow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return fmt.Errorf("storage database not initialized")
+	}
+	return s.db.SaveSessionPR(row)
+}
+
+// LoadSessionPRs returns the cached pull requests of all sessions.
+func (s *Storage) LoadSessionPRs() ([]*statedb.SessionPRRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return nil, fmt.Errorf("storage database not initialized")
+	}
+	return s.db.LoadSessionPRs()
+}
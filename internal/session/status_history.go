@@ -0,0 +1,115 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+// StatusTransition is one recorded status change, with the time the session
+// then spent in ToStatus (until the next transition or the end of the window).
+type StatusTransition struct {
+	FromStatus string
+	ToStatus   string
+	Source     string // statedb.StatusSourceHook or statedb.StatusSourcePoller
+	At         time.Time
+	Duration   time.Duration
+}
+
+// StatusTimeline summarizes a session's status history over [Since, Until].
+type StatusTimeline struct {
+	SessionID   string
+	Since       time.Time
+	Until       time.Time
+	Transitions []StatusTransition
+	// Totals is the time spent in each status inside the window.
+	Totals map[string]time.Duration
+}
+
+// LoadStatusTimeline reads status_history for a session and builds its
+// timeline for the window starting at since and ending now.
+func LoadStatusTimeline(db *statedb.StateDB, sessionID string, since time.Time) (*StatusTimeline, error) {
+	rows, err := db.LoadStatusHistory(sessionID, since)
+	if err != nil {
+		return nil, err
+	}
+	return BuildStatusTimeline(sessionID, rows, since, time.Now()), nil
+}
+
+// BuildStatusTimeline computes per-transition durations and per-status totals
+// clipped to [since, until]. rows must be ordered oldest first and may begin
+// with the last transition before since (as LoadStatusHistory returns), which
+// establishes the status at the start of the window.
+func BuildStatusTimeline(sessionID string, rows []*statedb.StatusHistoryRow, since, until time.Time) *StatusTimeline {
+	tl := &StatusTimeline{
+		SessionID: sessionID,
+		Since:     since,
+		Until:     until,
+		Totals:    make(map[string]time.Duration),
+	}
+
+	// Time before the first in-window transition is attributed to its
+	// FromStatus when no earlier transition is known.
+	if len(rows) > 0 && !rows[0].At.Before(since) && rows[0].FromStatus != "" {
+		if d := rows[0].At.Sub(since); d > 0 {
+			tl.Totals[rows[0].FromStatus] += d
+		}
+	}
+
+	for i, r := range rows {
+		end := until
+		if i+1 < len(rows) {
+			end = rows[i+1].At
+		}
+		start := r.At
+		if start.Before(since) {
+			start = since
+		}
+		if d := end.Sub(start); d > 0 {
+			tl.Totals[r.ToStatus] += d
+		}
+		if r.At.Before(since) {
+			continue
+		}
+		tl.Transitions = append(tl.Transitions, StatusTransition{
+			FromStatus: r.FromStatus,
+			ToStatus:   r.ToStatus,
+			Source:     r.Source,
+			At:         r.At,
+			Duration:   end.Sub(r.At),
+		})
+	}
+	return tl
+}
+
+// ParseSince parses a window start for history queries. It accepts a Go
+// duration ("24h", "90m") or a day count ("7d") meaning that long before now,
+// "today" for local midnight, a date ("2026-03-01") or an RFC 3339 timestamp.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return time.Time{}, fmt.Errorf("empty time")
+	case strings.EqualFold(s, "today"):
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a duration like 24h or 7d, \"today\", YYYY-MM-DD or RFC 3339)", s)
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sjoeboo/hangar/internal/statedb"
+)
+
+func TestBuildStatusTimeline(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	since := base
+	until := base.Add(60 * time.Minute)
+
+	rows := []*statedb.StatusHistoryRow{
+		// Before the window: establishes "waiting" at the window start.
+		{FromStatus: "running", ToStatus: "waiting", Source: statedb.StatusSourceHook, At: base.Add(-30 * time.Minute)},
+		{FromStatus: "waiting", ToStatus: "running", Source: statedb.StatusSourceHook, At: base.Add(10 * time.Minute)},
+		{FromStatus: "running", ToStatus: "waiting", Source: statedb.StatusSourcePoller, At: base.Add(25 * time.Minute)},
+	}
+
+	tl := BuildStatusTimeline("s1", rows, since, until)
+
+	if len(tl.Transitions) != 2 {
+		t.Fatalf("expected 2 in-window transitions, got %d", len(tl.Transitions))
+	}
+	if tl.Transitions[0].Duration != 15*time.Minute {
+		t.Errorf("running duration = %v, want 15m", tl.Transitions[0].Duration)
+	}
+	if tl.Transitions[1].Duration != 35*time.Minute || tl.Transitions[1].Source != statedb.StatusSourcePoller {
+		t.Errorf("unexpected last transition: %+v", tl.Transitions[1])
+	}
+	if got := tl.Totals["waiting"]; got != 45*time.Minute {
+		t.Errorf("waiting total = %v, want 45m", got)
+	}
+	if got := tl.Totals["running"]; got != 15*time.Minute {
+		t.Errorf("running total = %v, want 15m", got)
+	}
+}
+
+func TestBuildStatusTimeline_NoPriorTransition(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rows := []*statedb.StatusHistoryRow{
+		{FromStatus: "idle", ToStatus: "running", At: base.Add(20 * time.Minute)},
+	}
+	tl := BuildStatusTimeline("s1", rows, base, base.Add(30*time.Minute))
+	if tl.Totals["idle"] != 20*time.Minute || tl.Totals["running"] != 10*time.Minute {
+		t.Errorf("unexpected totals: %v", tl.Totals)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 1, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"today", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-02-20", time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)},
+		{"2026-02-20T10:00:00Z", time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseSince(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseSince("yesterday-ish", now); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
@@ -84,6 +84,9 @@ type InstanceData struct {
 
 	// SessionType distinguishes special session types (e.g., "tower")
 	SessionType string `json:"session_type,omitempty"`
+
+	// Env is exported before the tool starts
+	Env map[string]string `json:"env,omitempty"`
 }
 
 // GroupData represents serializable group data
@@ -260,7 +263,7 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 			inst.OpenCodeSessionID, inst.OpenCodeDetectedAt,
 			inst.CodexSessionID, inst.CodexDetectedAt,
 			inst.LatestPrompt, inst.LoadedMCPNames,
-			inst.ToolOptionsJSON,
+			inst.ToolOptionsJSON, inst.Env,
 		)
 
 		rows[i] = &statedb.InstanceRow{
@@ -401,7 +404,7 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			opencodeSID, opencodeAt,
 			codexSID, codexAt,
 			latestPrompt, loadedMCPs,
-			toolOpts := statedb.UnmarshalToolData(r.ToolData)
+			toolOpts, env := statedb.UnmarshalToolData(r.ToolData)
 
 		instances[i] = &InstanceData{
 			ID:                 r.ID,
@@ -434,6 +437,7 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			ToolOptionsJSON:    toolOpts,
 			LoadedMCPNames:     loadedMCPs,
 			SessionType:        r.SessionType,
+			Env:                env,
 		}
 	}
 
@@ -484,7 +488,7 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			opencodeSID, opencodeAt,
 			codexSID, codexAt,
 			latestPrompt, loadedMCPs,
-			toolOpts := statedb.UnmarshalToolData(r.ToolData)
+			toolOpts, env := statedb.UnmarshalToolData(r.ToolData)
 
 		data.Instances[i] = &InstanceData{
 			ID:                 r.ID,
@@ -517,6 +521,7 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			ToolOptionsJSON:    toolOpts,
 			LoadedMCPNames:     loadedMCPs,
 			SessionType:        r.SessionType,
+			Env:                env,
 		}
 	}
 
@@ -651,6 +656,7 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			LatestPrompt:       instData.LatestPrompt,
 			LoadedMCPNames:     instData.LoadedMCPNames,
 			SessionType:        instData.SessionType,
+			Env:                instData.Env,
 			tmuxSession:        tmuxSess,
 		}
 
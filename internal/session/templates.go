@@ -0,0 +1,180 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SessionTemplate is a named, reusable session setup from a
+// [templates.<name>] section of config.toml.
+type SessionTemplate struct {
+	// Description is optional help text shown when picking a template
+	Description string `toml:"description"`
+
+	// Tool is the tool to run: "claude", "gemini", "opencode", "codex",
+	// a custom tool name from [tools], or a shell command. Empty = shell.
+	Tool string `toml:"tool"`
+
+	// Wrapper is an optional wrapper command with a {command} placeholder
+	Wrapper string `toml:"wrapper"`
+
+	// Group is the group path new sessions are placed in
+	Group string `toml:"group"`
+
+	// Worktree creates the session in a new git worktree when true
+	// nil = leave the choice to the caller
+	Worktree *bool `toml:"worktree"`
+
+	// MCPs are names from [mcps] written to the project's .mcp.json
+	MCPs []string `toml:"mcps"`
+
+	// Env is exported in the session before the tool starts, after any
+	// configured env files and [tools.X].env
+	// Example: env = { RUST_LOG = "debug" }
+	Env map[string]string `toml:"env"`
+
+	// Prompt is sent to the session once it starts. It may reference
+	// {title}, {branch}, {path}, {group}, {todo.title}, {todo.description}
+	// and {todo.prompt}; variables without a value render empty.
+	Prompt string `toml:"prompt"`
+
+	// Claude overrides Claude launch options for claude sessions
+	Claude TemplateClaudeOptions `toml:"claude"`
+}
+
+// TemplateClaudeOptions are the Claude launch options a template can set.
+// Unset pointer fields keep the [claude] defaults.
+type TemplateClaudeOptions struct {
+	SkipPermissions      *bool `toml:"skip_permissions"`
+	AllowSkipPermissions *bool `toml:"allow_skip_permissions"`
+	UseChrome            bool  `toml:"chrome"`
+	UseTeammateMode      bool  `toml:"teammate_mode"`
+}
+
+// TemplateVars are the values substituted into a template prompt.
+type TemplateVars struct {
+	Title  string
+	Branch string
+	Path   string
+	Group  string
+	Todo   *Todo // optional
+}
+
+// GetSessionTemplate returns the named template from config.toml.
+func GetSessionTemplate(name string) (*SessionTemplate, error) {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		if t, ok := config.Templates[name]; ok {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("template '%s' not found in config.toml", name)
+}
+
+// GetSessionTemplateNames returns the names of all configured templates, sorted.
+func GetSessionTemplateNames() []string {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return nil
+	}
+	names := make([]string, 0, len(config.Templates))
+	for name := range config.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsesWorktree reports whether the template asks for a worktree session.
+func (t *SessionTemplate) UsesWorktree() bool {
+	return t.Worktree != nil && *t.Worktree
+}
+
+// ApplyClaudeOptions overlays the template's Claude settings onto opts.
+func (t *SessionTemplate) ApplyClaudeOptions(opts *ClaudeOptions) {
+	if t.Claude.SkipPermissions != nil {
+		opts.SkipPermissions = *t.Claude.SkipPermissions
+	}
+	if t.Claude.AllowSkipPermissions != nil {
+		opts.AllowSkipPermissions = *t.Claude.AllowSkipPermissions
+	}
+	if t.Claude.UseChrome {
+		opts.UseChrome = true
+	}
+	if t.Claude.UseTeammateMode {
+		opts.UseTeammateMode = true
+	}
+}
+
+// ApplyToInstance sets the template's wrapper, env and Claude options on a
+// new, not yet started instance and writes its MCPs to the project's
+// .mcp.json. Tool and group are left to the caller, which resolves them
+// alongside its own flags.
+func (t *SessionTemplate) ApplyToInstance(inst *Instance) error {
+	if t.Wrapper != "" && inst.Wrapper == "" {
+		inst.Wrapper = t.Wrapper
+	}
+	for k, v := range t.Env {
+		if _, ok := inst.Env[k]; ok {
+			continue
+		}
+		if inst.Env == nil {
+			inst.Env = make(map[string]string, len(t.Env))
+		}
+		inst.Env[k] = v
+	}
+	if inst.Tool == "claude" {
+		opts := inst.GetClaudeOptions()
+		if opts == nil {
+			config, _ := LoadUserConfig()
+			opts = NewClaudeOptions(config)
+		}
+		t.ApplyClaudeOptions(opts)
+		if err := inst.SetClaudeOptions(opts); err != nil {
+			return err
+		}
+	}
+	if len(t.MCPs) == 0 {
+		return nil
+	}
+	if err := t.ValidateMCPs(); err != nil {
+		return err
+	}
+	return WriteMCPJsonFromConfig(inst.ProjectPath, t.MCPs)
+}
+
+// ValidateMCPs checks that every MCP the template names is defined in config.toml.
+func (t *SessionTemplate) ValidateMCPs() error {
+	available := GetAvailableMCPs()
+	for _, name := range t.MCPs {
+		if _, ok := available[name]; !ok {
+			return fmt.Errorf("MCP '%s' not found in config.toml", name)
+		}
+	}
+	return nil
+}
+
+// RenderPrompt returns the template prompt with vars substituted.
+func (t *SessionTemplate) RenderPrompt(vars TemplateVars) string {
+	if t.Prompt == "" {
+		return ""
+	}
+	var todoTitle, todoDesc, todoPrompt string
+	if vars.Todo != nil {
+		todoTitle, todoDesc, todoPrompt = vars.Todo.Title, vars.Todo.Description, vars.Todo.Prompt
+	}
+	r := strings.NewReplacer(
+		"{title}", vars.Title,
+		"{branch}", vars.Branch,
+		"{path}", vars.Path,
+		"{group}", vars.Group,
+		"{todo.title}", todoTitle,
+		"{todo.description}", todoDesc,
+		"{todo.prompt}", todoPrompt,
+	)
+	return strings.TrimSpace(r.Replace(t.Prompt))
+}
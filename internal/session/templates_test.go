@@ -0,0 +1,107 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetSessionTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	hangarDir := filepath.Join(tempDir, ".hangar")
+	_ = os.MkdirAll(hangarDir, 0700)
+	configContent := `
+[templates.bugfix]
+tool = "claude"
+worktree = true
+group = "bugs"
+mcps = ["github"]
+prompt = "Fix {todo.title} on {branch}"
+
+[templates.bugfix.claude]
+skip_permissions = false
+chrome = true
+
+[templates.review]
+tool = "codex"
+env = { RUST_LOG = "debug", REVIEWER = "it's me" }
+`
+	if err := os.WriteFile(filepath.Join(hangarDir, "config.toml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := GetSessionTemplateNames(); len(got) != 2 || got[0] != "bugfix" || got[1] != "review" {
+		t.Errorf("GetSessionTemplateNames() = %v, want [bugfix review]", got)
+	}
+
+	tmpl, err := GetSessionTemplate("bugfix")
+	if err != nil {
+		t.Fatalf("GetSessionTemplate: %v", err)
+	}
+	if tmpl.Tool != "claude" || tmpl.Group != "bugs" || !tmpl.UsesWorktree() {
+		t.Errorf("unexpected template: %+v", tmpl)
+	}
+	if len(tmpl.MCPs) != 1 || tmpl.MCPs[0] != "github" {
+		t.Errorf("MCPs = %v, want [github]", tmpl.MCPs)
+	}
+	if err := tmpl.ValidateMCPs(); err == nil {
+		t.Error("ValidateMCPs should fail for an MCP missing from [mcps]")
+	}
+
+	opts := &ClaudeOptions{SkipPermissions: true, AllowSkipPermissions: true}
+	tmpl.ApplyClaudeOptions(opts)
+	if opts.SkipPermissions || !opts.AllowSkipPermissions || !opts.UseChrome {
+		t.Errorf("ApplyClaudeOptions = %+v", opts)
+	}
+
+	review, err := GetSessionTemplate("review")
+	if err != nil {
+		t.Fatalf("GetSessionTemplate(review): %v", err)
+	}
+	inst := &Instance{Tool: "codex", ProjectPath: tempDir, Env: map[string]string{"RUST_LOG": "info"}}
+	if err := review.ApplyToInstance(inst); err != nil {
+		t.Fatalf("ApplyToInstance: %v", err)
+	}
+	// Env already set on the instance wins over the template's.
+	if inst.Env["RUST_LOG"] != "info" || inst.Env["REVIEWER"] != "it's me" {
+		t.Errorf("Env = %v", inst.Env)
+	}
+	wantEnv := `export REVIEWER='it'\''s me' && export RUST_LOG='info'`
+	if got := inst.buildEnvSourceCommand(); !strings.Contains(got, wantEnv) {
+		t.Errorf("buildEnvSourceCommand() = %q, want it to contain %q", got, wantEnv)
+	}
+
+	if _, err := GetSessionTemplate("missing"); err == nil {
+		t.Error("GetSessionTemplate(missing) should fail")
+	}
+}
+
+func TestSessionTemplate_RenderPrompt(t *testing.T) {
+	tmpl := &SessionTemplate{Prompt: "Work on {todo.title} ({branch}) in {path}.\n\n{todo.description}"}
+
+	got := tmpl.RenderPrompt(TemplateVars{
+		Branch: "fix/login",
+		Path:   "/repo",
+		Todo:   &Todo{Title: "Login fails", Description: "500 on submit"},
+	})
+	want := "Work on Login fails (fix/login) in /repo.\n\n500 on submit"
+	if got != want {
+		t.Errorf("RenderPrompt = %q, want %q", got, want)
+	}
+
+	// Unset variables render empty.
+	if got := tmpl.RenderPrompt(TemplateVars{Branch: "b"}); got != "Work on  (b) in ." {
+		t.Errorf("RenderPrompt without todo = %q", got)
+	}
+
+	if got := (&SessionTemplate{}).RenderPrompt(TemplateVars{Title: "x"}); got != "" {
+		t.Errorf("empty prompt rendered %q", got)
+	}
+}
@@ -28,6 +28,8 @@ type Todo struct {
 	Status      TodoStatus
 	SessionID   string // empty = unlinked
 	Order       int
+	BlockedBy   []string // IDs of todos that must be done before this one starts
+	AutoStart   bool     // start a worktree session once all blockers are done
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -62,6 +64,8 @@ func todoFromRow(r *statedb.TodoRow) *Todo {
 		Status:      TodoStatus(r.Status),
 		SessionID:   r.SessionID,
 		Order:       r.Order,
+		BlockedBy:   r.BlockedBy,
+		AutoStart:   r.AutoStart,
 		CreatedAt:   r.CreatedAt,
 		UpdatedAt:   r.UpdatedAt,
 	}
@@ -77,6 +81,8 @@ func todoToRow(t *Todo) *statedb.TodoRow {
 		Status:      string(t.Status),
 		SessionID:   t.SessionID,
 		Order:       t.Order,
+		BlockedBy:   t.BlockedBy,
+		AutoStart:   t.AutoStart,
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
 	}
@@ -121,14 +127,21 @@ func (s *Storage) LoadAllTodos() ([]*Todo, error) {
 }
 
 // SaveTodo inserts or updates a todo. Updates todo.UpdatedAt to the current time before saving.
+// Saving a todo as done runs the RegisterOnTodoDone callbacks.
 func (s *Storage) SaveTodo(todo *Todo) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if s.db == nil {
+		s.mu.Unlock()
 		return fmt.Errorf("storage database not initialized")
 	}
+	prev, _ := s.db.FindTodoByID(todo.ID)
 	todo.UpdatedAt = time.Now()
-	return s.db.SaveTodo(todoToRow(todo))
+	err := s.db.SaveTodo(todoToRow(todo))
+	s.mu.Unlock()
+	if err == nil && todo.Status == TodoStatusDone && (prev == nil || prev.Status != string(TodoStatusDone)) {
+		notifyTodoDone(todo)
+	}
+	return err
 }
 
 // DeleteTodo removes a todo by ID.
@@ -142,13 +155,34 @@ func (s *Storage) DeleteTodo(id string) error {
 }
 
 // UpdateTodoStatus updates a todo's status and linked session ID.
+// Moving a todo to done runs the RegisterOnTodoDone callbacks.
 func (s *Storage) UpdateTodoStatus(id string, status TodoStatus, sessionID string) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if s.db == nil {
+		s.mu.Unlock()
 		return fmt.Errorf("storage database not initialized")
 	}
-	return s.db.UpdateTodoStatus(id, string(status), sessionID)
+	prev, _ := s.db.FindTodoByID(id)
+	err := s.db.UpdateTodoStatus(id, string(status), sessionID)
+	s.mu.Unlock()
+	if err == nil && status == TodoStatusDone && prev != nil && prev.Status != string(TodoStatusDone) {
+		todo := todoFromRow(prev)
+		todo.Status = status
+		todo.SessionID = sessionID
+		notifyTodoDone(todo)
+	}
+	return err
+}
+
+// ClaimTodo atomically moves an unlinked todo to in_progress, returning false
+// if another process already started it.
+func (s *Storage) ClaimTodo(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return false, fmt.Errorf("storage database not initialized")
+	}
+	return s.db.ClaimTodo(id)
 }
 
 // OrphanTodosForSession sets status to "orphaned" for the todo linked to the given session.
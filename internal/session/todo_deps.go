@@ -0,0 +1,265 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sjoeboo/hangar/internal/git"
+)
+
+var (
+	todoDoneMu    sync.Mutex
+	todoDoneHooks []func(*Todo)
+)
+
+// RegisterOnTodoDone registers a callback invoked after a todo moves to done
+// via Storage.UpdateTodoStatus or Storage.SaveTodo in this process.
+// Callbacks are called synchronously with no storage lock held.
+func RegisterOnTodoDone(fn func(*Todo)) {
+	todoDoneMu.Lock()
+	defer todoDoneMu.Unlock()
+	todoDoneHooks = append(todoDoneHooks, fn)
+}
+
+// notifyTodoDone invokes all registered RegisterOnTodoDone callbacks.
+func notifyTodoDone(t *Todo) {
+	todoDoneMu.Lock()
+	cbs := make([]func(*Todo), len(todoDoneHooks))
+	copy(cbs, todoDoneHooks)
+	todoDoneMu.Unlock()
+	for _, fn := range cbs {
+		fn(t)
+	}
+}
+
+// OpenBlockers returns the todos in all that t is blocked by and that are not
+// done yet. Blockers that have since been deleted no longer block.
+func (t *Todo) OpenBlockers(all []*Todo) []*Todo {
+	if len(t.BlockedBy) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Todo, len(all))
+	for _, o := range all {
+		byID[o.ID] = o
+	}
+	var open []*Todo
+	for _, id := range t.BlockedBy {
+		if b, ok := byID[id]; ok && b.Status != TodoStatusDone {
+			open = append(open, b)
+		}
+	}
+	return open
+}
+
+// IsBlocked reports whether any of t's blockers in all is not done yet.
+func (t *Todo) IsBlocked(all []*Todo) bool {
+	return len(t.OpenBlockers(all)) > 0
+}
+
+// ValidateTodoBlockers checks that blockedBy only names other todos in t's
+// project and that making t depend on them would not create a cycle.
+func ValidateTodoBlockers(t *Todo, blockedBy []string, all []*Todo) error {
+	byID := make(map[string]*Todo, len(all))
+	for _, o := range all {
+		byID[o.ID] = o
+	}
+	for _, id := range blockedBy {
+		b, ok := byID[id]
+		switch {
+		case id == t.ID:
+			return fmt.Errorf("a todo cannot block itself")
+		case !ok:
+			return fmt.Errorf("todo %s not found", id)
+		case b.ProjectPath != t.ProjectPath:
+			return fmt.Errorf("todo %q belongs to a different project", b.Title)
+		}
+	}
+
+	// Walk the dependency graph from the new blockers; reaching t means a cycle.
+	seen := map[string]bool{}
+	stack := append([]string(nil), blockedBy...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == t.ID {
+			return fmt.Errorf("dependency cycle: %q would end up blocking itself", t.Title)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if b, ok := byID[id]; ok {
+			stack = append(stack, b.BlockedBy...)
+		}
+	}
+	return nil
+}
+
+// NextUnblockedTodo returns the first auto-start todo in projectPath, in board
+// order, that is still in the todo column without a session and whose
+// blockers are all done. It returns nil if none is ready.
+func NextUnblockedTodo(todos []*Todo, projectPath string) *Todo {
+	for _, t := range todos {
+		if t.ProjectPath != projectPath || !t.AutoStart {
+			continue
+		}
+		if t.Status != TodoStatusTodo || t.SessionID != "" {
+			continue
+		}
+		if !t.IsBlocked(todos) {
+			return t
+		}
+	}
+	return nil
+}
+
+// StartNextTodo starts a worktree session for the next unblocked auto-start
+// todo in done's project (see NextUnblockedTodo). It returns nil, nil when no
+// todo is ready or another process claimed it first.
+func StartNextTodo(profile string, done *Todo) (*Instance, error) {
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	defer storage.Close()
+
+	todos, err := storage.LoadTodos(done.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("load todos: %w", err)
+	}
+	next := NextUnblockedTodo(todos, done.ProjectPath)
+	if next == nil {
+		return nil, nil
+	}
+	if ok, err := storage.ClaimTodo(next.ID); err != nil || !ok {
+		return nil, err
+	}
+	sessionLog.Info("todo_auto_start",
+		slog.String("todo", next.ID),
+		slog.String("after", done.ID))
+	inst, err := storage.StartTodoSession(next, "")
+	if err != nil {
+		// Put the todo back so it can be started by hand.
+		_ = storage.UpdateTodoStatus(next.ID, TodoStatusTodo, "")
+		return nil, err
+	}
+	return inst, nil
+}
+
+// EnableTodoChaining registers a RegisterOnTodoDone callback that starts the
+// next unblocked auto-start todo of the completed todo's project in the
+// background. Long-running processes (the TUI and hangar web) call it once.
+func EnableTodoChaining(profile string) {
+	RegisterOnTodoDone(func(done *Todo) {
+		go func() {
+			inst, err := StartNextTodo(profile, done)
+			if err != nil {
+				sessionLog.Warn("todo_auto_start_failed",
+					slog.String("after", done.ID),
+					slog.String("error", err.Error()))
+				return
+			}
+			if inst != nil {
+				sessionLog.Info("todo_auto_started",
+					slog.String("session", inst.ID),
+					slog.String("title", inst.Title))
+			}
+		}()
+	})
+}
+
+// StartTodoSession creates a worktree session for a todo in its project, the
+// same way the todo board's "create session" action does, links the todo to
+// it and starts it with message (or the todo's Prompt, if message is empty).
+// If the session cannot be saved or started, it is removed along with its
+// worktree (and branch, if created here) and the todo is left unlinked.
+func (s *Storage) StartTodoSession(todo *Todo, message string) (*Instance, error) {
+	groupPath := ""
+	if projects, err := LoadProjects(); err == nil {
+		if p := ProjectForPath(projects, todo.ProjectPath); p != nil {
+			groupPath = p.GroupPath()
+		}
+	}
+	if groupPath == "" {
+		return nil, fmt.Errorf("no project found for %s", todo.ProjectPath)
+	}
+
+	repoRoot, err := git.GetWorktreeBaseRoot(todo.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("repo root: %w", err)
+	}
+	branch := git.SanitizeBranchName(strings.ToLower(todo.Title))
+	wtSettings := GetWorktreeSettings()
+	worktreePath := git.WorktreePath(git.WorktreePathOptions{
+		Branch:    branch,
+		Location:  wtSettings.DefaultLocation,
+		RepoDir:   repoRoot,
+		SessionID: git.GeneratePathID(),
+		Template:  wtSettings.Template(),
+	})
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		return nil, fmt.Errorf("create worktree parent: %w", err)
+	}
+	if wtSettings.AutoUpdateBase {
+		baseBranch, _ := git.GetDefaultBranch(repoRoot)
+		if baseBranch == "" {
+			baseBranch = "main"
+		}
+		if err := git.UpdateBaseBranch(repoRoot, baseBranch); err != nil {
+			sessionLog.Warn("base_branch_update_failed", slog.String("error", err.Error()))
+		}
+	}
+	newBranch := !git.BranchExists(repoRoot, branch)
+	if err := git.CreateWorktree(repoRoot, worktreePath, branch); err != nil {
+		return nil, fmt.Errorf("create worktree: %w", err)
+	}
+	removeWorktree := func() {
+		if err := git.RemoveWorktree(repoRoot, worktreePath, true); err != nil {
+			sessionLog.Warn("todo_worktree_cleanup_failed", slog.String("path", worktreePath), slog.String("error", err.Error()))
+		}
+		if newBranch {
+			_ = git.DeleteBranch(repoRoot, branch, true)
+		}
+	}
+
+	inst := NewInstanceWithGroupAndTool(todo.Title, worktreePath, groupPath, "claude")
+	inst.Command = "claude"
+	inst.WorktreePath = worktreePath
+	inst.WorktreeBranch = branch
+	inst.WorktreeRepoRoot = repoRoot
+
+	// Persist before starting so a running TUI's storage watcher picks it up.
+	existing, err := s.Load()
+	if err != nil {
+		removeWorktree()
+		return nil, fmt.Errorf("load sessions: %w", err)
+	}
+	if err := s.Save(append(existing, inst)); err != nil {
+		removeWorktree()
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	if err := s.UpdateTodoStatus(todo.ID, TodoStatusInProgress, inst.ID); err != nil {
+		sessionLog.Warn("todo_link_failed", slog.String("todo", todo.ID), slog.String("error", err.Error()))
+	}
+
+	prompt := message
+	if prompt == "" {
+		prompt = todo.Prompt
+	}
+	if prompt == "" {
+		err = inst.Start()
+	} else {
+		err = inst.StartWithMessage(prompt)
+	}
+	if err != nil {
+		_ = s.DeleteInstance(inst.ID)
+		_ = s.UpdateTodoStatus(todo.ID, todo.Status, "")
+		removeWorktree()
+		return nil, err
+	}
+	return inst, nil
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestValidateTodoBlockers(t *testing.T) {
+	a := &Todo{ID: "a", ProjectPath: "/p", Title: "a"}
+	b := &Todo{ID: "b", ProjectPath: "/p", Title: "b", BlockedBy: []string{"a"}}
+	c := &Todo{ID: "c", ProjectPath: "/p", Title: "c", BlockedBy: []string{"b"}}
+	other := &Todo{ID: "x", ProjectPath: "/q", Title: "x"}
+	all := []*Todo{a, b, c, other}
+
+	tests := []struct {
+		name      string
+		todo      *Todo
+		blockedBy []string
+		wantErr   bool
+	}{
+		{"none", a, nil, false},
+		{"same project", c, []string{"a", "b"}, false},
+		{"self", a, []string{"a"}, true},
+		{"unknown", a, []string{"nope"}, true},
+		{"other project", a, []string{"x"}, true},
+		{"direct cycle", a, []string{"b"}, true},
+		{"transitive cycle", a, []string{"c"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTodoBlockers(tt.todo, tt.blockedBy, all)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTodoBlockers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextUnblockedTodo(t *testing.T) {
+	a := &Todo{ID: "a", ProjectPath: "/p", Status: TodoStatusInProgress, SessionID: "s1"}
+	b := &Todo{ID: "b", ProjectPath: "/p", Status: TodoStatusTodo, BlockedBy: []string{"a"}, AutoStart: true}
+	c := &Todo{ID: "c", ProjectPath: "/p", Status: TodoStatusTodo, BlockedBy: []string{"b"}, AutoStart: true}
+	manual := &Todo{ID: "m", ProjectPath: "/p", Status: TodoStatusTodo}
+	todos := []*Todo{a, b, c, manual}
+
+	if got := NextUnblockedTodo(todos, "/p"); got != nil {
+		t.Fatalf("expected nothing ready while a is in progress, got %s", got.ID)
+	}
+	if !b.IsBlocked(todos) || len(c.OpenBlockers(todos)) != 1 {
+		t.Error("b and c should be blocked")
+	}
+
+	a.Status = TodoStatusDone
+	if got := NextUnblockedTodo(todos, "/p"); got == nil || got.ID != "b" {
+		t.Fatalf("expected b, got %v", got)
+	}
+	if got := NextUnblockedTodo(todos, "/other"); got != nil {
+		t.Errorf("expected nil for another project, got %s", got.ID)
+	}
+
+	// A deleted blocker no longer blocks.
+	b.Status = TodoStatusInProgress
+	c.BlockedBy = []string{"deleted"}
+	if got := NextUnblockedTodo(todos, "/p"); got == nil || got.ID != "c" {
+		t.Errorf("expected c, got %v", got)
+	}
+}
+
+func TestRegisterOnTodoDone(t *testing.T) {
+	s := newTestStorage(t)
+	todo := NewTodo("ship it", "", "", "/p")
+	if err := s.SaveTodo(todo); err != nil {
+		t.Fatalf("SaveTodo: %v", err)
+	}
+
+	var mu sync.Mutex
+	var calls int
+	RegisterOnTodoDone(func(done *Todo) {
+		if done.ID != todo.ID {
+			return
+		}
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		if done.Status != TodoStatusDone || done.Title != "ship it" {
+			t.Errorf("callback got %+v", done)
+		}
+	})
+
+	if err := s.UpdateTodoStatus(todo.ID, TodoStatusInReview, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateTodoStatus(todo.ID, TodoStatusDone, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	// Already done: no second notification.
+	if err := s.UpdateTodoStatus(todo.ID, TodoStatusDone, "sess-1"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("callback calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,315 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TranscriptSource finds and parses one agent's conversation transcripts
+// for global search.
+type TranscriptSource interface {
+	// Tool is the agent that writes the transcripts: "claude", "gemini",
+	// "codex" or "opencode".
+	Tool() string
+	// Root is the directory the transcripts live under.
+	Root() string
+	// WalkDir reports whether the directory rel (relative to Root, "." for
+	// Root itself) can hold transcripts.
+	WalkDir(rel string) bool
+	// WatchDir reports whether to watch the directory rel for changes.
+	WatchDir(rel string) bool
+	// Match reports whether path is a transcript file.
+	Match(path string) bool
+	// Appendable reports whether transcripts only grow by whole lines, so an
+	// update can parse just the bytes added since the previous one.
+	Appendable() bool
+	// Parse builds a search entry from a transcript, or from the lines
+	// appended to one.
+	Parse(path string, data []byte, includeContent bool) (*SearchEntry, error)
+}
+
+// TranscriptTools lists the agents whose transcripts global search indexes.
+var TranscriptTools = []string{"claude", "gemini", "codex", "opencode"}
+
+// transcriptSources returns the sources for tools (all of TranscriptTools
+// when empty), with Claude's transcripts under claudeDir.
+func transcriptSources(claudeDir string, tools []string) []TranscriptSource {
+	if len(tools) == 0 {
+		tools = TranscriptTools
+	}
+	var sources []TranscriptSource
+	for _, tool := range tools {
+		switch strings.ToLower(strings.TrimSpace(tool)) {
+		case "claude":
+			sources = append(sources, claudeSource{root: filepath.Join(claudeDir, "projects")})
+		case "gemini":
+			sources = append(sources, geminiSource{root: filepath.Join(GetGeminiConfigDir(), "tmp")})
+		case "codex":
+			sources = append(sources, codexSource{root: filepath.Join(getCodexHomeDir(), "sessions")})
+		case "opencode":
+			sources = append(sources, openCodeSource{root: filepath.Join(getOpenCodeDataDir(), "storage", "session")})
+		}
+	}
+	return sources
+}
+
+// relDepth returns how many directories deep rel is ("." is 0).
+func relDepth(rel string) int {
+	if rel == "." || rel == "" {
+		return 0
+	}
+	return strings.Count(filepath.ToSlash(rel), "/") + 1
+}
+
+// claudeSource reads Claude Code's ~/.claude/projects/<project>/<uuid>.jsonl.
+type claudeSource struct{ root string }
+
+func (s claudeSource) Tool() string     { return "claude" }
+func (s claudeSource) Root() string     { return s.root }
+func (s claudeSource) Appendable() bool { return true }
+
+// WalkDir stops at project directories: deeper ones hold only subagent
+// transcripts and tool results, and watching them leaked thousands of file
+// descriptors on macOS.
+func (s claudeSource) WalkDir(rel string) bool  { return relDepth(rel) <= 1 }
+func (s claudeSource) WatchDir(rel string) bool { return relDepth(rel) <= 1 }
+
+// Match skips agent-*.jsonl subagent files.
+func (s claudeSource) Match(path string) bool { return isUUIDFileName(filepath.Base(path)) }
+
+func (s claudeSource) Parse(path string, data []byte, includeContent bool) (*SearchEntry, error) {
+	entry, err := parseClaudeJSONL(path, data, includeContent)
+	if entry != nil {
+		entry.Tool = "claude"
+	}
+	return entry, err
+}
+
+// geminiSource reads Gemini CLI's ~/.gemini/tmp/<project hash>/chats/session-*.json.
+// The files do not record the project directory, only its hash.
+type geminiSource struct{ root string }
+
+func (s geminiSource) Tool() string     { return "gemini" }
+func (s geminiSource) Root() string     { return s.root }
+func (s geminiSource) Appendable() bool { return false }
+
+func (s geminiSource) WalkDir(rel string) bool {
+	depth := relDepth(rel)
+	return depth <= 1 || (depth == 2 && filepath.Base(rel) == "chats")
+}
+
+func (s geminiSource) WatchDir(rel string) bool { return s.WalkDir(rel) }
+
+func (s geminiSource) Match(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "session-") && strings.HasSuffix(name, ".json") && filepath.Base(filepath.Dir(path)) == "chats"
+}
+
+func (s geminiSource) Parse(path string, data []byte, includeContent bool) (*SearchEntry, error) {
+	conv, err := parseGeminiConversation(data)
+	if err != nil {
+		return nil, err
+	}
+	entry := conversationSearchEntry(path, conv, includeContent)
+	entry.SessionID = conv.ConversationID
+	return entry, nil
+}
+
+// geminiProjectHash returns the project hash directory a Gemini session
+// file is stored under.
+func geminiProjectHash(path string) string {
+	return filepath.Base(filepath.Dir(filepath.Dir(path)))
+}
+
+// codexWatchDays is how many of the most recent day directories of Codex
+// sessions are watched. Rollouts are filed under the day they started, so
+// older directories rarely change; the catch-up scan at startup finds
+// anything missed.
+const codexWatchDays = 7
+
+// codexSessionIDPattern finds the session UUID in a rollout file name.
+var codexSessionIDPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// codexSource reads Codex's ~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl.
+type codexSource struct{ root string }
+
+func (s codexSource) Tool() string            { return "codex" }
+func (s codexSource) Root() string            { return s.root }
+func (s codexSource) Appendable() bool        { return true }
+func (s codexSource) WalkDir(rel string) bool { return true }
+
+func (s codexSource) WatchDir(rel string) bool {
+	if relDepth(rel) < 3 {
+		return true
+	}
+	day, err := time.ParseInLocation("2006/01/02", filepath.ToSlash(rel), time.Local)
+	return err == nil && time.Since(day) < codexWatchDays*24*time.Hour
+}
+
+func (s codexSource) Match(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "rollout-") && strings.HasSuffix(name, ".jsonl")
+}
+
+// Parse takes the session ID from the file name, since appended lines do
+// not repeat the session_meta record that carries it and the directory.
+func (s codexSource) Parse(path string, data []byte, includeContent bool) (*SearchEntry, error) {
+	entry := conversationSearchEntry(path, parseCodexConversation(data), includeContent)
+	entry.SessionID = codexSessionIDPattern.FindString(filepath.Base(path))
+	scanJSONLines(data, func(line []byte) {
+		if entry.CWD != "" || !bytes.Contains(line, []byte(`"session_meta"`)) {
+			return
+		}
+		var rec struct {
+			Type    string `json:"type"`
+			Payload struct {
+				CWD string `json:"cwd"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(line, &rec) == nil && rec.Type == "session_meta" {
+			entry.CWD = rec.Payload.CWD
+		}
+	})
+	return entry, nil
+}
+
+// getOpenCodeDataDir returns OpenCode's data directory, which follows the
+// XDG base directory spec on every platform.
+func getOpenCodeDataDir() string {
+	if dataHome := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); dataHome != "" {
+		return filepath.Join(dataHome, "opencode")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "opencode")
+	}
+	return filepath.Join(home, ".local", "share", "opencode")
+}
+
+// openCodeSource reads OpenCode's file storage. A session is
+// storage/session/<project>/ses_*.json, its messages are
+// storage/message/<session>/msg_*.json and their parts are
+// storage/part/<message>/prt_*.json. OpenCode rewrites the session file
+// whenever the conversation changes, so only session files are watched.
+type openCodeSource struct{ root string }
+
+func (s openCodeSource) Tool() string             { return "opencode" }
+func (s openCodeSource) Root() string             { return s.root }
+func (s openCodeSource) Appendable() bool         { return false }
+func (s openCodeSource) WalkDir(rel string) bool  { return relDepth(rel) <= 1 }
+func (s openCodeSource) WatchDir(rel string) bool { return relDepth(rel) <= 1 }
+
+func (s openCodeSource) Match(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, "ses_") && strings.HasSuffix(name, ".json")
+}
+
+func (s openCodeSource) Parse(path string, data []byte, includeContent bool) (*SearchEntry, error) {
+	var info struct {
+		ID        string `json:"id"`
+		Directory string `json:"directory"`
+		Title     string `json:"title"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	storage := filepath.Dir(s.root)
+	conv := newConversationBuilder("opencode").conv
+	if includeContent || info.Title == "" {
+		conv = parseOpenCodeConversation(storage, info.ID)
+	}
+	entry := conversationSearchEntry(path, conv, includeContent)
+	entry.SessionID = info.ID
+	entry.CWD = info.Directory
+	if info.Title != "" {
+		entry.Summary = info.Title
+	}
+	return entry, nil
+}
+
+// parseOpenCodeConversation assembles the text of session id's messages
+// from OpenCode's storage directory. Message and part IDs sort in creation
+// order.
+func parseOpenCodeConversation(storage, id string) *Conversation {
+	b := newConversationBuilder("opencode")
+	b.conv.ConversationID = id
+	messages, _ := filepath.Glob(filepath.Join(storage, "message", id, "msg_*.json"))
+	sort.Strings(messages)
+	for _, msgPath := range messages {
+		data, err := os.ReadFile(msgPath)
+		if err != nil {
+			continue
+		}
+		var msg struct {
+			ID   string `json:"id"`
+			Role string `json:"role"`
+			Time struct {
+				Created int64 `json:"created"` // unix milliseconds
+			} `json:"time"`
+		}
+		if json.Unmarshal(data, &msg) != nil || (msg.Role != "user" && msg.Role != "assistant") {
+			continue
+		}
+		parts, _ := filepath.Glob(filepath.Join(storage, "part", msg.ID, "prt_*.json"))
+		sort.Strings(parts)
+		var texts []string
+		for _, partPath := range parts {
+			data, err := os.ReadFile(partPath)
+			if err != nil {
+				continue
+			}
+			var part struct {
+				Type      string `json:"type"`
+				Text      string `json:"text"`
+				Synthetic bool   `json:"synthetic"`
+			}
+			if json.Unmarshal(data, &part) == nil && part.Type == "text" && !part.Synthetic && part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+		}
+		if len(texts) > 0 {
+			b.addTurn(b.conv, ConversationTurn{Role: msg.Role, Timestamp: time.UnixMilli(msg.Time.Created), Text: strings.Join(texts, "\n")})
+		}
+	}
+	return b.conv
+}
+
+// conversationSearchEntry indexes conv's user and assistant text as the
+// "User: ..." and "Assistant: ..." lines Claude transcripts are indexed as,
+// and takes the first prompt as the summary.
+func conversationSearchEntry(path string, conv *Conversation, includeContent bool) *SearchEntry {
+	entry := &SearchEntry{FilePath: path, Tool: conv.Tool}
+	var content bytes.Buffer
+	for _, turn := range conv.Turns {
+		if turn.Text == "" {
+			continue
+		}
+		if entry.Summary == "" && turn.Role == "user" {
+			entry.Summary = turn.Text
+			if len(entry.Summary) > 200 {
+				entry.Summary = entry.Summary[:200] + "..."
+			}
+		}
+		if !includeContent {
+			continue
+		}
+		switch turn.Role {
+		case "user":
+			content.WriteString("User: ")
+		case "assistant":
+			content.WriteString("Assistant: ")
+		}
+		content.WriteString(turn.Text)
+		content.WriteString("\n")
+	}
+	if content.Len() > 0 {
+		entry.setContent(content.Bytes())
+	}
+	return entry
+}
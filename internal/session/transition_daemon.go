@@ -15,6 +15,9 @@ const (
 	notifyPollMedium = 2 * time.Second
 	notifyPollSlow   = 3 * time.Second
 	hookFreshWindow  = 45 * time.Second
+
+	// notifyIdleGrace is how long Idle must report true before Run returns.
+	notifyIdleGrace = 2 * time.Minute
 )
 
 type hookTransitionCandidate struct {
@@ -31,6 +34,12 @@ type TransitionDaemon struct {
 
 	lastStatus  map[string]map[string]string
 	initialized map[string]bool
+
+	// Idle, if set, is called after each pass; Run returns once it has
+	// reported true for notifyIdleGrace.
+	Idle func() bool
+
+	tuiAlive bool // a profile had a live TUI in the last pass
 }
 
 func NewTransitionDaemon() *TransitionDaemon {
@@ -52,9 +61,11 @@ func (d *TransitionDaemon) Run(ctx context.Context) error {
 		interval = notifyPollSlow
 	}
 
+	var idleSince time.Time
 	for {
 		select {
 		case <-ctx.Done():
+			d.Flush()
 			return nil
 		case <-time.After(interval):
 			interval = d.SyncOnce(ctx)
@@ -62,12 +73,31 @@ func (d *TransitionDaemon) Run(ctx context.Context) error {
 				interval = notifyPollSlow
 			}
 		}
+		if d.Idle == nil {
+			continue
+		}
+		switch {
+		case !d.Idle():
+			idleSince = time.Time{}
+		case idleSince.IsZero():
+			idleSince = time.Now()
+		case time.Since(idleSince) >= notifyIdleGrace:
+			d.Flush()
+			return nil
+		}
 	}
 }
 
+// TUIAlive reports whether the last pass saw a TUI with a fresh heartbeat
+// in any profile.
+func (d *TransitionDaemon) TUIAlive() bool {
+	return d.tuiAlive
+}
+
 // SyncOnce performs one full monitoring pass and returns the recommended delay
 // until the next pass.
 func (d *TransitionDaemon) SyncOnce(_ context.Context) time.Duration {
+	d.tuiAlive = false
 	profiles := profilesForTransitionDaemon()
 	if len(profiles) == 0 {
 		return notifyPollSlow
@@ -80,9 +110,16 @@ func (d *TransitionDaemon) SyncOnce(_ context.Context) time.Duration {
 			nextInterval = interval
 		}
 	}
+	d.notifier.FlushTowerBatches(time.Now(), false)
 	return nextInterval
 }
 
+// Flush delivers any Tower notifications still waiting for their debounce
+// window to close.
+func (d *TransitionDaemon) Flush() {
+	d.notifier.FlushTowerBatches(time.Now(), true)
+}
+
 func profilesForTransitionDaemon() []string {
 	profiles, err := ListProfiles()
 	if err != nil || len(profiles) == 0 {
@@ -122,6 +159,7 @@ func (d *TransitionDaemon) syncProfile(profile string) time.Duration {
 	if db != nil {
 		if count, err := db.AliveInstanceCount(); err == nil && count > 0 {
 			tuiAlive = true
+			d.tuiAlive = true
 		}
 	}
 
@@ -146,7 +184,7 @@ func (d *TransitionDaemon) syncProfile(profile string) time.Duration {
 			status := normalizeStatusString(string(inst.GetStatusThreadSafe()))
 			statuses[inst.ID] = status
 			if db != nil && status != previousStatus {
-				_ = db.WriteStatus(inst.ID, status, inst.Tool)
+				_ = db.WriteStatusWithSource(inst.ID, status, inst.Tool, inst.GetStatusSource())
 			}
 		}
 	}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +15,8 @@ const (
 	transitionDeliveryFallbackSent = "fallback_sent"
 	transitionDeliveryFailed       = "failed"
 	transitionDeliveryDropped      = "dropped_no_target"
+	transitionDeliveryFiltered     = "dropped_filtered"
+	transitionDeliveryQueued       = "queued"
 )
 
 type TransitionNotificationEvent struct {
@@ -27,7 +28,7 @@ type TransitionNotificationEvent struct {
 	Timestamp      time.Time `json:"timestamp"`
 
 	TargetSessionID string `json:"target_session_id,omitempty"`
-	TargetKind      string `json:"target_kind,omitempty"` // parent | conductor
+	TargetKind      string `json:"target_kind,omitempty"` // parent | tower
 	DeliveryResult  string `json:"delivery_result,omitempty"`
 }
 
@@ -41,12 +42,23 @@ type transitionNotifyState struct {
 	Records map[string]transitionNotifyRecord `json:"records"`
 }
 
+// towerBatch collects Tower-bound events for one profile until due.
+type towerBatch struct {
+	towerID string
+	due     time.Time
+	events  []TransitionNotificationEvent
+}
+
 type TransitionNotifier struct {
 	statePath string
 	logPath   string
 
-	mu    sync.Mutex
-	state transitionNotifyState
+	// send delivers a message to a session; overridable in tests.
+	send func(profile, sessionRef, message string) error
+
+	mu      sync.Mutex
+	state   transitionNotifyState
+	pending map[string]*towerBatch // profile -> queued Tower events
 }
 
 func NewTransitionNotifier() *TransitionNotifier {
@@ -56,9 +68,11 @@ func NewTransitionNotifier() *TransitionNotifier {
 	n := &TransitionNotifier{
 		statePath: statePath,
 		logPath:   logPath,
+		send:      SendSessionMessageReliable,
 		state: transitionNotifyState{
 			Records: map[string]transitionNotifyRecord{},
 		},
+		pending: map[string]*towerBatch{},
 	}
 	n.loadState()
 	return n
@@ -116,9 +130,35 @@ func (n *TransitionNotifier) NotifyTransition(event TransitionNotificationEvent)
 	result := n.dispatch(event)
 	n.markNotified(result)
 	n.logEvent(result)
+	notifyTransitionHooks(result)
 	return result
 }
 
+var (
+	transitionHooksMu sync.Mutex
+	transitionHooks   []func(TransitionNotificationEvent)
+)
+
+// RegisterOnTransition registers a callback invoked for every attention
+// transition (running → waiting/error/idle) a TransitionNotifier in this
+// process accepts, after it has been routed to a parent or Tower.
+// Callbacks are called synchronously and must not block.
+func RegisterOnTransition(fn func(TransitionNotificationEvent)) {
+	transitionHooksMu.Lock()
+	defer transitionHooksMu.Unlock()
+	transitionHooks = append(transitionHooks, fn)
+}
+
+func notifyTransitionHooks(event TransitionNotificationEvent) {
+	transitionHooksMu.Lock()
+	cbs := make([]func(TransitionNotificationEvent), len(transitionHooks))
+	copy(cbs, transitionHooks)
+	transitionHooksMu.Unlock()
+	for _, fn := range cbs {
+		fn(event)
+	}
+}
+
 func (n *TransitionNotifier) dispatch(event TransitionNotificationEvent) TransitionNotificationEvent {
 	storage, err := NewStorageWithProfile(event.Profile)
 	if err != nil {
@@ -138,7 +178,8 @@ func (n *TransitionNotifier) dispatch(event TransitionNotificationEvent) Transit
 	}
 
 	child := byID[event.ChildSessionID]
-	if child == nil {
+	if child == nil || child.SessionType == "tower" {
+		// Tower's own transitions are never reported, to avoid notification loops.
 		event.DeliveryResult = transitionDeliveryDropped
 		return event
 	}
@@ -146,7 +187,7 @@ func (n *TransitionNotifier) dispatch(event TransitionNotificationEvent) Transit
 	parentID := strings.TrimSpace(child.ParentSessionID)
 	if parentID != "" && parentID != child.ID {
 		if parent := byID[parentID]; parent != nil {
-			if err := SendSessionMessageReliable(event.Profile, parent.ID, buildTransitionMessage(event)); err == nil {
+			if err := n.send(event.Profile, parent.ID, buildTransitionMessage(event)); err == nil {
 				event.TargetSessionID = parent.ID
 				event.TargetKind = "parent"
 				event.DeliveryResult = transitionDeliverySent
@@ -155,21 +196,29 @@ func (n *TransitionNotifier) dispatch(event TransitionNotificationEvent) Transit
 		}
 	}
 
-	conductor := selectFallbackConductor(event.Profile, instances)
-	if conductor == nil || conductor.ID == child.ID {
+	tower := selectLiveTower(instances)
+	if tower == nil {
 		event.DeliveryResult = transitionDeliveryDropped
 		return event
 	}
+	settings := GetTowerSettings().Notifications
+	if !towerNotificationMatches(&settings, child, event.ToStatus) {
+		event.DeliveryResult = transitionDeliveryFiltered
+		return event
+	}
+	event.TargetSessionID = tower.ID
+	event.TargetKind = "tower"
 
-	if err := SendSessionMessageReliable(event.Profile, conductor.ID, buildTransitionMessage(event)); err != nil {
-		event.TargetSessionID = conductor.ID
-		event.TargetKind = "conductor"
-		event.DeliveryResult = transitionDeliveryFailed
+	if window := settings.GetDebounce(); window > 0 {
+		n.queueForTower(event, window)
+		event.DeliveryResult = transitionDeliveryQueued
 		return event
 	}
 
-	event.TargetSessionID = conductor.ID
-	event.TargetKind = "conductor"
+	if err := n.send(event.Profile, tower.ID, buildTransitionMessage(event)); err != nil {
+		event.DeliveryResult = transitionDeliveryFailed
+		return event
+	}
 	if parentID != "" {
 		event.DeliveryResult = transitionDeliveryFallbackSent
 	} else {
@@ -189,48 +238,128 @@ func buildTransitionMessage(event TransitionNotificationEvent) string {
 	)
 }
 
-func selectFallbackConductor(profile string, instances []*Instance) *Instance {
-	metas, err := ListConductorsForProfile(profile)
-	if err != nil || len(metas) == 0 {
-		return nil
+// buildTowerBatchMessage combines several events for one profile into a
+// single Tower message.
+func buildTowerBatchMessage(events []TransitionNotificationEvent) string {
+	if len(events) == 1 {
+		return buildTransitionMessage(events[0])
 	}
-
-	sort.Slice(metas, func(i, j int) bool {
-		return strings.ToLower(metas[i].Name) < strings.ToLower(metas[j].Name)
-	})
-
-	byTitle := make(map[string]*Instance, len(instances))
-	for _, inst := range instances {
-		byTitle[inst.Title] = inst
+	var b strings.Builder
+	fmt.Fprintf(&b, "[EVENT] %d sessions need attention:\n", len(events))
+	for _, e := range events {
+		fmt.Fprintf(&b, "- '%s' (%s) is %s\n", e.ChildTitle, e.ChildSessionID, e.ToStatus)
 	}
+	fmt.Fprintf(&b, "Check: hangar -p %s session output <id> -q", events[0].Profile)
+	return b.String()
+}
 
-	hb := make([]*Instance, 0)
-	other := make([]*Instance, 0)
-
-	for _, meta := range metas {
-		title := ConductorSessionTitle(meta.Name)
-		inst := byTitle[title]
-		if inst == nil {
+// selectLiveTower returns the profile's Tower session if it is running.
+func selectLiveTower(instances []*Instance) *Instance {
+	for _, inst := range instances {
+		if inst.SessionType != "tower" {
 			continue
 		}
 		_ = inst.UpdateStatus()
-		if !isLiveSessionStatus(inst.Status) {
-			continue
+		if isLiveSessionStatus(inst.Status) {
+			return inst
 		}
-		if meta.HeartbeatEnabled {
-			hb = append(hb, inst)
-		} else {
-			other = append(other, inst)
+	}
+	return nil
+}
+
+// towerNotificationMatches applies the [tower.notifications] filters to an
+// event for inst. Project filters load projects.toml only when set.
+func towerNotificationMatches(settings *TowerNotificationSettings, inst *Instance, toStatus string) bool {
+	if !settings.GetEnabled() {
+		return false
+	}
+	if len(settings.Statuses) > 0 && !containsFold(settings.Statuses, toStatus) {
+		return false
+	}
+	if len(settings.Groups) > 0 {
+		matched := false
+		for _, g := range settings.Groups {
+			if inGroup(inst, g) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(settings.Projects) > 0 {
+		projects, err := LoadProjects()
+		if err != nil {
+			return false
+		}
+		matched := false
+		for _, p := range projects {
+			if containsFold(settings.Projects, p.Name) && inProject(inst, p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
 		}
 	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// queueForTower adds event to its profile's pending Tower batch, starting a
+// new batch window if none is open.
+func (n *TransitionNotifier) queueForTower(event TransitionNotificationEvent, window time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.pending == nil {
+		n.pending = map[string]*towerBatch{}
+	}
+	b := n.pending[event.Profile]
+	if b == nil {
+		b = &towerBatch{due: time.Now().Add(window)}
+		n.pending[event.Profile] = b
+	}
+	b.towerID = event.TargetSessionID
+	b.events = append(b.events, event)
+}
 
-	if len(hb) > 0 {
-		return hb[0]
+// FlushTowerBatches delivers every queued Tower batch whose window has
+// elapsed by now, or all of them when force is set.
+func (n *TransitionNotifier) FlushTowerBatches(now time.Time, force bool) {
+	n.mu.Lock()
+	var due map[string]*towerBatch
+	for profile, b := range n.pending {
+		if force || !now.Before(b.due) {
+			if due == nil {
+				due = map[string]*towerBatch{}
+			}
+			due[profile] = b
+			delete(n.pending, profile)
+		}
 	}
-	if len(other) > 0 {
-		return other[0]
+	n.mu.Unlock()
+
+	for profile, b := range due {
+		result := transitionDeliverySent
+		if err := n.send(profile, b.towerID, buildTowerBatchMessage(b.events)); err != nil {
+			result = transitionDeliveryFailed
+		}
+		for _, e := range b.events {
+			e.DeliveryResult = result
+			n.logEvent(e)
+		}
 	}
-	return nil
 }
 
 func isLiveSessionStatus(status Status) bool {
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -124,6 +126,20 @@ func TestIsCodexTerminalHookEvent(t *testing.T) {
 	}
 }
 
+func TestNotifyTransition_DropsConductorSessions(t *testing.T) {
+	n := NewTransitionNotifier()
+	result := n.NotifyTransition(TransitionNotificationEvent{
+		Profile:        "nonexistent-profile-for-test",
+		ChildSessionID: "c1",
+		ChildTitle:     "Conductor-main",
+		FromStatus:     "running",
+		ToStatus:       "waiting",
+	})
+	if result.DeliveryResult != transitionDeliveryDropped {
+		t.Fatalf("DeliveryResult = %q, want dropped", result.DeliveryResult)
+	}
+}
+
 func TestDispatch_StorageIsClosed(t *testing.T) {
 	// Verify dispatch() doesn't panic when storage doesn't exist for the profile
 	// and returns a dropped/failed result cleanly.
@@ -138,3 +154,72 @@ func TestDispatch_StorageIsClosed(t *testing.T) {
 	// Either is acceptable — we just verify no panic
 	_ = result
 }
+
+func TestTowerNotificationMatches(t *testing.T) {
+	inst := &Instance{ID: "a", GroupPath: "projects/api/fixes", ProjectPath: "/code/api"}
+	off := false
+
+	tests := []struct {
+		name     string
+		settings TowerNotificationSettings
+		to       string
+		want     bool
+	}{
+		{name: "defaults", settings: TowerNotificationSettings{}, to: "idle", want: true},
+		{name: "disabled", settings: TowerNotificationSettings{Enabled: &off}, to: "waiting", want: false},
+		{name: "status allowed", settings: TowerNotificationSettings{Statuses: []string{"Waiting", "error"}}, to: "waiting", want: true},
+		{name: "status filtered", settings: TowerNotificationSettings{Statuses: []string{"error"}}, to: "waiting", want: false},
+		{name: "parent group", settings: TowerNotificationSettings{Groups: []string{"other", "projects/api"}}, to: "waiting", want: true},
+		{name: "group filtered", settings: TowerNotificationSettings{Groups: []string{"projects/ap"}}, to: "waiting", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := towerNotificationMatches(&tt.settings, inst, tt.to); got != tt.want {
+				t.Fatalf("towerNotificationMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionNotifier_TowerBatch(t *testing.T) {
+	dir := t.TempDir()
+	var sent []string
+	n := &TransitionNotifier{
+		statePath: filepath.Join(dir, "state.json"),
+		logPath:   filepath.Join(dir, "notify.log"),
+		send: func(profile, ref, msg string) error {
+			sent = append(sent, profile+"|"+ref+"|"+msg)
+			return nil
+		},
+	}
+
+	for _, title := range []string{"api-fix", "web-ui"} {
+		n.queueForTower(TransitionNotificationEvent{
+			ChildSessionID:  title + "-id",
+			ChildTitle:      title,
+			Profile:         "default",
+			ToStatus:        "waiting",
+			TargetSessionID: "tower-id",
+		}, time.Minute)
+	}
+
+	n.FlushTowerBatches(time.Now(), false)
+	if len(sent) != 0 {
+		t.Fatalf("batch flushed before its window closed: %v", sent)
+	}
+
+	n.FlushTowerBatches(time.Now().Add(2*time.Minute), false)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1 batched message", len(sent))
+	}
+	if !strings.HasPrefix(sent[0], "default|tower-id|[EVENT] 2 sessions need attention") ||
+		!strings.Contains(sent[0], "'api-fix' (api-fix-id) is waiting") ||
+		!strings.Contains(sent[0], "'web-ui' (web-ui-id) is waiting") {
+		t.Errorf("unexpected batch message: %q", sent[0])
+	}
+
+	n.FlushTowerBatches(time.Now().Add(time.Hour), true)
+	if len(sent) != 1 {
+		t.Error("batch was delivered twice")
+	}
+}
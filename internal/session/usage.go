@@ -0,0 +1,349 @@
+package session
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Usage grouping dimensions.
+const (
+	UsageByProject = "project"
+	UsageByBranch  = "branch"
+	UsageByDay     = "day"
+	UsageByModel   = "model"
+)
+
+// UsageDimensions lists every grouping dimension in report column order.
+var UsageDimensions = []string{UsageByProject, UsageByBranch, UsageByDay, UsageByModel}
+
+// UsageRecord is the token usage and estimated cost of one group of turns.
+// Dimensions the report is not grouped by are left empty.
+type UsageRecord struct {
+	Project string
+	Branch  string
+	Day     string // YYYY-MM-DD in local time
+	Model   string
+
+	Sessions         int
+	Turns            int
+	InputTokens      int
+	OutputTokens     int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	EstimatedCost    float64
+
+	sessionIDs map[string]bool
+}
+
+// TotalTokens returns the sum of all token types
+func (r *UsageRecord) TotalTokens() int {
+	return r.InputTokens + r.OutputTokens + r.CacheReadTokens + r.CacheWriteTokens
+}
+
+// add folds src's counters into r and tracks the sessions it covers.
+func (r *UsageRecord) add(src *UsageRecord) {
+	r.Turns += src.Turns
+	r.InputTokens += src.InputTokens
+	r.OutputTokens += src.OutputTokens
+	r.CacheReadTokens += src.CacheReadTokens
+	r.CacheWriteTokens += src.CacheWriteTokens
+	r.EstimatedCost += src.EstimatedCost
+	if r.sessionIDs == nil {
+		r.sessionIDs = make(map[string]bool)
+	}
+	for id := range src.sessionIDs {
+		r.sessionIDs[id] = true
+	}
+	r.Sessions = len(r.sessionIDs)
+}
+
+// UsageReport aggregates Claude token usage across sessions.
+type UsageReport struct {
+	Since   time.Time
+	Until   time.Time
+	GroupBy []string
+	Records []UsageRecord // highest estimated cost first
+	Total   UsageRecord
+}
+
+// UsageOptions controls AggregateUsage.
+type UsageOptions struct {
+	Since   time.Time // only turns at or after Since are counted
+	Project string    // project name or group slug; empty means all projects
+	GroupBy []string  // subset of UsageDimensions; empty means all of them
+	// Projects maps session group paths to display names. Sessions outside
+	// any project are reported under their group path or directory name.
+	Projects []*Project
+}
+
+// ParseUsageGroupBy parses a comma-separated list of grouping dimensions
+// ("project,model"). An empty string selects every dimension.
+func ParseUsageGroupBy(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return UsageDimensions, nil
+	}
+	selected := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		valid := false
+		for _, d := range UsageDimensions {
+			if part == d {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid group-by %q (use %s)", part, strings.Join(UsageDimensions, ", "))
+		}
+		selected[part] = true
+	}
+	// Keep canonical column order regardless of input order.
+	var dims []string
+	for _, d := range UsageDimensions {
+		if selected[d] {
+			dims = append(dims, d)
+		}
+	}
+	return dims, nil
+}
+
+// AggregateUsage sums token usage from the Claude JSONL transcripts of the
+// given sessions and groups it by project, worktree branch, day and model.
+// Cost is estimated per model with the same pricing as
+// SessionAnalytics.CalculateCost. Sessions without a transcript are skipped.
+func AggregateUsage(instances []*Instance, opts UsageOptions) *UsageReport {
+	groupBy := opts.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = UsageDimensions
+	}
+	report := &UsageReport{Since: opts.Since, Until: time.Now(), GroupBy: groupBy}
+
+	projectNames := make(map[string]string, len(opts.Projects))
+	for _, p := range opts.Projects {
+		projectNames[projectSlug(p.Name)] = p.Name
+	}
+
+	// Accumulate at full granularity first so cost is always priced per model,
+	// then roll up into the requested dimensions.
+	fine := make(map[usageKey]*UsageRecord)
+	seen := make(map[string]bool)
+	for _, inst := range instances {
+		project := usageProjectName(inst, projectNames)
+		if opts.Project != "" && !usageProjectMatches(inst, project, opts.Project) {
+			continue
+		}
+		path := inst.GetJSONLPath()
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		if info, err := os.Stat(path); err != nil || (!opts.Since.IsZero() && info.ModTime().Before(opts.Since)) {
+			continue
+		}
+
+		err := scanUsageEntries(path, func(e *jsonlEntry) {
+			if !opts.Since.IsZero() && e.Timestamp.Before(opts.Since) {
+				return
+			}
+			u := e.Message.Usage
+			if u.InputTokens+u.OutputTokens+u.CacheReadInputTokens+u.CacheCreationInputTokens == 0 {
+				return
+			}
+			key := usageKey{
+				Project: project,
+				Branch:  inst.WorktreeBranch,
+				Day:     e.Timestamp.Local().Format("2006-01-02"),
+				Model:   e.Message.Model,
+			}
+			rec, ok := fine[key]
+			if !ok {
+				rec = key.record()
+				rec.sessionIDs = make(map[string]bool)
+				fine[key] = rec
+			}
+			rec.sessionIDs[inst.ID] = true
+			rec.Turns++
+			rec.InputTokens += u.InputTokens
+			rec.OutputTokens += u.OutputTokens
+			rec.CacheReadTokens += u.CacheReadInputTokens
+			rec.CacheWriteTokens += u.CacheCreationInputTokens
+		})
+		if err != nil {
+			slog.Debug("usage_scan_failed", slog.String("path", path), slog.String("error", err.Error()))
+		}
+	}
+
+	grouped := make(map[usageKey]*UsageRecord)
+	for _, rec := range fine {
+		a := SessionAnalytics{
+			InputTokens:      rec.InputTokens,
+			OutputTokens:     rec.OutputTokens,
+			CacheReadTokens:  rec.CacheReadTokens,
+			CacheWriteTokens: rec.CacheWriteTokens,
+		}
+		rec.EstimatedCost = a.CalculateCost(rec.Model)
+
+		key := usageGroupKey(rec, groupBy)
+		g, ok := grouped[key]
+		if !ok {
+			g = key.record()
+			grouped[key] = g
+		}
+		g.add(rec)
+		report.Total.add(rec)
+	}
+
+	report.Records = make([]UsageRecord, 0, len(grouped))
+	for _, g := range grouped {
+		report.Records = append(report.Records, *g)
+	}
+	sort.Slice(report.Records, func(i, j int) bool {
+		a, b := report.Records[i], report.Records[j]
+		if a.EstimatedCost != b.EstimatedCost {
+			return a.EstimatedCost > b.EstimatedCost
+		}
+		return usageSortKey(a) < usageSortKey(b)
+	})
+	return report
+}
+
+// WriteCSV writes the report as CSV with one column per grouping dimension
+// followed by the counters.
+func (r *UsageReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{}, r.GroupBy...)
+	header = append(header, "sessions", "turns", "input_tokens", "output_tokens",
+		"cache_read_tokens", "cache_write_tokens", "total_tokens", "estimated_cost_usd")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i := range r.Records {
+		rec := &r.Records[i]
+		row := make([]string, 0, len(header))
+		for _, d := range r.GroupBy {
+			row = append(row, rec.Dimension(d))
+		}
+		row = append(row,
+			strconv.Itoa(rec.Sessions),
+			strconv.Itoa(rec.Turns),
+			strconv.Itoa(rec.InputTokens),
+			strconv.Itoa(rec.OutputTokens),
+			strconv.Itoa(rec.CacheReadTokens),
+			strconv.Itoa(rec.CacheWriteTokens),
+			strconv.Itoa(rec.TotalTokens()),
+			strconv.FormatFloat(rec.EstimatedCost, 'f', 4, 64),
+		)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Dimension returns the record's value for a grouping dimension.
+func (r *UsageRecord) Dimension(dim string) string {
+	switch dim {
+	case UsageByProject:
+		return r.Project
+	case UsageByBranch:
+		return r.Branch
+	case UsageByDay:
+		return r.Day
+	case UsageByModel:
+		return r.Model
+	}
+	return ""
+}
+
+// usageKey identifies one aggregation bucket.
+type usageKey struct {
+	Project, Branch, Day, Model string
+}
+
+func (k usageKey) record() *UsageRecord {
+	return &UsageRecord{Project: k.Project, Branch: k.Branch, Day: k.Day, Model: k.Model}
+}
+
+// usageGroupKey returns the bucket for rec when grouping by groupBy only.
+func usageGroupKey(rec *UsageRecord, groupBy []string) usageKey {
+	var key usageKey
+	for _, d := range groupBy {
+		switch d {
+		case UsageByProject:
+			key.Project = rec.Project
+		case UsageByBranch:
+			key.Branch = rec.Branch
+		case UsageByDay:
+			key.Day = rec.Day
+		case UsageByModel:
+			key.Model = rec.Model
+		}
+	}
+	return key
+}
+
+func usageSortKey(r UsageRecord) string {
+	return r.Project + "\x00" + r.Branch + "\x00" + r.Day + "\x00" + r.Model
+}
+
+// usageProjectName resolves the project a session is reported under: the
+// projects.toml name for its group, else the group path, else the repo
+// (or project) directory name.
+func usageProjectName(inst *Instance, projectNames map[string]string) string {
+	if name, ok := projectNames[inst.GroupPath]; ok {
+		return name
+	}
+	if inst.GroupPath != "" {
+		return inst.GroupPath
+	}
+	if inst.WorktreeRepoRoot != "" {
+		return filepath.Base(inst.WorktreeRepoRoot)
+	}
+	return filepath.Base(inst.ProjectPath)
+}
+
+func usageProjectMatches(inst *Instance, project, filter string) bool {
+	return strings.EqualFold(project, filter) ||
+		strings.EqualFold(inst.GroupPath, filter) ||
+		inst.GroupPath == projectSlug(filter)
+}
+
+// scanUsageEntries calls fn for each assistant entry in a Claude JSONL file.
+func scanUsageEntries(path string, fn func(*jsonlEntry)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Increase buffer for large lines (some tool outputs can be huge)
+	buf := make([]byte, 0, 1024*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+
+	for scanner.Scan() {
+		var entry jsonlEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip malformed lines
+		}
+		if entry.Type != "assistant" {
+			continue
+		}
+		fn(&entry)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeUsageJSONL(t *testing.T, configDir string, inst *Instance, lines ...string) {
+	t.Helper()
+	projectPath, err := filepath.EvalSymlinks(inst.ProjectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(configDir, "projects", ConvertToClaudeDirName(projectPath))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, inst.ClaudeSessionID+".jsonl"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAggregateUsage(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	a := NewInstanceWithGroupAndTool("a", t.TempDir(), "my-app", "claude")
+	a.ClaudeSessionID = "sess-a"
+	a.WorktreeBranch = "feature/x"
+	b := NewInstanceWithGroupAndTool("b", t.TempDir(), "my-app", "claude")
+	b.ClaudeSessionID = "sess-b"
+	shell := NewInstanceWithGroupAndTool("sh", t.TempDir(), "my-app", "shell")
+
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	day2 := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	old := time.Date(2026, 2, 1, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	entry := func(ts, model string, in, out int) string {
+		return `{"type":"assistant","timestamp":"` + ts + `","message":{"model":"` + model +
+			`","usage":{"input_tokens":` + strconv.Itoa(in) + `,"output_tokens":` + strconv.Itoa(out) + `}}}`
+	}
+	writeUsageJSONL(t, configDir, a,
+		entry(old, "claude-sonnet-4-20250514", 999, 999), // before since
+		entry(day1, "claude-sonnet-4-20250514", 1_000_000, 0),
+		`{"type":"user","timestamp":"`+day1+`"}`,
+		entry(day2, "claude-opus-4-20250514", 0, 1_000_000),
+	)
+	writeUsageJSONL(t, configDir, b, entry(day1, "claude-sonnet-4-20250514", 1_000_000, 0))
+
+	projects := []*Project{{Name: "My App"}}
+	since := time.Date(2026, 2, 15, 0, 0, 0, 0, time.Local)
+	instances := []*Instance{a, b, shell}
+
+	report := AggregateUsage(instances, UsageOptions{Since: since, Projects: projects})
+	if len(report.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d: %+v", len(report.Records), report.Records)
+	}
+	top := report.Records[0]
+	if top.Model != "claude-opus-4-20250514" || top.Project != "My App" || top.Branch != "feature/x" || top.Day != "2026-03-02" {
+		t.Errorf("unexpected top record: %+v", top)
+	}
+	if top.EstimatedCost != 75.0 {
+		t.Errorf("opus cost = %v, want 75", top.EstimatedCost)
+	}
+	if report.Total.Sessions != 2 || report.Total.Turns != 3 || report.Total.InputTokens != 2_000_000 {
+		t.Errorf("unexpected total: %+v", report.Total)
+	}
+
+	// Rolling up by project prices each model separately before summing.
+	byProject := AggregateUsage(instances, UsageOptions{Since: since, Projects: projects, GroupBy: []string{UsageByProject}})
+	if len(byProject.Records) != 1 {
+		t.Fatalf("expected 1 project record, got %d", len(byProject.Records))
+	}
+	if got := byProject.Records[0].EstimatedCost; got != 81.0 {
+		t.Errorf("project cost = %v, want 81", got)
+	}
+
+	if r := AggregateUsage(instances, UsageOptions{Since: since, Project: "other"}); len(r.Records) != 0 {
+		t.Errorf("project filter should exclude all sessions, got %d records", len(r.Records))
+	}
+	if r := AggregateUsage(instances, UsageOptions{Since: since, Project: "my app", Projects: projects}); r.Total.Sessions != 2 {
+		t.Errorf("project filter by name should match, got %+v", r.Total)
+	}
+
+	var buf bytes.Buffer
+	if err := byProject.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "project,sessions,") || !strings.HasPrefix(lines[1], "My App,2,3,") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestParseUsageGroupBy(t *testing.T) {
+	dims, err := ParseUsageGroupBy("model, project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(dims, ",") != "project,model" {
+		t.Errorf("dims = %v, want canonical order [project model]", dims)
+	}
+	if dims, _ := ParseUsageGroupBy(""); len(dims) != len(UsageDimensions) {
+		t.Errorf("empty group-by should select all dimensions, got %v", dims)
+	}
+	if _, err := ParseUsageGroupBy("repo"); err == nil {
+		t.Error("expected error for unknown dimension")
+	}
+}
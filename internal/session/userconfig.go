@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
@@ -110,11 +111,27 @@ type UserConfig struct {
 	// Example: "ghe.mycompany.com" = "github"
 	RemoteLabels map[string]string `toml:"remote_labels"`
 
+	// PRProviders maps git remote hostnames to the code host that serves
+	// their PRs: "github" (gh CLI) or "gitlab" (glab CLI). Hosts not listed
+	// use GitHub, except gitlab.com.
+	// Example: "gitlab.mycompany.com" = "gitlab"
+	PRProviders map[string]string `toml:"pr_providers"`
+
 	// Tmux defines tmux option overrides applied to every session
 	Tmux TmuxSettings `toml:"tmux"`
 
 	// API defines settings for the embedded HTTP/WebSocket API server.
 	API APISettings `toml:"api"`
+
+	// Tower defines settings for the Tower control session
+	Tower TowerSettings `toml:"tower"`
+
+	// Templates defines named session setups selectable when creating a session.
+	// Example:
+	// [templates.bugfix]
+	// tool = "claude"
+	// prompt = "Fix: {todo.title}"
+	Templates map[string]SessionTemplate `toml:"templates"`
 }
 
 // ProfileSettings defines per-profile configuration overrides.
@@ -261,6 +278,83 @@ type NotificationsConfig struct {
 	// Minimal shows a compact icon+count summary instead of session names: ● 2 │ ◐ 3 │ ○ 1
 	// When true, key bindings (Ctrl+b 1-6) are disabled. ShowAll is ignored. (default: false)
 	Minimal bool `toml:"minimal"`
+
+	// Sinks are outgoing notification targets ([[notifications.sinks]]) fired on
+	// session status transitions, PR state changes and todo completions
+	Sinks []NotificationSink `toml:"sinks"`
+}
+
+// NotificationSink is an outgoing notification target.
+//
+// Example config:
+//
+//	[[notifications.sinks]]
+//	name = "phone"
+//	type = "ntfy"
+//	url = "https://ntfy.sh/my-hangar-topic"
+//	statuses = ["waiting", "error"]
+type NotificationSink struct {
+	// Name identifies the sink in the delivery log. Default: the type
+	Name string `toml:"name"`
+
+	// Type is "webhook", "ntfy", "gotify" or "command"
+	Type string `toml:"type"`
+
+	// URL is the webhook endpoint, ntfy topic URL or Gotify server URL
+	URL string `toml:"url"`
+
+	// Token is sent as a bearer token (webhook, ntfy) or as the Gotify app token
+	Token string `toml:"token"`
+
+	// Headers are extra HTTP headers for webhook, ntfy and gotify sinks
+	Headers map[string]string `toml:"headers"`
+
+	// Body is a Go text/template for the webhook request body, executed with
+	// the event; {{json .Message}} renders a quoted JSON string.
+	// Default: the event encoded as JSON
+	Body string `toml:"body"`
+
+	// Command is run with "sh -c" and receives the event as JSON on stdin
+	Command string `toml:"command"`
+
+	// Events limits the sink to these event types: "status", "pr", "todo".
+	// Default: all
+	Events []string `toml:"events"`
+
+	// Statuses limits status events to these target statuses ("waiting",
+	// "error", "idle"). Default: all
+	Statuses []string `toml:"statuses"`
+
+	// Retries is how many times a failed delivery is retried, with backoff.
+	// Default: 3
+	Retries *int `toml:"retries"`
+
+	// TimeoutSeconds bounds each delivery attempt. Default: 10
+	TimeoutSeconds int `toml:"timeout_seconds"`
+}
+
+// GetName returns the sink name, defaulting to its type.
+func (n *NotificationSink) GetName() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Type
+}
+
+// GetRetries returns the retry count, defaulting to 3.
+func (n *NotificationSink) GetRetries() int {
+	if n.Retries == nil {
+		return 3
+	}
+	return max(0, *n.Retries)
+}
+
+// GetTimeout returns the per-attempt timeout, defaulting to 10 seconds.
+func (n *NotificationSink) GetTimeout() time.Duration {
+	if n.TimeoutSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(n.TimeoutSeconds) * time.Second
 }
 
 // InstanceSettings configures multiple hangar instance behavior
@@ -463,6 +557,8 @@ func (c *ClaudeSettings) GetHookServerPort() int {
 //	[api]
 //	port = 47437
 //	bind_address = "127.0.0.1"   # localhost only (default: "0.0.0.0" = all interfaces)
+//	require_auth = true          # require API keys from "hangar web token create"
+//	github_webhook_secret = "…"  # enable /api/v1/webhooks/github
 type APISettings struct {
 	// Port is the TCP port for the API server. Default: 47437.
 	// If unset, falls back to Claude.HookServerPort for backward compat.
@@ -472,6 +568,22 @@ type APISettings struct {
 	// Use "127.0.0.1" to restrict to localhost only (more secure on multi-user hosts).
 	// Use "0.0.0.0" to allow access from other devices on the network (e.g. Tailscale).
 	BindAddress *string `toml:"bind_address"`
+
+	// RequireAuth enforces API keys on the REST API, WebSocket and /stream
+	// endpoints and restricts browser access to same-origin pages.
+	// Create keys with: hangar web token create --scope read|control|admin
+	// Default: false (Tailscale trust model).
+	RequireAuth *bool `toml:"require_auth"`
+
+	// AllowedOrigins lists extra browser origins (e.g. a reverse proxy
+	// hostname) allowed to change state through the API. Same-host pages
+	// always are; without require_auth, other origins may only read.
+	AllowedOrigins []string `toml:"allowed_origins"`
+
+	// GitHubWebhookSecret enables POST /api/v1/webhooks/github, which
+	// refreshes PRs as soon as GitHub reports a change. Use the same secret
+	// in the webhook's settings on GitHub. Default: "" (disabled).
+	GitHubWebhookSecret string `toml:"github_webhook_secret"`
 }
 
 // GetPort returns the API server port. Prefers [api] port, falls back to
@@ -494,6 +606,68 @@ func (a *APISettings) GetBindAddress() string {
 	return *a.BindAddress
 }
 
+// GetRequireAuth returns whether API key authentication is enforced, defaulting to false.
+func (a *APISettings) GetRequireAuth() bool {
+	if a.RequireAuth == nil {
+		return false
+	}
+	return *a.RequireAuth
+}
+
+// TowerSettings defines settings for the Tower control session.
+type TowerSettings struct {
+	// Notifications controls which session transitions are routed to Tower
+	Notifications TowerNotificationSettings `toml:"notifications"`
+}
+
+// TowerNotificationSettings filters and batches the running→waiting/error/idle
+// events delivered to Tower by the transition notifier. Events for sessions
+// with a parent go to the parent first; Tower receives the rest.
+//
+// Example config:
+//
+//	[tower.notifications]
+//	statuses = ["waiting", "error"]
+//	groups = ["projects/api"]
+//	debounce_seconds = 30
+type TowerNotificationSettings struct {
+	// Enabled routes transition events to Tower. Default: true
+	Enabled *bool `toml:"enabled"`
+
+	// Statuses limits events to these target statuses ("waiting", "error", "idle").
+	// Default: all three
+	Statuses []string `toml:"statuses"`
+
+	// Projects limits events to sessions of these projects (names from projects.toml).
+	// Default: all projects
+	Projects []string `toml:"projects"`
+
+	// Groups limits events to sessions in these groups, including subgroups.
+	// Default: all groups
+	Groups []string `toml:"groups"`
+
+	// DebounceSeconds collects events for this many seconds after the first
+	// one and delivers them to Tower as a single message.
+	// Default: 0 (deliver each event immediately)
+	DebounceSeconds int `toml:"debounce_seconds"`
+}
+
+// GetEnabled returns whether Tower notifications are enabled, defaulting to true.
+func (t *TowerNotificationSettings) GetEnabled() bool {
+	if t.Enabled == nil {
+		return true
+	}
+	return *t.Enabled
+}
+
+// GetDebounce returns the batching window for Tower notifications.
+func (t *TowerNotificationSettings) GetDebounce() time.Duration {
+	if t.DebounceSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(t.DebounceSeconds) * time.Second
+}
+
 // GeminiSettings defines Gemini CLI configuration
 type GeminiSettings struct {
 	// YoloMode enables --yolo flag for Gemini sessions (auto-approve all actions)
@@ -568,10 +742,12 @@ type GlobalSearchSettings struct {
 	// Enabled enables/disables global search feature (default: true when loaded via LoadUserConfig)
 	Enabled bool `toml:"enabled"`
 
-	// Tier controls search strategy: "auto", "instant", "balanced", "disabled"
+	// Tier controls search strategy: "auto", "instant", "balanced", "persistent", "disabled"
 	// auto: Auto-detect based on data size (recommended)
 	// instant: Force full in-memory (fast, uses more RAM)
 	// balanced: Force LRU cache mode (slower, capped RAM)
+	// persistent: On-disk full-text index of all history; starts instantly,
+	//             supports "phrases", AND/OR/NOT and prefix* queries
 	// disabled: Disable global search entirely
 	Tier string `toml:"tier"`
 
@@ -586,6 +762,14 @@ type GlobalSearchSettings struct {
 	// IndexRateLimit limits files indexed per second during background indexing
 	// Lower = less CPU impact (default: 20)
 	IndexRateLimit int `toml:"index_rate_limit"`
+
+	// IndexPath is the SQLite file for the persistent tier
+	// (default: ~/.hangar/search.db)
+	IndexPath string `toml:"index_path"`
+
+	// Sources lists the agents whose transcripts are searched: "claude",
+	// "gemini", "codex" and "opencode" (default: all of them)
+	Sources []string `toml:"sources"`
 }
 
 // ToolDef defines a custom AI tool
@@ -1308,6 +1492,15 @@ func GetTmuxSettings() TmuxSettings {
 	return config.Tmux
 }
 
+// GetTowerSettings returns Tower settings from config
+func GetTowerSettings() TowerSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return TowerSettings{} // Defaults applied via getters
+	}
+	return config.Tower
+}
+
 // GetInstanceSettings returns instance behavior settings
 func GetInstanceSettings() InstanceSettings {
 	config, err := LoadUserConfig()
@@ -1545,6 +1738,60 @@ auto_cleanup = true
 # dangerous_flag = "--dangerously-skip-permissions"
 # env = { ANTHROPIC_BASE_URL = "https://api.example.com/v4", API_KEY = "your-key" }
 
+# ============================================================================
+# Session Templates
+# ============================================================================
+# Named session setups, selectable with Ctrl+T in the new session dialog,
+# 'hangar add --template' and the API. Prompt variables: {title}, {branch},
+# {path}, {group}, {todo.title}, {todo.description}, {todo.prompt}.
+#
+# [templates.bugfix]
+# tool = "claude"
+# worktree = true
+# group = "bugs"
+# mcps = ["github"]
+# prompt = "Fix this bug on {branch}: {todo.title}\n\n{todo.description}"
+# [templates.bugfix.claude]
+# skip_permissions = true
+
+# ============================================================================
+# Notification Sinks
+# ============================================================================
+# Push session status transitions (running -> waiting/error/idle), PR state
+# changes and todo completions to webhooks, ntfy, Gotify or a command.
+# Failed deliveries are retried; results go to ~/.hangar/logs/notifications.log.
+#
+# [[notifications.sinks]]
+# name = "phone"
+# type = "ntfy"                       # webhook | ntfy | gotify | command
+# url = "https://ntfy.sh/my-hangar-topic"
+# events = ["status", "pr"]           # default: status, pr, todo
+# statuses = ["waiting", "error"]     # status events only
+#
+# [[notifications.sinks]]
+# name = "chat"
+# type = "webhook"
+# url = "https://hooks.example.com/hangar"
+# body = '{"text": {{json .Message}}}'
+#
+# [[notifications.sinks]]
+# type = "command"
+# command = "jq -r .message | say"
+
+# ============================================================================
+# Tower Notifications
+# ============================================================================
+# When a session goes from running to waiting, error or idle and has no parent
+# session, 'hangar notify-daemon' (started by 'hangar tower') tells Tower.
+# Filters combine with AND; empty lists match everything.
+#
+# [tower.notifications]
+# enabled = true
+# statuses = ["waiting", "error"]
+# projects = ["api"]
+# groups = ["projects/api"]
+# debounce_seconds = 30   # batch events into one message (0 = send each now)
+
 # ============================================================================
 # Status Detection Pattern Overrides (Advanced)
 # ============================================================================
@@ -1573,6 +1820,16 @@ auto_cleanup = true
 # [remote_labels]
 # "ghe.mycompany.com" = "github"
 # "gitlab.mycompany.com" = "gitlab"
+
+# ============================================================================
+# PR Providers
+# ============================================================================
+# Maps git remote hostnames to the code host serving their PRs: "github"
+# (needs the gh CLI) or "gitlab" (needs glab). Hosts not listed use GitHub,
+# except gitlab.com.
+#
+# [pr_providers]
+# "gitlab.mycompany.com" = "gitlab"
 `
 
 	// Ensure directory exists
@@ -1654,26 +1911,6 @@ type SlackSettings struct {
 	AppToken string `toml:"app_token"`
 }
 
-// ConductorMeta holds metadata about a conductor instance (stub; conductor is removed).
-type ConductorMeta struct {
-	Name              string `json:"name"`
-	Profile           string `json:"profile"`
-	HeartbeatEnabled  bool   `json:"heartbeat_enabled"`
-	HeartbeatInterval int    `json:"heartbeat_interval"`
-	Description       string `json:"description,omitempty"`
-	CreatedAt         string `json:"created_at"`
-}
-
-// ConductorSessionTitle returns the tmux session title for a conductor (stub).
-func ConductorSessionTitle(name string) string {
-	return fmt.Sprintf("conductor-%s", name)
-}
-
-// ListConductorsForProfile returns conductors for a profile (stub; always empty).
-func ListConductorsForProfile(_ string) ([]ConductorMeta, error) {
-	return nil, nil
-}
-
 // InstallBridgeScript is a stub; conductor bridge is no longer installed.
 func InstallBridgeScript() error {
 	return nil
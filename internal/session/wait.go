@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultWaitStatuses are the statuses a wait ends on when none are given:
+// the agent has stopped working, for better or worse.
+var DefaultWaitStatuses = []Status{StatusWaiting, StatusIdle, StatusError}
+
+// ParseWaitStatuses parses a comma-separated status list such as
+// "waiting,idle". An empty string yields DefaultWaitStatuses.
+func ParseWaitStatuses(s string) ([]Status, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultWaitStatuses, nil
+	}
+	var statuses []Status
+	for _, part := range strings.Split(s, ",") {
+		st := Status(strings.ToLower(strings.TrimSpace(part)))
+		switch st {
+		case StatusRunning, StatusWaiting, StatusIdle, StatusError:
+			statuses = append(statuses, st)
+		case "":
+		default:
+			return nil, fmt.Errorf("invalid status '%s' (want running, waiting, idle or error)", part)
+		}
+	}
+	return statuses, nil
+}
+
+// WaitOptions controls WaitForStatus.
+type WaitOptions struct {
+	Statuses []Status // statuses that end the wait
+	// Changed ignores a matching status until the session has been seen in
+	// another status, so a wait started right after sending a prompt does not
+	// return before the agent picks it up.
+	Changed bool
+	// Wake returns a channel that fires the next time statuses may have
+	// changed; nil relies on Poll alone.
+	Wake func() <-chan struct{}
+	Poll time.Duration // fallback re-check interval (default 1s)
+}
+
+// WaitForStatus blocks until current returns one of opts.Statuses or ctx is
+// done. It returns the last status seen and whether it matched.
+func WaitForStatus(ctx context.Context, current func() Status, opts WaitOptions) (Status, bool) {
+	poll := opts.Poll
+	if poll <= 0 {
+		poll = time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	left := !opts.Changed
+	for {
+		// Take the wake channel before reading the status so a change in
+		// between is not missed.
+		var wake <-chan struct{}
+		if opts.Wake != nil {
+			wake = opts.Wake()
+		}
+		status := current()
+		matched := false
+		for _, s := range opts.Statuses {
+			if status == s {
+				matched = true
+				break
+			}
+		}
+		if matched && left {
+			return status, true
+		}
+		if !matched {
+			left = true
+		}
+		select {
+		case <-ctx.Done():
+			return status, false
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
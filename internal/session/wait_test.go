@@ -0,0 +1,57 @@
+package session
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseWaitStatuses(t *testing.T) {
+	got, err := ParseWaitStatuses("")
+	if err != nil || len(got) != len(DefaultWaitStatuses) {
+		t.Errorf("empty = %v, %v; want defaults", got, err)
+	}
+	got, err = ParseWaitStatuses(" Waiting, idle ,")
+	if err != nil || len(got) != 2 || got[0] != StatusWaiting || got[1] != StatusIdle {
+		t.Errorf("list = %v, %v", got, err)
+	}
+	if _, err := ParseWaitStatuses("waiting,done"); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	var status atomic.Value
+	status.Store(StatusWaiting)
+	current := func() Status { return status.Load().(Status) }
+	wait := func(changed bool, timeout time.Duration, wake func() <-chan struct{}) (Status, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return WaitForStatus(ctx, current, WaitOptions{
+			Statuses: []Status{StatusWaiting},
+			Changed:  changed,
+			Wake:     wake,
+			Poll:     time.Hour,
+		})
+	}
+
+	if st, ok := wait(false, time.Second, nil); !ok || st != StatusWaiting {
+		t.Errorf("already waiting = %s, %v; want immediate match", st, ok)
+	}
+	if st, ok := wait(true, 50*time.Millisecond, nil); ok || st != StatusWaiting {
+		t.Errorf("changed with no transition = %s, %v; want timeout", st, ok)
+	}
+
+	// A wake after running → waiting ends a changed wait without polling.
+	status.Store(StatusRunning)
+	wakeCh := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		status.Store(StatusWaiting)
+		close(wakeCh)
+	}()
+	if st, ok := wait(true, 5*time.Second, func() <-chan struct{} { return wakeCh }); !ok || st != StatusWaiting {
+		t.Errorf("woken wait = %s, %v; want match", st, ok)
+	}
+}
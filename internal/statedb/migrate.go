@@ -64,19 +64,20 @@ type jsonGroupData struct {
 
 // toolDataBlob is the JSON structure stored in the tool_data column.
 type toolDataBlob struct {
-	ClaudeSessionID    string          `json:"claude_session_id,omitempty"`
-	ClaudeDetectedAt   int64           `json:"claude_detected_at,omitempty"`
-	GeminiSessionID    string          `json:"gemini_session_id,omitempty"`
-	GeminiDetectedAt   int64           `json:"gemini_detected_at,omitempty"`
-	GeminiYoloMode     *bool           `json:"gemini_yolo_mode,omitempty"`
-	GeminiModel        string          `json:"gemini_model,omitempty"`
-	OpenCodeSessionID  string          `json:"opencode_session_id,omitempty"`
-	OpenCodeDetectedAt int64           `json:"opencode_detected_at,omitempty"`
-	CodexSessionID     string          `json:"codex_session_id,omitempty"`
-	CodexDetectedAt    int64           `json:"codex_detected_at,omitempty"`
-	LatestPrompt       string          `json:"latest_prompt,omitempty"`
-	LoadedMCPNames     []string        `json:"loaded_mcp_names,omitempty"`
-	ToolOptions        json.RawMessage `json:"tool_options,omitempty"`
+	ClaudeSessionID    string            `json:"claude_session_id,omitempty"`
+	ClaudeDetectedAt   int64             `json:"claude_detected_at,omitempty"`
+	GeminiSessionID    string            `json:"gemini_session_id,omitempty"`
+	GeminiDetectedAt   int64             `json:"gemini_detected_at,omitempty"`
+	GeminiYoloMode     *bool             `json:"gemini_yolo_mode,omitempty"`
+	GeminiModel        string            `json:"gemini_model,omitempty"`
+	OpenCodeSessionID  string            `json:"opencode_session_id,omitempty"`
+	OpenCodeDetectedAt int64             `json:"opencode_detected_at,omitempty"`
+	CodexSessionID     string            `json:"codex_session_id,omitempty"`
+	CodexDetectedAt    int64             `json:"codex_detected_at,omitempty"`
+	LatestPrompt       string            `json:"latest_prompt,omitempty"`
+	LoadedMCPNames     []string          `json:"loaded_mcp_names,omitempty"`
+	ToolOptions        json.RawMessage   `json:"tool_options,omitempty"`
+	Env                map[string]string `json:"env,omitempty"`
 }
 
 // MigrateFromJSON reads a sessions.json file and inserts all data into the StateDB.
@@ -179,7 +180,7 @@ func MarshalToolData(
 	openCodeSessionID string, openCodeDetectedAt time.Time,
 	codexSessionID string, codexDetectedAt time.Time,
 	latestPrompt string, loadedMCPNames []string,
-	toolOptionsJSON json.RawMessage,
+	toolOptionsJSON json.RawMessage, env map[string]string,
 ) json.RawMessage {
 	td := toolDataBlob{
 		ClaudeSessionID:   claudeSessionID,
@@ -191,6 +192,7 @@ func MarshalToolData(
 		LatestPrompt:      latestPrompt,
 		LoadedMCPNames:    loadedMCPNames,
 		ToolOptions:       toolOptionsJSON,
+		Env:               env,
 	}
 	if !claudeDetectedAt.IsZero() {
 		td.ClaudeDetectedAt = claudeDetectedAt.Unix()
@@ -217,7 +219,7 @@ func UnmarshalToolData(data json.RawMessage) (
 	openCodeSessionID string, openCodeDetectedAt time.Time,
 	codexSessionID string, codexDetectedAt time.Time,
 	latestPrompt string, loadedMCPNames []string,
-	toolOptionsJSON json.RawMessage,
+	toolOptionsJSON json.RawMessage, env map[string]string,
 ) {
 	if len(data) == 0 {
 		return
@@ -247,5 +249,6 @@ func UnmarshalToolData(data json.RawMessage) (
 	latestPrompt = td.LatestPrompt
 	loadedMCPNames = td.LoadedMCPNames
 	toolOptionsJSON = td.ToolOptions
+	env = td.Env
 	return
 }
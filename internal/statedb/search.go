@@ -0,0 +1,274 @@
+package statedb
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// searchCompactChunks is how many appended chunks a file may have in the
+// full-text table before they are merged into one row. Incremental updates
+// add a chunk per write burst; merging keeps boolean queries, which match
+// within a row, working across the conversation.
+const searchCompactChunks = 32
+
+// searchSealedChunkSize is the size at which a chunk is no longer merged
+// with later ones, so compacting a long conversation rewrites at most about
+// this much text rather than all of it.
+const searchSealedChunkSize = 1 << 20
+
+// SearchDB is the persistent full-text index of agent conversations used by
+// global search. It lives in its own file rather than state.db because
+// transcripts are shared by every profile and the index can always be
+// rebuilt from them.
+type SearchDB struct {
+	db *sql.DB
+}
+
+// SearchFileRow tracks one indexed transcript file.
+type SearchFileRow struct {
+	Path      string
+	SessionID string
+	Tool      string // agent that wrote the transcript
+	CWD       string
+	Summary   string
+	ModTime   time.Time
+	Size      int64
+	Offset    int64 // bytes of the file indexed so far (always at a line boundary)
+}
+
+// SearchHitRow is a transcript matching a full-text query.
+type SearchHitRow struct {
+	SearchFileRow
+	Snippet string
+	Rank    float64 // bm25; lower is a better match
+}
+
+// OpenSearch creates or opens the search index at dbPath.
+func OpenSearch(dbPath string) (*SearchDB, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0700); err != nil {
+		return nil, fmt.Errorf("searchdb: mkdir: %w", err)
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("searchdb: open: %w", err)
+	}
+	for _, pragma := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("searchdb: %s: %w", pragma, err)
+		}
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS search_files (
+			path       TEXT PRIMARY KEY,
+			session_id TEXT NOT NULL DEFAULT '',
+			tool       TEXT NOT NULL DEFAULT 'claude',
+			cwd        TEXT NOT NULL DEFAULT '',
+			summary    TEXT NOT NULL DEFAULT '',
+			mod_time   INTEGER NOT NULL DEFAULT 0, -- unix nanoseconds
+			size       INTEGER NOT NULL DEFAULT 0,
+			offset     INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("searchdb: create search_files: %w", err)
+	}
+	// Indexes created before other agents were searched lack the tool
+	// column; every file in them is a Claude transcript.
+	if _, err := db.Exec(`ALTER TABLE search_files ADD COLUMN tool TEXT NOT NULL DEFAULT 'claude'`); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column name") {
+			db.Close()
+			return nil, fmt.Errorf("searchdb: add tool column: %w", err)
+		}
+	}
+	if _, err := db.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS search_content USING fts5(
+			content,
+			path UNINDEXED,
+			tokenize = 'porter unicode61'
+		)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("searchdb: create search_content: %w", err)
+	}
+	// search_content's path column is not indexed, so each file's rows are
+	// found through search_chunks, keyed by their full-text rowid.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS search_chunks (
+			id   INTEGER PRIMARY KEY, -- rowid in search_content
+			path TEXT NOT NULL,
+			size INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("searchdb: create search_chunks: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_search_chunks_path ON search_chunks(path, id)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("searchdb: create search_chunks index: %w", err)
+	}
+	// Indexes created before search_chunks existed have only the
+	// full-text rows.
+	if _, err := db.Exec(`
+		INSERT INTO search_chunks (id, path, size)
+		SELECT rowid, path, length(content) FROM search_content
+		WHERE NOT EXISTS (SELECT 1 FROM search_chunks)
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("searchdb: fill search_chunks: %w", err)
+	}
+	return &SearchDB{db: db}, nil
+}
+
+// Close checkpoints WAL and closes the database.
+func (s *SearchDB) Close() error {
+	_, _ = s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return s.db.Close()
+}
+
+// SearchFiles returns every indexed file.
+func (s *SearchDB) SearchFiles() ([]SearchFileRow, error) {
+	rows, err := s.db.Query(`SELECT path, session_id, tool, cwd, summary, mod_time, size, offset FROM search_files`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var files []SearchFileRow
+	for rows.Next() {
+		var f SearchFileRow
+		var modTime int64
+		if err := rows.Scan(&f.Path, &f.SessionID, &f.Tool, &f.CWD, &f.Summary, &modTime, &f.Size, &f.Offset); err != nil {
+			return nil, err
+		}
+		f.ModTime = time.Unix(0, modTime)
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// IndexSearchFile records file and appends content (text read from the
+// file since the previous offset) to its full-text rows. reset drops the
+// file's existing rows first, for files that were truncated or replaced.
+func (s *SearchDB) IndexSearchFile(file SearchFileRow, content string, reset bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if reset {
+		if err := deleteSearchChunks(tx, file.Path, 0); err != nil {
+			return fmt.Errorf("searchdb: reset %s: %w", file.Path, err)
+		}
+	}
+	if content != "" {
+		if err := insertSearchChunk(tx, file.Path, content); err != nil {
+			return fmt.Errorf("searchdb: index %s: %w", file.Path, err)
+		}
+	}
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO search_files (path, session_id, tool, cwd, summary, mod_time, size, offset)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, file.Path, file.SessionID, file.Tool, file.CWD, file.Summary, file.ModTime.UnixNano(), file.Size, file.Offset); err != nil {
+		return fmt.Errorf("searchdb: save %s: %w", file.Path, err)
+	}
+
+	// Only the chunks after the last sealed one are merged.
+	var chunks int
+	var first int64
+	if err := tx.QueryRow(`
+		SELECT COUNT(*), COALESCE(MIN(id), 0) FROM search_chunks
+		WHERE path = ? AND id > COALESCE((SELECT MAX(id) FROM search_chunks WHERE path = ? AND size >= ?), 0)
+	`, file.Path, file.Path, searchSealedChunkSize).Scan(&chunks, &first); err != nil {
+		return err
+	}
+	if chunks > searchCompactChunks {
+		var merged string
+		if err := tx.QueryRow(`
+			SELECT group_concat(content, '') FROM (
+				SELECT c.content FROM search_chunks k JOIN search_content c ON c.rowid = k.id
+				WHERE k.path = ? AND k.id >= ? ORDER BY k.id
+			)
+		`, file.Path, first).Scan(&merged); err != nil {
+			return fmt.Errorf("searchdb: compact %s: %w", file.Path, err)
+		}
+		if err := deleteSearchChunks(tx, file.Path, first); err != nil {
+			return fmt.Errorf("searchdb: compact %s: %w", file.Path, err)
+		}
+		if err := insertSearchChunk(tx, file.Path, merged); err != nil {
+			return fmt.Errorf("searchdb: compact %s: %w", file.Path, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// insertSearchChunk adds content as a new full-text row of path.
+func insertSearchChunk(tx *sql.Tx, path, content string) error {
+	res, err := tx.Exec(`INSERT INTO search_content (content, path) VALUES (?, ?)`, content, path)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO search_chunks (id, path, size) VALUES (?, ?, ?)`, id, path, len(content))
+	return err
+}
+
+// deleteSearchChunks removes path's full-text rows with rowid from on.
+func deleteSearchChunks(tx *sql.Tx, path string, from int64) error {
+	if _, err := tx.Exec(`DELETE FROM search_content WHERE rowid IN (SELECT id FROM search_chunks WHERE path = ? AND id >= ?)`, path, from); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM search_chunks WHERE path = ? AND id >= ?`, path, from)
+	return err
+}
+
+// SearchContent runs an FTS5 query (words, "phrases", AND/OR/NOT, prefix*)
+// and returns up to limit files, best match first, each with a snippet from
+// its best-matching chunk. Files for which keep returns false are skipped
+// before the limit applies; a nil keep keeps all. A malformed query returns
+// the FTS5 syntax error.
+func (s *SearchDB) SearchContent(match string, limit int, keep func(*SearchHitRow) bool) ([]SearchHitRow, error) {
+	rows, err := s.db.Query(`
+		SELECT c.path, snippet(search_content, 0, '', '', '...', 24), bm25(search_content),
+		       f.session_id, f.tool, f.cwd, f.summary, f.mod_time, f.size, f.offset
+		FROM search_content c
+		JOIN search_files f ON f.path = c.path
+		WHERE search_content MATCH ?
+		ORDER BY bm25(search_content)
+	`, match)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHitRow
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var h SearchHitRow
+		var modTime int64
+		if err := rows.Scan(&h.Path, &h.Snippet, &h.Rank, &h.SessionID, &h.Tool, &h.CWD, &h.Summary, &modTime, &h.Size, &h.Offset); err != nil {
+			return nil, err
+		}
+		// Rows are chunks; keep each file's best one.
+		if seen[h.Path] {
+			continue
+		}
+		seen[h.Path] = true
+		h.ModTime = time.Unix(0, modTime)
+		if keep != nil && !keep(&h) {
+			continue
+		}
+		hits = append(hits, h)
+		if limit > 0 && len(hits) == limit {
+			break
+		}
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,137 @@
+package statedb
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchDB(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "search.db")
+	db, err := OpenSearch(dbPath)
+	if err != nil {
+		t.Fatalf("OpenSearch: %v", err)
+	}
+
+	mod := time.Unix(1772000000, 123456789)
+	a := SearchFileRow{Path: "/p/a.jsonl", SessionID: "a", CWD: "/src/api", Summary: "auth", ModTime: mod, Size: 10, Offset: 10}
+	b := SearchFileRow{Path: "/p/b.jsonl", SessionID: "b", Tool: "codex", CWD: "/src/web", ModTime: mod, Size: 10, Offset: 10}
+	if err := db.IndexSearchFile(a, "User: fix the authentication bug in login\n", false); err != nil {
+		t.Fatalf("IndexSearchFile: %v", err)
+	}
+	if err := db.IndexSearchFile(b, "User: the login page is slow\nAssistant: caching the login form\n", false); err != nil {
+		t.Fatalf("IndexSearchFile: %v", err)
+	}
+	a.Offset, a.Size = 20, 20
+	if err := db.IndexSearchFile(a, "Assistant: fixed the token refresh\n", false); err != nil {
+		t.Fatalf("IndexSearchFile append: %v", err)
+	}
+
+	paths := func(match string) string {
+		t.Helper()
+		hits, err := db.SearchContent(match, 0, nil)
+		if err != nil {
+			t.Fatalf("SearchContent(%q): %v", match, err)
+		}
+		var out []string
+		for _, h := range hits {
+			out = append(out, filepath.Base(h.Path))
+		}
+		return strings.Join(out, ",")
+	}
+	for match, want := range map[string]string{
+		`login`:                     "b.jsonl,a.jsonl", // b mentions it more often
+		`"authentication bug"`:      "a.jsonl",
+		`auth*`:                     "a.jsonl",
+		`login NOT slow`:            "a.jsonl",
+		`(slow OR token) NOT login`: "a.jsonl",
+		`refresh`:                   "a.jsonl", // appended chunk
+		`bugs`:                      "a.jsonl", // porter stemming
+	} {
+		if got := paths(match); got != want {
+			t.Errorf("SearchContent(%q) = %q, want %q", match, got, want)
+		}
+	}
+	if _, err := db.SearchContent(`main.go`, 0, nil); err == nil {
+		t.Error("expected a syntax error for main.go")
+	}
+
+	hits, _ := db.SearchContent(`refresh`, 0, nil)
+	if len(hits) != 1 || hits[0].Offset != 20 || !hits[0].ModTime.Equal(mod) || hits[0].CWD != "/src/api" || !strings.Contains(hits[0].Snippet, "token refresh") {
+		t.Errorf("hit = %+v", hits)
+	}
+
+	// keep filters before the limit.
+	hits, _ = db.SearchContent(`login`, 1, func(h *SearchHitRow) bool { return h.CWD == "/src/api" })
+	if len(hits) != 1 || hits[0].Path != a.Path {
+		t.Errorf("filtered hits = %+v, want a only", hits)
+	}
+
+	// reset drops the old content.
+	if err := db.IndexSearchFile(a, "User: something else\n", true); err != nil {
+		t.Fatalf("IndexSearchFile reset: %v", err)
+	}
+	if got := paths("refresh"); got != "" {
+		t.Errorf("after reset, refresh matched %q", got)
+	}
+
+	// Many small appends are compacted so boolean queries span them.
+	for i := 0; i < searchCompactChunks; i++ {
+		word := "filler"
+		switch i {
+		case 0:
+			word = "alpha"
+		case searchCompactChunks - 1:
+			word = "omega"
+		}
+		if err := db.IndexSearchFile(b, word+"\n", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := paths("alpha AND omega"); got != "b.jsonl" {
+		t.Errorf("alpha AND omega = %q after compaction", got)
+	}
+
+	// A sealed chunk is kept as is; only the chunks after it are merged.
+	big := strings.Repeat("filler ", searchSealedChunkSize/7+1) + "\n"
+	if err := db.IndexSearchFile(a, big, true); err != nil {
+		t.Fatal(err)
+	}
+	var sealed int64
+	if err := db.db.QueryRow(`SELECT id FROM search_chunks WHERE path = ?`, a.Path).Scan(&sealed); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= searchCompactChunks; i++ {
+		if err := db.IndexSearchFile(a, "beta\n", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var chunks, kept int
+	if err := db.db.QueryRow(`SELECT COUNT(*), SUM(id = ?) FROM search_chunks WHERE path = ?`, sealed, a.Path).Scan(&chunks, &kept); err != nil {
+		t.Fatal(err)
+	}
+	if chunks != 2 || kept != 1 {
+		t.Errorf("chunks = %d (sealed kept: %d), want the sealed chunk and one merged chunk", chunks, kept)
+	}
+	if got := paths("beta"); got != "a.jsonl" {
+		t.Errorf("beta = %q after compaction", got)
+	}
+	db.Close()
+
+	// Files and their offsets survive a reopen.
+	db, err = OpenSearch(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	files, err := db.SearchFiles()
+	if err != nil || len(files) != 2 {
+		t.Fatalf("SearchFiles = %v, %v", files, err)
+	}
+	for _, f := range files {
+		if f.Path == b.Path && f.Tool != "codex" {
+			t.Errorf("tool = %q, want codex", f.Tool)
+		}
+	}
+}
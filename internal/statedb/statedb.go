@@ -15,7 +15,7 @@ import (
 
 // SchemaVersion tracks the current database schema version.
 // Bump this when adding migrations.
-const SchemaVersion = 4
+const SchemaVersion = 10
 
 // StateDB wraps a SQLite database for session/group persistence.
 // Thread-safe for concurrent use from multiple goroutines within one process.
@@ -66,10 +66,23 @@ type TodoRow struct {
 	Status      string // todo | in_progress | in_review | done | orphaned
 	SessionID   string // soft FK to instances.id (empty = unlinked)
 	Order       int
+	BlockedBy   []string // IDs of todos that must be done first (stored as JSON)
+	AutoStart   bool     // start a session automatically once unblocked
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+// APIKeyRow represents an API key for the embedded API server.
+// Only the SHA-256 hash of the token is stored; the plaintext is shown once at creation.
+type APIKeyRow struct {
+	ID         string
+	Name       string
+	Scope      string // read | control | admin
+	TokenHash  string // hex-encoded SHA-256 of the token
+	CreatedAt  time.Time
+	LastUsedAt time.Time // zero if never used
+}
+
 // StatusRow holds status + acknowledgment for a session.
 type StatusRow struct {
 	Status       string
@@ -77,6 +90,58 @@ type StatusRow struct {
 	Acknowledged bool
 }
 
+// Status transition sources recorded in status_history.
+const (
+	StatusSourceHook   = "hook"   // status derived from a fresh Claude/Codex lifecycle hook
+	StatusSourcePoller = "poller" // status derived from tmux pane polling
+)
+
+// StatusHistoryRow is one recorded status transition for an instance.
+type StatusHistoryRow struct {
+	ID         int64
+	InstanceID string
+	FromStatus string
+	ToStatus   string
+	Source     string // StatusSourceHook or StatusSourcePoller
+	At         time.Time
+}
+
+// ScheduleRow represents a scheduled job executed by the primary instance.
+type ScheduleRow struct {
+	ID        string
+	Name      string
+	Cron      string    // 5-field cron expression or @shortcut; empty for one-shot jobs
+	RunAt     time.Time // fire time for one-shot jobs (zero for cron jobs)
+	Action    string    // send | todo
+	SessionID string    // target session for send jobs
+	TodoID    string    // todo to start a worktree session from (todo jobs)
+	Message   string    // text to send; for todo jobs overrides the todo prompt
+	Enabled   bool
+	NextRun   time.Time // zero once a one-shot job has fired
+	LastRun   time.Time // zero if never run
+	LastError string    // empty if the last run succeeded
+	CreatedAt time.Time
+}
+
+// PendingFinishRow is a worktree session waiting for its pull request to be
+// merged before the worktree and session are torn down.
+type PendingFinishRow struct {
+	SessionID  string
+	PRNumber   int
+	PRURL      string
+	KeepBranch bool // keep the local branch after teardown
+	CreatedAt  time.Time
+}
+
+// SessionPRRow is the last known pull request of a worktree session, kept so
+// PR badges show immediately after a restart.
+type SessionPRRow struct {
+	SessionID string
+	Data      string    // JSON-encoded PR; empty when the branch has no PR
+	UpdatedAt time.Time // the PR's own updated-at, zero when there is none
+	FetchedAt time.Time
+}
+
 // global singleton for cross-package access (status writes from background worker)
 var (
 	globalDB   *StateDB
@@ -247,6 +312,95 @@ func (s *StateDB) Migrate() error {
 		}
 	}
 
+	// Migration v5: api_keys table for API server authentication.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id           TEXT PRIMARY KEY,
+			name         TEXT NOT NULL DEFAULT '',
+			scope        TEXT NOT NULL,
+			token_hash   TEXT NOT NULL UNIQUE,
+			created_at   INTEGER NOT NULL,
+			last_used_at INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create api_keys: %w", err)
+	}
+
+	// Migration v6: status_history table recording every status transition.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS status_history (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			instance_id TEXT NOT NULL,
+			from_status TEXT NOT NULL DEFAULT '',
+			to_status   TEXT NOT NULL,
+			source      TEXT NOT NULL DEFAULT '',
+			at          INTEGER NOT NULL -- unix milliseconds
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create status_history: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_status_history_instance ON status_history(instance_id, at)`); err != nil {
+		return fmt.Errorf("statedb: create status_history index: %w", err)
+	}
+
+	// Migration v7: schedules table for cron-style and one-shot jobs.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS schedules (
+			id         TEXT PRIMARY KEY,
+			name       TEXT NOT NULL DEFAULT '',
+			cron       TEXT NOT NULL DEFAULT '',
+			run_at     INTEGER NOT NULL DEFAULT 0,
+			action     TEXT NOT NULL,
+			session_id TEXT NOT NULL DEFAULT '',
+			todo_id    TEXT NOT NULL DEFAULT '',
+			message    TEXT NOT NULL DEFAULT '',
+			enabled    INTEGER NOT NULL DEFAULT 1,
+			next_run   INTEGER NOT NULL DEFAULT 0,
+			last_run   INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create schedules: %w", err)
+	}
+
+	// Migration v8: todo dependencies and auto-start.
+	if _, err := tx.Exec(`ALTER TABLE todos ADD COLUMN blocked_by TEXT NOT NULL DEFAULT '[]'`); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("statedb: add blocked_by column: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`ALTER TABLE todos ADD COLUMN auto_start INTEGER NOT NULL DEFAULT 0`); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("statedb: add auto_start column: %w", err)
+		}
+	}
+
+	// Migration v9: worktree sessions finished via a pull request.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS pending_finishes (
+			session_id  TEXT PRIMARY KEY,
+			pr_number   INTEGER NOT NULL DEFAULT 0,
+			pr_url      TEXT NOT NULL DEFAULT '',
+			keep_branch INTEGER NOT NULL DEFAULT 0,
+			created_at  INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create pending_finishes: %w", err)
+	}
+
+	// Migration v10: last known PR of each worktree session.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS session_prs (
+			session_id TEXT PRIMARY KEY,
+			data       TEXT NOT NULL DEFAULT '',
+			updated_at INTEGER NOT NULL DEFAULT 0,
+			fetched_at INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create session_prs: %w", err)
+	}
+
 	// Set schema version only when missing or changed.
 	// Avoiding a write on every open reduces lock contention between CLI processes.
 	schemaVersion := fmt.Sprintf("%d", SchemaVersion)
@@ -409,9 +563,15 @@ func (s *StateDB) LoadInstances() ([]*InstanceRow, error) {
 	return result, rows.Err()
 }
 
-// DeleteInstance removes an instance by ID.
+// DeleteInstance removes an instance, its status history and its cached PR by ID.
 func (s *StateDB) DeleteInstance(id string) error {
-	_, err := s.db.Exec("DELETE FROM instances WHERE id = ?", id)
+	if _, err := s.db.Exec("DELETE FROM instances WHERE id = ?", id); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("DELETE FROM session_prs WHERE session_id = ?", id); err != nil {
+		return err
+	}
+	_, err := s.db.Exec("DELETE FROM status_history WHERE instance_id = ?", id)
 	return err
 }
 
@@ -492,16 +652,91 @@ func (s *StateDB) DeleteGroup(path string) error {
 
 // --- Status + Acknowledgment ---
 
-// WriteStatus updates the status and tool for an instance.
+// WriteStatus updates the status and tool for an instance, recording any
+// transition with StatusSourcePoller. See WriteStatusWithSource.
 func (s *StateDB) WriteStatus(id, status, tool string) error {
-	_, err := s.db.Exec(
+	return s.WriteStatusWithSource(id, status, tool, StatusSourcePoller)
+}
+
+// WriteStatusWithSource updates the status and tool for an instance. When the
+// status differs from the last recorded transition and is running, waiting,
+// idle or error, the transition is appended to status_history with the given
+// source. Both writes share one transaction so concurrent writers cannot
+// record the same transition twice.
+func (s *StateDB) WriteStatusWithSource(id, status, tool, source string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Compare against the last recorded transition rather than instances.status:
+	// SaveInstances also writes status, and would otherwise hide transitions.
+	if _, err := tx.Exec(
+		`INSERT INTO status_history (instance_id, from_status, to_status, source, at)
+		 SELECT id, COALESCE(
+		     (SELECT to_status FROM status_history WHERE instance_id = ? ORDER BY at DESC, id DESC LIMIT 1),
+		     status), ?, ?, ?
+		 FROM instances
+		 WHERE id = ? AND ? IN ('running', 'waiting', 'idle', 'error')
+		   AND COALESCE(
+		     (SELECT to_status FROM status_history WHERE instance_id = ? ORDER BY at DESC, id DESC LIMIT 1),
+		     '') != ?`,
+		id, status, source, time.Now().UnixMilli(), id, status, id, status,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
 		`UPDATE instances
 		 SET status = ?, tool = ?,
 		     acknowledged = CASE WHEN ? = 'running' THEN 0 ELSE acknowledged END
 		 WHERE id = ?`,
 		status, tool, status, id,
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// LoadStatusHistory returns the transitions recorded for an instance at or
+// after since, oldest first. It also returns the last transition before since
+// (if any) as the first element, so callers know the status at the window start.
+func (s *StateDB) LoadStatusHistory(id string, since time.Time) ([]*StatusHistoryRow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, instance_id, from_status, to_status, source, at FROM (
+			SELECT * FROM (
+				SELECT * FROM status_history WHERE instance_id = ? AND at < ?
+				ORDER BY at DESC, id DESC LIMIT 1
+			)
+			UNION ALL
+			SELECT * FROM status_history WHERE instance_id = ? AND at >= ?
+		) ORDER BY at, id
+	`, id, since.UnixMilli(), id, since.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*StatusHistoryRow
+	for rows.Next() {
+		r := &StatusHistoryRow{}
+		var atMillis int64
+		if err := rows.Scan(&r.ID, &r.InstanceID, &r.FromStatus, &r.ToStatus, &r.Source, &atMillis); err != nil {
+			return nil, err
+		}
+		r.At = time.UnixMilli(atMillis)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// PruneStatusHistory deletes transitions older than before.
+func (s *StateDB) PruneStatusHistory(before time.Time) (int64, error) {
+	res, err := s.db.Exec("DELETE FROM status_history WHERE at < ?", before.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 // ReadAllStatuses returns status + acknowledged flag for every instance.
@@ -687,22 +922,58 @@ func (s *StateDB) LastModified() (int64, error) {
 
 // SaveTodo inserts or replaces a single todo row.
 func (s *StateDB) SaveTodo(row *TodoRow) error {
-	_, err := s.db.Exec(`
+	blockedBy, err := json.Marshal(row.BlockedBy)
+	if err != nil {
+		return err
+	}
+	if row.BlockedBy == nil {
+		blockedBy = []byte("[]")
+	}
+	autoStart := 0
+	if row.AutoStart {
+		autoStart = 1
+	}
+	_, err = s.db.Exec(`
 		INSERT OR REPLACE INTO todos
-			(id, project_path, title, description, prompt, status, session_id, sort_order, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			(id, project_path, title, description, prompt, status, session_id, sort_order, blocked_by, auto_start, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		row.ID, row.ProjectPath, row.Title, row.Description, row.Prompt,
-		row.Status, row.SessionID, row.Order,
+		row.Status, row.SessionID, row.Order, string(blockedBy), autoStart,
 		row.CreatedAt.Unix(), row.UpdatedAt.Unix(),
 	)
 	return err
 }
 
+const todoColumns = `id, project_path, title, description, prompt, status, session_id, sort_order, blocked_by, auto_start, created_at, updated_at`
+
+// scanTodo scans one todo row selected with todoColumns.
+func scanTodo(sc interface{ Scan(...any) error }) (*TodoRow, error) {
+	r := &TodoRow{}
+	var blockedBy string
+	var autoStart int
+	var createdUnix, updatedUnix int64
+	if err := sc.Scan(
+		&r.ID, &r.ProjectPath, &r.Title, &r.Description, &r.Prompt,
+		&r.Status, &r.SessionID, &r.Order, &blockedBy, &autoStart,
+		&createdUnix, &updatedUnix,
+	); err != nil {
+		return nil, err
+	}
+	if blockedBy != "" && blockedBy != "[]" {
+		// A malformed value only loses the dependency list, not the todo.
+		_ = json.Unmarshal([]byte(blockedBy), &r.BlockedBy)
+	}
+	r.AutoStart = autoStart != 0
+	r.CreatedAt = time.Unix(createdUnix, 0)
+	r.UpdatedAt = time.Unix(updatedUnix, 0)
+	return r, nil
+}
+
 // LoadTodos returns all todos for a given project path, ordered by sort_order then created_at.
 func (s *StateDB) LoadTodos(projectPath string) ([]*TodoRow, error) {
 	rows, err := s.db.Query(`
-		SELECT id, project_path, title, description, prompt, status, session_id, sort_order, created_at, updated_at
+		SELECT `+todoColumns+`
 		FROM todos WHERE project_path = ? ORDER BY sort_order, created_at
 	`, projectPath)
 	if err != nil {
@@ -712,17 +983,10 @@ func (s *StateDB) LoadTodos(projectPath string) ([]*TodoRow, error) {
 
 	var result []*TodoRow
 	for rows.Next() {
-		r := &TodoRow{}
-		var createdUnix, updatedUnix int64
-		if err := rows.Scan(
-			&r.ID, &r.ProjectPath, &r.Title, &r.Description, &r.Prompt,
-			&r.Status, &r.SessionID, &r.Order,
-			&createdUnix, &updatedUnix,
-		); err != nil {
+		r, err := scanTodo(rows)
+		if err != nil {
 			return nil, err
 		}
-		r.CreatedAt = time.Unix(createdUnix, 0)
-		r.UpdatedAt = time.Unix(updatedUnix, 0)
 		result = append(result, r)
 	}
 	return result, rows.Err()
@@ -731,7 +995,7 @@ func (s *StateDB) LoadTodos(projectPath string) ([]*TodoRow, error) {
 // LoadAllTodos returns all todos across all projects, ordered by project_path then sort_order.
 func (s *StateDB) LoadAllTodos() ([]*TodoRow, error) {
 	rows, err := s.db.Query(`
-		SELECT id, project_path, title, description, prompt, status, session_id, sort_order, created_at, updated_at
+		SELECT ` + todoColumns + `
 		FROM todos ORDER BY project_path, sort_order, created_at
 	`)
 	if err != nil {
@@ -741,17 +1005,10 @@ func (s *StateDB) LoadAllTodos() ([]*TodoRow, error) {
 
 	var result []*TodoRow
 	for rows.Next() {
-		r := &TodoRow{}
-		var createdUnix, updatedUnix int64
-		if err := rows.Scan(
-			&r.ID, &r.ProjectPath, &r.Title, &r.Description, &r.Prompt,
-			&r.Status, &r.SessionID, &r.Order,
-			&createdUnix, &updatedUnix,
-		); err != nil {
+		r, err := scanTodo(rows)
+		if err != nil {
 			return nil, err
 		}
-		r.CreatedAt = time.Unix(createdUnix, 0)
-		r.UpdatedAt = time.Unix(updatedUnix, 0)
 		result = append(result, r)
 	}
 	return result, rows.Err()
@@ -782,29 +1039,36 @@ func (s *StateDB) UpdateTodoStatus(id, status, sessionID string) error {
 	return nil
 }
 
+// ClaimTodo moves an unlinked todo from "todo" to "in_progress". It returns
+// false if the todo was already started elsewhere, so concurrent processes
+// reacting to the same completion start at most one session for it.
+func (s *StateDB) ClaimTodo(id string) (bool, error) {
+	res, err := s.db.Exec(
+		"UPDATE todos SET status = 'in_progress', updated_at = ? WHERE id = ? AND status = 'todo' AND session_id = ''",
+		time.Now().Unix(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
 // FindTodoByID returns the todo with the given ID, or nil if not found.
 func (s *StateDB) FindTodoByID(id string) (*TodoRow, error) {
 	if id == "" {
 		return nil, nil
 	}
-	r := &TodoRow{}
-	var createdUnix, updatedUnix int64
-	err := s.db.QueryRow(`
-		SELECT id, project_path, title, description, prompt, status, session_id, sort_order, created_at, updated_at
+	r, err := scanTodo(s.db.QueryRow(`
+		SELECT `+todoColumns+`
 		FROM todos WHERE id = ? LIMIT 1
-	`, id).Scan(
-		&r.ID, &r.ProjectPath, &r.Title, &r.Description, &r.Prompt,
-		&r.Status, &r.SessionID, &r.Order,
-		&createdUnix, &updatedUnix,
-	)
+	`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	r.CreatedAt = time.Unix(createdUnix, 0)
-	r.UpdatedAt = time.Unix(updatedUnix, 0)
 	return r, nil
 }
 
@@ -813,23 +1077,283 @@ func (s *StateDB) FindTodoBySessionID(sessionID string) (*TodoRow, error) {
 	if sessionID == "" {
 		return nil, nil
 	}
-	r := &TodoRow{}
-	var createdUnix, updatedUnix int64
-	err := s.db.QueryRow(`
-		SELECT id, project_path, title, description, prompt, status, session_id, sort_order, created_at, updated_at
+	r, err := scanTodo(s.db.QueryRow(`
+		SELECT `+todoColumns+`
 		FROM todos WHERE session_id = ? LIMIT 1
-	`, sessionID).Scan(
-		&r.ID, &r.ProjectPath, &r.Title, &r.Description, &r.Prompt,
-		&r.Status, &r.SessionID, &r.Order,
-		&createdUnix, &updatedUnix,
-	)
+	`, sessionID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	r.CreatedAt = time.Unix(createdUnix, 0)
-	r.UpdatedAt = time.Unix(updatedUnix, 0)
 	return r, nil
 }
+
+// --- API Keys ---
+
+// SaveAPIKey inserts or updates an API key row. Token hashes are unique, so
+// saving a second key with an existing hash fails.
+func (s *StateDB) SaveAPIKey(row *APIKeyRow) error {
+	var lastUsed int64
+	if !row.LastUsedAt.IsZero() {
+		lastUsed = row.LastUsedAt.Unix()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO api_keys (id, name, scope, token_hash, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name, scope = excluded.scope, token_hash = excluded.token_hash,
+			created_at = excluded.created_at, last_used_at = excluded.last_used_at
+	`, row.ID, row.Name, row.Scope, row.TokenHash, row.CreatedAt.Unix(), lastUsed)
+	return err
+}
+
+// LoadAPIKeys returns all API keys ordered by creation time.
+func (s *StateDB) LoadAPIKeys() ([]*APIKeyRow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, scope, token_hash, created_at, last_used_at
+		FROM api_keys ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*APIKeyRow
+	for rows.Next() {
+		r := &APIKeyRow{}
+		var createdUnix, lastUsedUnix int64
+		if err := rows.Scan(&r.ID, &r.Name, &r.Scope, &r.TokenHash, &createdUnix, &lastUsedUnix); err != nil {
+			return nil, err
+		}
+		r.CreatedAt = time.Unix(createdUnix, 0)
+		if lastUsedUnix > 0 {
+			r.LastUsedAt = time.Unix(lastUsedUnix, 0)
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// DeleteAPIKey removes an API key by ID. Returns an error if no key matched.
+func (s *StateDB) DeleteAPIKey(id string) error {
+	res, err := s.db.Exec("DELETE FROM api_keys WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("statedb: api key %q not found", id)
+	}
+	return nil
+}
+
+// TouchAPIKey records that the key with the given ID was just used.
+func (s *StateDB) TouchAPIKey(id string) error {
+	_, err := s.db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", time.Now().Unix(), id)
+	return err
+}
+
+// --- Schedules ---
+
+// unixOrZero converts t to unix seconds, mapping the zero time to 0.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeOrZero converts unix seconds to a time, mapping 0 to the zero time.
+func timeOrZero(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// SaveSchedule inserts or replaces a schedule row.
+func (s *StateDB) SaveSchedule(row *ScheduleRow) error {
+	enabled := 0
+	if row.Enabled {
+		enabled = 1
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO schedules
+			(id, name, cron, run_at, action, session_id, todo_id, message,
+			 enabled, next_run, last_run, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, row.ID, row.Name, row.Cron, unixOrZero(row.RunAt), row.Action, row.SessionID, row.TodoID, row.Message,
+		enabled, unixOrZero(row.NextRun), unixOrZero(row.LastRun), row.LastError, row.CreatedAt.Unix())
+	return err
+}
+
+// LoadSchedules returns all schedules ordered by creation time.
+func (s *StateDB) LoadSchedules() ([]*ScheduleRow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, cron, run_at, action, session_id, todo_id, message,
+		       enabled, next_run, last_run, last_error, created_at
+		FROM schedules ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*ScheduleRow
+	for rows.Next() {
+		r := &ScheduleRow{}
+		var runAt, nextRun, lastRun, created int64
+		var enabled int
+		if err := rows.Scan(&r.ID, &r.Name, &r.Cron, &runAt, &r.Action, &r.SessionID, &r.TodoID, &r.Message,
+			&enabled, &nextRun, &lastRun, &r.LastError, &created); err != nil {
+			return nil, err
+		}
+		r.RunAt = timeOrZero(runAt)
+		r.Enabled = enabled == 1
+		r.NextRun = timeOrZero(nextRun)
+		r.LastRun = timeOrZero(lastRun)
+		r.CreatedAt = time.Unix(created, 0)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// DeleteSchedule removes a schedule by ID. Returns an error if none matched.
+func (s *StateDB) DeleteSchedule(id string) error {
+	res, err := s.db.Exec("DELETE FROM schedules WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("statedb: schedule %q not found", id)
+	}
+	return nil
+}
+
+// ClaimScheduleRun atomically advances a due schedule from due to next
+// (zero next disables it) and records ran as its last run. It returns false
+// if the schedule was changed or claimed by another process since it was
+// loaded, in which case the caller must not run it.
+func (s *StateDB) ClaimScheduleRun(id string, due, next, ran time.Time) (bool, error) {
+	enabled := 1
+	if next.IsZero() {
+		enabled = 0
+	}
+	res, err := s.db.Exec(`
+		UPDATE schedules SET next_run = ?, last_run = ?, enabled = ?
+		WHERE id = ? AND next_run = ? AND enabled = 1
+	`, unixOrZero(next), ran.Unix(), enabled, id, due.Unix())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// SetScheduleError records the outcome of a schedule's last run
+// (empty msg means success).
+func (s *StateDB) SetScheduleError(id, msg string) error {
+	_, err := s.db.Exec("UPDATE schedules SET last_error = ? WHERE id = ?", msg, id)
+	return err
+}
+
+// --- Pending finishes ---
+
+// SavePendingFinish inserts or replaces the pending finish for a session.
+func (s *StateDB) SavePendingFinish(row *PendingFinishRow) error {
+	keep := 0
+	if row.KeepBranch {
+		keep = 1
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO pending_finishes (session_id, pr_number, pr_url, keep_branch, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, row.SessionID, row.PRNumber, row.PRURL, keep, row.CreatedAt.Unix())
+	return err
+}
+
+// LoadPendingFinishes returns all pending finishes ordered by creation time.
+func (s *StateDB) LoadPendingFinishes() ([]*PendingFinishRow, error) {
+	rows, err := s.db.Query(`
+		SELECT session_id, pr_number, pr_url, keep_branch, created_at
+		FROM pending_finishes ORDER BY created_at, session_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*PendingFinishRow
+	for rows.Next() {
+		r := &PendingFinishRow{}
+		var keep int
+		var created int64
+		if err := rows.Scan(&r.SessionID, &r.PRNumber, &r.PRURL, &keep, &created); err != nil {
+			return nil, err
+		}
+		r.KeepBranch = keep == 1
+		r.CreatedAt = time.Unix(created, 0)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// ClaimPendingFinish deletes the pending finish for a session, returning
+// false if there was none (e.g. another process already claimed it).
+func (s *StateDB) ClaimPendingFinish(sessionID string) (bool, error) {
+	res, err := s.db.Exec("DELETE FROM pending_finishes WHERE session_id = ?", sessionID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// --- Session PRs ---
+
+// SaveSessionPR inserts or replaces the cached PR of a session.
+func (s *StateDB) SaveSessionPR(row *SessionPRRow) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO session_prs (session_id, data, updated_at, fetched_at)
+		VALUES (?, ?, ?, ?)
+	`, row.SessionID, row.Data, unixOrZero(row.UpdatedAt), row.FetchedAt.Unix())
+	return err
+}
+
+// LoadSessionPRs returns the cached PRs of all sessions that still exist.
+func (s *StateDB) LoadSessionPRs() ([]*SessionPRRow, error) {
+	rows, err := s.db.Query(`
+		SELECT session_id, data, updated_at, fetched_at FROM session_prs
+		WHERE session_id IN (SELECT id FROM instances)
+		ORDER BY session_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*SessionPRRow
+	for rows.Next() {
+		r := &SessionPRRow{}
+		var updated, fetched int64
+		if err := rows.Scan(&r.SessionID, &r.Data, &updated, &fetched); err != nil {
+			return nil, err
+		}
+		r.UpdatedAt = timeOrZero(updated)
+		r.FetchedAt = time.Unix(fetched, 0)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
@@ -183,6 +183,73 @@ func TestStatusReadWrite(t *testing.T) {
 	}
 }
 
+func TestStatusHistory(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.SaveInstance(&InstanceRow{
+		ID: "s1", Title: "S1", ProjectPath: "/tmp", GroupPath: "grp",
+		Tool: "claude", Status: "starting", CreatedAt: time.Now(), ToolData: json.RawMessage("{}"),
+	}); err != nil {
+		t.Fatalf("SaveInstance: %v", err)
+	}
+
+	start := time.Now()
+	writes := []struct{ status, source string }{
+		{"running", StatusSourceHook},
+		{"running", StatusSourcePoller},  // no change: not recorded
+		{"starting", StatusSourcePoller}, // untracked status: not recorded
+		{"waiting", StatusSourceHook},
+		{"idle", StatusSourcePoller},
+	}
+	for _, w := range writes {
+		if err := db.WriteStatusWithSource("s1", w.status, "claude", w.source); err != nil {
+			t.Fatalf("WriteStatusWithSource(%s): %v", w.status, err)
+		}
+	}
+	// Unknown instances never get history rows.
+	_ = db.WriteStatusWithSource("ghost", "running", "claude", StatusSourcePoller)
+
+	rows, err := db.LoadStatusHistory("s1", start.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("LoadStatusHistory: %v", err)
+	}
+	want := []struct{ from, to, source string }{
+		{"starting", "running", StatusSourceHook},
+		{"running", "waiting", StatusSourceHook},
+		{"waiting", "idle", StatusSourcePoller},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d transitions, got %d", len(want), len(rows))
+	}
+	for i, w := range want {
+		if rows[i].FromStatus != w.from || rows[i].ToStatus != w.to || rows[i].Source != w.source {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], w)
+		}
+	}
+
+	// A window starting after all transitions still returns the latest one,
+	// so callers know the status at the window start.
+	rows, _ = db.LoadStatusHistory("s1", time.Now().Add(time.Minute))
+	if len(rows) != 1 || rows[0].ToStatus != "idle" {
+		t.Errorf("expected only the idle transition, got %+v", rows)
+	}
+
+	if n, err := db.PruneStatusHistory(time.Now().Add(time.Minute)); err != nil || n != 3 {
+		t.Errorf("PruneStatusHistory = %d, %v; want 3", n, err)
+	}
+
+	// Deleting an instance drops its history.
+	_ = db.WriteStatusWithSource("s1", "running", "claude", StatusSourceHook)
+	if err := db.DeleteInstance("s1"); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	if rows, _ := db.LoadStatusHistory("s1", time.Time{}); len(rows) != 0 {
+		t.Errorf("expected history removed with instance, got %d rows", len(rows))
+	}
+	if rows, _ := db.LoadStatusHistory("ghost", time.Time{}); len(rows) != 0 {
+		t.Errorf("expected no history for unknown instance, got %d rows", len(rows))
+	}
+}
+
 func TestAcknowledgedSync(t *testing.T) {
 	db := newTestDB(t)
 
@@ -687,6 +754,190 @@ func TestFindTodoBySessionID(t *testing.T) {
 	}
 }
 
+func TestTodoDependencies(t *testing.T) {
+	db := newTestDB(t)
+	_ = db.SaveTodo(&TodoRow{ID: "a", ProjectPath: "/p", Title: "schema", Status: "todo", CreatedAt: time.Unix(1, 0), UpdatedAt: time.Unix(1, 0)})
+	_ = db.SaveTodo(&TodoRow{ID: "b", ProjectPath: "/p", Title: "api", Status: "todo", BlockedBy: []string{"a"}, AutoStart: true, CreatedAt: time.Unix(2, 0), UpdatedAt: time.Unix(2, 0)})
+
+	b, err := db.FindTodoByID("b")
+	if err != nil || b == nil {
+		t.Fatalf("FindTodoByID: %v, %v", b, err)
+	}
+	if len(b.BlockedBy) != 1 || b.BlockedBy[0] != "a" || !b.AutoStart {
+		t.Errorf("got BlockedBy=%v AutoStart=%v, want [a] true", b.BlockedBy, b.AutoStart)
+	}
+	a, _ := db.FindTodoByID("a")
+	if a.BlockedBy != nil || a.AutoStart {
+		t.Errorf("unblocked todo: got BlockedBy=%v AutoStart=%v", a.BlockedBy, a.AutoStart)
+	}
+
+	// Only the first claim wins.
+	if ok, err := db.ClaimTodo("b"); err != nil || !ok {
+		t.Fatalf("first ClaimTodo = %v, %v; want true", ok, err)
+	}
+	if ok, _ := db.ClaimTodo("b"); ok {
+		t.Error("second ClaimTodo should fail")
+	}
+	b, _ = db.FindTodoByID("b")
+	if b.Status != "in_progress" {
+		t.Errorf("Status after claim: got %q want in_progress", b.Status)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	db := newTestDB(t)
+	row := &APIKeyRow{ID: "key-1", Name: "laptop", Scope: "read", TokenHash: "abc123", CreatedAt: time.Unix(1000, 0)}
+	if err := db.SaveAPIKey(row); err != nil {
+		t.Fatalf("SaveAPIKey: %v", err)
+	}
+	// Duplicate hashes are rejected so one token can never map to two scopes.
+	if err := db.SaveAPIKey(&APIKeyRow{ID: "key-2", Scope: "admin", TokenHash: "abc123", CreatedAt: time.Unix(1001, 0)}); err == nil {
+		t.Error("expected error saving duplicate token hash")
+	}
+
+	if err := db.TouchAPIKey("key-1"); err != nil {
+		t.Fatalf("TouchAPIKey: %v", err)
+	}
+	keys, err := db.LoadAPIKeys()
+	if err != nil {
+		t.Fatalf("LoadAPIKeys: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(keys))
+	}
+	if keys[0].Name != "laptop" || keys[0].Scope != "read" || keys[0].TokenHash != "abc123" {
+		t.Errorf("unexpected key: %+v", keys[0])
+	}
+	if keys[0].LastUsedAt.IsZero() {
+		t.Error("LastUsedAt should be set after TouchAPIKey")
+	}
+
+	if err := db.DeleteAPIKey("key-1"); err != nil {
+		t.Fatalf("DeleteAPIKey: %v", err)
+	}
+	if err := db.DeleteAPIKey("key-1"); err == nil {
+		t.Error("expected error deleting missing key")
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	db := newTestDB(t)
+	due := time.Unix(5000, 0)
+	row := &ScheduleRow{
+		ID: "sched-1", Name: "review", Cron: "0 9 * * 1-5", Action: "send", SessionID: "s1",
+		Message: "/pr-review", Enabled: true, NextRun: due, CreatedAt: time.Unix(1000, 0),
+	}
+	if err := db.SaveSchedule(row); err != nil {
+		t.Fatalf("SaveSchedule: %v", err)
+	}
+
+	next := due.Add(24 * time.Hour)
+	ok, err := db.ClaimScheduleRun("sched-1", due, next, due)
+	if err != nil || !ok {
+		t.Fatalf("ClaimScheduleRun = %v, %v; want true", ok, err)
+	}
+	// A second claim for the same due time must lose.
+	if ok, _ := db.ClaimScheduleRun("sched-1", due, next, due); ok {
+		t.Error("second claim for the same run should fail")
+	}
+	if err := db.SetScheduleError("sched-1", "boom"); err != nil {
+		t.Fatalf("SetScheduleError: %v", err)
+	}
+
+	rows, err := db.LoadSchedules()
+	if err != nil {
+		t.Fatalf("LoadSchedules: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 schedule, got %d", len(rows))
+	}
+	got := rows[0]
+	if !got.NextRun.Equal(next) || !got.LastRun.Equal(due) || got.LastError != "boom" || !got.Enabled || !got.RunAt.IsZero() {
+		t.Errorf("unexpected schedule: %+v", got)
+	}
+
+	// Claiming with a zero next run disables the job (one-shot).
+	if ok, _ := db.ClaimScheduleRun("sched-1", next, time.Time{}, next); !ok {
+		t.Fatal("claim of one-shot run failed")
+	}
+	rows, _ = db.LoadSchedules()
+	if rows[0].Enabled || !rows[0].NextRun.IsZero() {
+		t.Errorf("one-shot schedule should be disabled: %+v", rows[0])
+	}
+
+	if err := db.DeleteSchedule("sched-1"); err != nil {
+		t.Fatalf("DeleteSchedule: %v", err)
+	}
+	if err := db.DeleteSchedule("sched-1"); err == nil {
+		t.Error("expected error deleting missing schedule")
+	}
+}
+
+func TestPendingFinishes(t *testing.T) {
+	db := newTestDB(t)
+	row := &PendingFinishRow{
+		SessionID: "s1", PRNumber: 42, PRURL: "https://github.com/o/r/pull/42",
+		KeepBranch: true, CreatedAt: time.Unix(1000, 0),
+	}
+	if err := db.SavePendingFinish(row); err != nil {
+		t.Fatalf("SavePendingFinish: %v", err)
+	}
+
+	rows, err := db.LoadPendingFinishes()
+	if err != nil {
+		t.Fatalf("LoadPendingFinishes: %v", err)
+	}
+	if len(rows) != 1 || *rows[0] != *row {
+		t.Fatalf("LoadPendingFinishes = %+v, want [%+v]", rows, row)
+	}
+
+	if ok, err := db.ClaimPendingFinish("s1"); err != nil || !ok {
+		t.Fatalf("ClaimPendingFinish = %v, %v; want true", ok, err)
+	}
+	if ok, _ := db.ClaimPendingFinish("s1"); ok {
+		t.Error("second claim should fail")
+	}
+	if rows, _ := db.LoadPendingFinishes(); len(rows) != 0 {
+		t.Errorf("expected no pending finishes after claim, got %d", len(rows))
+	}
+}
+
+func TestSessionPRs(t *testing.T) {
+	db := newTestDB(t)
+	withPR := &SessionPRRow{SessionID: "s1", Data: `{"number":4}`, UpdatedAt: time.Unix(2000, 0), FetchedAt: time.Unix(3000, 0)}
+	noPR := &SessionPRRow{SessionID: "s2", FetchedAt: time.Unix(3000, 0)}
+	orphan := &SessionPRRow{SessionID: "gone", FetchedAt: time.Unix(3000, 0)}
+	for _, row := range []*SessionPRRow{withPR, noPR, orphan} {
+		if err := db.SaveSessionPR(row); err != nil {
+			t.Fatalf("SaveSessionPR: %v", err)
+		}
+	}
+	for _, id := range []string{"s1", "s2"} {
+		if err := db.SaveInstance(&InstanceRow{
+			ID: id, Title: id, ProjectPath: "/tmp", GroupPath: "grp",
+			Tool: "shell", Status: "idle", CreatedAt: time.Now(), ToolData: json.RawMessage("{}"),
+		}); err != nil {
+			t.Fatalf("SaveInstance: %v", err)
+		}
+	}
+
+	rows, err := db.LoadSessionPRs()
+	if err != nil {
+		t.Fatalf("LoadSessionPRs: %v", err)
+	}
+	if len(rows) != 2 || *rows[0] != *withPR || *rows[1] != *noPR {
+		t.Fatalf("LoadSessionPRs = %+v, want the rows of existing sessions", rows)
+	}
+
+	// Deleting the session drops its cached PR.
+	if err := db.DeleteInstance("s1"); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	if rows, _ := db.LoadSessionPRs(); len(rows) != 1 || rows[0].SessionID != "s2" {
+		t.Errorf("after delete = %+v", rows)
+	}
+}
+
 func TestGlobalSingleton(t *testing.T) {
 	// Initially nil
 	if GetGlobal() != nil {
@@ -224,6 +224,41 @@ func TestPipeManager_OutputCallback(t *testing.T) {
 	}
 }
 
+func TestPipeManager_OutputListener(t *testing.T) {
+	name := createTestSession(t, "pm-listener")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pm := NewPipeManager(ctx, nil)
+	defer pm.Close()
+
+	got := make(chan string, 16)
+	remove := pm.AddOutputListener(func(sessionName string) {
+		select {
+		case got <- sessionName:
+		default:
+		}
+	})
+
+	require.NoError(t, pm.Connect(name))
+	time.Sleep(300 * time.Millisecond)
+
+	_ = exec.Command("tmux", "send-keys", "-t", name, "echo listener-test", "Enter").Run()
+
+	select {
+	case s := <-got:
+		assert.Equal(t, name, s)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for output listener")
+	}
+
+	remove()
+	pm.listenersMu.RLock()
+	assert.Empty(t, pm.listeners)
+	pm.listenersMu.RUnlock()
+}
+
 func TestPipeManager_RefreshAllActivities(t *testing.T) {
 	name := createTestSession(t, "pm-refresh")
 
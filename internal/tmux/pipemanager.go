@@ -21,6 +21,11 @@ type PipeManager struct {
 	// Callback for output events (invoked when %output detected from a session)
 	onOutput func(sessionName string)
 
+	// Additional output listeners registered via AddOutputListener
+	listenersMu sync.RWMutex
+	listeners   map[int]func(sessionName string)
+	nextID      int
+
 	// Reconnection tracking
 	reconnectMu  sync.Mutex
 	reconnecting map[string]bool
@@ -37,12 +42,28 @@ func NewPipeManager(ctx context.Context, onOutput func(sessionName string)) *Pip
 	return &PipeManager{
 		pipes:        make(map[string]*ControlPipe),
 		onOutput:     onOutput,
+		listeners:    make(map[int]func(string)),
 		reconnecting: make(map[string]bool),
 		ctx:          childCtx,
 		cancel:       cancel,
 	}
 }
 
+// AddOutputListener registers fn to be called alongside the onOutput callback
+// whenever a connected session produces output. Returns a func that removes it.
+func (pm *PipeManager) AddOutputListener(fn func(sessionName string)) func() {
+	pm.listenersMu.Lock()
+	id := pm.nextID
+	pm.nextID++
+	pm.listeners[id] = fn
+	pm.listenersMu.Unlock()
+	return func() {
+		pm.listenersMu.Lock()
+		delete(pm.listeners, id)
+		pm.listenersMu.Unlock()
+	}
+}
+
 // Connect creates a control mode pipe for the given tmux session.
 // If a pipe already exists and is alive, this is a no-op.
 // Uses reconnecting map to prevent concurrent pipe creation for the same session.
@@ -326,6 +347,11 @@ func (pm *PipeManager) forwardOutputEvents(sessionName string, pipe *ControlPipe
 			if pm.onOutput != nil {
 				pm.onOutput(sessionName)
 			}
+			pm.listenersMu.RLock()
+			for _, fn := range pm.listeners {
+				fn(sessionName)
+			}
+			pm.listenersMu.RUnlock()
 		case <-pipe.Done():
 			return
 		}
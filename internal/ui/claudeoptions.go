@@ -70,6 +70,14 @@ func (p *ClaudeOptionsPanel) SetDefaults(config *session.UserConfig) {
 	}
 }
 
+// SetOptions sets the checkbox states from opts (e.g. from a session template)
+func (p *ClaudeOptionsPanel) SetOptions(opts *session.ClaudeOptions) {
+	p.skipPermissions = opts.SkipPermissions
+	p.allowSkipPermissions = opts.AllowSkipPermissions
+	p.useChrome = opts.UseChrome
+	p.useTeammateMode = opts.UseTeammateMode
+}
+
 // Focus sets focus to this panel
 func (p *ClaudeOptionsPanel) Focus() {
 	p.focusIndex = 0
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gorilla/websocket"
+	"github.com/sjoeboo/hangar/internal/apiserver"
 )
 
 // daemonWSMsg is a parsed event received from the daemon WebSocket.
@@ -28,7 +30,12 @@ type DaemonClient struct {
 // a background read loop. Returns nil if the connection fails.
 func newDaemonClient(port int) *DaemonClient {
 	url := fmt.Sprintf("ws://127.0.0.1:%d/api/v1/ws", port)
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	// When the daemon requires auth it publishes a local admin token.
+	var header http.Header
+	if token := apiserver.LoadLocalToken(); token != "" {
+		header = http.Header{"Authorization": []string{"Bearer " + token}}
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
 	if err != nil {
 		slog.Warn("daemon_ws_connect_failed", slog.String("url", url), slog.String("error", err.Error()))
 		return nil
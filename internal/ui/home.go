@@ -25,6 +25,7 @@ import (
 	"github.com/sjoeboo/hangar/internal/git"
 	"github.com/sjoeboo/hangar/internal/editor"
 	"github.com/sjoeboo/hangar/internal/logging"
+	"github.com/sjoeboo/hangar/internal/notify"
 	prpkg "github.com/sjoeboo/hangar/internal/pr"
 	"github.com/sjoeboo/hangar/internal/session"
 	"github.com/sjoeboo/hangar/internal/statedb"
@@ -134,25 +135,27 @@ type Home struct {
 	globalSearch         *GlobalSearch              // Global session search across all Claude conversations
 	globalSearchIndex    *session.GlobalSearchIndex // Search index (nil if disabled)
 	newDialog            *NewDialog
-	groupDialog          *GroupDialog          // For creating/renaming groups
-	forkDialog           *ForkDialog           // For forking sessions
-	confirmDialog        *ConfirmDialog        // For confirming destructive actions
-	helpOverlay          *HelpOverlay          // For showing keyboard shortcuts
-	diffView             *DiffView             // For showing inline git diff overlay
-	setupWizard          *SetupWizard          // For first-run setup
-	settingsPanel        *SettingsPanel        // For editing settings
-	geminiModelDialog    *GeminiModelDialog    // For selecting Gemini model
-	sessionPickerDialog  *SessionPickerDialog  // For sending output to another session
-	worktreeFinishDialog *WorktreeFinishDialog // For finishing worktree sessions (optional merge + cleanup)
-	reviewDialog         *ReviewDialog         // For launching a review session for the current branch
-	todoDialog           *TodoDialog           // For viewing/managing per-project todos
-	editorPickerDialog   *EditorPickerDialog   // For picking an editor to open the worktree directory
-	prDetailOverlay      *PRDetailOverlay      // For viewing full PR details (Overview/Diff/Conversation)
-	pendingTodoID        string                // Todo ID waiting for a session to be created from it
-	pendingTodoPrompt    string                // prompt to send when the pending todo's session starts
-	sendTextDialog       *SendTextDialog       // For sending text to a session without attaching
-	sendTextTargetID     string                // Session ID targeted by sendTextDialog
-	sendTextTargetIDs    []string              // Session IDs for bulk send-text
+	groupDialog          *GroupDialog             // For creating/renaming groups
+	forkDialog           *ForkDialog              // For forking sessions
+	confirmDialog        *ConfirmDialog           // For confirming destructive actions
+	helpOverlay          *HelpOverlay             // For showing keyboard shortcuts
+	diffView             *DiffView                // For showing inline git diff overlay
+	setupWizard          *SetupWizard             // For first-run setup
+	settingsPanel        *SettingsPanel           // For editing settings
+	geminiModelDialog    *GeminiModelDialog       // For selecting Gemini model
+	sessionPickerDialog  *SessionPickerDialog     // For sending output to another session
+	worktreeFinishDialog *WorktreeFinishDialog    // For finishing worktree sessions (optional merge + cleanup)
+	reviewDialog         *ReviewDialog            // For launching a review session for the current branch
+	prMergeDialog        *PRMergeDialog           // For merging a PR from the PR overview
+	todoDialog           *TodoDialog              // For viewing/managing per-project todos
+	editorPickerDialog   *EditorPickerDialog      // For picking an editor to open the worktree directory
+	prDetailOverlay      *PRDetailOverlay         // For viewing full PR details (Overview/Diff/Conversation)
+	pendingTodoID        string                   // Todo ID waiting for a session to be created from it
+	pendingTodoPrompt    string                   // prompt to send when the pending todo's session starts
+	pendingTemplate      *session.SessionTemplate // template chosen in the new session dialog, applied on create
+	sendTextDialog       *SendTextDialog          // For sending text to a session without attaching
+	sendTextTargetID     string                   // Session ID targeted by sendTextDialog
+	sendTextTargetIDs    []string                 // Session IDs for bulk send-text
 
 	// State
 	cursor       int            // Selected item index in flatItems
@@ -239,6 +242,9 @@ type Home struct {
 	// File watcher for external changes (auto-reload)
 	storageWatcher *StorageWatcher
 
+	// prMergedCh receives sessions finished via PR whose PR has merged
+	prMergedCh chan worktreePRMergedMsg
+
 	// System theme watcher (active when theme="system"; nil otherwise)
 	themeWatcher *ThemeWatcher
 
@@ -606,6 +612,11 @@ type sendTextResultMsg struct {
 	err         error
 }
 
+// broadcastResultMsg is sent when a bulk send-text to several sessions completes
+type broadcastResultMsg struct {
+	results []session.BroadcastResult
+}
+
 // diffFetchedMsg is sent when a git diff has been fetched for the focused session.
 type diffFetchedMsg struct {
 	sessionID string
@@ -654,9 +665,28 @@ type prFetchedMsg struct {
 type worktreeFinishResultMsg struct {
 	sessionID    string
 	sessionTitle string
+	viaPR        bool // torn down after its PR merged
 	err          error
 }
 
+// worktreePRFinishResultMsg is sent when a PR-mode finish has pushed the
+// branch and opened (or found) the PR. pr is non-nil if the PR exists even
+// when err reports a later step (auto-merge) failing.
+type worktreePRFinishResultMsg struct {
+	sessionID    string
+	sessionTitle string
+	pr           *prpkg.PR
+	err          error
+}
+
+// worktreePRMergedMsg is sent by the finish watcher when a session finished
+// via PR has had its PR merged and its worktree torn down, so the session
+// should now be removed.
+type worktreePRMergedMsg struct {
+	inst       *session.Instance
+	keepBranch bool
+}
+
 // worktreeCreatedForNewSessionMsg is sent when async worktree creation for a new session completes
 type worktreeCreatedForNewSessionMsg struct {
 	name         string
@@ -716,11 +746,18 @@ type lazygitReadyMsg struct{ session *session.Instance }
 
 // prActionResultMsg is sent when a PR action (approve, comment, state change) completes.
 type prActionResultMsg struct {
-	action string // "approve", "comment", "state"
+	action string // "approve", "comment", "state", "merge", "auto-merge", "disable auto-merge"
 	repo   string
 	number int
 }
 
+// prMergeDetailLoadedMsg is returned when the fresh detail fetch for the
+// merge dialog completes.
+type prMergeDetailLoadedMsg struct {
+	detail *prpkg.PRDetail
+	err    error
+}
+
 // prDetailLoadedMsg is returned when async PR detail fetch completes.
 type prDetailLoadedMsg struct {
 	detail *prpkg.PRDetail
@@ -745,6 +782,20 @@ type prDetailCreateReviewMsg struct {
 	pr *prpkg.PR
 }
 
+// prDetailAddressFeedbackMsg is sent by PRDetailOverlay when the user presses
+// 'F' to send the PR's review comments and failed checks to its session.
+type prDetailAddressFeedbackMsg struct {
+	pr *prpkg.PR
+}
+
+// prFeedbackFetchedMsg is returned when the feedback for an 'F' press has been
+// collected into a prompt.
+type prFeedbackFetchedMsg struct {
+	pr     *prpkg.PR
+	prompt string
+	err    error
+}
+
 // prReviewResolveBranchMsg is returned by the async head-branch fetch that runs
 // when the user presses 's' on a PR whose HeadBranch is not yet populated
 // (global search results omit headRefName).
@@ -820,6 +871,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		sessionPickerDialog:  NewSessionPickerDialog(),
 		worktreeFinishDialog: NewWorktreeFinishDialog(),
 		reviewDialog:         NewReviewDialog(),
+		prMergeDialog:        NewPRMergeDialog(),
 		todoDialog:           NewTodoDialog(),
 		editorPickerDialog:   NewEditorPickerDialog(),
 		prDetailOverlay:      NewPRDetailOverlay(),
@@ -876,7 +928,36 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 			}
 		}
 	})
+	// Show the PRs saved by the last run until they are re-fetched.
+	if h.storage != nil {
+		h.prManager.LoadSessionPRCache(h.storage)
+	}
 	h.prManager.Start()
+	// Every TUI and "hangar web" watches the session PRs, so only the
+	// primary TUI acts on their changes.
+	isPrimary := func() bool {
+		db := statedb.GetGlobal()
+		if db == nil {
+			return true
+		}
+		primary, err := db.ElectPrimary(30 * time.Second)
+		return err == nil && primary
+	}
+	// Move linked todos along with their session's PR.
+	prpkg.NewTodoReconciler(h.prManager, h.profile).Start(h.ctx, isPrimary)
+	// Push session PR state changes to notification sinks.
+	notify.FromConfig().WatchPRs(h.ctx, h.prManager, isPrimary)
+	// Tear down worktrees finished via PR once the PR merges. The session is
+	// then removed through the UI so it leaves h.instances too.
+	h.prMergedCh = make(chan worktreePRMergedMsg, 8)
+	finishWatcher := prpkg.NewFinishWatcher(h.prManager, h.profile)
+	finishWatcher.OnMerged(func(inst *session.Instance, keepBranch bool) {
+		select {
+		case h.prMergedCh <- worktreePRMergedMsg{inst: inst, keepBranch: keepBranch}:
+		case <-h.ctx.Done():
+		}
+	})
+	finishWatcher.Start(h.ctx)
 
 	// Keep settings panel profile-aware so profile overrides (e.g., Claude config dir)
 	// are displayed and edited in the correct scope.
@@ -1529,6 +1610,11 @@ func (h *Home) Init() tea.Cmd {
 		cmds = append(cmds, listenForReloads(h.storageWatcher))
 	}
 
+	// Start listening for merged PRs of worktrees finished via PR
+	if h.prMergedCh != nil {
+		cmds = append(cmds, listenForPRMerged(h.prMergedCh))
+	}
+
 	// Start listening for hook status changes (immediate TUI refresh on hook events)
 	if h.hookWatcher != nil {
 		cmds = append(cmds, listenForHookChanges(h.hookWatcher))
@@ -1566,6 +1652,14 @@ func listenForReloads(sw *StorageWatcher) tea.Cmd {
 	}
 }
 
+// listenForPRMerged waits for the finish watcher to report a merged PR.
+// MUST be re-issued in the Update handler to keep listening.
+func listenForPRMerged(ch <-chan worktreePRMergedMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
 // listenForHookChanges waits for a hook status change notification.
 // MUST be re-issued in the Update handler to keep listening.
 func listenForHookChanges(w *session.StatusFileWatcher) tea.Cmd {
@@ -2137,7 +2231,7 @@ func (h *Home) backgroundStatusUpdate() {
 
 		// Write current status for each instance so other TUI instances stay in sync
 		for _, inst := range instances {
-			_ = db.WriteStatus(inst.ID, string(inst.GetStatusThreadSafe()), inst.Tool)
+			_ = db.WriteStatusWithSource(inst.ID, string(inst.GetStatusThreadSafe()), inst.Tool, inst.GetStatusSource())
 		}
 
 		// Read acknowledgments from SQLite (picks up acks from other instances)
@@ -2628,6 +2722,7 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case worktreeCreatedForNewSessionMsg:
 		h.removePendingWorktree(msg.branchName)
 		if msg.err != nil {
+			h.pendingTemplate = nil
 			h.setError(fmt.Errorf("failed to create worktree: %w", msg.err))
 			return h, nil
 		}
@@ -2664,6 +2759,12 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case worktreeFinishResultMsg:
 		return h, h.handleWorktreeFinishResult(msg)
 
+	case worktreePRFinishResultMsg:
+		return h, h.handleWorktreePRFinishResult(msg)
+
+	case worktreePRMergedMsg:
+		return h, tea.Batch(h.handleWorktreePRMerged(msg), listenForPRMerged(h.prMergedCh))
+
 	case copyResultMsg:
 		if msg.err != nil {
 			h.setError(msg.err)
@@ -2688,6 +2789,11 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case broadcastResultMsg:
+		summary := session.BroadcastSummary(msg.results)
+		h.setError(fmt.Errorf("%s%s", strings.ToUpper(summary[:1]), summary[1:]))
+		return h, nil
+
 	case prActionResultMsg:
 		h.setError(fmt.Errorf("PR #%d: %s done", msg.number, msg.action))
 		if msg.action == "approve" {
@@ -2695,6 +2801,15 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if h.prManager != nil {
 			h.prManager.InvalidateDetail(msg.repo, msg.number)
+			if msg.action == "merge" {
+				h.prManager.TriggerRefresh()
+			}
+		}
+		return h, nil
+
+	case prMergeDetailLoadedMsg:
+		if h.prMergeDialog.IsVisible() {
+			h.prMergeDialog.SetDetail(msg.detail, msg.err)
 		}
 		return h, nil
 
@@ -2705,12 +2820,11 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case prDetailApproveRequestMsg:
 		h.prDetailOverlay.Hide()
 		if h.prManager != nil && msg.pr.Repo != "" {
-			ghPath := h.prManager.GHPath()
-			if ghPath != "" {
+			if provider := h.prManager.ProviderFor(msg.pr.Repo); provider != nil {
 				repo, number := msg.pr.Repo, msg.pr.Number
 				h.setError(fmt.Errorf("Approving PR…"))
 				return h, func() tea.Msg {
-					err := prpkg.Approve(ghPath, repo, number, "")
+					err := provider.Approve(repo, number, "")
 					if err != nil {
 						return errMsg{err: err}
 					}
@@ -2731,6 +2845,40 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.prDetailOverlay.Hide()
 		return h, h.startPRReviewSession(msg.pr)
 
+	case prDetailAddressFeedbackMsg:
+		h.prDetailOverlay.Hide()
+		if h.prManager == nil || msg.pr.Repo == "" {
+			return h, nil
+		}
+		if h.prManager.ProviderNameFor(msg.pr.Repo) != prpkg.ProviderGitHub {
+			h.setError(fmt.Errorf("addressing feedback is only supported for GitHub PRs"))
+			return h, nil
+		}
+		ghPath := h.prManager.GHPath()
+		if ghPath == "" {
+			h.setError(fmt.Errorf("gh not available — cannot fetch PR feedback"))
+			return h, nil
+		}
+		h.setError(fmt.Errorf("Collecting feedback for PR #%d…", msg.pr.Number))
+		pr := msg.pr
+		return h, func() tea.Msg {
+			fb, err := prpkg.FetchFeedback(ghPath, pr.Repo, pr.Number)
+			if err != nil {
+				return prFeedbackFetchedMsg{pr: pr, err: err}
+			}
+			if fb.Empty() {
+				return prFeedbackFetchedMsg{pr: pr, err: fmt.Errorf("PR #%d has no unresolved review comments or failed checks", pr.Number)}
+			}
+			return prFeedbackFetchedMsg{pr: pr, prompt: prpkg.FeedbackPrompt(pr, fb)}
+		}
+
+	case prFeedbackFetchedMsg:
+		if msg.err != nil {
+			h.setError(msg.err)
+			return h, nil
+		}
+		return h, h.addressPRFeedback(msg.pr, msg.prompt)
+
 	case prReviewResolveBranchMsg:
 		h.pendingPRReview = nil
 		if msg.err != nil {
@@ -2936,6 +3084,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.worktreeFinishDialog.IsVisible() {
 			return h.handleWorktreeFinishDialogKey(msg)
 		}
+		if h.prMergeDialog.IsVisible() {
+			return h.handlePRMergeDialogKey(msg)
+		}
 
 		// PR detail overlay (takes precedence over PR overview keys)
 		if h.prDetailOverlay.IsVisible() {
@@ -3143,6 +3294,8 @@ func (h *Home) handleNewDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		groupPath := h.newDialog.GetSelectedGroup()
 		claudeOpts := h.newDialog.GetClaudeOptions() // Get Claude options if applicable
 
+		h.pendingTemplate = h.newDialog.GetTemplate()
+
 		// Handle worktree creation if enabled
 		var worktreePath, worktreeRepoRoot string
 		if worktreeEnabled && branchName != "" {
@@ -3243,6 +3396,7 @@ func (h *Home) handleNewDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		h.newDialog.Hide()
 		h.pendingTodoID = "" // Clear pending todo link on cancel
 		h.pendingTodoPrompt = ""
+		h.pendingTemplate = nil
 		h.clearError() // Clear any validation error
 		return h, nil
 	}
@@ -3292,11 +3446,10 @@ func (h *Home) handlePRMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			h.prDetailOverlay.Show(p)
 			h.prDetailOverlay.SetSize(h.width, h.height)
 			if h.prManager != nil && p.Repo != "" {
-				ghPath := h.prManager.GHPath()
-				if ghPath != "" {
+				if provider := h.prManager.ProviderFor(p.Repo); provider != nil {
 					repo, number := p.Repo, p.Number
 					return h, func() tea.Msg {
-						detail, err := prpkg.FetchDetail(ghPath, repo, number)
+						detail, err := provider.Detail(repo, number)
 						return prDetailLoadedMsg{detail: detail, err: err}
 					}
 				}
@@ -3325,7 +3478,7 @@ func (h *Home) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		h.groupDialog.IsVisible() || h.forkDialog.IsVisible() ||
 		h.confirmDialog.IsVisible() || h.geminiModelDialog.IsVisible() ||
 		h.sessionPickerDialog.IsVisible() || h.sendTextDialog.IsVisible() ||
-		h.worktreeFinishDialog.IsVisible() ||
+		h.worktreeFinishDialog.IsVisible() || h.prMergeDialog.IsVisible() ||
 		h.reviewDialog.IsVisible() || h.prDetailOverlay.IsVisible() ||
 		h.editorPickerDialog.IsVisible() {
 		return h, nil
@@ -3513,26 +3666,15 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if h.ghPath != "" {
 			h.viewMode = "prs"
 			h.prViewCursor = 0
-			// Trigger fetches for any session missing recent PR data
-			var cmds []tea.Cmd
-			for _, item := range h.flatItems {
-				if item.Type == session.ItemTypeSession && item.Session != nil && item.Session.IsWorktree() && item.Session.WorktreePath != "" {
-					_, cachedAt, hasCached := h.cache.HasPREntry(item.Session.ID)
-					needsFetch := !hasCached || time.Since(cachedAt) > prCacheTTL
-					if needsFetch {
-						h.cache.TouchPR(item.Session.ID)
-					}
-					if needsFetch {
-						sid := item.Session.ID
-						wtPath := item.Session.WorktreePath
-						ghPath := h.ghPath
-						cmds = append(cmds, func() tea.Msg {
-							return fetchPRInfo(sid, wtPath, ghPath)
-						})
+			// Queue lookups for any session missing recent PR data; the PR
+			// manager skips fresh and in-flight ones and batches the rest.
+			if h.prManager != nil {
+				for _, item := range h.flatItems {
+					if item.Type == session.ItemTypeSession && item.Session != nil && item.Session.IsWorktree() && item.Session.WorktreePath != "" {
+						h.prManager.UpdateSessionPR(item.Session.ID, item.Session.WorktreePath)
 					}
 				}
 			}
-			return h, tea.Batch(cmds...)
 		}
 		return h, nil
 
@@ -4158,10 +4300,20 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				} else {
 					h.selectedSessionIDs[id] = true
 				}
+			} else if item.Type == session.ItemTypeGroup {
+				// Toggle every session in the group (and its subgroups)
+				h.toggleBulkSelection(session.BroadcastSelector{Group: item.Path})
 			}
 		}
 		return h, nil
 
+	case "*":
+		// Bulk mode: toggle selection of all waiting sessions
+		if h.bulkSelectMode {
+			h.toggleBulkSelection(session.BroadcastSelector{Status: session.StatusWaiting})
+		}
+		return h, nil
+
 	case "d":
 		// Bulk mode: if any sessions are selected, show bulk confirm dialog
 		if h.bulkSelectMode && len(h.selectedSessionIDs) > 0 {
@@ -4423,16 +4575,12 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			sid := inst.ID
 			wtPath := inst.WorktreePath
 			h.cache.InvalidateWorktreeDirty(sid)
-			h.cache.InvalidatePRTimestamp(sid)
 			cmds = append(cmds, func() tea.Msg {
 				dirty, err := git.HasUncommittedChanges(wtPath)
 				return worktreeDirtyCheckMsg{sessionID: sid, isDirty: dirty, err: err}
 			})
-			if h.ghPath != "" {
-				ghPath := h.ghPath
-				cmds = append(cmds, func() tea.Msg {
-					return fetchPRInfo(sid, wtPath, ghPath)
-				})
+			if h.prManager != nil {
+				h.prManager.RefreshSessionPR(sid, wtPath)
 			}
 		}
 
@@ -4573,11 +4721,10 @@ func (h *Home) handlePRViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.prDetailOverlay.Show(p)
 			h.prDetailOverlay.SetSize(h.width, h.height)
 			if h.prManager != nil && p.Repo != "" {
-				ghPath := h.prManager.GHPath()
-				if ghPath != "" {
+				if provider := h.prManager.ProviderFor(p.Repo); provider != nil {
 					repo, number := p.Repo, p.Number
 					return h, func() tea.Msg {
-						detail, err := prpkg.FetchDetail(ghPath, repo, number)
+						detail, err := provider.Detail(repo, number)
 						return prDetailLoadedMsg{detail: detail, err: err}
 					}
 				}
@@ -4597,12 +4744,12 @@ func (h *Home) handlePRViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Approve selected PR
 		if h.prViewCursor < len(prs) && h.prManager != nil {
 			p := prs[h.prViewCursor]
-			ghPath := h.prManager.GHPath()
-			if ghPath != "" && p.Repo != "" {
+			provider := h.prManager.ProviderFor(p.Repo)
+			if provider != nil && p.Repo != "" {
 				repo, number := p.Repo, p.Number
 				h.setError(fmt.Errorf("Approving PR…"))
 				return h, func() tea.Msg {
-					err := prpkg.Approve(ghPath, repo, number, "")
+					err := provider.Approve(repo, number, "")
 					if err != nil {
 						return errMsg{err: err}
 					}
@@ -4627,15 +4774,15 @@ func (h *Home) handlePRViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Close / Reopen selected PR (only works for own PRs)
 		if h.prViewCursor < len(prs) && h.prManager != nil {
 			p := prs[h.prViewCursor]
-			ghPath := h.prManager.GHPath()
-			if ghPath != "" && p.Repo != "" {
+			provider := h.prManager.ProviderFor(p.Repo)
+			if provider != nil && p.Repo != "" {
 				repo, number, state := p.Repo, p.Number, p.State
 				return h, func() tea.Msg {
 					var err error
 					if state == "CLOSED" {
-						err = prpkg.Reopen(ghPath, repo, number)
+						err = provider.Reopen(repo, number)
 					} else {
-						err = prpkg.Close(ghPath, repo, number)
+						err = provider.Close(repo, number)
 					}
 					if err != nil {
 						return errMsg{err: err}
@@ -4647,23 +4794,38 @@ func (h *Home) handlePRViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return h, nil
 
 	case "r":
-		// Force re-fetch PR data for all sessions
-		var cmds []tea.Cmd
+		// Force re-fetch PR data for all sessions, batched by the PR manager
+		if h.prManager == nil {
+			return h, nil
+		}
 		for _, item := range h.flatItems {
 			if item.Type == session.ItemTypeSession && item.Session != nil && item.Session.IsWorktree() && item.Session.WorktreePath != "" {
-				h.cache.InvalidatePRTimestamp(item.Session.ID)
-				if h.prManager != nil {
-					h.prManager.InvalidateDetail(item.Session.WorktreePath, 0)
+				h.prManager.InvalidateDetail(item.Session.WorktreePath, 0)
+				h.prManager.RefreshSessionPR(item.Session.ID, item.Session.WorktreePath)
+			}
+		}
+		return h, nil
+
+	case "m":
+		// Merge the selected PR (or manage its auto-merge) after confirmation.
+		if h.prViewCursor < len(prs) && h.prManager != nil {
+			p := prs[h.prViewCursor]
+			if p.Repo != "" && h.prManager.ProviderFor(p.Repo) != nil {
+				h.prMergeDialog.SetSize(h.width, h.height)
+				h.prMergeDialog.Show(p)
+				repo, number := p.Repo, p.Number
+				// Judge checks and mergeability on fresh data.
+				h.prManager.InvalidateDetail(repo, number)
+				return h, func() tea.Msg {
+					detail, err := h.prManager.FetchDetail(repo, number)
+					if err == nil && detail == nil {
+						err = fmt.Errorf("PR not found")
+					}
+					return prMergeDetailLoadedMsg{detail: detail, err: err}
 				}
-				sid := item.Session.ID
-				wtPath := item.Session.WorktreePath
-				ghPath := h.ghPath
-				cmds = append(cmds, func() tea.Msg {
-					return fetchPRInfo(sid, wtPath, ghPath)
-				})
 			}
 		}
-		return h, tea.Batch(cmds...)
+		return h, nil
 
 	case "s":
 		// Create a review session for the selected PR.
@@ -4711,6 +4873,7 @@ func (h *Home) handleConfirmDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.confirmDialog.Hide()
 			h.pendingTodoID = "" // Clear pending todo link if user cancelled directory creation
 			h.pendingTodoPrompt = ""
+			h.pendingTemplate = nil
 			return h, nil
 		}
 		return h, nil
@@ -4739,11 +4902,13 @@ func (h *Home) handleConfirmDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					port := h.configuredHookPort
 					if port > 0 {
 						userCfg, _ := session.LoadUserConfig()
-						bindAddr := "0.0.0.0"
+						cfg2 := apiserver.APIConfig{Port: port, BindAddress: "0.0.0.0"}
 						if userCfg != nil {
-							bindAddr = userCfg.API.GetBindAddress()
+							cfg2.BindAddress = userCfg.API.GetBindAddress()
+							cfg2.RequireAuth = userCfg.API.GetRequireAuth()
+							cfg2.AllowedOrigins = userCfg.API.AllowedOrigins
+							cfg2.GitHubWebhookSecret = userCfg.API.GitHubWebhookSecret
 						}
-						cfg2 := apiserver.APIConfig{Port: port, BindAddress: bindAddr}
 						getInstances2 := func() []*session.Instance {
 							h.instancesMu.RLock()
 							snap := make([]*session.Instance, len(h.instances))
@@ -5414,6 +5579,25 @@ func (h *Home) loadUIState() {
 
 // createSessionInGroupWithWorktreeAndOptions creates a new session with full options and tool options
 func (h *Home) createSessionInGroupWithWorktreeAndOptions(name, path, command, groupPath, worktreePath, worktreeRepoRoot, worktreeBranch string, toolOptionsJSON json.RawMessage) tea.Cmd {
+	tmpl := h.pendingTemplate
+	h.pendingTemplate = nil
+	if tmpl != nil {
+		// A template prompt replaces the todo prompt; it can pull the todo in via {todo.*}
+		var todo *session.Todo
+		if h.pendingTodoID != "" && h.storage != nil {
+			todo, _ = h.storage.LoadTodoByID(h.pendingTodoID)
+		}
+		prompt := tmpl.RenderPrompt(session.TemplateVars{
+			Title:  name,
+			Branch: worktreeBranch,
+			Path:   path,
+			Group:  groupPath,
+			Todo:   todo,
+		})
+		if prompt != "" {
+			h.pendingTodoPrompt = prompt
+		}
+	}
 	return func() tea.Msg {
 		// Check tmux availability before creating session
 		if err := tmux.IsTmuxAvailable(); err != nil {
@@ -5455,6 +5639,13 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(name, path, command, g
 			inst.ToolOptionsJSON = toolOptionsJSON
 		}
 
+		// Template wrapper and MCPs (its Claude options are already in toolOptionsJSON)
+		if tmpl != nil {
+			if err := tmpl.ApplyToInstance(inst); err != nil {
+				return sessionCreatedMsg{err: fmt.Errorf("apply template: %w", err)}
+			}
+		}
+
 		if err := inst.Start(); err != nil {
 			return sessionCreatedMsg{err: err}
 		}
@@ -6147,6 +6338,7 @@ func (h *Home) updateSizes() {
 	h.confirmDialog.SetSize(h.width, h.height)
 	h.geminiModelDialog.SetSize(h.width, h.height)
 	h.worktreeFinishDialog.SetSize(h.width, h.height)
+	h.prMergeDialog.SetSize(h.width, h.height)
 	h.reviewDialog.SetSize(h.width, h.height)
 	h.sendTextDialog.SetSize(h.width, h.height)
 	h.todoDialog.SetSize(h.width, h.height-2) // -2 for header + nav tab rows above
@@ -6237,6 +6429,9 @@ func (h *Home) View() string {
 	if h.worktreeFinishDialog.IsVisible() {
 		return h.worktreeFinishDialog.View()
 	}
+	if h.prMergeDialog.IsVisible() {
+		return h.prMergeDialog.View()
+	}
 	if h.reviewDialog.IsVisible() {
 		return h.reviewDialog.View()
 	}
@@ -7098,6 +7293,7 @@ func (h *Home) renderHelpBarBulkMode() string {
 	sep := dimStyle.Render("  ·  ")
 	hint := labelStyle.Render("VISUAL") + "  " + dimStyle.Render(countStr) +
 		sep + keyStyle.Render("spc") + dimStyle.Render(":toggle") +
+		sep + keyStyle.Render("*") + dimStyle.Render(":waiting") +
 		sep + keyStyle.Render("d") + dimStyle.Render(":delete") +
 		sep + keyStyle.Render("x") + dimStyle.Render(":message") +
 		sep + keyStyle.Render("R") + dimStyle.Render(":restart") +
@@ -7424,6 +7620,7 @@ func (h *Home) renderPROverview() string {
 		renderKey("o", "Browser"),
 		renderKey("a", "Approve"),
 		renderKey("c", "Comment"),
+		renderKey("m", "Merge"),
 		renderKey("Tab", "Switch tab"),
 		renderKey("D", func() string {
 			if h.prHideDrafts {
@@ -7494,58 +7691,6 @@ func (h *Home) listItemAt(x, y int) int {
 	return idx
 }
 
-// fetchPRInfo runs "gh pr view" in the given worktree directory and returns PR
-// metadata for the branch currently checked out there. Returns a nil pr if no
-// PR exists, if gh is not installed, or if the command fails for any reason.
-func fetchPRInfo(sessionID, worktreePath, ghPath string) prFetchedMsg {
-	type ghCheck struct {
-		Status     string `json:"status"`     // QUEUED, IN_PROGRESS, COMPLETED, WAITING, PENDING, REQUESTED
-		Conclusion string `json:"conclusion"` // SUCCESS, FAILURE, CANCELLED, SKIPPED, TIMED_OUT, etc.
-	}
-	type ghPR struct {
-		Number            int       `json:"number"`
-		Title             string    `json:"title"`
-		State             string    `json:"state"`
-		IsDraft           bool      `json:"isDraft"`
-		URL               string    `json:"url"`
-		StatusCheckRollup []ghCheck `json:"statusCheckRollup"`
-	}
-	cmd := exec.Command(ghPath, "pr", "view", "--json", "number,title,state,isDraft,url,statusCheckRollup")
-	cmd.Dir = worktreePath
-	if host := ghHostFromDir(worktreePath); host != "" && host != "github.com" {
-		cmd.Env = append(os.Environ(), "GH_HOST="+host)
-	}
-	out, err := cmd.Output()
-	if err != nil {
-		return prFetchedMsg{sessionID: sessionID, pr: nil}
-	}
-	var pr ghPR
-	if err := json.Unmarshal(out, &pr); err != nil {
-		return prFetchedMsg{sessionID: sessionID, pr: nil}
-	}
-	entry := &prCacheEntry{
-		Number:    pr.Number,
-		Title:     pr.Title,
-		State:     prpkg.StateFromSearchResult(pr.State, pr.IsDraft),
-		URL:       pr.URL,
-		HasChecks: len(pr.StatusCheckRollup) > 0,
-	}
-	for _, c := range pr.StatusCheckRollup {
-		switch c.Status {
-		case "COMPLETED":
-			switch c.Conclusion {
-			case "SUCCESS", "SKIPPED", "NEUTRAL":
-				entry.ChecksPassed++
-			default: // FAILURE, CANCELLED, TIMED_OUT, ACTION_REQUIRED
-				entry.ChecksFailed++
-			}
-		default: // QUEUED, IN_PROGRESS, WAITING, PENDING, REQUESTED
-			entry.ChecksPending++
-		}
-	}
-	return prFetchedMsg{sessionID: sessionID, pr: entry}
-}
-
 // normalizeRemoteURL converts a git remote URL to a short human-readable form.
 //
 //	git@github.com:user/repo.git         -> "github: user/repo"
@@ -7696,11 +7841,10 @@ func (h *Home) handleSendTextDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			p := h.pendingPRComment
 			h.pendingPRComment = nil
 			if h.prManager != nil {
-				ghPath := h.prManager.GHPath()
-				if ghPath != "" {
+				if provider := h.prManager.ProviderFor(p.Repo); provider != nil {
 					repo, number := p.Repo, p.Number
 					return h, func() tea.Msg {
-						err := prpkg.AddComment(ghPath, repo, number, text, "", 0)
+						err := provider.Comment(repo, number, text, "", 0)
 						if err != nil {
 							return errMsg{err: err}
 						}
@@ -7719,24 +7863,15 @@ func (h *Home) handleSendTextDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.bulkSelectMode = false
 			h.selectedSessionIDs = make(map[string]bool)
 			return h, func() tea.Msg {
-				var lastErr error
+				var targets []*session.Instance
+				h.instancesMu.RLock()
 				for _, id := range targetIDs {
-					h.instancesMu.RLock()
-					inst := h.instanceByID[id]
-					h.instancesMu.RUnlock()
-					if inst == nil {
-						continue
-					}
-					tmuxSession := inst.GetTmuxSession()
-					if tmuxSession == nil {
-						continue
-					}
-					if err := tmuxSession.SendKeysAndEnter(text); err != nil {
-						lastErr = err
+					if inst := h.instanceByID[id]; inst != nil {
+						targets = append(targets, inst)
 					}
 				}
-				targetTitle := fmt.Sprintf("%d sessions", len(targetIDs))
-				return sendTextResultMsg{targetTitle: targetTitle, err: lastErr}
+				h.instancesMu.RUnlock()
+				return broadcastResultMsg{results: session.Broadcast(targets, text)}
 			}
 		}
 		if text != "" && h.sendTextTargetID != "" {
@@ -7756,6 +7891,28 @@ func (h *Home) handleSendTextDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// toggleBulkSelection selects every session matching sel, or deselects them
+// all if they are already selected.
+func (h *Home) toggleBulkSelection(sel session.BroadcastSelector) {
+	h.instancesMu.RLock()
+	matched, _ := session.SelectSessions(h.instances, sel, nil)
+	h.instancesMu.RUnlock()
+	allSelected := len(matched) > 0
+	for _, inst := range matched {
+		if !h.selectedSessionIDs[inst.ID] {
+			allSelected = false
+			break
+		}
+	}
+	for _, inst := range matched {
+		if allSelected {
+			delete(h.selectedSessionIDs, inst.ID)
+		} else {
+			h.selectedSessionIDs[inst.ID] = true
+		}
+	}
+}
+
 // sendTextToSession returns a tea.Cmd that sends text to the session with the given ID.
 func (h *Home) sendTextToSession(targetID, text string) tea.Cmd {
 	return func() tea.Msg {
@@ -7805,6 +7962,7 @@ func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd
 	case "confirm":
 		// Execute the finish operation
 		keepBranch := h.worktreeFinishDialog.GetOptions()
+		viaPR, autoMerge := h.worktreeFinishDialog.GetPROptions()
 		h.worktreeFinishDialog.SetExecuting(true)
 
 		sid := h.worktreeFinishDialog.sessionID
@@ -7818,12 +7976,59 @@ func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		inst := h.instanceByID[sid]
 		h.instancesMu.RUnlock()
 
-		return h, h.finishWorktree(inst, sid, sTitle, branch, repoRoot, wtPath, keepBranch)
+		if viaPR {
+			return h, h.finishWorktreeViaPR(inst, keepBranch, autoMerge)
+		}
+		return h, h.finishWorktree(inst, sid, sTitle, branch, repoRoot, wtPath, keepBranch, false)
 	}
 
 	return h, nil
 }
 
+// handlePRMergeDialogKey processes key events for the PR merge dialog
+func (h *Home) handlePRMergeDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := h.prMergeDialog.HandleKey(msg.String())
+	if action != "confirm" && action != "disable_auto" {
+		return h, nil
+	}
+	p := h.prMergeDialog.PR()
+	method, auto, deleteBranch := h.prMergeDialog.GetOptions()
+	h.prMergeDialog.Hide()
+	if p == nil || h.prManager == nil {
+		return h, nil
+	}
+	repo, number := p.Repo, p.Number
+	provider := h.prManager.ProviderFor(repo)
+	if provider == nil {
+		return h, nil
+	}
+	switch {
+	case action == "disable_auto":
+		h.setError(fmt.Errorf("Disabling auto-merge…"))
+		return h, func() tea.Msg {
+			if err := provider.DisableAutoMerge(repo, number); err != nil {
+				return errMsg{err: err}
+			}
+			return prActionResultMsg{action: "disable auto-merge", repo: repo, number: number}
+		}
+	case auto:
+		h.setError(fmt.Errorf("Enabling auto-merge…"))
+		return h, func() tea.Msg {
+			if err := provider.EnableAutoMerge(repo, number, method, deleteBranch); err != nil {
+				return errMsg{err: err}
+			}
+			return prActionResultMsg{action: "auto-merge", repo: repo, number: number}
+		}
+	}
+	h.setError(fmt.Errorf("Merging PR…"))
+	return h, func() tea.Msg {
+		if err := provider.Merge(repo, number, method, deleteBranch); err != nil {
+			return errMsg{err: err}
+		}
+		return prActionResultMsg{action: "merge", repo: repo, number: number}
+	}
+}
+
 // getReviewContext derives the project repo directory, display name, and group path
 // from the currently selected sidebar item. Returns empty strings if no project context
 // can be determined.
@@ -7904,19 +8109,19 @@ func (h *Home) startPRReviewSession(p *prpkg.PR) tea.Cmd {
 	}
 	// HeadBranch is not populated by gh search prs — resolve it asynchronously.
 	if p.HeadBranch == "" {
-		ghPath := ""
+		var provider prpkg.Provider
 		if h.prManager != nil {
-			ghPath = h.prManager.GHPath()
+			provider = h.prManager.ProviderFor(p.Repo)
 		}
-		if ghPath == "" {
-			h.setError(fmt.Errorf("gh not available — cannot fetch PR branch"))
+		if provider == nil {
+			h.setError(fmt.Errorf("gh/glab not available — cannot fetch PR branch"))
 			return nil
 		}
 		h.pendingPRReview = p
 		h.setError(fmt.Errorf("Fetching PR branch info…"))
 		repo, number := p.Repo, p.Number
 		return func() tea.Msg {
-			detail, err := prpkg.FetchDetail(ghPath, repo, number)
+			detail, err := provider.Detail(repo, number)
 			if err != nil {
 				return prReviewResolveBranchMsg{err: err}
 			}
@@ -7951,6 +8156,51 @@ func (h *Home) startPRReviewSession(p *prpkg.PR) tea.Cmd {
 	return nil
 }
 
+// addressPRFeedback sends prompt to the session linked to p, restarting it if
+// it has stopped. Without a linked session it starts a worktree session on
+// the PR's branch in the project matching its repo, like startPRReviewSession.
+func (h *Home) addressPRFeedback(p *prpkg.PR, prompt string) tea.Cmd {
+	if inst := h.getInstanceByID(h.prManager.SessionForPR(p.Repo, p.Number)); inst != nil {
+		h.setError(fmt.Errorf("Sending PR #%d feedback to %s…", p.Number, inst.Title))
+		return func() tea.Msg {
+			send := inst.SendText
+			if !inst.Exists() {
+				if err := inst.Restart(); err != nil {
+					return errMsg{err: fmt.Errorf("restart %s: %w", inst.Title, err)}
+				}
+				// Wait for the restarted agent to load before sending.
+				send = inst.SendMessageWhenReady
+			}
+			if err := send(prompt); err != nil {
+				return errMsg{err: fmt.Errorf("send PR feedback: %w", err)}
+			}
+			return errMsg{err: fmt.Errorf("Sent PR #%d feedback to %s", p.Number, inst.Title)}
+		}
+	}
+	if p.HeadBranch == "" {
+		h.setError(fmt.Errorf("PR #%d branch unknown — open its detail first", p.Number))
+		return nil
+	}
+	projects, err := session.LoadProjects()
+	if err == nil {
+		for _, proj := range projects {
+			if proj.BaseDir == "" || prpkg.RepoFromDir(proj.BaseDir) != p.Repo {
+				continue
+			}
+			sessionName := fmt.Sprintf("feedback/pr-%s", prpkg.NumberStr(p.Number))
+			h.pendingWorktrees = append(h.pendingWorktrees, pendingWorktreeItem{
+				branchName: sessionName,
+				groupPath:  proj.Name,
+				startedAt:  time.Now(),
+			})
+			h.viewMode = ""
+			return h.createReviewSession(proj.BaseDir, p.HeadBranch, sessionName, proj.Name, prompt)
+		}
+	}
+	h.setError(fmt.Errorf("no session is linked to PR #%d and no project matches %s", p.Number, p.Repo))
+	return nil
+}
+
 // createReviewSession fetches the branch, creates a worktree, and starts a Claude session.
 func (h *Home) createReviewSession(
 	repoDir, branch, sessionName, groupPath, initialPrompt string,
@@ -8001,7 +8251,9 @@ func (h *Home) createReviewSession(
 
 // finishWorktree performs the worktree finish operation asynchronously:
 // remove worktree, delete branch, kill session, remove from storage
-func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, branchName, repoRoot, worktreePath string, keepBranch bool) tea.Cmd {
+// viaPR marks a teardown after the session's PR merged; the branch is then
+// force-deleted since squash and rebase merges leave it unmerged locally.
+func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, branchName, repoRoot, worktreePath string, keepBranch, viaPR bool) tea.Cmd {
 	return func() tea.Msg {
 		// Step 1: Remove worktree
 		if _, statErr := os.Stat(worktreePath); !os.IsNotExist(statErr) {
@@ -8011,7 +8263,7 @@ func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, b
 
 		// Step 2: Delete branch (if not keeping)
 		if !keepBranch {
-			_ = git.DeleteBranch(repoRoot, branchName, false)
+			_ = git.DeleteBranch(repoRoot, branchName, viaPR)
 		}
 
 		// Step 3: Kill tmux session
@@ -8022,10 +8274,31 @@ func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, b
 		return worktreeFinishResultMsg{
 			sessionID:    sessionID,
 			sessionTitle: sessionTitle,
+			viaPR:        viaPR,
 		}
 	}
 }
 
+// finishWorktreeViaPR pushes the session's branch and opens a PR for it
+// asynchronously. The worktree is torn down later, when the finish watcher
+// sees the PR merged.
+func (h *Home) finishWorktreeViaPR(inst *session.Instance, keepBranch, autoMerge bool) tea.Cmd {
+	if inst == nil {
+		return nil
+	}
+	ghPath := ""
+	if h.prManager != nil {
+		ghPath = h.prManager.GHPath()
+	}
+	return func() tea.Msg {
+		p, err := prpkg.FinishViaPR(ghPath, h.storage, inst, prpkg.FinishOptions{
+			AutoMerge:  autoMerge,
+			KeepBranch: keepBranch,
+		})
+		return worktreePRFinishResultMsg{sessionID: inst.ID, sessionTitle: inst.Title, pr: p, err: err}
+	}
+}
+
 // getOtherActiveSessions returns sessions excluding the given ID and error-status sessions.
 func (h *Home) getOtherActiveSessions(excludeID string) []*session.Instance {
 	var result []*session.Instance
@@ -8238,6 +8511,33 @@ func (h *Home) handleTodoDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.todoDialog.SetTodos(todos)
 		}
 
+	case TodoActionSaveDeps:
+		todo := h.todoDialog.SelectedTodo()
+		if todo != nil {
+			blockedBy, autoStart := h.todoDialog.GetDepsValues()
+			todos, err := h.storage.LoadAllTodos()
+			if err != nil {
+				h.setError(fmt.Errorf("reload todos: %w", err))
+				return h, nil
+			}
+			if err := session.ValidateTodoBlockers(todo, blockedBy, todos); err != nil {
+				h.setError(fmt.Errorf("dependencies: %w", err))
+				return h, nil
+			}
+			todo.BlockedBy = blockedBy
+			todo.AutoStart = autoStart
+			if err := h.storage.SaveTodo(todo); err != nil {
+				h.setError(fmt.Errorf("update todo: %w", err))
+				return h, nil
+			}
+			todos, err = h.storage.LoadAllTodos()
+			if err != nil {
+				h.setError(fmt.Errorf("reload todos: %w", err))
+				return h, nil
+			}
+			h.todoDialog.SetTodos(todos)
+		}
+
 	case TodoActionUpdateStatus:
 		todo := h.todoDialog.SelectedTodo()
 		if todo != nil {
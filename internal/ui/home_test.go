@@ -1905,6 +1905,49 @@ func TestBulkSelectMode_XKeyOpensSendDialog(t *testing.T) {
 	}
 }
 
+func TestBulkSelectMode_SelectGroupAndWaiting(t *testing.T) {
+	home := NewHome()
+	home.width = 100
+	home.height = 30
+	home.initialLoading = false
+
+	inst1 := &session.Instance{ID: "id-1", Title: "sess-1", Tool: "claude", GroupPath: "work", Status: session.StatusWaiting}
+	inst2 := &session.Instance{ID: "id-2", Title: "sess-2", Tool: "claude", GroupPath: "work", Status: session.StatusRunning}
+	inst3 := &session.Instance{ID: "id-3", Title: "sess-3", Tool: "claude", GroupPath: "play", Status: session.StatusWaiting}
+	home.instancesMu.Lock()
+	home.instances = []*session.Instance{inst1, inst2, inst3}
+	home.instanceByID = map[string]*session.Instance{"id-1": inst1, "id-2": inst2, "id-3": inst3}
+	home.instancesMu.Unlock()
+	home.groupTree = session.NewGroupTree(home.instances)
+	home.rebuildFlatItems()
+	for i, item := range home.flatItems {
+		if item.Type == session.ItemTypeGroup && item.Path == "work" {
+			home.cursor = i
+			break
+		}
+	}
+	home.bulkSelectMode = true
+	home.selectedSessionIDs = map[string]bool{}
+
+	space := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+	model, _ := home.Update(space)
+	h := model.(*Home)
+	if len(h.selectedSessionIDs) != 2 || !h.selectedSessionIDs["id-1"] || !h.selectedSessionIDs["id-2"] {
+		t.Fatalf("space on group selected %v, want id-1 and id-2", h.selectedSessionIDs)
+	}
+	model, _ = h.Update(space)
+	h = model.(*Home)
+	if len(h.selectedSessionIDs) != 0 {
+		t.Fatalf("second space on group should deselect, got %v", h.selectedSessionIDs)
+	}
+
+	model, _ = h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	h = model.(*Home)
+	if len(h.selectedSessionIDs) != 2 || !h.selectedSessionIDs["id-1"] || !h.selectedSessionIDs["id-3"] {
+		t.Errorf("* selected %v, want id-1 and id-3", h.selectedSessionIDs)
+	}
+}
+
 func TestBulkSelectMode_XKeyFallsThrough_WhenNoSelections(t *testing.T) {
 	home := NewHome()
 	home.width = 100
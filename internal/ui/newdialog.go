@@ -37,6 +37,10 @@ type NewDialog struct {
 	// Inline validation error displayed inside the dialog
 	validationErr string
 	pathCycler    session.CompletionCycler // Path autocomplete state
+	// Session templates from config.toml; index 0 is "no template"
+	templateNames  []string
+	templateCursor int
+	template       *session.SessionTemplate
 }
 
 // buildPresetCommands returns the list of commands for the picker,
@@ -133,6 +137,9 @@ func (d *NewDialog) ShowInGroup(groupPath, groupName, defaultPath string) {
 	if userConfig, err := session.LoadUserConfig(); err == nil && userConfig != nil {
 		d.claudeOptions.SetDefaults(userConfig)
 	}
+	d.templateNames = append([]string{""}, session.GetSessionTemplateNames()...)
+	d.templateCursor = 0
+	d.template = nil
 	// Project is always determined by cursor context — no picker step needed.
 	d.focusIndex = 0
 	d.nameInput.Focus()
@@ -171,11 +178,77 @@ func (d *NewDialog) SetDefaultTool(tool string) {
 	d.updateToolOptions()
 }
 
-// GetSelectedGroup returns the parent group path
+// GetSelectedGroup returns the parent group path, or the selected
+// template's group when it sets one
 func (d *NewDialog) GetSelectedGroup() string {
+	if d.template != nil && d.template.Group != "" {
+		return d.template.Group
+	}
 	return d.parentGroupPath
 }
 
+// GetTemplate returns the selected session template, or nil for none
+func (d *NewDialog) GetTemplate() *session.SessionTemplate {
+	return d.template
+}
+
+// cycleTemplate selects the next configured template (wrapping to none)
+func (d *NewDialog) cycleTemplate() {
+	if len(d.templateNames) <= 1 {
+		return
+	}
+	d.templateCursor = (d.templateCursor + 1) % len(d.templateNames)
+	name := d.templateNames[d.templateCursor]
+	if name == "" {
+		d.applyTemplate(nil)
+		return
+	}
+	tmpl, err := session.GetSessionTemplate(name)
+	if err != nil {
+		d.SetError(err.Error())
+		return
+	}
+	d.applyTemplate(tmpl)
+}
+
+// applyTemplate fills the dialog's tool, worktree and Claude option fields
+// from tmpl. A nil template restores the config defaults.
+func (d *NewDialog) applyTemplate(tmpl *session.SessionTemplate) {
+	d.template = tmpl
+	d.validationErr = ""
+	d.commandInput.SetValue("")
+
+	opts := &session.ClaudeOptions{}
+	if userConfig, err := session.LoadUserConfig(); err == nil && userConfig != nil {
+		opts = session.NewClaudeOptions(userConfig)
+	}
+	if tmpl == nil {
+		d.SetDefaultTool(session.GetDefaultTool())
+		d.claudeOptions.SetOptions(opts)
+		return
+	}
+
+	if tmpl.Tool != "" {
+		d.SetDefaultTool(tmpl.Tool)
+		if d.GetSelectedCommand() != tmpl.Tool {
+			// Not a preset: run it as a custom shell command
+			d.commandCursor = 0
+			d.commandInput.SetValue(tmpl.Tool)
+		}
+	}
+	d.updateToolOptions()
+	if tmpl.Worktree != nil && *tmpl.Worktree != d.worktreeEnabled {
+		d.ToggleWorktree()
+	}
+	tmpl.ApplyClaudeOptions(opts)
+	d.claudeOptions.SetOptions(opts)
+
+	if d.focusIndex > d.getMaxFocusIndex() {
+		d.focusIndex = d.getMaxFocusIndex()
+	}
+	d.updateFocus()
+}
+
 // SetSize sets the dialog dimensions
 func (d *NewDialog) SetSize(width, height int) {
 	d.width = width
@@ -495,6 +568,10 @@ func (d *NewDialog) Update(msg tea.Msg) (*NewDialog, tea.Cmd) {
 			d.Hide()
 			return d, nil
 
+		case "ctrl+t":
+			d.cycleTemplate()
+			return d, nil
+
 		case "enter":
 			// Let parent handle enter (create session)
 			return d, nil
@@ -644,7 +721,19 @@ func (d *NewDialog) View() string {
 	content.WriteString("\n")
 	groupInfoStyle := lipgloss.NewStyle().Foreground(ColorPurple) // Purple for project context
 	content.WriteString(groupInfoStyle.Render("  in project: " + d.parentGroupName))
-	content.WriteString("\n\n")
+	content.WriteString("\n")
+	if len(d.templateNames) > 1 {
+		templateName := "none"
+		if d.templateCursor > 0 {
+			templateName = d.templateNames[d.templateCursor]
+			if d.template != nil && d.template.Group != "" {
+				templateName += " → group " + d.template.Group
+			}
+		}
+		content.WriteString(labelStyle.Render("  Template: " + templateName + " (Ctrl+T)"))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
 
 	// Name input
 	if d.focusIndex == 0 {
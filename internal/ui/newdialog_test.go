@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sjoeboo/hangar/internal/session"
 )
 
 func TestNewNewDialog(t *testing.T) {
@@ -802,3 +803,43 @@ func TestNewDialog_ShowInGroup_WorktreeEnabledByDefault(t *testing.T) {
 		t.Error("expected worktreeEnabled=true after ShowInGroup, got false")
 	}
 }
+
+func TestNewDialog_ApplyTemplate(t *testing.T) {
+	d := NewNewDialog()
+	d.Show()
+	d.worktreeEnabled = false
+
+	worktree := true
+	skip := true
+	d.applyTemplate(&session.SessionTemplate{
+		Tool:     "claude",
+		Group:    "bugs",
+		Worktree: &worktree,
+		Claude:   session.TemplateClaudeOptions{SkipPermissions: &skip, UseChrome: true},
+	})
+
+	if d.GetSelectedCommand() != "claude" {
+		t.Errorf("command = %q, want claude", d.GetSelectedCommand())
+	}
+	if !d.IsWorktreeEnabled() {
+		t.Error("template worktree = true should enable worktree")
+	}
+	if d.GetSelectedGroup() != "bugs" {
+		t.Errorf("group = %q, want bugs", d.GetSelectedGroup())
+	}
+	opts := d.GetClaudeOptions()
+	if opts == nil || !opts.SkipPermissions || !opts.UseChrome {
+		t.Errorf("claude options = %+v", opts)
+	}
+
+	// A tool that isn't a preset becomes a custom command.
+	d.applyTemplate(&session.SessionTemplate{Tool: "my-script --fast"})
+	if _, _, command := d.GetValues(); command != "my-script --fast" {
+		t.Errorf("custom command = %q", command)
+	}
+
+	d.applyTemplate(nil)
+	if d.GetTemplate() != nil || d.GetSelectedGroup() != "default" {
+		t.Errorf("clearing template: template=%v group=%q", d.GetTemplate(), d.GetSelectedGroup())
+	}
+}
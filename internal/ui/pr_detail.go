@@ -200,7 +200,7 @@ func (o *PRDetailOverlay) View() string {
 	}
 
 	b.WriteString(sep + "\n")
-	hint := "  Tab/Shift+Tab switch tab · j/k scroll · g/G top/bottom · o browser · a approve · c comment · s review · q close"
+	hint := "  Tab/Shift+Tab switch tab · j/k scroll · g/G top/bottom · o browser · a approve · c comment · s review · F feedback · q close"
 	if o.tab == 2 && len(o.diffFiles) > 0 {
 		hint = "  j/k navigate files · enter toggle · d/u half-page scroll · g/G top/bottom · o browser · q close"
 	}
@@ -297,6 +297,17 @@ func (o *PRDetailOverlay) HandleKey(key string) (bool, tea.Cmd) {
 			return true, func() tea.Msg { return prDetailCreateReviewMsg{pr: pr} }
 		}
 		return true, nil
+	case "F":
+		if o.pr != nil {
+			// Like 's', a new session needs the detail's HeadBranch.
+			pr := o.pr
+			if o.detail != nil && o.detail.HeadBranch != "" {
+				prCopy := o.detail.PR
+				pr = &prCopy
+			}
+			return true, func() tea.Msg { return prDetailAddressFeedbackMsg{pr: pr} }
+		}
+		return true, nil
 	case "tab":
 		o.tab = (o.tab + 1) % 4
 		o.scrollOffset = 0
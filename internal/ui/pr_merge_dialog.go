@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	prpkg "github.com/sjoeboo/hangar/internal/pr"
+)
+
+// PRMergeDialog handles merging a PR from the PR overview:
+// Step 0: Choose merge method, auto-merge and branch deletion
+// Step 1: Confirm, with any failing or pending checks called out
+type PRMergeDialog struct {
+	visible bool
+	width   int
+	height  int
+
+	pr      *prpkg.PR
+	detail  *prpkg.PRDetail // nil until the fresh detail fetch returns
+	loadErr string
+
+	// Options (step 0)
+	methodIdx    int // index into prpkg.MergeMethods
+	auto         bool
+	deleteBranch bool
+
+	// Dialog state
+	step int // 0=options, 1=confirm
+}
+
+// NewPRMergeDialog creates a new PR merge dialog
+func NewPRMergeDialog() *PRMergeDialog {
+	return &PRMergeDialog{}
+}
+
+// Show displays the dialog for p. Call SetDetail once its detail is fetched.
+func (d *PRMergeDialog) Show(p *prpkg.PR) {
+	d.visible = true
+	d.pr = p
+	d.detail = nil
+	d.loadErr = ""
+	d.methodIdx = 0
+	d.auto = false
+	d.deleteBranch = true
+	d.step = 0
+}
+
+// Hide hides the dialog
+func (d *PRMergeDialog) Hide() {
+	d.visible = false
+	d.pr = nil
+	d.detail = nil
+}
+
+// IsVisible returns whether the dialog is visible
+func (d *PRMergeDialog) IsVisible() bool {
+	return d.visible
+}
+
+// SetSize sets the dialog dimensions for centering
+func (d *PRMergeDialog) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// SetDetail stores the freshly fetched detail the merge is judged on.
+func (d *PRMergeDialog) SetDetail(detail *prpkg.PRDetail, err error) {
+	d.detail = detail
+	d.loadErr = ""
+	if err != nil {
+		d.loadErr = err.Error()
+	}
+}
+
+// PR returns the PR the dialog is for
+func (d *PRMergeDialog) PR() *prpkg.PR {
+	return d.pr
+}
+
+// GetOptions returns the chosen merge method, whether to enable auto-merge
+// instead of merging now, and whether to delete the head branch.
+func (d *PRMergeDialog) GetOptions() (method string, auto, deleteBranch bool) {
+	return prpkg.MergeMethods[d.methodIdx], d.auto, d.deleteBranch
+}
+
+// check returns MergeCheck's verdict, or a blocker while the detail is
+// missing.
+func (d *PRMergeDialog) check() (blocker, warning string) {
+	if d.detail == nil {
+		if d.loadErr != "" {
+			return "could not load PR: " + d.loadErr, ""
+		}
+		return "checking mergeability...", ""
+	}
+	return prpkg.MergeCheck(d.detail, d.auto)
+}
+
+// HandleKey processes a key event and returns the action to take:
+// "close", "confirm", "disable_auto" or "".
+func (d *PRMergeDialog) HandleKey(key string) (action string) {
+	if d.step == 1 {
+		switch key {
+		case "y":
+			return "confirm"
+		case "n", "esc":
+			d.step = 0
+		}
+		return ""
+	}
+
+	switch key {
+	case "esc", "q":
+		d.Hide()
+		return "close"
+	case "m", "tab":
+		d.methodIdx = (d.methodIdx + 1) % len(prpkg.MergeMethods)
+	case "a":
+		d.auto = !d.auto
+	case " ":
+		d.deleteBranch = !d.deleteBranch
+	case "x":
+		if d.detail != nil && d.detail.AutoMerge != "" {
+			return "disable_auto"
+		}
+	case "enter":
+		if blocker, _ := d.check(); blocker == "" {
+			d.step = 1
+		}
+	}
+	return ""
+}
+
+// View renders the dialog
+func (d *PRMergeDialog) View() string {
+	if !d.visible || d.pr == nil {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorCyan)
+	labelStyle := lipgloss.NewStyle().Foreground(ColorText)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorAccent)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	errStyle := lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+
+	dialogWidth := 56
+	if d.width > 0 && d.width < dialogWidth+10 {
+		dialogWidth = d.width - 10
+		if dialogWidth < 35 {
+			dialogWidth = 35
+		}
+	}
+	blocker, warning := d.check()
+	boxBorder := ColorAccent
+	if blocker != "" && d.detail != nil {
+		boxBorder = ColorRed
+	}
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(boxBorder).
+		Padding(1, 2).
+		Width(dialogWidth)
+
+	method, auto, deleteBranch := d.GetOptions()
+	var b strings.Builder
+
+	if d.step == 1 {
+		b.WriteString(titleStyle.Render("Confirm"))
+		b.WriteString("\n\n")
+		b.WriteString(labelStyle.Render("  This will:"))
+		b.WriteString("\n")
+		if auto {
+			b.WriteString(labelStyle.Render(fmt.Sprintf("  • Enable auto-merge (%s) on #%d", method, d.pr.Number)))
+			b.WriteString("\n")
+			b.WriteString(labelStyle.Render("    GitHub merges it once reviews and checks pass"))
+		} else {
+			b.WriteString(labelStyle.Render(fmt.Sprintf("  • %s #%d into %s", mergeMethodVerb(method), d.pr.Number, d.detail.BaseBranch)))
+		}
+		b.WriteString("\n")
+		if deleteBranch {
+			b.WriteString(labelStyle.Render(fmt.Sprintf("  • Delete branch %s", d.detail.HeadBranch)))
+			b.WriteString("\n")
+		}
+		if warning != "" {
+			b.WriteString("\n")
+			b.WriteString(warnStyle.Render("  ⚠ " + warning))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render("y confirm | n back"))
+		return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+	}
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Merge PR #%d", d.pr.Number)))
+	b.WriteString("\n\n")
+	title := d.pr.Title
+	if maxTitle := dialogWidth - 8; maxTitle > 0 && len([]rune(title)) > maxTitle {
+		title = string([]rune(title)[:maxTitle]) + "…"
+	}
+	b.WriteString(labelStyle.Render("  " + title))
+	b.WriteString("\n")
+	if d.detail != nil {
+		b.WriteString(labelStyle.Render("  Branch:  "))
+		b.WriteString(valueStyle.Render(d.detail.BaseBranch + " ← " + d.detail.HeadBranch))
+		b.WriteString("\n")
+		if d.detail.HasChecks {
+			b.WriteString(labelStyle.Render("  Checks:  "))
+			var parts []string
+			if d.detail.ChecksPassed > 0 {
+				parts = append(parts, lipgloss.NewStyle().Foreground(ColorGreen).Render(fmt.Sprintf("✓ %d passed", d.detail.ChecksPassed)))
+			}
+			if d.detail.ChecksFailed > 0 {
+				parts = append(parts, lipgloss.NewStyle().Foreground(ColorRed).Render(fmt.Sprintf("✗ %d failed", d.detail.ChecksFailed)))
+			}
+			if d.detail.ChecksPending > 0 {
+				parts = append(parts, lipgloss.NewStyle().Foreground(ColorYellow).Render(fmt.Sprintf("⟳ %d pending", d.detail.ChecksPending)))
+			}
+			b.WriteString(strings.Join(parts, "  "))
+			b.WriteString("\n")
+		}
+		if d.detail.AutoMerge != "" {
+			b.WriteString(labelStyle.Render("  Auto:    "))
+			b.WriteString(valueStyle.Render("auto-merge on (" + d.detail.AutoMerge + ")"))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString(labelStyle.Render("  Method:  "))
+	for i, m := range prpkg.MergeMethods {
+		if i > 0 {
+			b.WriteString(labelStyle.Render(" · "))
+		}
+		if i == d.methodIdx {
+			b.WriteString(valueStyle.Bold(true).Render(m))
+		} else {
+			b.WriteString(footerStyle.Render(m))
+		}
+	}
+	b.WriteString("\n")
+	autoCheck, deleteCheck := "[ ]", "[ ]"
+	if auto {
+		autoCheck = "[x]"
+	}
+	if deleteBranch {
+		deleteCheck = "[x]"
+	}
+	b.WriteString(labelStyle.Render(fmt.Sprintf("  %s Auto-merge when checks pass", autoCheck)))
+	b.WriteString("\n")
+	b.WriteString(labelStyle.Render(fmt.Sprintf("  %s Delete branch after merge", deleteCheck)))
+	b.WriteString("\n")
+
+	switch {
+	case blocker != "" && d.detail == nil && d.loadErr == "":
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render("  " + blocker))
+		b.WriteString("\n")
+	case blocker != "":
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render("  ✗ " + blocker))
+		b.WriteString("\n")
+	case warning != "":
+		b.WriteString("\n")
+		b.WriteString(warnStyle.Render("  ⚠ " + warning))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	hint := "m method | a auto | Space branch | Enter merge | Esc cancel"
+	if d.detail != nil && d.detail.AutoMerge != "" {
+		hint = "m method | a auto | Space branch | x auto off | Enter | Esc"
+	}
+	b.WriteString(footerStyle.Render(hint))
+
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
+
+// mergeMethodVerb describes merging with method, e.g. "Squash-merge".
+func mergeMethodVerb(method string) string {
+	switch method {
+	case "squash":
+		return "Squash-merge"
+	case "rebase":
+		return "Rebase-merge"
+	}
+	return "Merge"
+}
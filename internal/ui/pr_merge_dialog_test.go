@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	prpkg "github.com/sjoeboo/hangar/internal/pr"
+)
+
+func TestPRMergeDialog_Confirm(t *testing.T) {
+	d := NewPRMergeDialog()
+	d.SetSize(120, 40)
+	p := &prpkg.PR{Number: 42, Title: "Add user auth", Repo: "o/r", State: "OPEN"}
+	d.Show(p)
+
+	// Nothing can be merged until the detail arrives.
+	if d.HandleKey("enter"); d.step != 0 {
+		t.Fatal("enter advanced before the detail loaded")
+	}
+	if view := d.View(); !strings.Contains(view, "checking mergeability") {
+		t.Errorf("expected loading state, got:\n%s", view)
+	}
+
+	d.SetDetail(&prpkg.PRDetail{
+		PR:           prpkg.PR{Number: 42, State: "OPEN", BaseBranch: "main", HeadBranch: "feat/auth", HasChecks: true, ChecksFailed: 1},
+		Mergeability: "MERGEABLE",
+	}, nil)
+	if view := d.View(); !strings.Contains(view, "1 check failing") {
+		t.Errorf("expected failing-check warning, got:\n%s", view)
+	}
+
+	d.HandleKey("m") // squash → merge
+	d.HandleKey(" ") // keep the branch
+	d.HandleKey("enter")
+	if view := d.View(); !strings.Contains(view, "Merge #42 into main") || strings.Contains(view, "Delete branch") {
+		t.Errorf("confirm view wrong:\n%s", view)
+	}
+	if action := d.HandleKey("y"); action != "confirm" {
+		t.Fatalf("y = %q, want confirm", action)
+	}
+	method, auto, deleteBranch := d.GetOptions()
+	if method != "merge" || auto || deleteBranch {
+		t.Errorf("options = %s, %v, %v", method, auto, deleteBranch)
+	}
+	if d.PR() != p {
+		t.Error("PR() lost the PR")
+	}
+}
+
+func TestPRMergeDialog_Blocked(t *testing.T) {
+	d := NewPRMergeDialog()
+	d.SetSize(120, 40)
+	d.Show(&prpkg.PR{Number: 7, State: "OPEN"})
+	d.SetDetail(&prpkg.PRDetail{PR: prpkg.PR{Number: 7, State: "OPEN", BaseBranch: "main"}, Mergeability: "CONFLICTING", AutoMerge: "squash"}, nil)
+
+	d.HandleKey("enter")
+	if d.step != 0 {
+		t.Error("a conflicting PR reached the confirm step")
+	}
+	if view := d.View(); !strings.Contains(view, "merge conflicts") || !strings.Contains(view, "x auto off") {
+		t.Errorf("blocked view wrong:\n%s", view)
+	}
+	if action := d.HandleKey("x"); action != "disable_auto" {
+		t.Errorf("x = %q, want disable_auto", action)
+	}
+	if action := d.HandleKey("esc"); action != "close" || d.IsVisible() {
+		t.Errorf("esc = %q, visible %v", action, d.IsVisible())
+	}
+}
@@ -63,6 +63,7 @@ const (
 	todoModeStatus                       // status picker
 	todoModeProjectFilter                // project filter picker (f key)
 	todoModeNewProject                   // project picker before new-todo form (n key, all-projects view)
+	todoModeDeps                         // dependency picker (b key)
 )
 
 // TodoDialog shows and manages todos for a project.
@@ -100,6 +101,12 @@ type TodoDialog struct {
 	filterProjects   []string // unique project paths with todos (populated on Show/SetTodos)
 	allProjectPaths  []string // all known project paths (set by home via SetAllProjects)
 	filterCursor     int      // cursor in the picker list (reused for new-project picker)
+
+	// dependency picker
+	depsCandidates []*session.Todo // other todos in the selected todo's project
+	depsChecked    map[string]bool // todo ID -> blocks the selected todo
+	depsAutoStart  bool
+	depsCursor     int
 }
 
 // NewTodoDialog creates a new TodoDialog.
@@ -343,6 +350,14 @@ func todoStatusStyle(status session.TodoStatus) lipgloss.Style {
 	}
 }
 
+// Markers for todo dependencies on kanban cards and in the detail panel.
+const (
+	todoBlockedIcon   = "⊘"
+	todoAutoStartIcon = "↻"
+)
+
+var todoBlockedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff8700"))
+
 // TodoAction is returned by HandleKey to signal what the caller should do.
 type TodoAction int
 
@@ -355,6 +370,7 @@ const (
 	TodoActionUpdateStatus             // update status of selected todo (caller reads GetPickedStatus)
 	TodoActionMoveCardLeft             // shift+left: move selected card to previous status column
 	TodoActionMoveCardRight            // shift+right: move selected card to next status column
+	TodoActionSaveDeps                 // save dependencies of selected todo (caller reads GetDepsValues)
 )
 
 // GetFormValues returns the title, description, prompt, editing ID, and status after TodoActionSaveTodo.
@@ -366,6 +382,17 @@ func (d *TodoDialog) GetFormValues() (title, description, prompt, editingID stri
 		d.newTodoStatus
 }
 
+// GetDepsValues returns the blocker IDs (in board order) and auto-start flag
+// chosen in the dependency picker after TodoActionSaveDeps.
+func (d *TodoDialog) GetDepsValues() (blockedBy []string, autoStart bool) {
+	for _, t := range d.depsCandidates {
+		if d.depsChecked[t.ID] {
+			blockedBy = append(blockedBy, t.ID)
+		}
+	}
+	return blockedBy, d.depsAutoStart
+}
+
 // GetPickedStatus returns the status chosen in the status picker.
 func (d *TodoDialog) GetPickedStatus() session.TodoStatus {
 	if d.statusCursor < len(d.statusOptions) {
@@ -387,6 +414,8 @@ func (d *TodoDialog) HandleKey(msg tea.KeyMsg) TodoAction {
 		return d.handleProjectFilterKey(msg.String())
 	case todoModeNewProject:
 		return d.handleNewProjectKey(msg.String())
+	case todoModeDeps:
+		return d.handleDepsKey(msg.String())
 	}
 	return TodoActionNone
 }
@@ -514,6 +543,50 @@ func (d *TodoDialog) handleStatusKey(key string) TodoAction {
 	return TodoActionNone
 }
 
+func (d *TodoDialog) handleDepsKey(key string) TodoAction {
+	switch key {
+	case "up", "k":
+		if d.depsCursor > 0 {
+			d.depsCursor--
+		}
+	case "down", "j":
+		if d.depsCursor < len(d.depsCandidates)-1 {
+			d.depsCursor++
+		}
+	case " ", "x":
+		if d.depsCursor < len(d.depsCandidates) {
+			id := d.depsCandidates[d.depsCursor].ID
+			d.depsChecked[id] = !d.depsChecked[id]
+		}
+	case "a":
+		d.depsAutoStart = !d.depsAutoStart
+	case "enter":
+		d.mode = todoModeKanban
+		return TodoActionSaveDeps
+	case "esc", "b":
+		d.mode = todoModeKanban
+	}
+	return TodoActionNone
+}
+
+// openDepsPicker opens the dependency picker for t, listing the other todos
+// in its project.
+func (d *TodoDialog) openDepsPicker(t *session.Todo) {
+	d.mode = todoModeDeps
+	d.depsCandidates = nil
+	for _, o := range d.todos {
+		if o.ID != t.ID && o.ProjectPath == t.ProjectPath {
+			d.depsCandidates = append(d.depsCandidates, o)
+		}
+	}
+	d.depsChecked = make(map[string]bool, len(t.BlockedBy))
+	for _, id := range t.BlockedBy {
+		d.depsChecked[id] = true
+	}
+	d.depsAutoStart = t.AutoStart
+	d.depsCursor = 0
+}
+
 func (d *TodoDialog) openNewForm() {
 	d.mode = todoModeNew
 	d.editingID = ""
@@ -593,6 +666,8 @@ func (d *TodoDialog) View() string {
 		return d.viewProjectFilter()
 	case todoModeNewProject:
 		return d.viewNewProjectPicker()
+	case todoModeDeps:
+		return d.viewDepsPicker()
 	default:
 		return d.viewKanban()
 	}
@@ -759,6 +834,49 @@ func (d *TodoDialog) viewStatusPicker() string {
 		borderStyle.Render(content))
 }
 
+func (d *TodoDialog) viewDepsPicker() string {
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#5fd7ff")).
+		Padding(1, 2).
+		Width(60)
+
+	title := "Dependencies"
+	if t := d.SelectedTodo(); t != nil {
+		title += ": " + t.Title
+	}
+	header := lipgloss.NewStyle().Bold(true).Render(title)
+	sub := lipgloss.NewStyle().Foreground(lipgloss.Color("#5a6a7a")).Render("Blocked by:")
+
+	var rows []string
+	if len(d.depsCandidates) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(lipgloss.Color("#3a4a5a")).Render("  no other todos in this project"))
+	}
+	for i, t := range d.depsCandidates {
+		box := "[ ]"
+		if d.depsChecked[t.ID] {
+			box = "[x]"
+		}
+		st := todoStatusStyle(t.Status)
+		line := fmt.Sprintf(" %s %s %s", box, st.Render(todoStatusIcon(t.Status)), t.Title)
+		if i == d.depsCursor {
+			line = lipgloss.NewStyle().Background(lipgloss.Color("#2a3a4a")).Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	auto := "[ ]"
+	if d.depsAutoStart {
+		auto = "[x]"
+	}
+	autoLine := fmt.Sprintf("%s %s auto-start a session when unblocked", auto, todoAutoStartIcon)
+
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("#5a6a7a")).Render("↑↓ move  space toggle  a auto-start  enter save  esc cancel")
+	content := header + "\n\n" + sub + "\n" + strings.Join(rows, "\n") + "\n\n" + autoLine + "\n\n" + hint
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center,
+		borderStyle.Render(content))
+}
+
 // TodoBranchName converts a todo title to a git branch name.
 func TodoBranchName(title string) string {
 	lower := strings.ToLower(title)
@@ -839,6 +957,10 @@ func (d *TodoDialog) handleKanbanKey(key string) TodoAction {
 		if t := d.SelectedTodo(); t != nil {
 			d.openStatusPicker(t)
 		}
+	case "b":
+		if t := d.SelectedTodo(); t != nil {
+			d.openDepsPicker(t)
+		}
 	case "enter":
 		if d.SelectedTodo() == nil {
 			return TodoActionNone
@@ -923,6 +1045,19 @@ func (d *TodoDialog) renderDetailPanel(innerW int) string {
 
 	content := label + "\n" + body
 
+	if blockers := t.OpenBlockers(d.todos); len(blockers) > 0 {
+		titles := make([]string, len(blockers))
+		for i, b := range blockers {
+			titles[i] = b.Title
+		}
+		blocked := wordWrapText(todoBlockedIcon+" blocked by: "+strings.Join(titles, ", "), textWidth, 2)
+		content += "\n\n" + todoBlockedStyle.Render(blocked)
+	}
+	if t.AutoStart && t.Status == session.TodoStatusTodo && t.SessionID == "" {
+		content += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#5a6a7a")).
+			Render(todoAutoStartIcon+" starts automatically when unblocked")
+	}
+
 	if t.Prompt != "" {
 		promptLabel := lipgloss.NewStyle().Foreground(lipgloss.Color("#5a6a7a")).Render("⌨ prompt")
 		promptPreview := wordWrapText(t.Prompt, textWidth, 2)
@@ -956,7 +1091,7 @@ func (d *TodoDialog) viewKanban() string {
 	header := lipgloss.NewStyle().Bold(true).Render("All Todos") + filterLabel
 
 	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("#5a6a7a")).Render(
-		"←/→ col  ↑/↓ card  f filter  n new  enter open  s status  e edit  d delete  shift+←/→ move  esc close",
+		"←/→ col  ↑/↓ card  f filter  n new  enter open  s status  e edit  b deps  d delete  shift+←/→ move  esc close",
 	)
 
 	// Empty board
@@ -1045,9 +1180,16 @@ func (d *TodoDialog) renderKanbanColumn(colIdx int, col kanbanColumn, width int)
 
 func (d *TodoDialog) renderKanbanCard(t *session.Todo, isSelected, colFocused bool, width int) string {
 	icon := todoStatusIcon(t.Status)
+	iconStyle := todoStatusStyle(t.Status)
+	if t.IsBlocked(d.todos) {
+		icon = todoBlockedIcon
+		iconStyle = todoBlockedStyle
+	}
 	sessionMark := ""
 	if t.SessionID != "" {
 		sessionMark = " ⬡"
+	} else if t.AutoStart && t.Status == session.TodoStatusTodo {
+		sessionMark = " " + todoAutoStartIcon
 	}
 
 	// Available title width: selector(1) + icon(1) + space(1) + title + sessionMark
@@ -1080,7 +1222,7 @@ func (d *TodoDialog) renderKanbanCard(t *session.Todo, isSelected, colFocused bo
 	var titleLine string
 	switch {
 	case isSelected:
-		styledIcon := todoStatusStyle(t.Status).Render(icon)
+		styledIcon := iconStyle.Render(icon)
 		line := fmt.Sprintf("%s%s %s%s", selector, styledIcon, title, sessionMark)
 		titleLine = lipgloss.NewStyle().
 			Background(lipgloss.Color("#2a3a4a")).
@@ -1099,7 +1241,7 @@ func (d *TodoDialog) renderKanbanCard(t *session.Todo, isSelected, colFocused bo
 			Width(width).
 			Render(line)
 	default:
-		styledIcon := todoStatusStyle(t.Status).Render(icon)
+		styledIcon := iconStyle.Render(icon)
 		line := fmt.Sprintf("%s%s %s%s", selector, styledIcon, title, sessionMark)
 		titleLine = lipgloss.NewStyle().Width(width).Render(line)
 	}
@@ -369,6 +369,58 @@ func TestTodoDialog_ViewKanban_SessionLinkIndicator(t *testing.T) {
 	}
 }
 
+func TestTodoDialog_ViewKanban_BlockedIndicator(t *testing.T) {
+	d := NewTodoDialog()
+	d.SetSize(160, 40)
+	blocker := makeTodo("schema", session.TodoStatusInProgress)
+	blocked := makeTodo("api", session.TodoStatusTodo)
+	blocked.BlockedBy = []string{"schema"}
+	d.Show("/myproject", "", "", []*session.Todo{blocked, blocker})
+
+	view := d.View()
+	if !strings.Contains(view, todoBlockedIcon) {
+		t.Errorf("expected blocked indicator %s in view:\n%s", todoBlockedIcon, view)
+	}
+	if !strings.Contains(view, "blocked by: schema") {
+		t.Errorf("expected blocker title in detail panel:\n%s", view)
+	}
+
+	blocker.Status = session.TodoStatusDone
+	d.SetTodos([]*session.Todo{blocked, blocker})
+	if strings.Contains(d.View(), "blocked by") {
+		t.Error("blocked line should disappear once the blocker is done")
+	}
+}
+
+func TestTodoDialog_DepsPicker(t *testing.T) {
+	d := NewTodoDialog()
+	d.SetSize(160, 40)
+	a := makeTodo("a", session.TodoStatusTodo)
+	b := makeTodo("b", session.TodoStatusTodo)
+	c := makeTodo("c", session.TodoStatusTodo)
+	other := makeTodo("other", session.TodoStatusTodo)
+	other.ProjectPath = "/elsewhere"
+	d.Show("/proj", "", "", []*session.Todo{a, b, c, other})
+
+	d.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if d.mode != todoModeDeps {
+		t.Fatalf("expected deps mode after b, got %v", d.mode)
+	}
+	if len(d.depsCandidates) != 2 {
+		t.Fatalf("expected 2 candidates from the same project, got %d", len(d.depsCandidates))
+	}
+	d.HandleKey(tea.KeyMsg{Type: tea.KeyDown})
+	d.HandleKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	d.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if action := d.HandleKey(tea.KeyMsg{Type: tea.KeyEnter}); action != TodoActionSaveDeps {
+		t.Fatalf("expected TodoActionSaveDeps, got %v", action)
+	}
+	blockedBy, autoStart := d.GetDepsValues()
+	if len(blockedBy) != 1 || blockedBy[0] != "c" || !autoStart {
+		t.Errorf("GetDepsValues() = %v, %v; want [c], true", blockedBy, autoStart)
+	}
+}
+
 func TestTodoDialog_ViewKanban_TruncatesLongTitle(t *testing.T) {
 	d := NewTodoDialog()
 	d.SetSize(80, 40) // narrow width to force truncation
@@ -192,22 +192,13 @@ func (h *Home) handleLoadSessions(msg loadSessionsMsg) tea.Cmd {
 		}
 		// Trigger immediate preview fetch for initial selection
 		h.updateDiffStat()
-		// Eager PR fetch for worktree sessions with no cached PR data
-		var prCmds []tea.Cmd
-		if h.ghPath != "" {
+		// Eager PR fetch for worktree sessions. The PR manager batches the
+		// lookups and fills the UI cache through its change callback.
+		if h.prManager != nil {
 			for _, item := range h.flatItems {
 				if item.Type == session.ItemTypeSession && item.Session != nil &&
 					item.Session.IsWorktree() && item.Session.WorktreePath != "" {
-					_, _, hasCached := h.cache.HasPREntry(item.Session.ID)
-					if !hasCached {
-						h.cache.TouchPR(item.Session.ID)
-						sid := item.Session.ID
-						wtPath := item.Session.WorktreePath
-						ghPath := h.ghPath
-						prCmds = append(prCmds, func() tea.Msg {
-							return fetchPRInfo(sid, wtPath, ghPath)
-						})
-					}
+					h.prManager.UpdateSessionPR(item.Session.ID, item.Session.WorktreePath)
 				}
 			}
 		}
@@ -215,18 +206,16 @@ func (h *Home) handleLoadSessions(msg loadSessionsMsg) tea.Cmd {
 			h.previewFetchingMu.Lock()
 			h.previewFetchingID = selected.ID
 			h.previewFetchingMu.Unlock()
-			// Batch preview fetch with any OpenCode detection commands, diff fetch, and PR fetches
+			// Batch preview fetch with any OpenCode detection commands and diff fetch
 			allCmds := append(detectionCmds, h.fetchPreview(selected))
 			if dir := h.effectiveDir(selected); dir != "" && git.IsGitRepo(dir) {
 				allCmds = append(allCmds, fetchDiffCmd(dir, selected.ID))
 			}
-			allCmds = append(allCmds, prCmds...)
 			return tea.Batch(allCmds...)
 		}
-		// No selection, but still run detection and PR fetch commands if any
-		allCmds := append(detectionCmds, prCmds...)
-		if len(allCmds) > 0 {
-			return tea.Batch(allCmds...)
+		// No selection, but still run detection commands if any
+		if len(detectionCmds) > 0 {
+			return tea.Batch(detectionCmds...)
 		}
 	}
 	return nil
@@ -878,28 +867,7 @@ func (h *Home) handlePRFetched(msg prFetchedMsg) tea.Cmd {
 		h.worktreeFinishDialog.SetPR(msg.pr, true)
 	}
 
-	// Auto-advance todo status based on PR state
-	if msg.pr != nil {
-		if todo, err := h.storage.FindTodoBySessionID(msg.sessionID); err == nil && todo != nil {
-			var newStatus session.TodoStatus
-			switch msg.pr.State {
-			case "OPEN", "DRAFT":
-				if todo.Status != session.TodoStatusInReview && todo.Status != session.TodoStatusDone {
-					newStatus = session.TodoStatusInReview
-				}
-			case "MERGED":
-				if todo.Status != session.TodoStatusDone {
-					newStatus = session.TodoStatusDone
-				}
-				// "CLOSED" (PR closed without merging): intentionally no transition — todo stays in its current state
-			}
-			if newStatus != "" {
-				if err := h.storage.UpdateTodoStatus(todo.ID, newStatus, msg.sessionID); err != nil {
-					uiLog.Warn("pr_todo_status_err", slog.String("todo", todo.ID), slog.String("err", err.Error()))
-				}
-			}
-		}
-	}
+	// Linked todos follow the PR via the TodoReconciler registered in NewHome.
 	return nil
 }
 
@@ -973,7 +941,9 @@ func (h *Home) handleWorktreeFinishResult(msg worktreeFinishResultMsg) tea.Cmd {
 	}
 
 	// Success: remove session from instances and clean up
-	h.worktreeFinishDialog.Hide()
+	if h.worktreeFinishDialog.GetSessionID() == msg.sessionID {
+		h.worktreeFinishDialog.Hide()
+	}
 
 	h.instancesMu.Lock()
 	for i, s := range h.instances {
@@ -1007,7 +977,12 @@ func (h *Home) handleWorktreeFinishResult(msg worktreeFinishResultMsg) tea.Cmd {
 	}
 	h.forceSaveInstances()
 
-	// Delete the todo linked to this session (worktree finish = work complete)
+	// After a PR merge the todo reconciler has moved the linked todo to done;
+	// keep it on the board. Otherwise finishing means the work is complete.
+	if msg.viaPR {
+		h.setError(fmt.Errorf("PR merged: finished worktree '%s'", msg.sessionTitle))
+		return nil
+	}
 	if err := h.storage.DeleteTodosForSession(msg.sessionID); err != nil {
 		uiLog.Warn("delete_todo_for_session_err", slog.String("session", msg.sessionID), slog.String("err", err.Error()))
 	}
@@ -1017,6 +992,44 @@ func (h *Home) handleWorktreeFinishResult(msg worktreeFinishResultMsg) tea.Cmd {
 	return nil
 }
 
+// handleWorktreePRFinishResult processes worktreePRFinishResultMsg. The
+// session stays until its PR merges; see handleWorktreePRMerged.
+func (h *Home) handleWorktreePRFinishResult(msg worktreePRFinishResultMsg) tea.Cmd {
+	if msg.pr == nil {
+		if h.worktreeFinishDialog.IsVisible() {
+			h.worktreeFinishDialog.SetError(msg.err.Error())
+		} else {
+			h.setError(msg.err)
+		}
+		return nil
+	}
+	if h.worktreeFinishDialog.GetSessionID() == msg.sessionID {
+		h.worktreeFinishDialog.Hide()
+	}
+	if h.prManager != nil {
+		h.prManager.SetSessionPR(msg.sessionID, msg.pr)
+	}
+	if msg.err != nil {
+		h.setError(fmt.Errorf("Opened PR #%d for '%s', but: %w", msg.pr.Number, msg.sessionTitle, msg.err))
+		return nil
+	}
+	h.setError(fmt.Errorf("Opened PR #%d for '%s'; the worktree is cleaned up once it merges", msg.pr.Number, msg.sessionTitle))
+	return nil
+}
+
+// handleWorktreePRMerged tears down a session finished via PR after the
+// finish watcher saw its PR merged.
+func (h *Home) handleWorktreePRMerged(msg worktreePRMergedMsg) tea.Cmd {
+	h.instancesMu.RLock()
+	inst := h.instanceByID[msg.inst.ID]
+	h.instancesMu.RUnlock()
+	if inst == nil {
+		inst = msg.inst
+	}
+	return h.finishWorktree(inst, inst.ID, inst.Title, inst.WorktreeBranch,
+		inst.WorktreeRepoRoot, inst.WorktreePath, msg.keepBranch, true)
+}
+
 // handleTick processes tickMsg, performing all periodic maintenance tasks:
 // error dismissal, animation cleanup, preview cache refresh, and PR status polling.
 func (h *Home) handleTick(msg tickMsg) tea.Cmd {
@@ -1131,28 +1144,25 @@ func (h *Home) handleTick(msg tickMsg) tea.Cmd {
 		}
 		h.previewFetchingMu.Unlock()
 	}
-	// PR fetch for ALL worktree sessions whose cache is missing or expired.
-	// Handles startup population and keeps all PR statuses fresh in the background.
-	var prCmds []tea.Cmd
-	if h.ghPath != "" {
-		ghPath := h.ghPath
+	// The PR manager fills the PR cache in the background; hand an open
+	// finish dialog its session's PR once it has arrived.
+	if h.worktreeFinishDialog.IsVisible() && !h.worktreeFinishDialog.prLoaded {
+		if cachedPR, ok := h.cache.GetPR(h.worktreeFinishDialog.GetSessionID()); ok {
+			h.worktreeFinishDialog.SetPR(cachedPR, true)
+		}
+	}
+	// PR fetch for ALL worktree sessions whose PR is missing or expired.
+	// Handles startup population and keeps all PR statuses fresh in the
+	// background; the PR manager skips fresh entries and batches the rest
+	// into one query per repo.
+	if h.prManager != nil {
 		h.instancesMu.RLock()
 		for _, inst := range h.instances {
-			if !inst.IsWorktree() || inst.WorktreePath == "" {
-				continue
+			if inst.IsWorktree() && inst.WorktreePath != "" {
+				h.prManager.UpdateSessionPR(inst.ID, inst.WorktreePath)
 			}
-			_, cachedAtPR, hasCachedPR := h.cache.HasPREntry(inst.ID)
-			if hasCachedPR && time.Since(cachedAtPR) <= prCacheTTL {
-				continue
-			}
-			h.cache.TouchPR(inst.ID) // Prevent duplicate fetches before goroutine lands
-			sid := inst.ID
-			wtPath := inst.WorktreePath
-			prCmds = append(prCmds, func() tea.Msg {
-				return fetchPRInfo(sid, wtPath, ghPath)
-			})
 		}
 		h.instancesMu.RUnlock()
 	}
-	return tea.Batch(append([]tea.Cmd{h.tick(), previewCmd}, prCmds...)...)
+	return tea.Batch(h.tick(), previewCmd)
 }
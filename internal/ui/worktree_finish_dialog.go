@@ -8,7 +8,7 @@ import (
 )
 
 // WorktreeFinishDialog handles the two-step worktree finish flow:
-// Step 0: Configure options (keep branch)
+// Step 0: Configure options (keep branch, finish via PR, auto-merge)
 // Step 1: Confirm the destructive actions
 type WorktreeFinishDialog struct {
 	visible bool
@@ -32,6 +32,8 @@ type WorktreeFinishDialog struct {
 
 	// Options (step 0)
 	keepBranch bool
+	viaPR      bool // push and open a PR; tear down once it merges
+	autoMerge  bool // with viaPR: enable auto-merge on the PR
 
 	// Dialog state
 	step int // 0=options, 1=confirm
@@ -57,6 +59,8 @@ func (d *WorktreeFinishDialog) Show(sessionID, sessionTitle, branchName, repoRoo
 	d.prEntry = nil
 	d.prLoaded = false
 	d.keepBranch = false
+	d.viaPR = false
+	d.autoMerge = false
 	d.step = 0
 }
 
@@ -115,6 +119,12 @@ func (d *WorktreeFinishDialog) GetOptions() (keepBranch bool) {
 	return d.keepBranch
 }
 
+// GetPROptions returns whether to finish via a pull request and whether to
+// enable auto-merge on it.
+func (d *WorktreeFinishDialog) GetPROptions() (viaPR, autoMerge bool) {
+	return d.viaPR, d.viaPR && d.autoMerge
+}
+
 // HandleKey processes a key event and returns the action to take.
 // Returns: action string ("close", "confirm", ""), and whether the dialog handled the key.
 func (d *WorktreeFinishDialog) HandleKey(key string) (action string) {
@@ -149,6 +159,16 @@ func (d *WorktreeFinishDialog) HandleKey(key string) (action string) {
 		d.keepBranch = !d.keepBranch
 		return ""
 
+	case "p":
+		d.viaPR = !d.viaPR
+		return ""
+
+	case "a":
+		if d.viaPR {
+			d.autoMerge = !d.autoMerge
+		}
+		return ""
+
 	case "enter":
 		d.errorMsg = ""
 		d.step = 1
@@ -284,6 +304,21 @@ func (d *WorktreeFinishDialog) viewOptions(titleStyle, labelStyle, valueStyle, c
 	b.WriteString(checkboxStyle.Render(fmt.Sprintf("  %s Keep branch after cleanup", keepCheck)))
 	b.WriteString("\n")
 
+	prCheck := "[ ]"
+	if d.viaPR {
+		prCheck = "[x]"
+	}
+	b.WriteString(checkboxStyle.Render(fmt.Sprintf("  %s Finish via pull request", prCheck)))
+	b.WriteString("\n")
+	if d.viaPR {
+		autoCheck := "[ ]"
+		if d.autoMerge {
+			autoCheck = "[x]"
+		}
+		b.WriteString(checkboxStyle.Render(fmt.Sprintf("      %s Enable auto-merge", autoCheck)))
+		b.WriteString("\n")
+	}
+
 	// Error line
 	if d.errorMsg != "" {
 		b.WriteString("\n")
@@ -292,7 +327,11 @@ func (d *WorktreeFinishDialog) viewOptions(titleStyle, labelStyle, valueStyle, c
 	}
 
 	b.WriteString("\n")
-	b.WriteString(footerStyle.Render("Space toggle | Enter confirm | Esc cancel"))
+	hint := "Space keep branch | p PR | Enter confirm | Esc cancel"
+	if d.viaPR {
+		hint = "Space keep branch | p PR | a auto-merge | Enter confirm | Esc cancel"
+	}
+	b.WriteString(footerStyle.Render(hint))
 
 	dialog := boxStyle.Render(b.String())
 	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, dialog)
@@ -326,13 +365,31 @@ func (d *WorktreeFinishDialog) viewConfirm(titleStyle, labelStyle, errStyle, foo
 	b.WriteString("\n")
 
 	actionStyle := lipgloss.NewStyle().Foreground(ColorText)
-	b.WriteString(actionStyle.Render("  • Remove worktree directory"))
+	indent := "  "
+	if d.viaPR {
+		b.WriteString(actionStyle.Render(fmt.Sprintf("  • Push branch %s", d.branchName)))
+		b.WriteString("\n")
+		if d.prEntry != nil && (d.prEntry.State == "OPEN" || d.prEntry.State == "DRAFT") {
+			b.WriteString(actionStyle.Render(fmt.Sprintf("  • Use existing PR #%d", d.prEntry.Number)))
+		} else {
+			b.WriteString(actionStyle.Render("  • Open a pull request"))
+		}
+		b.WriteString("\n")
+		if d.autoMerge {
+			b.WriteString(actionStyle.Render("  • Enable auto-merge"))
+			b.WriteString("\n")
+		}
+		b.WriteString(actionStyle.Render("  • Once the PR is merged:"))
+		b.WriteString("\n")
+		indent = "    "
+	}
+	b.WriteString(actionStyle.Render(indent + "• Remove worktree directory"))
 	b.WriteString("\n")
 	if !d.keepBranch {
-		b.WriteString(actionStyle.Render(fmt.Sprintf("  • Delete branch %s", d.branchName)))
+		b.WriteString(actionStyle.Render(fmt.Sprintf("%s• Delete branch %s", indent, d.branchName)))
 		b.WriteString("\n")
 	}
-	b.WriteString(actionStyle.Render("  • Remove session from hangar"))
+	b.WriteString(actionStyle.Render(indent + "• Remove session from hangar"))
 	b.WriteString("\n")
 
 	// Dirty warning
@@ -99,3 +99,42 @@ func TestWorktreeFinishDialog_ShowResetsPR(t *testing.T) {
 		t.Errorf("expected 'checking' after re-show, got:\n%s", view)
 	}
 }
+
+func TestWorktreeFinishDialog_PRMode(t *testing.T) {
+	d := NewWorktreeFinishDialog()
+	d.SetSize(120, 40)
+	d.Show("id1", "my-session", "feat/foo", "/repo", "/repo/.worktrees/foo")
+	d.SetPR(&prCacheEntry{Number: 7, State: "OPEN"}, true)
+
+	// Auto-merge only applies in PR mode.
+	d.HandleKey("a")
+	if viaPR, autoMerge := d.GetPROptions(); viaPR || autoMerge {
+		t.Fatalf("GetPROptions() = %v, %v before enabling PR mode", viaPR, autoMerge)
+	}
+
+	d.HandleKey("p")
+	d.HandleKey("a")
+	if viaPR, autoMerge := d.GetPROptions(); !viaPR || !autoMerge {
+		t.Fatalf("GetPROptions() = %v, %v; want true, true", viaPR, autoMerge)
+	}
+	if view := d.View(); !strings.Contains(view, "Enable auto-merge") {
+		t.Errorf("expected auto-merge option in view, got:\n%s", view)
+	}
+
+	d.HandleKey("enter")
+	view := d.View()
+	for _, want := range []string{"Push branch feat/foo", "Use existing PR #7", "Once the PR is merged"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in confirm view, got:\n%s", want, view)
+		}
+	}
+	if d.HandleKey("y") != "confirm" {
+		t.Error("expected confirm action")
+	}
+
+	// Show resets PR mode.
+	d.Show("id2", "other", "feat/bar", "/repo", "/repo/.worktrees/bar")
+	if viaPR, _ := d.GetPROptions(); viaPR {
+		t.Error("Show should reset PR mode")
+	}
+}
@@ -1,8 +1,11 @@
-import { QueryClient, QueryClientProvider } from '@tanstack/react-query'
+import { QueryClient, QueryClientProvider, useQuery } from '@tanstack/react-query'
 import { BrowserRouter, Routes, Route, Navigate } from 'react-router-dom'
 import { useEffect } from 'react'
 import { useUIStore } from './stores/uiStore'
+import { useAuthStore } from './stores/authStore'
+import { api } from './api/client'
 import { AppShell } from './components/layout/AppShell'
+import { LoginPage } from './components/auth/LoginPage'
 
 const queryClient = new QueryClient({
   defaultOptions: {
@@ -36,15 +39,40 @@ function ThemeProvider({ children }: { children: React.ReactNode }) {
   return <>{children}</>
 }
 
+// AuthGate shows the login screen when the server enforces API tokens and the
+// stored token is missing or no longer valid.
+function AuthGate({ children }: { children: React.ReactNode }) {
+  const token = useAuthStore((s) => s.token)
+  const { data, isLoading } = useQuery({
+    queryKey: ['auth', token],
+    queryFn: api.getAuth,
+    staleTime: 60_000,
+  })
+
+  if (isLoading) {
+    return (
+      <div className="flex items-center justify-center h-screen text-muted-foreground">
+        Loading...
+      </div>
+    )
+  }
+  if (data?.auth_required && !data.authenticated) {
+    return <LoginPage />
+  }
+  return <>{children}</>
+}
+
 export default function App() {
   return (
     <QueryClientProvider client={queryClient}>
       <BrowserRouter basename="/ui">
         <ThemeProvider>
-          <Routes>
-            <Route path="/*" element={<AppShell />} />
-            <Route path="/" element={<Navigate to="/sessions" replace />} />
-          </Routes>
+          <AuthGate>
+            <Routes>
+              <Route path="/*" element={<AppShell />} />
+              <Route path="/" element={<Navigate to="/sessions" replace />} />
+            </Routes>
+          </AuthGate>
         </ThemeProvider>
       </BrowserRouter>
     </QueryClientProvider>
@@ -1,4 +1,5 @@
-import type { Session, SessionOutputResponse, Project, Todo, CreateSessionRequest, CreateTodoRequest, PRDashboard, PRDetail } from './types'
+import type { Session, SessionOutputResponse, Project, Todo, CreateSessionRequest, CreateTodoRequest, PRDashboard, PRDetail, PRFeedbackResult, AuthInfo } from './types'
+import { useAuthStore } from '../stores/authStore'
 
 const getBaseURL = (): string => {
   // In dev, Vite proxy handles /api → localhost:47437
@@ -9,10 +10,19 @@ const getBaseURL = (): string => {
 const BASE = getBaseURL()
 
 export async function apiFetch<T>(path: string, init?: RequestInit): Promise<T> {
+  const token = useAuthStore.getState().token
   const res = await fetch(`${BASE}${path}`, {
-    headers: { 'Content-Type': 'application/json', ...init?.headers },
     ...init,
+    headers: {
+      'Content-Type': 'application/json',
+      ...(token ? { Authorization: `Bearer ${token}` } : {}),
+      ...init?.headers,
+    },
   })
+  if (res.status === 401) {
+    // Token missing, revoked or wrong — drop it so the login screen shows.
+    useAuthStore.getState().setToken(null)
+  }
   if (!res.ok) {
     const err = await res.json().catch(() => ({ error: res.statusText }))
     throw new Error((err as { error?: string }).error || `HTTP ${res.status}`)
@@ -24,6 +34,7 @@ export async function apiFetch<T>(path: string, init?: RequestInit): Promise<T>
 }
 
 export const api = {
+  getAuth: () => apiFetch<AuthInfo>('/api/v1/auth'),
   getSessions: () => apiFetch<Session[]>('/api/v1/sessions'),
   getSession: (id: string) => apiFetch<Session>(`/api/v1/sessions/${id}`),
   getSessionOutput: (id: string, width?: number) =>
@@ -64,7 +75,7 @@ export const api = {
       method: 'POST',
       body: JSON.stringify(req),
     }),
-  updateTodo: (id: string, req: { status?: string; title?: string; description?: string; prompt?: string }) =>
+  updateTodo: (id: string, req: { status?: string; title?: string; description?: string; prompt?: string; blocked_by?: string[]; auto_start?: boolean }) =>
     apiFetch<Todo>(`/api/v1/todos/${id}`, {
       method: 'PATCH',
       body: JSON.stringify(req),
@@ -90,4 +101,8 @@ export const api = {
       method: 'POST',
       body: JSON.stringify({ action }),
     }),
+  addressPRFeedback: (repo: string, number: number) =>
+    apiFetch<PRFeedbackResult>(`/api/v1/prs/feedback?repo=${encodeURIComponent(repo)}&number=${number}`, {
+      method: 'POST',
+    }),
 }
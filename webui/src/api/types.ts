@@ -65,12 +65,21 @@ export interface PRFileChange {
 export interface PRDetail extends PRFullInfo {
   body?: string
   mergeability?: string
+  merge_state?: string
+  auto_merge?: string
   comments: PRComment[]
   reviews: PRReview[]
   files: PRFileChange[]
   diff_content?: string
 }
 
+export interface PRFeedbackResult {
+  session_id: string
+  session_title: string
+  created: boolean
+  prompt: string
+}
+
 export interface Session {
   id: string
   title: string
@@ -103,6 +112,9 @@ export interface Todo {
   status: 'todo' | 'in_progress' | 'done'
   session_id?: string
   order: number
+  blocked_by?: string[]
+  blocked: boolean
+  auto_start: boolean
   created_at: string
   updated_at: string
 }
@@ -144,3 +156,20 @@ export interface WsSessionOutputData {
   session_id: string
   output: string
 }
+
+export interface WsSessionStatusData {
+  session_id: string
+  status: Session['status']
+  previous_status?: Session['status']
+}
+
+export interface WsPRsChangedData {
+  repo: string
+  numbers: number[]
+}
+
+export interface AuthInfo {
+  auth_required: boolean
+  authenticated: boolean
+  scope?: 'read' | 'control' | 'admin'
+}
@@ -1,4 +1,5 @@
 import type { WsMessage } from './types'
+import { withToken } from '../stores/authStore'
 
 type EventHandler = (data: unknown) => void
 
@@ -8,6 +9,8 @@ class HangarWebSocket {
   private reconnectTimer: ReturnType<typeof setTimeout> | null = null
   private reconnectDelay = 1000
   private url: string
+  // Output subscriptions, ref-counted per session ID and replayed on reconnect
+  private outputSubs = new Map<string, number>()
 
   constructor() {
     const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
@@ -17,7 +20,7 @@ class HangarWebSocket {
 
   connect() {
     if (this.ws?.readyState === WebSocket.OPEN) return
-    this.ws = new WebSocket(this.url)
+    this.ws = new WebSocket(withToken(this.url))
 
     this.ws.onmessage = (e: MessageEvent) => {
       try {
@@ -44,6 +47,34 @@ class HangarWebSocket {
 
     this.ws.onopen = () => {
       this.reconnectDelay = 1000
+      if (this.outputSubs.size > 0) {
+        this.send('subscribe', { session_ids: [...this.outputSubs.keys()], events: ['session_output'] })
+      }
+    }
+  }
+
+  private send(type: string, data: unknown) {
+    if (this.ws?.readyState === WebSocket.OPEN) {
+      this.ws.send(JSON.stringify({ type, data }))
+    }
+  }
+
+  // subscribeOutput asks the server to push session_output events for a
+  // session. Returns an unsubscribe function.
+  subscribeOutput(sessionId: string): () => void {
+    const count = this.outputSubs.get(sessionId) ?? 0
+    this.outputSubs.set(sessionId, count + 1)
+    if (count === 0) {
+      this.send('subscribe', { session_ids: [sessionId], events: ['session_output'] })
+    }
+    return () => {
+      const n = (this.outputSubs.get(sessionId) ?? 1) - 1
+      if (n > 0) {
+        this.outputSubs.set(sessionId, n)
+        return
+      }
+      this.outputSubs.delete(sessionId)
+      this.send('unsubscribe', { session_ids: [sessionId], events: ['session_output'] })
     }
   }
 
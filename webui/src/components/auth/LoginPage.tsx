@@ -0,0 +1,61 @@
+import { useState } from 'react'
+import { useQueryClient } from '@tanstack/react-query'
+import { api } from '@/api/client'
+import { useAuthStore } from '@/stores/authStore'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+
+export function LoginPage() {
+  const [value, setValue] = useState('')
+  const [error, setError] = useState<string | null>(null)
+  const [checking, setChecking] = useState(false)
+  const setToken = useAuthStore((s) => s.setToken)
+  const queryClient = useQueryClient()
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    const token = value.trim()
+    if (!token) return
+    setChecking(true)
+    setError(null)
+    setToken(token)
+    try {
+      const info = await api.getAuth()
+      if (!info.authenticated) {
+        setToken(null)
+        setError('Invalid or revoked token')
+        return
+      }
+      queryClient.invalidateQueries()
+    } catch (err) {
+      setToken(null)
+      setError(err instanceof Error ? err.message : 'Failed to reach server')
+    } finally {
+      setChecking(false)
+    }
+  }
+
+  return (
+    <div className="flex items-center justify-center h-screen bg-background text-foreground">
+      <form onSubmit={handleSubmit} className="w-full max-w-sm space-y-4 p-6 rounded-lg border border-border bg-card">
+        <div>
+          <h1 className="text-lg font-semibold">Hangar</h1>
+          <p className="text-sm text-muted-foreground mt-1">
+            This server requires an API token. Create one with{' '}
+            <code className="text-xs">hangar web token create --scope control</code>.
+          </p>
+        </div>
+        <div className="space-y-1.5">
+          <Label htmlFor="api-token">API token</Label>
+          <Input id="api-token" type="password" value={value} onChange={(e) => setValue(e.target.value)}
+            placeholder="hgr_..." className="bg-accent border-border" autoFocus />
+        </div>
+        {error && <p className="text-sm text-destructive">{error}</p>}
+        <Button type="submit" className="w-full" disabled={!value.trim() || checking}>
+          {checking ? 'Checking...' : 'Sign in'}
+        </Button>
+      </form>
+    </div>
+  )
+}
@@ -4,6 +4,7 @@ import { SessionList } from '../sessions/SessionList'
 import { CreateSessionDialog } from '../dialogs/CreateSessionDialog'
 import { AddProjectDialog } from '../projects/AddProjectDialog'
 import { useUIStore } from '@/stores/uiStore'
+import { useAuthStore } from '@/stores/authStore'
 import { useSessions } from '@/hooks/useSessions'
 import { usePRDashboard } from '@/hooks/usePRDashboard'
 import { cn } from '@/lib/utils'
@@ -30,6 +31,7 @@ export function AppShell() {
   const [addProjectOpen, setAddProjectOpen] = useState(false)
   const { sidebarOpen, setSidebarOpen, sidebarWidth, setSidebarWidth, theme, setTheme, selectedSessionId } = useUIStore()
   const { data: sessions = [] } = useSessions()
+  const { token, setToken } = useAuthStore()
 
   // Derive the current project from the selected session's group_path so the
   // new session dialog can pre-populate the project field
@@ -153,6 +155,15 @@ export function AppShell() {
           >
             {THEME_ICON[theme]}
           </button>
+          {token && (
+            <button
+              onClick={() => setToken(null)}
+              className="p-1.5 rounded hover:bg-accent text-muted-foreground hover:text-foreground transition-colors text-sm"
+              title="Sign out"
+            >
+              ⏻
+            </button>
+          )}
         </div>
 
         {/* Route content */}
@@ -152,6 +152,19 @@ export function PRDetail({ pr, onClose, onNavigateToSession }: PRDetailProps) {
     },
   })
 
+  const [feedbackSentTo, setFeedbackSentTo] = useState<string | null>(null)
+  const feedbackMutation = useMutation({
+    mutationFn: () => api.addressPRFeedback(repo, pr.number),
+    onSuccess: (result) => {
+      setMutationError(null)
+      setFeedbackSentTo(result.session_title)
+      if (result.created) {
+        queryClient.invalidateQueries({ queryKey: ['sessions'] })
+      }
+    },
+    onError: (err: Error) => setMutationError(err.message),
+  })
+
   const effectiveState = pr.is_draft ? 'DRAFT' : pr.state
   const isOwn = pr.source === 'mine'
   const isOpen = pr.state === 'OPEN' || effectiveState === 'DRAFT'
@@ -382,6 +395,9 @@ export function PRDetail({ pr, onClose, onNavigateToSession }: PRDetailProps) {
             {mutationError && (
               <p className="w-full mt-1 text-xs text-(--oasis-red)">{mutationError}</p>
             )}
+            {feedbackSentTo && !mutationError && (
+              <p className="w-full mt-1 text-xs text-(--oasis-green)">Feedback sent to {feedbackSentTo}</p>
+            )}
             {isOpen && (
               <>
                 <button
@@ -435,6 +451,16 @@ export function PRDetail({ pr, onClose, onNavigateToSession }: PRDetailProps) {
                 )}
               </>
             )}
+            {isOpen && (isOwn || pr.session_id) && (
+              <button
+                onClick={() => feedbackMutation.mutate()}
+                disabled={feedbackMutation.isPending}
+                title="Send unresolved review comments and failed check logs to the PR's session"
+                className="px-3 py-1.5 rounded text-xs font-medium bg-(--oasis-accent)/20 hover:bg-(--oasis-accent)/30 text-(--oasis-accent) border border-(--oasis-accent)/30 transition-colors disabled:opacity-50"
+              >
+                {feedbackMutation.isPending ? 'Collecting Feedback…' : 'Address Feedback'}
+              </button>
+            )}
             {isOwn && pr.state === 'CLOSED' && (
               <button
                 onClick={() => stateMutation.mutate({ action: 'reopen' })}
@@ -3,6 +3,7 @@ import { Terminal } from '@xterm/xterm'
 import { FitAddon } from '@xterm/addon-fit'
 import { WebLinksAddon } from '@xterm/addon-web-links'
 import '@xterm/xterm/css/xterm.css'
+import { withToken } from '@/stores/authStore'
 
 interface TerminalViewProps {
   sessionId: string
@@ -12,7 +13,7 @@ interface TerminalViewProps {
 function getStreamURL(sessionId: string): string {
   const proto = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
   const host = import.meta.env.DEV ? 'localhost:47437' : window.location.host
-  return `${proto}//${host}/api/v1/sessions/${sessionId}/stream`
+  return withToken(`${proto}//${host}/api/v1/sessions/${sessionId}/stream`)
 }
 
 export function TerminalView({ sessionId, className }: TerminalViewProps) {
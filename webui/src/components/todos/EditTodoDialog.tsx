@@ -12,13 +12,22 @@ interface EditTodoDialogProps {
   open: boolean
   onOpenChange: (open: boolean) => void
   todo: Todo
+  projectTodos: Todo[]
 }
 
-export function EditTodoDialog({ open, onOpenChange, todo }: EditTodoDialogProps) {
+export function EditTodoDialog({ open, onOpenChange, todo, projectTodos }: EditTodoDialogProps) {
   const [title, setTitle] = useState(todo.title)
   const [description, setDescription] = useState(todo.description ?? '')
   const [prompt, setPrompt] = useState(todo.prompt ?? '')
+  const [blockedBy, setBlockedBy] = useState<string[]>(todo.blocked_by ?? [])
+  const [autoStart, setAutoStart] = useState(todo.auto_start)
   const updateMutation = useUpdateTodo()
+  const candidates = projectTodos.filter((t) => t.id !== todo.id)
+  // Compare dependencies by value so refetches don't reset in-progress edits.
+  const blockedKey = (todo.blocked_by ?? []).join(',')
+
+  const toggleBlocker = (id: string) =>
+    setBlockedBy((prev) => (prev.includes(id) ? prev.filter((b) => b !== id) : [...prev, id]))
 
   // Reset fields whenever the dialog opens with (potentially) a new todo
   useEffect(() => {
@@ -26,8 +35,10 @@ export function EditTodoDialog({ open, onOpenChange, todo }: EditTodoDialogProps
       setTitle(todo.title)
       setDescription(todo.description ?? '')
       setPrompt(todo.prompt ?? '')
+      setBlockedBy(blockedKey ? blockedKey.split(',') : [])
+      setAutoStart(todo.auto_start)
     }
-  }, [open, todo.id, todo.title, todo.description, todo.prompt])
+  }, [open, todo.id, todo.title, todo.description, todo.prompt, blockedKey, todo.auto_start])
 
   const handleSubmit = (e: React.FormEvent) => {
     e.preventDefault()
@@ -39,6 +50,8 @@ export function EditTodoDialog({ open, onOpenChange, todo }: EditTodoDialogProps
           title: title.trim(),
           description: description.trim() || undefined,
           prompt: prompt.trim() || undefined,
+          blocked_by: blockedBy,
+          auto_start: autoStart,
         },
       },
       { onSuccess: () => onOpenChange(false) }
@@ -82,6 +95,34 @@ export function EditTodoDialog({ open, onOpenChange, todo }: EditTodoDialogProps
               className="bg-accent border-border"
             />
           </div>
+          {candidates.length > 0 && (
+            <div className="space-y-1.5">
+              <Label>Blocked by</Label>
+              <div className="max-h-32 overflow-y-auto space-y-1 rounded-md border border-border bg-accent p-2">
+                {candidates.map((t) => (
+                  <label key={t.id} className="flex items-center gap-2 text-sm">
+                    <input
+                      type="checkbox"
+                      checked={blockedBy.includes(t.id)}
+                      onChange={() => toggleBlocker(t.id)}
+                    />
+                    <span className="truncate">{t.title}</span>
+                  </label>
+                ))}
+              </div>
+            </div>
+          )}
+          <label className="flex items-center gap-2 text-sm">
+            <input
+              type="checkbox"
+              checked={autoStart}
+              onChange={(e) => setAutoStart(e.target.checked)}
+            />
+            Start a session automatically when unblocked
+          </label>
+          {updateMutation.isError && (
+            <p className="text-xs text-red-400">{(updateMutation.error as Error).message}</p>
+          )}
           <DialogFooter>
             <Button type="button" variant="ghost" onClick={() => onOpenChange(false)}>
               Cancel
@@ -109,7 +109,7 @@ export function TodoBoard() {
                     )}
                   >
                     {(grouped[col.key] ?? []).map((todo, i) => (
-                      <TodoCard key={todo.id} todo={todo} index={i} />
+                      <TodoCard key={todo.id} todo={todo} index={i} projectTodos={todos} />
                     ))}
                     {provided.placeholder}
                     {(grouped[col.key] ?? []).length === 0 && !snapshot.isDraggingOver && (
@@ -10,9 +10,10 @@ const DRAG_THRESHOLD = 5
 interface TodoCardProps {
   todo: Todo
   index: number
+  projectTodos: Todo[]
 }
 
-export function TodoCard({ todo, index }: TodoCardProps) {
+export function TodoCard({ todo, index, projectTodos }: TodoCardProps) {
   const deleteMutation = useDeleteTodo()
   const [editOpen, setEditOpen] = useState(false)
   const dragStartPos = useRef<{ x: number; y: number } | null>(null)
@@ -32,6 +33,10 @@ export function TodoCard({ todo, index }: TodoCardProps) {
     }
   }
 
+  const openBlockers = projectTodos.filter(
+    (t) => todo.blocked_by?.includes(t.id) && t.status !== 'done'
+  )
+
   const handleClick = () => {
     if (wasDragging.current) return
     setEditOpen(true)
@@ -51,6 +56,7 @@ export function TodoCard({ todo, index }: TodoCardProps) {
             className={cn(
               'rounded-md border p-3 text-sm cursor-pointer active:cursor-grabbing',
               'border-border bg-accent hover:border-ring',
+              todo.blocked && 'border-dashed border-orange-600/60 opacity-75',
               'transition-colors select-none',
               snapshot.isDragging && 'shadow-lg border-ring rotate-1'
             )}
@@ -69,6 +75,17 @@ export function TodoCard({ todo, index }: TodoCardProps) {
             {todo.description && (
               <p className="mt-1 text-xs text-muted-foreground line-clamp-2">{todo.description}</p>
             )}
+            {todo.blocked && (
+              <div
+                className="mt-2 text-xs text-orange-400 truncate"
+                title={openBlockers.map((t) => t.title).join(', ')}
+              >
+                ⊘ blocked by {openBlockers.map((t) => t.title).join(', ') || 'another todo'}
+              </div>
+            )}
+            {todo.auto_start && todo.status === 'todo' && !todo.session_id && (
+              <div className="mt-1 text-xs text-muted-foreground">↻ starts when unblocked</div>
+            )}
             {todo.session_id && (
               <div className="mt-2 text-xs text-muted-foreground font-mono truncate">
                 session: {todo.session_id.slice(0, 8)}…
@@ -77,7 +94,7 @@ export function TodoCard({ todo, index }: TodoCardProps) {
           </div>
         )}
       </Draggable>
-      <EditTodoDialog open={editOpen} onOpenChange={setEditOpen} todo={todo} />
+      <EditTodoDialog open={editOpen} onOpenChange={setEditOpen} todo={todo} projectTodos={projectTodos} />
     </>
   )
 }
@@ -1,7 +1,7 @@
 import { useEffect } from 'react'
 import { useQueryClient } from '@tanstack/react-query'
 import { wsClient } from '../api/websocket'
-import type { WsSessionOutputData } from '../api/types'
+import type { Session, WsPRsChangedData, WsSessionOutputData, WsSessionStatusData } from '../api/types'
 
 export function useWebSocket() {
   const queryClient = useQueryClient()
@@ -25,11 +25,33 @@ export function useWebSocket() {
       void queryClient.invalidateQueries({ queryKey: ['sessions'] })
     })
 
+    // Patch status in place instead of re-fetching the whole list.
+    const offStatus = wsClient.on('session_status', (data) => {
+      const d = data as WsSessionStatusData
+      queryClient.setQueryData<Session[]>(['sessions'], (prev) =>
+        prev?.map((s) => (s.id === d.session_id ? { ...s, status: d.status } : s))
+      )
+      queryClient.setQueryData<Session>(['sessions', d.session_id], (prev) =>
+        prev ? { ...prev, status: d.status } : prev
+      )
+    })
+
+    // Sent when a GitHub webhook reports PR changes.
+    const offPRs = wsClient.on('prs_changed', (data) => {
+      const d = data as WsPRsChangedData
+      void queryClient.invalidateQueries({ queryKey: ['prs'] })
+      for (const n of d.numbers ?? []) {
+        void queryClient.invalidateQueries({ queryKey: ['pr-detail', d.repo, n] })
+      }
+    })
+
     return () => {
       offChanged()
       offUpdated()
       offCreated()
       offDeleted()
+      offStatus()
+      offPRs()
     }
   }, [queryClient])
 
@@ -44,6 +66,10 @@ export function useSessionOutput(sessionId: string, onOutput: (output: string) =
         onOutput(d.output)
       }
     })
-    return off
+    const unsubscribe = wsClient.subscribeOutput(sessionId)
+    return () => {
+      unsubscribe()
+      off()
+    }
   }, [sessionId, onOutput])
 }
@@ -0,0 +1,27 @@
+import { create } from 'zustand'
+import { persist } from 'zustand/middleware'
+
+interface AuthState {
+  token: string | null
+  setToken: (token: string | null) => void
+}
+
+// The API token is only needed when the server runs with [api] require_auth = true.
+export const useAuthStore = create<AuthState>()(
+  persist(
+    (set) => ({
+      token: null,
+      setToken: (token) => set({ token }),
+    }),
+    { name: 'hangar-auth' }
+  )
+)
+
+// withToken appends ?access_token= to a WebSocket URL. Browsers cannot set
+// headers on WebSocket connections, so the server accepts the token here.
+export function withToken(url: string): string {
+  const token = useAuthStore.getState().token
+  if (!token) return url
+  const sep = url.includes('?') ? '&' : '?'
+  return `${url}${sep}access_token=${encodeURIComponent(token)}`
+}